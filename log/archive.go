@@ -0,0 +1,246 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Uploader uploads objects to an object store, e.g. an S3Uploader.
+type Uploader interface {
+	// Upload uploads the size bytes of body under the key.
+	Upload(ctx context.Context, key string, body io.Reader, size int64) error
+}
+
+// ArchiverSettings are the settings for an Archiver.
+type ArchiverSettings struct {
+	// Uploader uploads the archives. Required.
+	Uploader Uploader
+	// KeyTemplate is the template of the object keys. "{name}" is replaced with the name of the file, "{date}" and
+	// "{time}" with the UTC date ("2006-01-02") and time ("20060102T150405Z") of the upload, and "{host}" with the host
+	// name. Defaults to "{name}".
+	KeyTemplate string
+	// MaxAttempts is the number of times an upload is attempted before giving up. Defaults to 3.
+	MaxAttempts int
+	// Backoff is the delay before the second attempt of an upload, doubled for every attempt after it. Defaults to one
+	// second.
+	Backoff time.Duration
+	// RemoveArchived removes local files once they have been uploaded.
+	RemoveArchived bool
+	// Manifests uploads an ArchiveManifest with every archive, as JSON, under the key of the archive followed by
+	// ".manifest.json". It's uploaded once the archive is, so auditors can verify that every archive was transferred
+	// completely.
+	Manifests bool
+	// OnError, if set, is called with the errors of the uploads run in the background, e.g. of rotated files.
+	OnError func(error)
+}
+
+var defaultArchiverSettings = ArchiverSettings{
+	KeyTemplate: "{name}",
+	MaxAttempts: 3,
+	Backoff:     time.Second,
+}
+
+func (s *ArchiverSettings) mergeDefault() {
+	if s.KeyTemplate == "" {
+		s.KeyTemplate = defaultArchiverSettings.KeyTemplate
+	}
+	if s.MaxAttempts <= 0 {
+		s.MaxAttempts = defaultArchiverSettings.MaxAttempts
+	}
+	if s.Backoff <= 0 {
+		s.Backoff = defaultArchiverSettings.Backoff
+	}
+}
+
+// Archiver uploads log files to an object store, with retries. Set it as the DatedFileSettings.Archiver to upload
+// files as they are rotated, or use it as the FileSink of a batching destination, like the ParquetWriter, to upload
+// its batches.
+type Archiver struct {
+	settings ArchiverSettings
+	host     string
+	now      func() time.Time
+
+	pending sync.WaitGroup
+}
+
+// NewArchiver returns an Archiver uploading with settings.Uploader.
+func NewArchiver(settings *ArchiverSettings) (*Archiver, error) {
+	if settings == nil || settings.Uploader == nil {
+		return nil, ErrorArchiverUploaderNotSpecified
+	}
+	s := *settings
+	s.mergeDefault()
+
+	host, _ := os.Hostname()
+	return &Archiver{settings: s, host: host, now: time.Now}, nil
+}
+
+// ArchiveManifest describes an archive uploaded by an Archiver.
+type ArchiveManifest struct {
+	// Name is the name of the archived file.
+	Name string `json:"name"`
+	// Key is the object key of the archive.
+	Key string `json:"key"`
+	// Lines is the number of lines of the archive. A last line without a trailing newline counts.
+	Lines int64 `json:"lines"`
+	// Bytes is the size of the archive.
+	Bytes int64 `json:"bytes"`
+	// SHA256 is the hex-encoded SHA-256 digest of the archive.
+	SHA256 string `json:"sha256"`
+}
+
+// ArchiveFile uploads the file at path, and removes it if RemoveArchived is set.
+func (a *Archiver) ArchiveFile(ctx context.Context, path string) error {
+	err := a.archive(ctx, filepath.Base(path), func() (io.ReadCloser, int64, error) {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, 0, err
+		}
+		info, err := file.Stat()
+		if err != nil {
+			_ = file.Close()
+			return nil, 0, err
+		}
+		return file, info.Size(), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if a.settings.RemoveArchived {
+		return os.Remove(path)
+	}
+	return nil
+}
+
+// WriteFile implements FileSink. It uploads the data under the name.
+func (a *Archiver) WriteFile(name string, data []byte) error {
+	return a.archive(context.Background(), name, func() (io.ReadCloser, int64, error) {
+		return io.NopCloser(bytes.NewReader(data)), int64(len(data)), nil
+	})
+}
+
+// Wait waits for the uploads running in the background to finish.
+func (a *Archiver) Wait() {
+	a.pending.Wait()
+}
+
+// archiveInBackground archives the file at path in the background. Errors are reported to OnError.
+func (a *Archiver) archiveInBackground(path string) {
+	a.pending.Add(1)
+	go func() {
+		defer a.pending.Done()
+		if err := a.ArchiveFile(context.Background(), path); err != nil && a.settings.OnError != nil {
+			a.settings.OnError(err)
+		}
+	}()
+}
+
+// archive uploads the archive of the file name, and its manifest if Manifests is set.
+func (a *Archiver) archive(ctx context.Context, name string, open func() (io.ReadCloser, int64, error)) error {
+	key := a.key(name)
+	if err := a.upload(ctx, key, open); err != nil {
+		return err
+	}
+	if !a.settings.Manifests {
+		return nil
+	}
+
+	manifest, err := newArchiveManifest(name, key, open)
+	if err != nil {
+		return &ErrorArchiveUpload{key: key, err: err}
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return &ErrorArchiveUpload{key: key, err: err}
+	}
+	return a.upload(ctx, key+".manifest.json", func() (io.ReadCloser, int64, error) {
+		return io.NopCloser(bytes.NewReader(data)), int64(len(data)), nil
+	})
+}
+
+// newArchiveManifest reads the archive, and returns its manifest.
+func newArchiveManifest(name, key string, open func() (io.ReadCloser, int64, error)) (*ArchiveManifest, error) {
+	body, _, err := open()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	hash := sha256.New()
+	manifest := &ArchiveManifest{Name: name, Key: key}
+	buf := make([]byte, 32*1024)
+	last := byte('\n')
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			hash.Write(buf[:n])
+			manifest.Bytes += int64(n)
+			manifest.Lines += int64(bytes.Count(buf[:n], []byte{'\n'}))
+			last = buf[n-1]
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if last != '\n' {
+		manifest.Lines++
+	}
+	manifest.SHA256 = hex.EncodeToString(hash.Sum(nil))
+
+	return manifest, nil
+}
+
+// upload uploads the object under the key, opening a new body for every attempt.
+func (a *Archiver) upload(ctx context.Context, key string, open func() (io.ReadCloser, int64, error)) error {
+	backoff := a.settings.Backoff
+
+	var errs []error
+	for attempt := range a.settings.MaxAttempts {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return &ErrorArchiveUpload{key: key, err: errors.Join(append(errs, ctx.Err())...)}
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		body, size, err := open()
+		if err != nil {
+			// The file won't get any more readable.
+			return &ErrorArchiveUpload{key: key, err: err}
+		}
+		err = a.settings.Uploader.Upload(ctx, key, body, size)
+		_ = body.Close()
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return &ErrorArchiveUpload{key: key, err: errors.Join(errs...)}
+}
+
+// key returns the object key of the file name.
+func (a *Archiver) key(name string) string {
+	now := a.now().UTC()
+	return strings.NewReplacer(
+		"{name}", name,
+		"{date}", now.Format(time.DateOnly),
+		"{time}", now.Format("20060102T150405Z"),
+		"{host}", a.host,
+	).Replace(a.settings.KeyTemplate)
+}
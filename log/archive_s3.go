@@ -0,0 +1,150 @@
+package log
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// s3UnsignedPayload is the payload hash of requests whose body isn't signed, so it can be streamed.
+const s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3Uploader is an Uploader that puts objects in an S3 bucket, or a bucket of an S3-compatible object store, signing
+// its requests with AWS Signature Version 4. Bodies are streamed, not signed, which S3 only accepts over HTTPS.
+type S3Uploader struct {
+	// Bucket is the name of the bucket. Required.
+	Bucket string
+	// Region is the region of the bucket, e.g. "us-east-1". Required.
+	Region string
+	// Endpoint is the URL of an S3-compatible object store, e.g. "http://localhost:9000", whose buckets are addressed
+	// by path. Defaults to the virtual-hosted-style AWS endpoint of the bucket.
+	Endpoint string
+	// AccessKeyID, SecretAccessKey and, for temporary credentials, SessionToken are the credentials of the requests.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// Client is the HTTP client of the requests. Defaults to http.DefaultClient.
+	Client *http.Client
+
+	// for testing
+	now func() time.Time
+}
+
+// Upload puts the object.
+func (u *S3Uploader) Upload(ctx context.Context, key string, body io.Reader, size int64) error {
+	endpoint, path := "https://"+u.Bucket+".s3."+u.Region+".amazonaws.com", "/"+key
+	if u.Endpoint != "" {
+		endpoint, path = strings.TrimSuffix(u.Endpoint, "/"), "/"+u.Bucket+"/"+key
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	// The path is sent escaped exactly as it's signed.
+	req.URL.Path, req.URL.RawPath = path, s3EscapePath(path)
+
+	now := time.Now
+	if u.now != nil {
+		now = u.now
+	}
+	u.sign(req, s3UnsignedPayload, now())
+
+	client := u.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("s3 returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// sign signs the request with AWS Signature Version 4, covering the host and every header of the request.
+func (u *S3Uploader) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	scope := amzDate[:8] + "/" + u.Region + "/s3/aws4_request"
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if u.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", u.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := []byte("AWS4" + u.SecretAccessKey)
+	for _, part := range []string{amzDate[:8], u.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		u.AccessKeyID,
+		scope,
+		signedHeaders,
+		signature,
+	))
+}
+
+// s3EscapePath escapes the path as S3 expects in canonical requests: every byte but the unreserved characters of RFC
+// 3986, and '/', is percent-encoded.
+func s3EscapePath(path string) string {
+	var b strings.Builder
+	for i := range len(path) {
+		c := path[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9', strings.IndexByte("-_.~/", c) >= 0:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
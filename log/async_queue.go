@@ -0,0 +1,196 @@
+package log
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// AsyncQueueSettings are the settings of the async queue of a logger: the lines dispatched to best-effort destinations
+// that are still being formatted and written in the background.
+//
+// The queue has two lanes. Error and Panic lines go through the priority lane, and everything else through the normal
+// lane, so under overload the normal lane fills up and drops Debug, Info and Warn lines first, while errors still get
+// through.
+type AsyncQueueSettings struct {
+	// Capacity is the maximum number of lines in the normal lane. Lines dispatched while their lane is full are dropped,
+	// and counted in LoggerStats.Dropped. Defaults to 4096.
+	Capacity int
+	// PriorityCapacity is the maximum number of lines in the priority lane. Defaults to 1024.
+	PriorityCapacity int
+	// Thresholds are the utilizations of the normal lane, between 0 and 1, at which OnPressure is called, e.g.
+	// []float64{0.5, 0.9}.
+	Thresholds []float64
+	// OnPressure, if set, is called whenever the utilization of the queue rises above or falls back below one of the
+	// Thresholds, so the application can shed its own load, e.g. raise its minimum level, before the logger starts
+	// dropping lines. It is called by the goroutine that logged or wrote the line that crossed the threshold; it must
+	// not block, or log through the logger.
+	OnPressure func(Pressure)
+}
+
+var defaultAsyncQueueSettings = AsyncQueueSettings{
+	Capacity:         4096,
+	PriorityCapacity: 1024,
+}
+
+func (s *AsyncQueueSettings) mergeDefault() {
+	if s.Capacity <= 0 {
+		s.Capacity = defaultAsyncQueueSettings.Capacity
+	}
+	if s.PriorityCapacity <= 0 {
+		s.PriorityCapacity = defaultAsyncQueueSettings.PriorityCapacity
+	}
+}
+
+// Pressure describes the load on the normal lane of the async queue of a logger, i.e. the lane that drops lines first.
+type Pressure struct {
+	// InFlight is the number of lines in the lane.
+	InFlight int
+	// Capacity is the capacity of the lane, zero if it's unbounded.
+	Capacity int
+	// Utilization is InFlight divided by Capacity, zero if the lane is unbounded.
+	Utilization float64
+}
+
+// AsyncLaneStats are the counters of a lane of the async queue of a logger.
+type AsyncLaneStats struct {
+	// InFlight is the number of lines in the lane.
+	InFlight int `json:"inFlight"`
+	// Capacity is the capacity of the lane, zero if it's unbounded.
+	Capacity int `json:"capacity"`
+	// Queued is the number of lines that went through the lane.
+	Queued uint64 `json:"queued"`
+	// Dropped is the number of lines dropped because the lane was full. Lines dropped after a timeout are only counted
+	// in LoggerStats.Dropped.
+	Dropped uint64 `json:"dropped"`
+}
+
+// AsyncQueueStats are the counters of the lanes of the async queue of a logger.
+type AsyncQueueStats struct {
+	Normal   AsyncLaneStats `json:"normal"`
+	Priority AsyncLaneStats `json:"priority"`
+}
+
+// WithAsyncQueue bounds the async queue of the logger, and reports its pressure. By default, the queue is unbounded:
+// async lines are only dropped when they time out.
+func WithAsyncQueue(settings *AsyncQueueSettings) LoggerOption {
+	return func(l *ultraLogger) error {
+		s := AsyncQueueSettings{}
+		if settings != nil {
+			s = *settings
+		}
+		s.mergeDefault()
+
+		l.queue = asyncQueue{
+			normal:     asyncLane{capacity: int64(s.Capacity)},
+			priority:   asyncLane{capacity: int64(s.PriorityCapacity)},
+			thresholds: slices.Sorted(slices.Values(s.Thresholds)),
+			onPressure: s.OnPressure,
+		}
+		l.options.asyncQueue = true
+		return nil
+	}
+}
+
+// asyncQueue counts the async lines of a logger in flight, in their lanes. It is safe for concurrent use.
+type asyncQueue struct {
+	normal     asyncLane
+	priority   asyncLane
+	thresholds []float64
+	onPressure func(Pressure)
+
+	mu    sync.Mutex // Serializes the pressure reports, so they arrive in order.
+	level int        // The number of thresholds the utilization is at or above, as last reported.
+}
+
+// asyncLane is a lane of an asyncQueue.
+type asyncLane struct {
+	capacity int64 // Zero if the lane is unbounded.
+	inFlight atomic.Int64
+	queued   atomic.Uint64
+	dropped  atomic.Uint64
+}
+
+// lane returns the lane of lines at the level.
+func (q *asyncQueue) lane(level Level) *asyncLane {
+	if level >= Error {
+		return &q.priority
+	}
+	return &q.normal
+}
+
+// acquire adds a line at the level to its lane, and returns the lane. It returns nil, and doesn't add the line, if the
+// lane is full.
+func (q *asyncQueue) acquire(level Level) *asyncLane {
+	lane := q.lane(level)
+	inFlight := lane.inFlight.Add(1)
+	if lane.capacity > 0 && inFlight > lane.capacity {
+		lane.inFlight.Add(-1)
+		lane.dropped.Add(1)
+		return nil
+	}
+	lane.queued.Add(1)
+
+	if lane == &q.normal {
+		q.observe()
+	}
+	return lane
+}
+
+// release removes a line from the lane.
+func (q *asyncQueue) release(lane *asyncLane) {
+	lane.inFlight.Add(-1)
+
+	if lane == &q.normal {
+		q.observe()
+	}
+}
+
+// observe calls onPressure if the utilization of the normal lane crossed a threshold since it was last reported.
+func (q *asyncQueue) observe() {
+	if q.onPressure == nil {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pressure := q.pressure()
+	level := 0
+	for _, threshold := range q.thresholds {
+		if pressure.Utilization >= threshold {
+			level++
+		}
+	}
+	if level != q.level {
+		q.level = level
+		q.onPressure(pressure)
+	}
+}
+
+func (q *asyncQueue) pressure() Pressure {
+	inFlight := q.normal.inFlight.Load()
+	p := Pressure{InFlight: int(inFlight), Capacity: int(q.normal.capacity)}
+	if q.normal.capacity > 0 {
+		p.Utilization = float64(inFlight) / float64(q.normal.capacity)
+	}
+	return p
+}
+
+func (q *asyncQueue) stats() AsyncQueueStats {
+	return AsyncQueueStats{Normal: q.normal.stats(), Priority: q.priority.stats()}
+}
+
+func (l *asyncLane) stats() AsyncLaneStats {
+	return AsyncLaneStats{
+		InFlight: int(l.inFlight.Load()),
+		Capacity: int(l.capacity),
+		Queued:   l.queued.Load(),
+		Dropped:  l.dropped.Load(),
+	}
+}
+
+// Pressure returns the current load on the normal lane of the async queue of the logger.
+func (l *ultraLogger) Pressure() Pressure {
+	return l.queue.pressure()
+}
@@ -0,0 +1,94 @@
+package log
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestWithAsyncQueue(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+	w := &blockingWriter{entered: make(chan struct{}, 2), release: make(chan struct{})}
+
+	var mu sync.Mutex
+	var reported []float64
+	logger, _ := NewLoggerWithOptions(
+		WithDestination(w, formatter),
+		WithAsyncQueue(&AsyncQueueSettings{
+			Capacity:   2,
+			Thresholds: []float64{1, 0.5},
+			OnPressure: func(p Pressure) {
+				mu.Lock()
+				defer mu.Unlock()
+				reported = append(reported, p.Utilization)
+			},
+		}),
+	)
+	errs := logger.InternalErrors()
+
+	logger.Info("one")
+	logger.Info("two")
+	logger.Info("three")
+
+	if got, want := logger.Pressure(), (Pressure{InFlight: 2, Capacity: 2, Utilization: 1}); got != want {
+		t.Errorf("Pressure() = %+v, want %+v", got, want)
+	}
+	if got := logger.(Inspector).Inspect().Stats.Dropped; got != 1 {
+		t.Errorf("Dropped = %d, want 1", got)
+	}
+
+	close(w.release)
+	logger.Flush()
+
+	if got, want := logger.Pressure(), (Pressure{Capacity: 2}); got != want {
+		t.Errorf("Pressure() after Flush = %+v, want %+v", got, want)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []float64{0.5, 1, 0.5, 0}; !slices.Equal(reported, want) {
+		t.Errorf("reported utilizations = %v, want %v", reported, want)
+	}
+
+	select {
+	case err := <-errs:
+		t.Errorf("internal error = %v, want none", err)
+	default:
+	}
+}
+
+func TestPressure_unbounded(t *testing.T) {
+	logger, _ := NewLoggerWithOptions(WithAsync(false))
+	if got := logger.Pressure(); got != (Pressure{}) {
+		t.Errorf("Pressure() = %+v, want the zero Pressure", got)
+	}
+}
+
+func TestWithAsyncQueue_priorityLane(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+	w := &blockingWriter{entered: make(chan struct{}, 2), release: make(chan struct{})}
+
+	logger, _ := NewLoggerWithOptions(
+		WithDestination(w, formatter),
+		WithAsyncQueue(&AsyncQueueSettings{Capacity: 1, PriorityCapacity: 1}),
+	)
+
+	logger.Info("one")
+	logger.Info("two")
+	logger.Error("three")
+
+	want := AsyncQueueStats{
+		Normal:   AsyncLaneStats{InFlight: 1, Capacity: 1, Queued: 1, Dropped: 1},
+		Priority: AsyncLaneStats{InFlight: 1, Capacity: 1, Queued: 1},
+	}
+	if got := logger.(Inspector).Inspect().Queue; got != want {
+		t.Errorf("Inspect().Queue = %+v, want %+v", got, want)
+	}
+
+	close(w.release)
+	logger.Flush()
+
+	want.Normal.InFlight, want.Priority.InFlight = 0, 0
+	if got := logger.(Inspector).Inspect().Queue; got != want {
+		t.Errorf("Inspect().Queue after Flush = %+v, want %+v", got, want)
+	}
+}
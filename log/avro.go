@@ -0,0 +1,449 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// AvroSettings are the settings of an Avro formatter.
+type AvroSettings struct {
+	// Schema is the Avro schema of the entries, as JSON. It must be a record schema. Required, unless the latest schema
+	// of the Subject is fetched from the Registry.
+	Schema string
+	// Registry, if set, is the Confluent-style schema registry the schema is registered with, or fetched from. Entries
+	// are then written in the Confluent wire format: a zero byte, and the 4-byte schema ID, before the Avro data.
+	Registry *SchemaRegistry
+	// Subject is the registry subject of the schema, e.g. "logs-value". Required with a Registry.
+	Subject string
+}
+
+// NewAvroFormatter returns a formatter that writes the entries of the base formatter as Avro binary data, against a
+// record schema. The fields of the entries are matched to the fields of the schema by key; fields of the schema
+// without an entry field are written with their default, or as null if their type is a union with null.
+//
+// If settings.Registry is set, settings.Schema is registered under settings.Subject, or if there is no Schema, the
+// latest schema of the subject is fetched. The registry is only contacted here, not for every line.
+//
+// The base formatter must be a RecordFormatter; JSON formatters give the most faithful values, e.g. times as
+// time.Time for timestamp-millis fields. Like every line, Avro entries are written with a trailing newline;
+// destinations that frame their messages themselves, e.g. Kafka producers, should drop it.
+func NewAvroFormatter(ctx context.Context, base LogLineFormatter, settings *AvroSettings) (LogLineFormatter, error) {
+	recordFormatter, ok := base.(RecordFormatter)
+	if !ok {
+		return nil, ErrorAvroUnsupportedFormatter
+	}
+	if settings == nil {
+		settings = &AvroSettings{}
+	}
+
+	schemaJSON, header := settings.Schema, []byte(nil)
+	if settings.Registry != nil {
+		if settings.Subject == "" {
+			return nil, ErrorAvroSubjectNotSpecified
+		}
+
+		var id int
+		var err error
+		if schemaJSON != "" {
+			id, err = settings.Registry.Register(ctx, settings.Subject, schemaJSON)
+		} else {
+			id, schemaJSON, err = settings.Registry.Latest(ctx, settings.Subject)
+		}
+		if err != nil {
+			return nil, err
+		}
+		header = binary.BigEndian.AppendUint32([]byte{0}, uint32(id))
+	}
+
+	schema, err := ParseAvroSchema(schemaJSON)
+	if err != nil {
+		return nil, err
+	}
+	if schema.kind != "record" {
+		return nil, &ErrorAvroSchema{msg: fmt.Sprintf("entries need a record schema, got %s", schema.kind)}
+	}
+
+	return &avroFormatter{BaseFormatter: recordFormatter, schema: schema, header: header}, nil
+}
+
+// avroFormatter encodes the Records of the base formatter as Avro.
+type avroFormatter struct {
+	BaseFormatter RecordFormatter
+	schema        *AvroSchema
+	header        []byte // The Confluent wire format header, if the schema is registered.
+}
+
+// FormatLogLine formats the log line using the provided data and returns a FormatResult which contains the formatted
+// log line and any errors that may have occurred.
+func (f *avroFormatter) FormatLogLine(args LogLineArgs, data []any) FormatResult {
+	record, err := f.BaseFormatter.BuildRecord(args, data)
+	if err != nil {
+		return FormatResult{nil, err}
+	}
+
+	line, err := f.Encode(record)
+	return FormatResult{line, err}
+}
+
+// Encode renders the Record as Avro binary data, preceded by the wire format header if the schema is registered.
+func (f *avroFormatter) Encode(record *Record) ([]byte, error) {
+	values := make(map[string]any, len(record.Fields))
+	for _, field := range record.Fields {
+		values[field.Key] = field.Value
+	}
+
+	buf := bytes.NewBuffer(bytes.Clone(f.header))
+	if err := f.schema.encodeFields(buf, values); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unwrap returns the base formatter.
+func (f *avroFormatter) Unwrap() LogLineFormatter {
+	return f.BaseFormatter
+}
+
+// AvroSchema is a parsed Avro schema. The primitive types, records, enums, arrays, maps, fixed, unions, and the
+// timestamp-millis and timestamp-micros logical types are supported.
+type AvroSchema struct {
+	kind    string // The Avro type, e.g. "long" or "record".
+	name    string
+	logical string
+
+	fields  []avroField   // Of records.
+	symbols []string      // Of enums.
+	items   *AvroSchema   // Of arrays and maps.
+	types   []*AvroSchema // Of unions.
+	size    int           // Of fixed.
+}
+
+// avroField is a field of a record schema.
+type avroField struct {
+	name       string
+	schema     *AvroSchema
+	def        any
+	hasDefault bool
+}
+
+// ParseAvroSchema parses an Avro schema from its JSON representation.
+func ParseAvroSchema(schemaJSON string) (*AvroSchema, error) {
+	var raw any
+	if err := json.Unmarshal([]byte(schemaJSON), &raw); err != nil {
+		return nil, &ErrorAvroSchema{msg: err.Error()}
+	}
+	return parseAvroSchema(raw, map[string]*AvroSchema{})
+}
+
+func parseAvroSchema(raw any, named map[string]*AvroSchema) (*AvroSchema, error) {
+	switch raw := raw.(type) {
+	case string:
+		switch raw {
+		case "null", "boolean", "int", "long", "float", "double", "bytes", "string":
+			return &AvroSchema{kind: raw}, nil
+		}
+		if schema, ok := named[raw]; ok {
+			return schema, nil
+		}
+		return nil, &ErrorAvroSchema{msg: fmt.Sprintf("unknown type %q", raw)}
+	case []any:
+		union := &AvroSchema{kind: "union"}
+		for _, branch := range raw {
+			schema, err := parseAvroSchema(branch, named)
+			if err != nil {
+				return nil, err
+			}
+			union.types = append(union.types, schema)
+		}
+		return union, nil
+	case map[string]any:
+		return parseAvroComplexSchema(raw, named)
+	default:
+		return nil, &ErrorAvroSchema{msg: fmt.Sprintf("invalid schema %v", raw)}
+	}
+}
+
+func parseAvroComplexSchema(raw map[string]any, named map[string]*AvroSchema) (*AvroSchema, error) {
+	kind, _ := raw["type"].(string)
+	name, _ := raw["name"].(string)
+	schema := &AvroSchema{kind: kind, name: name}
+	schema.logical, _ = raw["logicalType"].(string)
+
+	switch kind {
+	case "record":
+		if name == "" {
+			return nil, &ErrorAvroSchema{msg: "record without a name"}
+		}
+		// Registered before the fields, which may refer to the record.
+		named[name] = schema
+
+		rawFields, _ := raw["fields"].([]any)
+		for _, rawField := range rawFields {
+			fieldMap, ok := rawField.(map[string]any)
+			if !ok {
+				return nil, &ErrorAvroSchema{msg: fmt.Sprintf("invalid field of record %s", name)}
+			}
+			fieldSchema, err := parseAvroSchema(fieldMap["type"], named)
+			if err != nil {
+				return nil, err
+			}
+			field := avroField{schema: fieldSchema}
+			field.name, _ = fieldMap["name"].(string)
+			field.def, field.hasDefault = fieldMap["default"]
+			schema.fields = append(schema.fields, field)
+		}
+	case "enum":
+		named[name] = schema
+		symbols, _ := raw["symbols"].([]any)
+		for _, symbol := range symbols {
+			s, _ := symbol.(string)
+			schema.symbols = append(schema.symbols, s)
+		}
+	case "fixed":
+		named[name] = schema
+		size, _ := raw["size"].(float64)
+		schema.size = int(size)
+	case "array", "map":
+		rawItems := raw["items"]
+		if kind == "map" {
+			rawItems = raw["values"]
+		}
+		items, err := parseAvroSchema(rawItems, named)
+		if err != nil {
+			return nil, err
+		}
+		schema.items = items
+	default:
+		// A primitive type with attributes, e.g. a logical type.
+		primitive, err := parseAvroSchema(kind, named)
+		if err != nil {
+			return nil, err
+		}
+		primitive = &AvroSchema{kind: primitive.kind, name: primitive.name, logical: schema.logical}
+		return primitive, nil
+	}
+
+	return schema, nil
+}
+
+// encodeFields encodes the values of the fields of a record schema.
+func (s *AvroSchema) encodeFields(buf *bytes.Buffer, values map[string]any) error {
+	for _, field := range s.fields {
+		value, ok := values[field.name]
+		if !ok && field.hasDefault {
+			value = field.def
+		}
+		if err := field.schema.encode(buf, value); err != nil {
+			return &ErrorAvroEncode{field: field.name, err: err}
+		}
+	}
+	return nil
+}
+
+// encode appends the Avro binary encoding of the value to buf.
+func (s *AvroSchema) encode(buf *bytes.Buffer, value any) error {
+	if s.kind == "union" {
+		return s.encodeUnion(buf, value)
+	}
+	if value == nil && s.kind != "null" {
+		return fmt.Errorf("no value for %s", s.kind)
+	}
+
+	switch s.kind {
+	case "null":
+		if value != nil {
+			return fmt.Errorf("%T is not null", value)
+		}
+	case "boolean":
+		b, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("%T is not a boolean", value)
+		}
+		if b {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+	case "int", "long":
+		n, err := s.avroLong(value)
+		if err != nil {
+			return err
+		}
+		if s.kind == "int" && (n < math.MinInt32 || n > math.MaxInt32) {
+			return fmt.Errorf("%d overflows int", n)
+		}
+		writeAvroLong(buf, n)
+	case "float", "double":
+		x, ok := avroDouble(value)
+		if !ok {
+			return fmt.Errorf("%T is not a number", value)
+		}
+		if s.kind == "float" {
+			buf.Write(binary.LittleEndian.AppendUint32(nil, math.Float32bits(float32(x))))
+		} else {
+			buf.Write(binary.LittleEndian.AppendUint64(nil, math.Float64bits(x)))
+		}
+	case "bytes", "string":
+		b, ok := avroBytes(value)
+		if !ok {
+			return fmt.Errorf("%T is not a %s", value, s.kind)
+		}
+		writeAvroLong(buf, int64(len(b)))
+		buf.Write(b)
+	case "fixed":
+		b, ok := avroBytes(value)
+		if !ok || len(b) != s.size {
+			return fmt.Errorf("%v is not a fixed of size %d", value, s.size)
+		}
+		buf.Write(b)
+	case "enum":
+		symbol := fmt.Sprint(value)
+		for i, candidate := range s.symbols {
+			if candidate == symbol {
+				writeAvroLong(buf, int64(i))
+				return nil
+			}
+		}
+		return fmt.Errorf("%q is not a symbol of enum %s", symbol, s.name)
+	case "array":
+		return s.encodeArray(buf, value)
+	case "map":
+		return s.encodeMap(buf, value)
+	case "record":
+		return s.encodeRecord(buf, value)
+	}
+	return nil
+}
+
+// encodeUnion encodes the value with the first branch of the union that accepts it.
+func (s *AvroSchema) encodeUnion(buf *bytes.Buffer, value any) error {
+	var branch bytes.Buffer
+	for i, schema := range s.types {
+		branch.Reset()
+		if schema.encode(&branch, value) == nil {
+			writeAvroLong(buf, int64(i))
+			buf.Write(branch.Bytes())
+			return nil
+		}
+	}
+	return fmt.Errorf("%T matches no branch of the union", value)
+}
+
+func (s *AvroSchema) encodeArray(buf *bytes.Buffer, value any) error {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return fmt.Errorf("%T is not an array", value)
+	}
+
+	if v.Len() > 0 {
+		writeAvroLong(buf, int64(v.Len()))
+		for i := range v.Len() {
+			if err := s.items.encode(buf, v.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+	}
+	writeAvroLong(buf, 0)
+	return nil
+}
+
+func (s *AvroSchema) encodeMap(buf *bytes.Buffer, value any) error {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("%T is not a map with string keys", value)
+	}
+
+	if v.Len() > 0 {
+		writeAvroLong(buf, int64(v.Len()))
+		for iter := v.MapRange(); iter.Next(); {
+			key := iter.Key().String()
+			writeAvroLong(buf, int64(len(key)))
+			buf.WriteString(key)
+			if err := s.items.encode(buf, iter.Value().Interface()); err != nil {
+				return err
+			}
+		}
+	}
+	writeAvroLong(buf, 0)
+	return nil
+}
+
+func (s *AvroSchema) encodeRecord(buf *bytes.Buffer, value any) error {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("%T is not a record", value)
+	}
+
+	values := make(map[string]any, v.Len())
+	for iter := v.MapRange(); iter.Next(); {
+		values[iter.Key().String()] = iter.Value().Interface()
+	}
+	return s.encodeFields(buf, values)
+}
+
+// avroLong converts the value to a long, honoring the timestamp logical types for times.
+func (s *AvroSchema) avroLong(value any) (int64, error) {
+	if t, ok := value.(time.Time); ok {
+		switch s.logical {
+		case "timestamp-millis":
+			return t.UnixMilli(), nil
+		case "timestamp-micros":
+			return t.UnixMicro(), nil
+		}
+		return 0, fmt.Errorf("time.Time needs a timestamp logical type, got %q", s.logical)
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("%d overflows long", v.Uint())
+		}
+		return int64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		if f := v.Float(); f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+			return int64(f), nil
+		}
+	}
+	return 0, fmt.Errorf("%T is not an integer", value)
+}
+
+func avroDouble(value any) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	}
+	return 0, false
+}
+
+func avroBytes(value any) ([]byte, bool) {
+	switch value := value.(type) {
+	case string:
+		return []byte(value), true
+	case []byte:
+		return value, true
+	case time.Time:
+		return []byte(value.Format(time.RFC3339Nano)), true
+	case fmt.Stringer:
+		return []byte(value.String()), true
+	}
+	return nil, false
+}
+
+// writeAvroLong writes n as a zig-zag varint.
+func writeAvroLong(buf *bytes.Buffer, n int64) {
+	buf.Write(binary.AppendVarint(nil, n))
+}
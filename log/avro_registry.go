@@ -0,0 +1,89 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// schemaRegistryContentType is the content type of the Confluent schema registry API.
+const schemaRegistryContentType = "application/vnd.schemaregistry.v1+json"
+
+// SchemaRegistry is a client of a Confluent-style schema registry, used by NewAvroFormatter to register and fetch
+// schemas.
+type SchemaRegistry struct {
+	// URL is the base URL of the registry, e.g. "http://localhost:8081".
+	URL string
+	// Client is the HTTP client of the requests. Defaults to http.DefaultClient.
+	Client *http.Client
+	// Username and Password, if set, authenticate the requests with basic auth.
+	Username string
+	Password string
+}
+
+// Register registers the schema under the subject, and returns its ID. Registering a schema that is already registered
+// returns its existing ID.
+func (r *SchemaRegistry) Register(ctx context.Context, subject, schema string) (int, error) {
+	body, err := json.Marshal(map[string]string{"schema": schema})
+	if err != nil {
+		return 0, err
+	}
+
+	var res struct {
+		ID int `json:"id"`
+	}
+	if err := r.do(ctx, http.MethodPost, "/subjects/"+url.PathEscape(subject)+"/versions", body, &res); err != nil {
+		return 0, err
+	}
+	return res.ID, nil
+}
+
+// Latest returns the ID and the schema of the latest version of the subject.
+func (r *SchemaRegistry) Latest(ctx context.Context, subject string) (int, string, error) {
+	var res struct {
+		ID     int    `json:"id"`
+		Schema string `json:"schema"`
+	}
+	if err := r.do(ctx, http.MethodGet, "/subjects/"+url.PathEscape(subject)+"/versions/latest", nil, &res); err != nil {
+		return 0, "", err
+	}
+	return res.ID, res.Schema, nil
+}
+
+func (r *SchemaRegistry) do(ctx context.Context, method, path string, body []byte, res any) error {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(r.URL, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", schemaRegistryContentType)
+	if body != nil {
+		req.Header.Set("Content-Type", schemaRegistryContentType)
+	}
+	if r.Username != "" || r.Password != "" {
+		req.SetBasicAuth(r.Username, r.Password)
+	}
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &ErrorSchemaRegistry{status: resp.StatusCode, msg: strings.TrimSpace(string(msg))}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(res); err != nil {
+		return fmt.Errorf("decoding schema registry response: %w", err)
+	}
+	return nil
+}
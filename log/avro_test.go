@@ -0,0 +1,152 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testAvroSchema = `{
+	"type": "record",
+	"name": "Entry",
+	"fields": [
+		{"name": "level", "type": {"type": "enum", "name": "Level", "symbols": ["DEBUG", "INFO", "WARN", "ERROR"]}},
+		{"name": "message", "type": "string"},
+		{"name": "count", "type": ["null", "long"], "default": null},
+		{"name": "time", "type": {"type": "long", "logicalType": "timestamp-millis"}}
+	]
+}`
+
+func newTestAvroBase(t *testing.T, now time.Time) LogLineFormatter {
+	t.Helper()
+
+	countField, _ := NewIntField("count")
+	base, err := NewFormatter(OutputFormatJSON, []Field{
+		NewDefaultLevelField(),
+		NewMessageField(),
+		countField,
+		NewCurrentTimeField(&CurrentTimeFieldSettings{Name: "time", fakeNow: &now}),
+	})
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	return base
+}
+
+func TestNewAvroFormatter(t *testing.T) {
+	now := time.UnixMilli(1_700_000_000_123)
+	formatter, err := NewAvroFormatter(context.Background(), newTestAvroBase(t, now), &AvroSettings{Schema: testAvroSchema})
+	if err != nil {
+		t.Fatalf("NewAvroFormatter() error = %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	logger, _ := NewLoggerWithOptions(WithAsync(false), WithDestination(buf, formatter))
+	logger.Info("hi", 42)
+
+	want := []byte{2, 4, 'h', 'i', 2, 84}
+	want = binary.AppendVarint(want, now.UnixMilli())
+	want = append(want, '\n')
+	if got := buf.Bytes(); !bytes.Equal(got, want) {
+		t.Errorf("line = %v, want %v", got, want)
+	}
+
+	buf.Reset()
+	logger.Info("hi")
+
+	want = []byte{2, 4, 'h', 'i', 0}
+	want = binary.AppendVarint(want, now.UnixMilli())
+	want = append(want, '\n')
+	if got := buf.Bytes(); !bytes.Equal(got, want) {
+		t.Errorf("line without count = %v, want %v", got, want)
+	}
+}
+
+func TestNewAvroFormatter_registry(t *testing.T) {
+	var registered string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /subjects/logs-value/versions":
+			var body struct{ Schema string }
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			registered = body.Schema
+			_, _ = w.Write([]byte(`{"id": 7}`))
+		case "GET /subjects/logs-value/versions/latest":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": 8, "schema": testAvroSchema})
+		default:
+			http.Error(w, `{"error_code": 40401, "message": "Subject not found"}`, http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	now := time.UnixMilli(1_700_000_000_123)
+	registry := &SchemaRegistry{URL: server.URL}
+
+	for _, tt := range []struct {
+		name     string
+		settings *AvroSettings
+		id       byte
+	}{
+		{"register", &AvroSettings{Schema: testAvroSchema, Registry: registry, Subject: "logs-value"}, 7},
+		{"latest", &AvroSettings{Registry: registry, Subject: "logs-value"}, 8},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter, err := NewAvroFormatter(context.Background(), newTestAvroBase(t, now), tt.settings)
+			if err != nil {
+				t.Fatalf("NewAvroFormatter() error = %v", err)
+			}
+
+			res := formatter.FormatLogLine(LogLineArgs{Level: Warn}, []any{"hi"})
+			if res.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", res.err)
+			}
+			want := binary.AppendVarint([]byte{0, 0, 0, 0, tt.id, 4, 4, 'h', 'i', 0}, now.UnixMilli())
+			if !bytes.Equal(res.bytes, want) {
+				t.Errorf("FormatLogLine() = %v, want %v", res.bytes, want)
+			}
+		})
+	}
+	if registered != testAvroSchema {
+		t.Errorf("registered schema = %q, want %q", registered, testAvroSchema)
+	}
+
+	_, err := NewAvroFormatter(context.Background(), newTestAvroBase(t, now), &AvroSettings{
+		Registry: registry,
+		Subject:  "unknown",
+	})
+	var registryErr *ErrorSchemaRegistry
+	if !errors.As(err, &registryErr) || registryErr.status != http.StatusNotFound {
+		t.Errorf("NewAvroFormatter() error = %v, want a 404 ErrorSchemaRegistry", err)
+	}
+}
+
+func TestAvroFormatter_mismatch(t *testing.T) {
+	formatter, _ := NewAvroFormatter(context.Background(), newTestAvroBase(t, time.Now()), &AvroSettings{
+		Schema: `{"type": "record", "name": "Entry", "fields": [{"name": "user", "type": "string"}]}`,
+	})
+
+	res := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{"hi"})
+	var encodeErr *ErrorAvroEncode
+	if !errors.As(res.err, &encodeErr) || encodeErr.field != "user" {
+		t.Errorf("FormatLogLine() error = %v, want an ErrorAvroEncode for user", res.err)
+	}
+}
+
+func TestParseAvroSchema_errors(t *testing.T) {
+	for _, schema := range []string{
+		`{`,
+		`"uuid"`,
+		`{"type": "record", "fields": []}`,
+		`{"type": "array", "items": "unknown"}`,
+	} {
+		if _, err := ParseAvroSchema(schema); err == nil {
+			t.Errorf("ParseAvroSchema(%s) error = nil, want an error", schema)
+		}
+	}
+}
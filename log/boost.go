@@ -0,0 +1,90 @@
+package log
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// noBoost is the boosted level of a logger without active boosts. It is higher than any level, so it never lowers the
+// effective minimum level.
+const noBoost = math.MaxInt64
+
+// levelBoosts tracks the temporary verbosity boosts of a logger. The lowest boosted level is kept in an atomic, so
+// checking it doesn't slow down the logging hot path.
+type levelBoosts struct {
+	mu     sync.Mutex
+	active map[uint64]Level
+	nextID uint64
+
+	lowest atomic.Int64
+}
+
+func newLevelBoosts() *levelBoosts {
+	b := &levelBoosts{active: map[uint64]Level{}}
+	b.lowest.Store(noBoost)
+	return b
+}
+
+func (b *levelBoosts) add(level Level) uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	b.active[b.nextID] = level
+	b.updateLocked()
+
+	return b.nextID
+}
+
+func (b *levelBoosts) remove(id uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.active, id)
+	b.updateLocked()
+}
+
+func (b *levelBoosts) updateLocked() {
+	lowest := int64(noBoost)
+	for _, level := range b.active {
+		lowest = min(lowest, int64(level))
+	}
+	b.lowest.Store(lowest)
+}
+
+// boosted returns the lowest active boosted level, and false if there are no active boosts.
+func (b *levelBoosts) boosted() (Level, bool) {
+	lowest := b.lowest.Load()
+	return Level(lowest), lowest != noBoost
+}
+
+// enabled reports whether lines of the given level are logged, taking active boosts into account.
+func (l *ultraLogger) enabled(level Level) bool {
+	return level >= l.minLevel || int64(level) >= l.boosts.lowest.Load()
+}
+
+// BoostLevel temporarily lowers the minimum level of the logger to level, e.g. to capture Debug lines while debugging
+// a live incident without restarting the service. The boost ends after the duration, or when the returned cancel func
+// is called, whichever comes first. A duration <= 0 boosts until cancel is called.
+//
+// Boosts only ever lower the minimum level; while several boosts are active, the lowest boosted level applies.
+func (l *ultraLogger) BoostLevel(level Level, duration time.Duration) (cancel func()) {
+	id := l.boosts.add(level)
+
+	var once sync.Once
+	end := func() {
+		once.Do(func() { l.boosts.remove(id) })
+	}
+
+	if duration <= 0 {
+		return end
+	}
+
+	timer := time.AfterFunc(duration, end)
+	return func() {
+		timer.Stop()
+		end()
+	}
+}
@@ -0,0 +1,94 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func newBoostTestLogger(t *testing.T) (*ultraLogger, *bytes.Buffer) {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+	logger, err := NewLoggerWithOptions(WithAsync(false), WithMinLevel(Warn), WithDestination(buf, formatter))
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	return logger.(*ultraLogger), buf
+}
+
+func TestLogger_BoostLevel_cancel(t *testing.T) {
+	logger, buf := newBoostTestLogger(t)
+
+	logger.Debug("before")
+	cancel := logger.BoostLevel(Debug, 0)
+	logger.Debug("boosted")
+	cancel()
+	cancel()
+	logger.Debug("after")
+
+	if got := buf.String(); got != "boosted\n" {
+		t.Errorf("output = %q, want only the boosted line", got)
+	}
+	if _, ok := logger.boosts.boosted(); ok {
+		t.Errorf("boost still active after cancel")
+	}
+}
+
+func TestLogger_BoostLevel_expires(t *testing.T) {
+	logger, buf := newBoostTestLogger(t)
+
+	logger.BoostLevel(Info, 10*time.Millisecond)
+	logger.Info("boosted")
+
+	deadline := time.Now().Add(time.Second)
+	for logger.enabled(Info) {
+		if time.Now().After(deadline) {
+			t.Fatalf("boost did not expire")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	logger.Info("expired")
+
+	if got := buf.String(); got != "boosted\n" {
+		t.Errorf("output = %q, want only the boosted line", got)
+	}
+}
+
+func TestLogger_BoostLevel_overlapping(t *testing.T) {
+	logger, _ := newBoostTestLogger(t)
+
+	cancelInfo := logger.BoostLevel(Info, 0)
+	cancelDebug := logger.BoostLevel(Debug, 0)
+
+	if level, _ := logger.boosts.boosted(); level != Debug {
+		t.Errorf("boosted level = %v, want %v", level, Debug)
+	}
+
+	cancelDebug()
+	if level, _ := logger.boosts.boosted(); level != Info {
+		t.Errorf("boosted level = %v, want %v", level, Info)
+	}
+	if logger.enabled(Debug) {
+		t.Errorf("Debug enabled after its boost was cancelled")
+	}
+
+	cancelInfo()
+	if snapshot := logger.Inspect(); snapshot.BoostedLevel != "" {
+		t.Errorf("Inspect().BoostedLevel = %q, want none", snapshot.BoostedLevel)
+	}
+}
+
+func TestLogger_BoostLevel_neverRaises(t *testing.T) {
+	logger, buf := newBoostTestLogger(t)
+
+	cancel := logger.BoostLevel(Error, 0)
+	defer cancel()
+	logger.Warn("warn")
+
+	if got := buf.String(); got != "warn\n" {
+		t.Errorf("output = %q, want the boost to leave the minimum level alone", got)
+	}
+}
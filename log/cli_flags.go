@@ -0,0 +1,109 @@
+package log
+
+import (
+	"flag"
+	"strconv"
+)
+
+// LevelFromVerbosity maps the number of verbosity flags of a CLI (-v, -vv) to a minimum level: no flags log Info and
+// above, like a logger without the flags, and -v or more add Debug. A negative verbosity, e.g. from a quiet flag, logs
+// Error and above.
+func LevelFromVerbosity(count int) Level {
+	switch {
+	case count < 0:
+		return Error
+	case count == 0:
+		return Info
+	default:
+		return Debug
+	}
+}
+
+// WithQuiet sets the minimum level of the logger to Error, for CLIs run with a quiet flag.
+func WithQuiet() LoggerOption {
+	return WithMinLevel(Error)
+}
+
+// LogFlags are the logging flags of a CLI, registered with RegisterFlags.
+type LogFlags struct {
+	// Verbosity is the number of times -v was passed, with -vv counting twice.
+	Verbosity int
+	// Quiet is set by -q or -quiet.
+	Quiet bool
+	// Level is set by -log-level. If set, it takes precedence over Verbosity and Quiet.
+	Level string
+}
+
+// RegisterFlags registers the common logging flags of a CLI on fs, or on flag.CommandLine if fs is nil:
+//
+//   - -v, -vv: increase verbosity (see LevelFromVerbosity).
+//   - -q, -quiet: only log errors.
+//   - -log-level: the minimum level by name, e.g. "debug".
+//
+// After the flags are parsed, pass LogFlags.Option to the logger.
+func RegisterFlags(fs *flag.FlagSet) *LogFlags {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+
+	f := &LogFlags{}
+	fs.Var(&verbosityFlag{count: &f.Verbosity, step: 1}, "v", "log everything, including debug lines")
+	fs.Var(&verbosityFlag{count: &f.Verbosity, step: 2}, "vv", "same as -v")
+	fs.BoolVar(&f.Quiet, "q", false, "only log errors")
+	fs.BoolVar(&f.Quiet, "quiet", false, "only log errors")
+	fs.StringVar(&f.Level, "log-level", "", "minimum log level: debug, info, warn, error, or panic")
+
+	return f
+}
+
+// MinLevel returns the minimum level selected by the flags. It returns an error if -log-level isn't a valid level.
+func (f *LogFlags) MinLevel() (Level, error) {
+	if f.Level != "" {
+		return ParseLevel(f.Level)
+	}
+	if f.Quiet {
+		return Error, nil
+	}
+	return LevelFromVerbosity(f.Verbosity), nil
+}
+
+// Option returns a LoggerOption that sets the minimum level selected by the flags. The option fails if -log-level
+// isn't a valid level.
+func (f *LogFlags) Option() LoggerOption {
+	return func(l *ultraLogger) error {
+		level, err := f.MinLevel()
+		if err != nil {
+			return &ErrorLoggerInitialization{err: err}
+		}
+		l.minLevel = level
+		return nil
+	}
+}
+
+// verbosityFlag is a boolean flag that adds step to count every time it is passed.
+type verbosityFlag struct {
+	count *int
+	step  int
+}
+
+func (v *verbosityFlag) String() string {
+	if v.count == nil {
+		return "0"
+	}
+	return strconv.Itoa(*v.count)
+}
+
+func (v *verbosityFlag) Set(value string) error {
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	if enabled {
+		*v.count += v.step
+	}
+	return nil
+}
+
+func (v *verbosityFlag) IsBoolFlag() bool {
+	return true
+}
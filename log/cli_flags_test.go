@@ -0,0 +1,75 @@
+package log
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"testing"
+)
+
+func TestLevelFromVerbosity(t *testing.T) {
+	tests := []struct {
+		count int
+		want  Level
+	}{
+		{-1, Error},
+		{0, Info},
+		{1, Debug},
+		{2, Debug},
+		{5, Debug},
+	}
+	for _, tt := range tests {
+		if got := LevelFromVerbosity(tt.count); got != tt.want {
+			t.Errorf("LevelFromVerbosity(%d) = %v, want %v", tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestRegisterFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    Level
+		wantErr bool
+	}{
+		{"no flags", nil, Info, false},
+		{"-v", []string{"-v"}, Debug, false},
+		{"-v -v", []string{"-v", "-v"}, Debug, false},
+		{"-vv", []string{"-vv"}, Debug, false},
+		{"-q", []string{"-q"}, Error, false},
+		{"-quiet -v", []string{"-quiet", "-v"}, Error, false},
+		{"-log-level", []string{"-log-level", "debug", "-q"}, Debug, false},
+		{"invalid -log-level", []string{"-log-level", "loud"}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("cli", flag.ContinueOnError)
+			flags := RegisterFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			logger, err := NewLoggerWithOptions(WithDestination(io.Discard, nil), flags.Option())
+			if tt.wantErr {
+				var parseErr *ErrorLevelParsing
+				if !errors.As(err, &parseErr) {
+					t.Errorf("NewLoggerWithOptions() error = %v, want ErrorLevelParsing", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewLoggerWithOptions() error = %v", err)
+			}
+			if got := logger.(*ultraLogger).minLevel; got != tt.want {
+				t.Errorf("min level = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithQuiet(t *testing.T) {
+	logger, _ := NewLoggerWithOptions(WithDestination(io.Discard, nil), WithQuiet())
+	if got := logger.(*ultraLogger).minLevel; got != Error {
+		t.Errorf("min level = %v, want %v", got, Error)
+	}
+}
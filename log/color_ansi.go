@@ -166,6 +166,29 @@ func (ac ColorAnsi) Colorize(content []byte) []byte {
     return buf
 }
 
+// appendColorized appends the content, wrapped in the ANSI color, to dst. The content is produced by appendContent so
+// it can be appended in place, without an intermediate buffer.
+func (ac ColorAnsi) appendColorized(dst []byte, appendContent func([]byte) ([]byte, bool)) ([]byte, bool) {
+    dst = append(dst, ansiCSInit...)
+    for _, setting := range ac.Settings {
+        dst = append(dst, setting...)
+        dst = append(dst, ansiCSSeparator)
+    }
+    if len(ac.Background) > 0 {
+        dst = append(dst, ac.Background...)
+        dst = append(dst, ansiCSSeparator)
+    }
+    dst = append(dst, ac.Code...)
+    dst = append(dst, ansiCSEnd)
+
+    dst, ok := appendContent(dst)
+    if !ok {
+        return nil, false
+    }
+
+    return append(dst, ansiReset...), true
+}
+
 func (ac ColorAnsi) totalBufferLength(content []byte) int {
     settingsLength := 0
     for _, setting := range ac.Settings {
@@ -0,0 +1,68 @@
+package log
+
+import (
+	"net/url"
+	"strings"
+)
+
+var ansiOSC8Open = []byte("\033]8;;")
+var ansiStringTerminator = []byte("\033\\")
+
+// AnsiHyperlink wraps content in an OSC 8 escape sequence, turning it into a clickable link to target in terminals that
+// support it. Terminals without OSC 8 support print the content as-is.
+//
+// See https://gist.github.com/egmontkob/eb114294efbcd5adb1944c9f3cb5feda for more info on OSC 8 hyperlinks.
+func AnsiHyperlink(target string, content []byte) []byte {
+	if len(content) == 0 || target == "" {
+		return content
+	}
+
+	buf := make([]byte, 0, 2*len(ansiOSC8Open)+2*len(ansiStringTerminator)+len(target)+len(content))
+
+	buf = append(buf, ansiOSC8Open...)
+	buf = append(buf, target...)
+	buf = append(buf, ansiStringTerminator...)
+	buf = append(buf, content...)
+	buf = append(buf, ansiOSC8Open...)
+	buf = append(buf, ansiStringTerminator...)
+
+	return buf
+}
+
+// expandLinkTemplate replaces every {value} placeholder in template with the escaped value. Placeholders in the path
+// are path-escaped, and placeholders in the query or fragment are query-escaped, so that e.g. spaces become %20 and +
+// respectively. Escaping also guarantees that control characters in the value can't terminate the OSC 8 sequence early.
+func expandLinkTemplate(template, value string) string {
+	const placeholder = "{value}"
+
+	var b strings.Builder
+	inPath := true
+	for {
+		before, after, found := strings.Cut(template, placeholder)
+		b.WriteString(before)
+		if strings.ContainsAny(before, "?#") {
+			inPath = false
+		}
+		if !found {
+			return b.String()
+		}
+
+		if inPath {
+			b.WriteString(url.PathEscape(value))
+		} else {
+			b.WriteString(url.QueryEscape(value))
+		}
+		template = after
+	}
+}
+
+// WithHyperlinks enables OSC 8 hyperlinks for text formatters. Fields with a [FieldSettings.LinkTemplate] are rendered
+// as clickable links; all other fields are unaffected. Non-text formatters ignore this option.
+func WithHyperlinks() FormatterOption {
+	return func(f LogLineFormatter) LogLineFormatter {
+		if tf, ok := unwrapFormatter[*textFormatter](f); ok {
+			tf.Hyperlinks = true
+		}
+		return f
+	}
+}
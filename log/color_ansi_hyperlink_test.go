@@ -0,0 +1,129 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAnsiHyperlink(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		content []byte
+		want    []byte
+	}{
+		{
+			name:    "Link",
+			target:  "https://example.com",
+			content: []byte("example"),
+			want:    []byte("\033]8;;https://example.com\033\\example\033]8;;\033\\"),
+		},
+		{
+			name:    "Empty content",
+			target:  "https://example.com",
+			content: []byte{},
+			want:    []byte{},
+		},
+		{
+			name:    "Empty target",
+			target:  "",
+			content: []byte("example"),
+			want:    []byte("example"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AnsiHyperlink(tt.target, tt.content); !bytes.Equal(got, tt.want) {
+				t.Errorf("AnsiHyperlink() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithHyperlinks(t *testing.T) {
+	traceField, _ := NewObjectField[string](
+		"trace",
+		func(args LogLineArgs, data string) (any, error) {
+			return data, nil
+		},
+		WithLinkTemplate("https://jaeger.local/trace/{value}"),
+	)
+
+	tests := []struct {
+		name       string
+		opts       []FormatterOption
+		outputFmt  OutputFormat
+		data       []any
+		wantOutput []byte
+	}{
+		{
+			name:       "Disabled by default",
+			outputFmt:  OutputFormatText,
+			data:       []any{"abc123"},
+			wantOutput: []byte("trace=abc123"),
+		},
+		{
+			name:       "Text",
+			opts:       []FormatterOption{WithHyperlinks()},
+			outputFmt:  OutputFormatText,
+			data:       []any{"abc123"},
+			wantOutput: []byte("trace=" + string(AnsiHyperlink("https://jaeger.local/trace/abc123", []byte("abc123")))),
+		},
+		{
+			name:       "Value is escaped",
+			opts:       []FormatterOption{WithHyperlinks()},
+			outputFmt:  OutputFormatText,
+			data:       []any{"a b\033"},
+			wantOutput: []byte("trace=" + string(AnsiHyperlink("https://jaeger.local/trace/a%20b%1B", []byte("a b\033")))),
+		},
+		{
+			name:       "Through colorization",
+			opts:       []FormatterOption{WithColorization(nil), WithHyperlinks(), WithColorPolicy(ColorPolicyNever)},
+			outputFmt:  OutputFormatText,
+			data:       []any{"abc123"},
+			wantOutput: []byte("trace=" + string(AnsiHyperlink("https://jaeger.local/trace/abc123", []byte("abc123")))),
+		},
+		{
+			name:       "JSON ignores hyperlinks",
+			opts:       []FormatterOption{WithHyperlinks()},
+			outputFmt:  OutputFormatJSON,
+			data:       []any{"abc123"},
+			wantOutput: []byte(`{"trace":"abc123"}`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFormatter(tt.outputFmt, []Field{traceField}, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+
+			got := f.FormatLogLine(LogLineArgs{Level: Info}, tt.data)
+			if !bytes.Equal(got.bytes, tt.wantOutput) {
+				t.Errorf("FormatLogLine() = %q, want %q", got.bytes, tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestExpandLinkTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		value    string
+		want     string
+	}{
+		{"Path", "https://host/trace/{value}", "a b/c", "https://host/trace/a%20b%2Fc"},
+		{"Query", "https://host/search?q={value}", "a b&c", "https://host/search?q=a+b%26c"},
+		{"Fragment", "https://host/page#{value}", "a b", "https://host/page#a+b"},
+		{"Path and query", "https://host/{value}?id={value}", "a b", "https://host/a%20b?id=a+b"},
+		{"No placeholder", "https://host/", "a b", "https://host/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandLinkTemplate(tt.template, tt.value); got != tt.want {
+				t.Errorf("expandLinkTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package log
+
+import (
+	"os"
+	"sync"
+)
+
+// ColorPolicy determines whether a ColorizedFormatter actually emits color codes.
+//
+// The zero value, ColorPolicyAuto, honors the de-facto terminal color conventions:
+//   - NO_COLOR (https://no-color.org) set to any non-empty value disables colors.
+//   - CLICOLOR=0 disables colors.
+//   - CLICOLOR_FORCE set to any non-empty value other than "0" forces colors, even if NO_COLOR or CLICOLOR=0 is set.
+//
+// The environment is read once per process, the first time an auto-policy formatter needs it.
+type ColorPolicy int
+
+const (
+	// ColorPolicyAuto colorizes unless the environment asks otherwise. This is the default.
+	ColorPolicyAuto ColorPolicy = iota
+	// ColorPolicyAlways colorizes regardless of the environment.
+	ColorPolicyAlways
+	// ColorPolicyNever never colorizes. The base formatter's output is passed through unchanged.
+	ColorPolicyNever
+)
+
+func (p ColorPolicy) String() string {
+	switch p {
+	case ColorPolicyAuto:
+		return "auto"
+	case ColorPolicyAlways:
+		return "always"
+	case ColorPolicyNever:
+		return "never"
+	default:
+		return "unknown"
+	}
+}
+
+// colorsEnabled resolves the policy into a yes/no answer.
+func (p ColorPolicy) colorsEnabled() bool {
+	switch p {
+	case ColorPolicyAlways:
+		return true
+	case ColorPolicyNever:
+		return false
+	default:
+		return colorEnvironmentEnabled()
+	}
+}
+
+// colorEnvironmentEnabled is a variable so tests can swap out the cached environment lookup.
+var colorEnvironmentEnabled = sync.OnceValue(func() bool {
+	return colorsEnabledByEnv(os.Getenv)
+})
+
+func colorsEnabledByEnv(getenv func(string) string) bool {
+	if force := getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return true
+	}
+
+	if getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	if getenv("CLICOLOR") == "0" {
+		return false
+	}
+
+	return true
+}
+
+// WithColorPolicy overrides the ColorPolicy of a colorized formatter. Apply it after WithColorization or
+// WithDefaultColorization; for formatters without colorization it is a no-op.
+func WithColorPolicy(policy ColorPolicy) FormatterOption {
+	return func(f LogLineFormatter) LogLineFormatter {
+		if cf, ok := unwrapFormatter[*ColorizedFormatter](f); ok {
+			cf.Policy = policy
+		}
+		return f
+	}
+}
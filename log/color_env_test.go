@@ -0,0 +1,73 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test_colorsEnabledByEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{"Empty environment", map[string]string{}, true},
+		{"NO_COLOR set", map[string]string{"NO_COLOR": "1"}, false},
+		{"NO_COLOR empty", map[string]string{"NO_COLOR": ""}, true},
+		{"CLICOLOR=0", map[string]string{"CLICOLOR": "0"}, false},
+		{"CLICOLOR=1", map[string]string{"CLICOLOR": "1"}, true},
+		{"CLICOLOR_FORCE overrides NO_COLOR", map[string]string{"NO_COLOR": "1", "CLICOLOR_FORCE": "1"}, true},
+		{"CLICOLOR_FORCE overrides CLICOLOR=0", map[string]string{"CLICOLOR": "0", "CLICOLOR_FORCE": "yes"}, true},
+		{"CLICOLOR_FORCE=0 is ignored", map[string]string{"NO_COLOR": "1", "CLICOLOR_FORCE": "0"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			if got := colorsEnabledByEnv(getenv); got != tt.want {
+				t.Errorf("colorsEnabledByEnv() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorizedFormatter_Policy(t *testing.T) {
+	originalEnv := colorEnvironmentEnabled
+	defer func() { colorEnvironmentEnabled = originalEnv }()
+
+	tests := []struct {
+		name       string
+		envEnabled bool
+		policy     ColorPolicy
+		wantColor  bool
+	}{
+		{"Auto, env allows color", true, ColorPolicyAuto, true},
+		{"Auto, env disables color", false, ColorPolicyAuto, false},
+		{"Always overrides env", false, ColorPolicyAlways, true},
+		{"Never overrides env", true, ColorPolicyNever, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			colorEnvironmentEnabled = func() bool { return tt.envEnabled }
+
+			f, err := NewFormatter(
+				OutputFormatText,
+				[]Field{NewMessageField()},
+				WithDefaultColorization(),
+				WithColorPolicy(tt.policy),
+			)
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+
+			got := f.FormatLogLine(LogLineArgs{Level: Info}, []any{"test"})
+			want := []byte("test")
+			if tt.wantColor {
+				want = Colors.White.Colorize(want)
+			}
+
+			if !bytes.Equal(got.bytes, want) {
+				t.Errorf("FormatLogLine() = %q, want %q", got.bytes, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package log
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// cycleMarker replaces values that refer back to one of their ancestors.
+const cycleMarker = "(cycle)"
+
+// maxReflectDepth bounds how deep the reflection paths descend into nested values, so that pathologically deep data
+// can't overflow the stack.
+const maxReflectDepth = 1000
+
+// depthMarker replaces values nested deeper than the reflection paths descend.
+const depthMarker = "(max depth)"
+
+// refKey identifies the backing storage of a pointer, map, or slice, to detect values that contain themselves.
+type refKey struct {
+	ptr uintptr
+	len int
+	typ reflect.Type
+}
+
+// refPath is the set of references on the path from the root value to the value being visited.
+type refPath map[refKey]struct{}
+
+// enter records the reference of v, if it has one. It returns false if v is already on the path, i.e. v contains
+// itself; leave must be called with the returned key once v has been visited.
+func (p refPath) enter(v reflect.Value) (refKey, bool) {
+	var key refKey
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map:
+		if v.IsNil() {
+			return key, true
+		}
+		key = refKey{ptr: v.Pointer(), typ: v.Type()}
+	case reflect.Slice:
+		if v.IsNil() {
+			return key, true
+		}
+		key = refKey{ptr: v.Pointer(), len: v.Len(), typ: v.Type()}
+	default:
+		return key, true
+	}
+
+	if _, ok := p[key]; ok {
+		return key, false
+	}
+	p[key] = struct{}{}
+	return key, true
+}
+
+func (p refPath) leave(key refKey) {
+	delete(p, key)
+}
+
+// formatTextValue formats a field value for the text formatter with %v. Values that contain themselves would make fmt
+// recurse until the stack overflows, and values exceeding the NestingLimits must be cut short, so those are formatted by
+// appendTruncatedText instead.
+func formatTextValue(value any, limits NestingLimits) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case *orderedMap:
+		return value.text(limits)
+	case nil, bool, int, int64, uint64, float64, error, fmt.Stringer:
+		return fmt.Sprintf("%v", value)
+	}
+
+	v := reflect.ValueOf(value)
+	if !textNeedsTruncation(v, limits, refPath{}, 0, 0) {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return string(appendTruncatedText(nil, v, limits, refPath{}, 0, 0))
+}
+
+// textNeedsTruncation reports whether formatting v with %v would recurse into a value that contains itself, deeper than
+// maxReflectDepth, or beyond the limits. It follows fmt: pointers are only dereferenced at the top level, and values
+// with a String or Error method aren't descended into.
+//
+// depth counts the values descended into, nesting only the containers (arrays, slices, maps, and structs).
+func textNeedsTruncation(v reflect.Value, limits NestingLimits, path refPath, depth, nesting int) bool {
+	if !v.IsValid() {
+		return false
+	}
+	if depth > maxReflectDepth {
+		return true
+	}
+	if depth > 0 && hasTextMethod(v) {
+		return false
+	}
+	if n, ok := containerLen(v); ok && (limits.tooDeep(nesting) || limits.shown(n) < n) {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if depth > 0 {
+			return false
+		}
+		return textNeedsTruncation(v.Elem(), limits, path, depth+1, nesting)
+
+	case reflect.Interface:
+		return textNeedsTruncation(v.Elem(), limits, path, depth, nesting)
+
+	case reflect.Map, reflect.Slice:
+		key, ok := path.enter(v)
+		if !ok {
+			return true
+		}
+		defer path.leave(key)
+
+		if v.Kind() == reflect.Map {
+			iter := v.MapRange()
+			for iter.Next() {
+				if textNeedsTruncation(iter.Key(), limits, path, depth+1, nesting+1) ||
+					textNeedsTruncation(iter.Value(), limits, path, depth+1, nesting+1) {
+					return true
+				}
+			}
+			return false
+		}
+		fallthrough
+
+	case reflect.Array:
+		for i := range v.Len() {
+			if textNeedsTruncation(v.Index(i), limits, path, depth+1, nesting+1) {
+				return true
+			}
+		}
+		return false
+
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if textNeedsTruncation(v.Field(i), limits, path, depth+1, nesting+1) {
+				return true
+			}
+		}
+		return false
+
+	default:
+		return false
+	}
+}
+
+// appendTruncatedText appends v formatted like %v, with values that contain themselves replaced by cycleMarker, and
+// containers cut short according to the limits.
+func appendTruncatedText(b []byte, v reflect.Value, limits NestingLimits, path refPath, depth, nesting int) []byte {
+	if !v.IsValid() {
+		return append(b, "<nil>"...)
+	}
+	if depth > maxReflectDepth {
+		return append(b, depthMarker...)
+	}
+	if depth > 0 && hasTextMethod(v) {
+		return fmt.Append(b, v.Interface())
+	}
+
+	if _, ok := containerLen(v); ok && limits.tooDeep(nesting) {
+		return append(b, depthMarker...)
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if depth > 0 || v.IsNil() {
+			return fmt.Append(b, v)
+		}
+		return appendTruncatedText(append(b, '&'), v.Elem(), limits, path, depth+1, nesting)
+
+	case reflect.Interface:
+		return appendTruncatedText(b, v.Elem(), limits, path, depth, nesting)
+
+	case reflect.Map:
+		ref, ok := path.enter(v)
+		if !ok {
+			return append(b, cycleMarker...)
+		}
+		defer path.leave(ref)
+
+		type entry struct {
+			key   []byte
+			value reflect.Value
+		}
+		entries := make([]entry, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			entries = append(entries, entry{
+				key:   appendTruncatedText(nil, iter.Key(), limits, path, depth+1, nesting+1),
+				value: iter.Value(),
+			})
+		}
+		slices.SortFunc(entries, func(a, b entry) int {
+			return strings.Compare(string(a.key), string(b.key))
+		})
+
+		b = append(b, "map["...)
+		shown := limits.shown(len(entries))
+		for i, entry := range entries[:shown] {
+			if i > 0 {
+				b = append(b, ' ')
+			}
+			b = append(append(b, entry.key...), ':')
+			b = appendTruncatedText(b, entry.value, limits, path, depth+1, nesting+1)
+		}
+		b = appendMoreMarker(b, shown, len(entries))
+		return append(b, ']')
+
+	case reflect.Slice, reflect.Array:
+		ref, ok := path.enter(v)
+		if !ok {
+			return append(b, cycleMarker...)
+		}
+		defer path.leave(ref)
+
+		b = append(b, '[')
+		shown := limits.shown(v.Len())
+		for i := range shown {
+			if i > 0 {
+				b = append(b, ' ')
+			}
+			b = appendTruncatedText(b, v.Index(i), limits, path, depth+1, nesting+1)
+		}
+		b = appendMoreMarker(b, shown, v.Len())
+		return append(b, ']')
+
+	case reflect.Struct:
+		b = append(b, '{')
+		shown := limits.shown(v.NumField())
+		for i := range shown {
+			if i > 0 {
+				b = append(b, ' ')
+			}
+			b = appendTruncatedText(b, v.Field(i), limits, path, depth+1, nesting+1)
+		}
+		b = appendMoreMarker(b, shown, v.NumField())
+		return append(b, '}')
+
+	default:
+		// fmt prints the value held by a reflect.Value, even for unexported fields.
+		return fmt.Append(b, v)
+	}
+}
+
+// containerLen returns the number of elements of v, and whether v is a container that counts towards the
+// NestingLimits.
+func containerLen(v reflect.Value) (int, bool) {
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return v.Len(), true
+	case reflect.Struct:
+		return v.NumField(), true
+	default:
+		return 0, false
+	}
+}
+
+// appendMoreMarker appends the moreMarker of a container with total elements, of which shown were rendered.
+func appendMoreMarker(b []byte, shown, total int) []byte {
+	if shown == total {
+		return b
+	}
+	if shown > 0 {
+		b = append(b, ' ')
+	}
+	return append(b, moreMarker(total-shown)...)
+}
+
+var (
+	stringerType = reflect.TypeFor[fmt.Stringer]()
+	errorType    = reflect.TypeFor[error]()
+)
+
+// hasTextMethod reports whether fmt would format v with its String or Error method.
+func hasTextMethod(v reflect.Value) bool {
+	if !v.CanInterface() {
+		return false
+	}
+	t := v.Type()
+	return t.Implements(stringerType) || t.Implements(errorType)
+}
@@ -0,0 +1,106 @@
+package log
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type cyclicNode struct {
+	Name string
+	Next *cyclicNode
+}
+
+func cyclicTestData() (map[string]any, []any, *cyclicNode) {
+	m := map[string]any{"name": "root"}
+	m["self"] = m
+
+	s := []any{"first", nil}
+	s[1] = s
+
+	n := &cyclicNode{Name: "a"}
+	n.Next = &cyclicNode{Name: "b", Next: n}
+
+	return m, s, n
+}
+
+func TestFormatTextValue_cycles(t *testing.T) {
+	m, s, n := cyclicTestData()
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"Map", m, "map[name:root self:(cycle)]"},
+		{"Slice", s, "[first (cycle)]"},
+		{"Nested map", map[string]any{"outer": m}, "map[outer:map[name:root self:(cycle)]]"},
+		// fmt only dereferences the top-level pointer, so pointer cycles are already safe.
+		{"Pointer", n, "&{a 0x"},
+		{"No cycle", map[string]int{"b": 2, "a": 1}, "map[a:1 b:2]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatTextValue(tt.value, NestingLimits{}); !strings.HasPrefix(got, tt.want) {
+				t.Errorf("formatTextValue() = %q, want prefix %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatter_cycles(t *testing.T) {
+	m, s, n := cyclicTestData()
+
+	mapField, _ := NewObjectField[map[string]any]("map", func(args LogLineArgs, data map[string]any) (any, error) {
+		return data, nil
+	})
+	sliceField, _ := NewObjectField[[]any]("slice", func(args LogLineArgs, data []any) (any, error) {
+		return data, nil
+	})
+	nodeField, _ := NewObjectField[*cyclicNode]("node", func(args LogLineArgs, data *cyclicNode) (any, error) {
+		return data, nil
+	})
+	fields := []Field{NewMessageField(), mapField, sliceField, nodeField}
+	data := []any{"hello", m, s, n}
+
+	t.Run("text", func(t *testing.T) {
+		formatter, _ := NewFormatter(OutputFormatText, fields)
+
+		result := formatter.FormatLogLine(LogLineArgs{Level: Info}, data)
+		if result.err != nil {
+			t.Fatalf("FormatLogLine() error = %v", result.err)
+		}
+		if want := "hello map=map[name:root self:(cycle)] slice=[first (cycle)] node=&{a 0x"; !strings.HasPrefix(string(result.bytes), want) {
+			t.Errorf("FormatLogLine() = %q, want prefix %q", result.bytes, want)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		formatter, _ := NewFormatter(OutputFormatJSON, fields)
+
+		result := formatter.FormatLogLine(LogLineArgs{Level: Info}, data)
+		if result.err != nil {
+			t.Fatalf("FormatLogLine() error = %v", result.err)
+		}
+
+		want := `{"map":{"name":"root","self":"(cycle)"},"message":"hello",` +
+			`"node":{"Name":"a","Next":{"Name":"b","Next":"(cycle)"}},"slice":["first","(cycle)"]}`
+		if string(result.bytes) != want {
+			t.Errorf("FormatLogLine() = %s, want %s", result.bytes, want)
+		}
+		if !json.Valid(result.bytes) {
+			t.Errorf("FormatLogLine() = %s, want valid JSON", result.bytes)
+		}
+	})
+}
+
+func TestFormatTextValue_maxDepth(t *testing.T) {
+	var deep any = "leaf"
+	for range maxReflectDepth + 10 {
+		deep = []any{deep}
+	}
+
+	if got := formatTextValue(deep, NestingLimits{}); !strings.Contains(got, depthMarker) {
+		t.Errorf("formatTextValue() = %.40q..., want it to be truncated at the max depth", got)
+	}
+}
@@ -0,0 +1,113 @@
+package log
+
+import (
+	"encoding/json"
+	"expvar"
+	"html/template"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// DebugHandlerPath is the conventional path to mount the handler returned by NewDebugHandler on.
+const DebugHandlerPath = "/debug/ultralog"
+
+// publishExpvarMu serializes PublishExpvar, so that its check of the name and publication can't race each other.
+var publishExpvarMu sync.Mutex
+
+// PublishExpvar publishes the internal counters of the logger (lines, drops, errors) as an expvar variable with the
+// provided name. They are then served by the expvar handler, typically mounted at /debug/vars.
+//
+// Unlike expvar.Publish, PublishExpvar doesn't panic if the name is already registered; it returns an
+// ErrorExpvarNameTaken. Loggers that don't implement [Inspector] publish an empty object.
+func PublishExpvar(name string, logger Logger) error {
+	publishExpvarMu.Lock()
+	defer publishExpvarMu.Unlock()
+
+	if expvar.Get(name) != nil {
+		return &ErrorExpvarNameTaken{name: name}
+	}
+	expvar.Publish(name, expvar.Func(func() any {
+		inspector, ok := logger.(Inspector)
+		if !ok {
+			return LoggerStats{}
+		}
+		return inspector.Inspect().Stats
+	}))
+	return nil
+}
+
+// NewDebugHandler returns an http.Handler that shows the current configuration, destination health, internal
+// counters, and recent internal errors of the logger. The handler responds with JSON if the request asks for it,
+// either with ?format=json or an Accept header of application/json, and with an HTML page otherwise.
+//
+// The logger must implement [Inspector]; the handler responds with 501 Not Implemented if it doesn't.
+//
+//	mux.Handle(log.DebugHandlerPath, log.NewDebugHandler(logger))
+func NewDebugHandler(logger Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inspector, ok := logger.(Inspector)
+		if !ok {
+			http.Error(w, "logger does not support inspection", http.StatusNotImplemented)
+			return
+		}
+
+		snapshot := inspector.Inspect()
+
+		if wantsJSON(r) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(snapshot)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = debugPageTemplate.Execute(w, snapshot)
+	})
+}
+
+func wantsJSON(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "json" {
+		return true
+	}
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+var debugPageTemplate = template.Must(template.New("ultralog").Parse(`<!DOCTYPE html>
+<html>
+<head><title>ultra/log</title></head>
+<body>
+<h1>ultra/log</h1>
+<h2>Configuration</h2>
+<table>
+<tr><th align="left">Min level</th><td>{{.MinLevel}}</td></tr>
+{{if .BoostedLevel}}<tr><th align="left">Boosted level</th><td>{{.BoostedLevel}}</td></tr>
+{{end}}<tr><th align="left">Tag</th><td>{{.Tag}}</td></tr>
+<tr><th align="left">Silent</th><td>{{.Silent}}</td></tr>
+<tr><th align="left">Async</th><td>{{.Async}}</td></tr>
+<tr><th align="left">Fallback</th><td>{{.Fallback}}</td></tr>
+</table>
+<h2>Destinations</h2>
+<table>
+<tr><th align="left">Writer</th><th align="left">Formatter</th><th align="left">Healthy</th><th align="left">Delivery</th></tr>
+{{range .Destinations}}<tr><td>{{.Writer}}</td><td>{{.Formatter}}</td><td>{{.Healthy}}</td><td>{{.Delivery}}</td></tr>
+{{end}}</table>
+<h2>Stats</h2>
+<table>
+<tr><th align="left">Lines</th><td>{{.Stats.Lines}}</td></tr>
+<tr><th align="left">Dropped</th><td>{{.Stats.Dropped}}</td></tr>
+<tr><th align="left">Errors</th><td>{{.Stats.Errors}}</td></tr>
+</table>
+<h2>Async queue</h2>
+<table>
+<tr><th align="left">Lane</th><th align="left">In flight</th><th align="left">Capacity</th><th align="left">Queued</th><th align="left">Dropped</th></tr>
+{{with .Queue.Normal}}<tr><td>normal</td><td>{{.InFlight}}</td><td>{{.Capacity}}</td><td>{{.Queued}}</td><td>{{.Dropped}}</td></tr>{{end}}
+{{with .Queue.Priority}}<tr><td>priority</td><td>{{.InFlight}}</td><td>{{.Capacity}}</td><td>{{.Queued}}</td><td>{{.Dropped}}</td></tr>{{end}}
+</table>
+<h2>Recent errors</h2>
+<table>
+{{range .RecentErrors}}<tr><td>{{.Time.Format "2006-01-02 15:04:05.000"}}</td><td>{{.Error}}</td></tr>
+{{else}}<tr><td>None</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
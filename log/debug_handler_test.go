@@ -0,0 +1,126 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+type failingWriter struct{}
+
+func (w failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestNewDebugHandler(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewDefaultLevelField(), NewMessageField()})
+	logger, _ := NewLoggerWithOptions(
+		WithDestination(io.Discard, formatter),
+		WithDestination(failingWriter{}, formatter),
+		WithTag("api"),
+		WithAsync(false),
+	)
+
+	logger.Info("hello")
+
+	handler := NewDebugHandler(logger)
+
+	t.Run("JSON", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, DebugHandlerPath+"?format=json", nil))
+
+		var snapshot LoggerSnapshot
+		if err := json.Unmarshal(rec.Body.Bytes(), &snapshot); err != nil {
+			t.Fatalf("invalid JSON response: %v, body=%s", err, rec.Body.String())
+		}
+
+		if snapshot.Tag != "api" || snapshot.MinLevel != "INFO" {
+			t.Errorf("snapshot config = %+v", snapshot)
+		}
+		if len(snapshot.Destinations) != 2 {
+			t.Errorf("snapshot has %d destinations, want 2", len(snapshot.Destinations))
+		}
+
+		healthy := 0
+		for _, d := range snapshot.Destinations {
+			if d.Healthy {
+				healthy++
+			}
+		}
+		if healthy != 1 {
+			t.Errorf("snapshot has %d healthy destinations, want 1", healthy)
+		}
+
+		if snapshot.Stats.Errors == 0 || len(snapshot.RecentErrors) == 0 {
+			t.Errorf("snapshot has no recorded errors: %+v", snapshot)
+		}
+		if snapshot.Stats.Lines == 0 {
+			t.Errorf("snapshot has no written lines: %+v", snapshot.Stats)
+		}
+	})
+
+	t.Run("HTML", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, DebugHandlerPath, nil))
+
+		if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+			t.Errorf("Content-Type = %v, want text/html", ct)
+		}
+		if !strings.Contains(rec.Body.String(), "broken pipe") {
+			t.Errorf("HTML response does not contain the recent error")
+		}
+	})
+}
+
+// expvarTestRuns numbers the runs of the expvar tests, since expvar names can't be unregistered, and the tests may run
+// several times in a process, e.g. with -count.
+var expvarTestRuns atomic.Int64
+
+func TestPublishExpvar(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+	logger, _ := NewLoggerWithOptions(WithDestination(io.Discard, formatter), WithAsync(false))
+
+	name := fmt.Sprintf("ultralog_test_%d", expvarTestRuns.Add(1))
+	if err := PublishExpvar(name, logger); err != nil {
+		t.Fatalf("PublishExpvar() error = %v", err)
+	}
+	logger.Info("one")
+	logger.Info("two")
+
+	var stats LoggerStats
+	if err := json.Unmarshal([]byte(expvar.Get(name).String()), &stats); err != nil {
+		t.Fatalf("invalid expvar value: %v", err)
+	}
+	if stats.Lines != 2 {
+		t.Errorf("expvar lines = %d, want 2", stats.Lines)
+	}
+
+	if err := PublishExpvar(name, logger); !errors.As(err, new(*ErrorExpvarNameTaken)) {
+		t.Errorf("PublishExpvar() error = %v, want ErrorExpvarNameTaken", err)
+	}
+}
+
+func Test_loggerStats_recentErrors(t *testing.T) {
+	s := &loggerStats{}
+	for i := 0; i < recentErrorsCapacity+2; i++ {
+		s.recordError(errors.New(strings.Repeat("x", i+1)))
+	}
+
+	recent := s.recentErrors()
+	if len(recent) != recentErrorsCapacity {
+		t.Fatalf("recentErrors() returned %d errors, want %d", len(recent), recentErrorsCapacity)
+	}
+	if got, want := len(recent[0].Error), 3; got != want {
+		t.Errorf("oldest error has length %d, want %d", got, want)
+	}
+	if got, want := len(recent[len(recent)-1].Error), recentErrorsCapacity+2; got != want {
+		t.Errorf("newest error has length %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,80 @@
+package log
+
+import "io"
+
+// DeliveryMode determines how lines are delivered to a destination, and what the logger gives up when the destination
+// is slow or unavailable.
+type DeliveryMode int
+
+const (
+	// DeliveryBestEffort writes lines in the background when the logger is async. Lines that can't be formatted and
+	// written within the line timeout are dropped. This is the default, and keeps slow destinations from blocking the
+	// caller.
+	DeliveryBestEffort DeliveryMode = iota
+	// DeliveryAtLeastOnce appends every line to a write-ahead log on the calling goroutine, and delivers it to the
+	// destination in the background until the destination accepts it. Lines survive destination outages and process
+	// crashes; a line may be delivered twice if the process crashes between delivering and checkpointing it. See
+	// [WALWriter].
+	DeliveryAtLeastOnce
+	// DeliverySynchronous formats and writes every line on the calling goroutine, even if the logger is async, and
+	// without a timeout. When Log returns, the destination has accepted the line.
+	DeliverySynchronous
+)
+
+func (m DeliveryMode) String() string {
+	switch m {
+	case DeliveryBestEffort:
+		return "best-effort"
+	case DeliveryAtLeastOnce:
+		return "at-least-once"
+	case DeliverySynchronous:
+		return "synchronous"
+	default:
+		return "unknown"
+	}
+}
+
+// DeliverySettings configures the delivery semantics of a destination.
+type DeliverySettings struct {
+	// Mode is the delivery mode of the destination. Defaults to DeliveryBestEffort.
+	Mode DeliveryMode
+	// WAL configures the write-ahead log of DeliveryAtLeastOnce destinations. Required for DeliveryAtLeastOnce,
+	// ignored otherwise.
+	WAL *WALSettings
+}
+
+// WithDeliveryDestination adds a destination with explicit delivery semantics, so that e.g. an audit destination can
+// demand durability while the console stays fast. See [DeliveryMode].
+//
+// DeliveryAtLeastOnce destinations are wrapped in a [WALWriter] owned by the logger; it is closed by the logger's Close
+// method. Only DeliveryBestEffort destinations are affected by WithWriteCoalescing.
+func WithDeliveryDestination(destination io.Writer, formatter LogLineFormatter, settings *DeliverySettings) LoggerOption {
+	return func(l *ultraLogger) error {
+		if settings == nil {
+			settings = &DeliverySettings{}
+		}
+
+		if settings.Mode == DeliveryAtLeastOnce {
+			walWriter, err := NewWALWriter(destination, settings.WAL)
+			if err != nil {
+				return &ErrorLoggerInitialization{err: err}
+			}
+			l.closers = append(l.closers, walWriter)
+			destination = walWriter
+		}
+
+		if l.destinations == nil {
+			l.destinations = map[io.Writer]LogLineFormatter{}
+		}
+		l.destinations[destination] = formatter
+
+		if settings.Mode != DeliveryBestEffort {
+			if l.deliveryModes == nil {
+				l.deliveryModes = map[io.Writer]DeliveryMode{}
+			}
+			l.deliveryModes[destination] = settings.Mode
+		}
+
+		return nil
+	}
+}
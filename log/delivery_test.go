@@ -0,0 +1,151 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slowWriter is a destination that takes longer than the line timeout to accept a line.
+type slowWriter struct {
+	toggleWriter
+	delay time.Duration
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(w.delay)
+	return w.toggleWriter.Write(p)
+}
+
+func TestWithDeliveryDestination_synchronous(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+	audit := &slowWriter{delay: loglineTimeout + 50*time.Millisecond}
+	// The console write must still be in progress after the synchronous write to the audit destination, which may
+	// happen first.
+	console := &slowWriter{delay: 4 * loglineTimeout}
+
+	internalErrors := make(chan error, 1)
+	logger, err := NewLoggerWithOptions(
+		WithAsync(true),
+		WithPartialDeliveryReports(true),
+		WithDeliveryDestination(audit, formatter, &DeliverySettings{Mode: DeliverySynchronous}),
+		WithDeliveryDestination(console, formatter, nil),
+		WithInternalErrorHandler(func(err error) { internalErrors <- err }),
+	)
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	logger.Info("hello")
+
+	// The synchronous destination has the line as soon as Log returns, regardless of the timeout.
+	if got := audit.received(); len(got) != 1 || got[0] != "hello\n" {
+		t.Errorf("synchronous destination received %q", got)
+	}
+
+	logger.Flush()
+
+	if got := console.received(); len(got) != 0 {
+		t.Errorf("best-effort destination received %q, want the line to be dropped after the timeout", got)
+	}
+	if dropped := logger.(Inspector).Inspect().Stats.Dropped; dropped != 1 {
+		t.Errorf("Dropped = %d, want 1", dropped)
+	}
+
+	var partial *ErrorPartialDelivery
+	if err := <-internalErrors; !errors.As(err, &partial) || len(partial.Delivered()) != 1 {
+		t.Errorf("internal error = %v, want an ErrorPartialDelivery with the synchronous destination", err)
+	}
+}
+
+func TestWithDeliveryDestination_atLeastOnce(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+	audit := &toggleWriter{failed: true}
+	console := &bytes.Buffer{}
+
+	logger, err := NewLoggerWithOptions(
+		WithAsync(false),
+		WithWriteCoalescing(nil),
+		WithDeliveryDestination(audit, formatter, &DeliverySettings{
+			Mode: DeliveryAtLeastOnce,
+			WAL:  &WALSettings{Path: filepath.Join(t.TempDir(), "audit.wal"), RetryInterval: time.Millisecond},
+		}),
+		WithDestination(console, formatter),
+	)
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	modes := map[string]int{}
+	for _, d := range logger.(Inspector).Inspect().Destinations {
+		modes[d.Delivery]++
+	}
+	if modes["at-least-once"] != 1 || modes["best-effort"] != 1 {
+		t.Errorf("Inspect() destination delivery modes = %v", modes)
+	}
+
+	// The destination is down, so the line waits in the WAL until it comes back.
+	logger.Info("hello")
+	audit.setFailed(false)
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if got := audit.received(); len(got) != 1 || got[0] != "hello\n" {
+		t.Errorf("at-least-once destination received %q", got)
+	}
+	if console.String() != "hello\n" {
+		t.Errorf("best-effort destination received %q", console.String())
+	}
+}
+
+func TestWithDeliveryDestination_atLeastOnceRequiresWAL(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+
+	_, err := NewLoggerWithOptions(
+		WithDeliveryDestination(&toggleWriter{}, formatter, &DeliverySettings{Mode: DeliveryAtLeastOnce}),
+	)
+	if !errors.Is(err, ErrorWALPathNotSpecified) {
+		t.Errorf("NewLoggerWithOptions() error = %v, want %v", err, ErrorWALPathNotSpecified)
+	}
+}
+
+func TestWithSynchronousLevel(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+
+	t.Run("async", func(t *testing.T) {
+		w := &slowWriter{delay: loglineTimeout + 50*time.Millisecond}
+		logger, _ := NewLoggerWithOptions(
+			WithAsync(true),
+			WithDestination(w, formatter),
+			WithSynchronousLevel(Error),
+		)
+
+		logger.Error("written")
+
+		// The Error line is written when Log returns, regardless of the timeout.
+		if got := w.received(); len(got) != 1 || got[0] != "written\n" {
+			t.Errorf("destination received %q, want the Error line", got)
+		}
+	})
+
+	t.Run("coalescing", func(t *testing.T) {
+		w := &toggleWriter{}
+		logger, _ := NewLoggerWithOptions(
+			WithAsync(true),
+			WithDestination(w, formatter),
+			WithWriteCoalescing(&CoalescingSettings{Window: time.Hour}),
+			WithSynchronousLevel(Error),
+		)
+
+		logger.Error("written")
+
+		if got := strings.Join(w.received(), ""); got != "written\n" {
+			t.Errorf("destination received %q, want the Error line flushed from the coalescing buffer", got)
+		}
+	})
+}
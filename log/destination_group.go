@@ -0,0 +1,118 @@
+package log
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// Destination is a writer, and the formatter of the lines written to it.
+type Destination struct {
+	Writer    io.Writer
+	Formatter LogLineFormatter
+}
+
+// WithAllOrNothing adds a group of destinations where partial delivery is unacceptable, e.g. an audit file and an
+// audit service. Lines are delivered to the group on the calling goroutine, even if the logger is async:
+//
+//  1. The line is formatted for every destination. If any formatter fails, nothing is written to the group, and the
+//     error is reported as an internal error of the logger.
+//  2. The line is written to the destinations in order. If a write fails, the remaining destinations are skipped, and
+//     an ErrorPartialDelivery naming the destinations that did receive the line is reported as an internal error.
+//
+// Writes can't be rolled back, so a failed write may still leave the line at the destinations before it; the point of
+// the group is that this is always detected and reported. Failing destinations aren't disabled, unlike regular
+// destinations with fallback enabled.
+func WithAllOrNothing(destinations ...Destination) LoggerOption {
+	return func(l *ultraLogger) error {
+		if len(destinations) == 0 {
+			return nil
+		}
+		l.groups = append(l.groups, &destinationGroup{destinations: destinations, allOrNothing: true})
+		return nil
+	}
+}
+
+// destinationGroup is a set of destinations, added with WithAllOrNothing or WithDestinationGroup.
+type destinationGroup struct {
+	name         string // Empty for groups added with WithAllOrNothing.
+	destinations []Destination
+	allOrNothing bool
+}
+
+// deliverAll formats and writes the line to every destination of an all-or-nothing group, stopping at the first
+// failure.
+func (g *destinationGroup) deliverAll(l *ultraLogger, args LogLineArgs, data []any) {
+	lines := make([][]byte, len(g.destinations))
+	for i, destination := range g.destinations {
+		destinationArgs := l.stampDestination(args, destination.Writer)
+		result := formatLogLine(context.Background(), l.runtimeTrace, destination.Formatter, destinationArgs, data)
+		if result.err != nil {
+			l.handleFormatError(destination.Formatter, data, result.err)
+			return
+		}
+		lines[i] = result.bytes
+	}
+
+	var delivered []io.Writer
+	for i, destination := range g.destinations {
+		if len(lines[i]) == 0 {
+			// Dropped by the formatter.
+			continue
+		}
+		if err := write(destination.Writer, args, lines[i]); err != nil {
+			l.reportInternalError(&ErrorPartialDelivery{
+				delivered: delivered,
+				failed:    []error{&ErrorDestinationWrite{writer: destination.Writer, err: err}},
+				total:     len(g.destinations),
+			})
+			return
+		}
+		delivered = append(delivered, destination.Writer)
+		l.stats.lines.Add(1)
+	}
+}
+
+// deliveryTracker collects the outcome of a line at each of the logger's destinations, and reports an
+// ErrorPartialDelivery once all of them are known, if the line reached some destinations but not all.
+type deliveryTracker struct {
+	logger  *ultraLogger
+	pending atomic.Int32
+	total   int
+
+	mu        sync.Mutex
+	delivered []io.Writer
+	failed    []error
+}
+
+func newDeliveryTracker(l *ultraLogger, destinations int) *deliveryTracker {
+	t := &deliveryTracker{logger: l, total: destinations}
+	t.pending.Store(int32(destinations))
+	return t
+}
+
+// done records the outcome of the line at the destination w. err is nil if the line was delivered.
+func (t *deliveryTracker) done(w io.Writer, err error) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	if err != nil {
+		t.failed = append(t.failed, err)
+	} else {
+		t.delivered = append(t.delivered, w)
+	}
+	t.mu.Unlock()
+
+	if t.pending.Add(-1) > 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.failed) > 0 && len(t.delivered) > 0 {
+		t.logger.reportInternalError(&ErrorPartialDelivery{delivered: t.delivered, failed: t.failed, total: t.total})
+	}
+}
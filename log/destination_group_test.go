@@ -0,0 +1,98 @@
+package log
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithAllOrNothing(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+	failing, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()}, WithEntryValidator(
+		ValidationReject,
+		func(args LogLineArgs, results FieldResults) error { return errors.New("rejected") },
+	))
+
+	t.Run("delivered", func(t *testing.T) {
+		file, remote := &toggleWriter{}, &toggleWriter{}
+		logger, _ := NewLoggerWithOptions(
+			WithAsync(true),
+			WithAllOrNothing(Destination{file, formatter}, Destination{remote, formatter}),
+		)
+
+		logger.Info("audit")
+
+		// Groups are delivered on the calling goroutine, even if the logger is async.
+		if len(file.received()) != 1 || len(remote.received()) != 1 {
+			t.Errorf("file = %q, remote = %q, want the line at both", file.received(), remote.received())
+		}
+	})
+
+	t.Run("format failure writes nothing", func(t *testing.T) {
+		file, remote := &toggleWriter{}, &toggleWriter{}
+		var internalErr error
+		logger, _ := NewLoggerWithOptions(
+			WithAllOrNothing(Destination{file, formatter}, Destination{remote, failing}),
+			WithInternalErrorHandler(func(err error) { internalErr = err }),
+		)
+
+		logger.Info("audit")
+
+		if len(file.received()) != 0 || len(remote.received()) != 0 {
+			t.Errorf("file = %q, remote = %q, want nothing written", file.received(), remote.received())
+		}
+		var formatErr *ErrorLineFormat
+		if !errors.As(internalErr, &formatErr) {
+			t.Errorf("internal error = %v, want an ErrorLineFormat", internalErr)
+		}
+	})
+
+	t.Run("write failure is reported", func(t *testing.T) {
+		file, remote, archive := &toggleWriter{}, &toggleWriter{failed: true}, &toggleWriter{}
+		var internalErr error
+		logger, _ := NewLoggerWithOptions(
+			WithAllOrNothing(Destination{file, formatter}, Destination{remote, formatter}, Destination{archive, formatter}),
+			WithInternalErrorHandler(func(err error) { internalErr = err }),
+		)
+
+		logger.Info("audit")
+
+		if len(archive.received()) != 0 {
+			t.Errorf("archive = %q, want the destinations after the failure to be skipped", archive.received())
+		}
+		var partial *ErrorPartialDelivery
+		if !errors.As(internalErr, &partial) {
+			t.Fatalf("internal error = %v, want an ErrorPartialDelivery", internalErr)
+		}
+		if delivered := partial.Delivered(); len(delivered) != 1 || delivered[0] != file {
+			t.Errorf("Delivered() = %v, want the file", delivered)
+		}
+	})
+}
+
+func TestLogger_partialDelivery(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+	healthy, broken := &toggleWriter{}, &toggleWriter{failed: true}
+
+	var internalErrs []error
+	logger, _ := NewLoggerWithOptions(
+		WithAsync(false),
+		WithPartialDeliveryReports(true),
+		WithDestination(healthy, formatter),
+		WithDestination(broken, formatter),
+		WithInternalErrorHandler(func(err error) { internalErrs = append(internalErrs, err) }),
+	)
+
+	logger.Info("hello")
+
+	var partial *ErrorPartialDelivery
+	found := false
+	for _, err := range internalErrs {
+		found = found || errors.As(err, &partial)
+	}
+	if !found {
+		t.Fatalf("internal errors = %v, want an ErrorPartialDelivery", internalErrs)
+	}
+	if delivered := partial.Delivered(); len(delivered) != 1 || delivered[0] != healthy {
+		t.Errorf("Delivered() = %v, want the healthy destination", delivered)
+	}
+}
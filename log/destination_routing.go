@@ -0,0 +1,106 @@
+package log
+
+import (
+	"path"
+	"slices"
+)
+
+// DestinationGroupSettings are the settings for WithDestinationGroup.
+type DestinationGroupSettings struct {
+	// Destinations are the destinations of the group.
+	Destinations []Destination
+	// AllOrNothing delivers lines to the group with the semantics of WithAllOrNothing. Otherwise, the destinations
+	// of the group are written like destinations added with WithDestination.
+	AllOrNothing bool
+}
+
+// WithDestinationGroup adds a named group of destinations, e.g. "console", "audit", or "shipping". Routes added with
+// WithRoutes decide which lines each group receives; without routes, every group receives every line. Adding a group
+// with the name of an existing group replaces it.
+//
+// Unlike destinations added with WithDestination, the destinations of a group aren't disabled when a write fails; the
+// error is reported as an internal error of the logger instead.
+func WithDestinationGroup(name string, settings *DestinationGroupSettings) LoggerOption {
+	return func(l *ultraLogger) error {
+		if settings == nil {
+			settings = &DestinationGroupSettings{}
+		}
+		group := &destinationGroup{
+			name:         name,
+			destinations: slices.Clone(settings.Destinations),
+			allOrNothing: settings.AllOrNothing,
+		}
+		if i := slices.IndexFunc(l.namedGroups, func(g *destinationGroup) bool { return g.name == name }); i >= 0 {
+			l.namedGroups[i] = group
+			return nil
+		}
+		l.namedGroups = append(l.namedGroups, group)
+		return nil
+	}
+}
+
+// LevelRange is an inclusive range of levels.
+type LevelRange struct {
+	Min Level
+	Max Level
+}
+
+// Contains reports whether the level is in the range.
+func (r LevelRange) Contains(level Level) bool {
+	return level >= r.Min && level <= r.Max
+}
+
+// RouteRule routes the lines it matches to destination groups. A line matches a rule if it matches all of the rule's
+// conditions; a rule without conditions matches every line.
+type RouteRule struct {
+	// Levels restricts the rule to lines with a level in the range. Nil matches every level.
+	Levels *LevelRange
+	// Tags restricts the rule to lines whose tag matches one of the glob patterns, as in path.Match, e.g. "audit.*".
+	// Empty matches every tag.
+	Tags []string
+	// Match restricts the rule to lines for which it returns true, e.g. lines logged with an audit event value. Nil
+	// matches every line.
+	Match func(args LogLineArgs, data []any) bool
+	// Groups are the names of the destination groups the matching lines are delivered to.
+	Groups []string
+}
+
+// WithRoutes routes lines to the destination groups added with WithDestinationGroup, so that complex topologies are
+// configured declaratively. E.g. errors everywhere, debug lines only to a file, and audit events to the audit group:
+//
+//	log.WithRoutes(
+//		log.RouteRule{Levels: &log.LevelRange{Min: log.Error, Max: log.Panic}, Groups: []string{"console", "file", "audit"}},
+//		log.RouteRule{Levels: &log.LevelRange{Min: log.Debug, Max: log.Debug}, Groups: []string{"file"}},
+//		log.RouteRule{Tags: []string{"audit*"}, Groups: []string{"audit"}},
+//	)
+//
+// A line is delivered once to each group of every rule it matches, and to no group if it matches none. Destinations
+// added with WithDestination aren't part of any group, and receive every line. Applying the option again adds more
+// rules. NewLoggerWithOptions returns an ErrorUnknownDestinationGroup if a rule names a group that doesn't exist.
+func WithRoutes(rules ...RouteRule) LoggerOption {
+	return func(l *ultraLogger) error {
+		for _, rule := range rules {
+			for _, pattern := range rule.Tags {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return &ErrorLoggerInitialization{err: err}
+				}
+			}
+		}
+		l.routes = append(l.routes, rules...)
+		return nil
+	}
+}
+
+// matches reports whether the line matches the rule.
+func (r RouteRule) matches(args LogLineArgs, data []any) bool {
+	if r.Levels != nil && !r.Levels.Contains(args.Level) {
+		return false
+	}
+	if len(r.Tags) > 0 && !slices.ContainsFunc(r.Tags, func(pattern string) bool {
+		matched, _ := path.Match(pattern, args.Tag)
+		return matched
+	}) {
+		return false
+	}
+	return r.Match == nil || r.Match(args, data)
+}
@@ -0,0 +1,186 @@
+package log
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLevelRange_Contains(t *testing.T) {
+	r := LevelRange{Min: Info, Max: Error}
+
+	for level, want := range map[Level]bool{Debug: false, Info: true, Warn: true, Error: true, Panic: false} {
+		if got := r.Contains(level); got != want {
+			t.Errorf("Contains(%v) = %v, want %v", level, got, want)
+		}
+	}
+}
+
+func TestWithRoutes(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+	console, file, audit, stdout := &toggleWriter{}, &toggleWriter{}, &toggleWriter{}, &toggleWriter{}
+
+	logger, err := NewLoggerWithOptions(
+		WithAsync(false),
+		WithMinLevel(Debug),
+		WithDestination(stdout, formatter),
+		WithDestinationGroup("console", &DestinationGroupSettings{Destinations: []Destination{{console, formatter}}}),
+		WithDestinationGroup("file", &DestinationGroupSettings{Destinations: []Destination{{file, formatter}}}),
+		WithDestinationGroup("audit", &DestinationGroupSettings{
+			Destinations: []Destination{{audit, formatter}},
+			AllOrNothing: true,
+		}),
+		WithRoutes(
+			RouteRule{Levels: &LevelRange{Min: Error, Max: Panic}, Groups: []string{"console", "file", "audit"}},
+			RouteRule{Levels: &LevelRange{Min: Debug, Max: Debug}, Groups: []string{"file"}},
+			RouteRule{
+				Match: func(args LogLineArgs, data []any) bool {
+					return len(data) > 0 && strings.HasPrefix(data[0].(string), "audit:")
+				},
+				Groups: []string{"audit", "file"},
+			},
+		),
+	)
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Info("audit: login")
+	logger.Error("error")
+
+	tests := []struct {
+		name   string
+		writer *toggleWriter
+		want   []string
+	}{
+		{"ungrouped destination", stdout, []string{"debug", "info", "audit: login", "error"}},
+		{"console", console, []string{"error"}},
+		{"file", file, []string{"debug", "audit: login", "error"}},
+		{"audit", audit, []string{"audit: login", "error"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.writer.received()
+			if len(got) != len(tt.want) {
+				t.Fatalf("received %q, want %q", got, tt.want)
+			}
+			for i, line := range got {
+				if strings.TrimSpace(line) != tt.want[i] {
+					t.Errorf("line %d = %q, want %q", i, line, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWithRoutes_tags(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+	audit := &toggleWriter{}
+
+	logger, _ := NewLoggerWithOptions(
+		WithAsync(false),
+		WithDestinationGroup("audit", &DestinationGroupSettings{Destinations: []Destination{{audit, formatter}}}),
+		WithRoutes(RouteRule{Tags: []string{"audit.*"}, Groups: []string{"audit"}}),
+	)
+
+	logger.Info("untagged")
+	logger.SetTag("audit.login")
+	logger.Info("tagged")
+
+	if got := audit.received(); len(got) != 1 || strings.TrimSpace(got[0]) != "tagged" {
+		t.Errorf("audit received %q, want only the tagged line", got)
+	}
+}
+
+func TestWithRoutes_invalid(t *testing.T) {
+	t.Run("unknown group", func(t *testing.T) {
+		_, err := NewLoggerWithOptions(
+			WithDestinationGroup("console", nil),
+			WithRoutes(RouteRule{Groups: []string{"console", "shipping"}}),
+		)
+
+		var unknown *ErrorUnknownDestinationGroup
+		if !errors.As(err, &unknown) {
+			t.Fatalf("NewLoggerWithOptions() error = %v, want an ErrorUnknownDestinationGroup", err)
+		}
+		if !strings.Contains(err.Error(), `"shipping"`) {
+			t.Errorf("error = %q, want it to name the group", err)
+		}
+	})
+
+	t.Run("bad tag pattern", func(t *testing.T) {
+		_, err := NewLoggerWithOptions(WithRoutes(RouteRule{Tags: []string{"["}}))
+
+		var initErr *ErrorLoggerInitialization
+		if !errors.As(err, &initErr) {
+			t.Errorf("NewLoggerWithOptions() error = %v, want an ErrorLoggerInitialization", err)
+		}
+	})
+}
+
+func TestWithDestinationGroup(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+
+	t.Run("every group receives every line without routes", func(t *testing.T) {
+		console, shipping := &toggleWriter{}, &toggleWriter{}
+		logger, _ := NewLoggerWithOptions(
+			WithAsync(false),
+			WithDestinationGroup("console", &DestinationGroupSettings{Destinations: []Destination{{console, formatter}}}),
+			WithDestinationGroup("shipping", &DestinationGroupSettings{Destinations: []Destination{{shipping, formatter}}}),
+		)
+
+		logger.Info("hello")
+
+		if len(console.received()) != 1 || len(shipping.received()) != 1 {
+			t.Errorf("console = %q, shipping = %q, want the line at both", console.received(), shipping.received())
+		}
+	})
+
+	t.Run("groups replace the default destination", func(t *testing.T) {
+		logger, _ := NewLoggerWithOptions(WithDestinationGroup("console", nil))
+
+		if n := len(logger.(*ultraLogger).loadDestinations().destinations); n != 0 {
+			t.Errorf("logger has %d ungrouped destinations, want 0", n)
+		}
+	})
+
+	t.Run("adding a group again replaces it", func(t *testing.T) {
+		first, second := &toggleWriter{}, &toggleWriter{}
+		logger, _ := NewLoggerWithOptions(
+			WithAsync(false),
+			WithDestinationGroup("console", &DestinationGroupSettings{Destinations: []Destination{{first, formatter}}}),
+			WithDestinationGroup("console", &DestinationGroupSettings{Destinations: []Destination{{second, formatter}}}),
+		)
+
+		logger.Info("hello")
+
+		if len(first.received()) != 0 || len(second.received()) != 1 {
+			t.Errorf("first = %q, second = %q, want the line only at the second", first.received(), second.received())
+		}
+	})
+
+	t.Run("failing destinations are reported, not disabled", func(t *testing.T) {
+		broken := &toggleWriter{failed: true}
+		var internalErrs []error
+		logger, _ := NewLoggerWithOptions(
+			WithAsync(false),
+			WithFallbackEnabled(false),
+			WithDestinationGroup("shipping", &DestinationGroupSettings{Destinations: []Destination{{broken, formatter}}}),
+			WithInternalErrorHandler(func(err error) { internalErrs = append(internalErrs, err) }),
+		)
+
+		logger.Info("first")
+		broken.setFailed(false)
+		logger.Info("second")
+
+		var writeErr *ErrorDestinationWrite
+		if len(internalErrs) != 1 || !errors.As(internalErrs[0], &writeErr) {
+			t.Errorf("internal errors = %v, want one ErrorDestinationWrite", internalErrs)
+		}
+		if got := broken.received(); len(got) != 1 {
+			t.Errorf("received %q after recovering, want the second line", got)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package log
+
+import (
+	"io"
+	"maps"
+	"slices"
+)
+
+// destinationSet is the set of destinations a logger writes to. The options of NewLoggerWithOptions build it, and it's
+// then published by the logger. A published set is never modified: disabling or swapping destinations publishes a new
+// set, so lines are dispatched without locking, and always see a consistent set.
+type destinationSet struct {
+	destinations  map[io.Writer]LogLineFormatter
+	deliveryModes map[io.Writer]DeliveryMode // Destinations that aren't DeliveryBestEffort.
+	groups        []*destinationGroup        // All-or-nothing destination groups, see WithAllOrNothing.
+	namedGroups   []*destinationGroup        // Named destination groups, see WithDestinationGroup.
+	routes        []RouteRule
+	origins       map[io.Writer]io.Writer // The writers wrapped by the logger, e.g. in a CoalescingWriter.
+}
+
+// loadDestinations returns the published destinationSet of the logger.
+func (l *ultraLogger) loadDestinations() *destinationSet {
+	return l.published.Load()
+}
+
+// publishDestinations publishes the destinations built by the options.
+func (l *ultraLogger) publishDestinations() {
+	set := l.destinationSet
+	l.published.Store(&set)
+	l.destinationSet = destinationSet{}
+}
+
+// disableDestination publishes a copy of the destinations where the writer is disabled. It reports whether the writer
+// was still enabled.
+func (l *ultraLogger) disableDestination(writer io.Writer) bool {
+	l.destinationsMu.Lock()
+	defer l.destinationsMu.Unlock()
+
+	set := *l.loadDestinations()
+	if set.destinations[writer] == nil {
+		return false
+	}
+	set.destinations = maps.Clone(set.destinations)
+	set.destinations[writer] = nil
+	l.published.Store(&set)
+	return true
+}
+
+// origin returns the writer the logger wrapped in w, or w itself.
+func (s *destinationSet) origin(w io.Writer) io.Writer {
+	if origin, ok := s.origins[w]; ok {
+		return origin
+	}
+	return w
+}
+
+// deliveryMode returns the delivery mode of the destination writer.
+func (s *destinationSet) deliveryMode(w io.Writer) DeliveryMode {
+	return s.deliveryModes[w]
+}
+
+// activeDestinations returns the number of destinations that haven't been disabled.
+func (s *destinationSet) activeDestinations() int {
+	active := 0
+	for _, f := range s.destinations {
+		if f != nil {
+			active++
+		}
+	}
+	return active
+}
+
+// validateRoutes checks that every route names an existing group.
+func (s *destinationSet) validateRoutes() error {
+	for _, rule := range s.routes {
+		for _, name := range rule.Groups {
+			if s.namedGroup(name) == nil {
+				return &ErrorUnknownDestinationGroup{name: name}
+			}
+		}
+	}
+	return nil
+}
+
+// namedGroup returns the named group, or nil if there is none.
+func (s *destinationSet) namedGroup(name string) *destinationGroup {
+	for _, group := range s.namedGroups {
+		if group.name == name {
+			return group
+		}
+	}
+	return nil
+}
+
+// routedGroups returns the named groups the line is delivered to, in the order they were added, or in the order of the
+// routes that matched the line.
+func (s *destinationSet) routedGroups(args LogLineArgs, data []any) []*destinationGroup {
+	if len(s.routes) == 0 {
+		return s.namedGroups
+	}
+
+	var groups []*destinationGroup
+	for _, rule := range s.routes {
+		if !rule.matches(args, data) {
+			continue
+		}
+		for _, name := range rule.Groups {
+			if group := s.namedGroup(name); !slices.Contains(groups, group) {
+				groups = append(groups, group)
+			}
+		}
+	}
+	return groups
+}
@@ -0,0 +1,52 @@
+package log
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestInspect_concurrentDisable(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+
+	var failing []*toggleWriter
+	opts := []LoggerOption{WithAsync(true), WithInternalErrorHandler(func(error) {})}
+	for range 8 {
+		w := &toggleWriter{failed: true}
+		failing = append(failing, w)
+		opts = append(opts, WithDestination(w, formatter))
+	}
+	healthy := &toggleWriter{}
+	opts = append(opts, WithDestination(healthy, formatter))
+
+	logger, err := NewLoggerWithOptions(opts...)
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for range 100 {
+			logger.(Inspector).Inspect()
+		}
+	}()
+	for range 10 {
+		logger.Info("hello")
+	}
+	wg.Wait()
+	logger.Flush()
+
+	healthyCount := 0
+	for _, d := range logger.(Inspector).Inspect().Destinations {
+		if d.Healthy {
+			healthyCount++
+		}
+	}
+	if healthyCount != 1 {
+		t.Errorf("%d healthy destinations, want 1", healthyCount)
+	}
+	if got := len(healthy.received()); got < 10 {
+		t.Errorf("healthy destination received %d lines, want at least 10", got)
+	}
+}
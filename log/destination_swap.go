@@ -0,0 +1,48 @@
+package log
+
+import (
+	"io"
+	"maps"
+	"sync"
+)
+
+// DestinationSwapper is implemented by loggers whose destinations can be replaced while they are in use, e.g. to
+// capture a logger's output in tests (see the logtest package). Loggers returned by this package implement it.
+type DestinationSwapper interface {
+	// SwapDestinations waits for in-flight lines to be written, and replaces all the destinations of the logger,
+	// including its destination groups and routes, with destinations. The returned restore func waits for in-flight
+	// lines again, and restores the previous destinations.
+	//
+	// The swapped-in destinations are written synchronously, so they receive lines in the order they were logged.
+	SwapDestinations(destinations map[io.Writer]LogLineFormatter) (restore func())
+}
+
+// SwapDestinations implements DestinationSwapper.
+func (l *ultraLogger) SwapDestinations(destinations map[io.Writer]LogLineFormatter) (restore func()) {
+	set := &destinationSet{
+		destinations:  maps.Clone(destinations),
+		deliveryModes: make(map[io.Writer]DeliveryMode, len(destinations)),
+	}
+	for w := range destinations {
+		set.deliveryModes[w] = DeliverySynchronous
+	}
+
+	l.Flush()
+	previous := l.replaceDestinations(set)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.Flush()
+			l.replaceDestinations(previous)
+		})
+	}
+}
+
+// replaceDestinations publishes set, and returns the previously published set.
+func (l *ultraLogger) replaceDestinations(set *destinationSet) (previous *destinationSet) {
+	l.destinationsMu.Lock()
+	defer l.destinationsMu.Unlock()
+
+	return l.published.Swap(set)
+}
@@ -0,0 +1,84 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+)
+
+func TestSwapDestinations(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+	console := &toggleWriter{}
+	audit := &toggleWriter{}
+	shipping := &toggleWriter{}
+
+	logger, err := NewLoggerWithOptions(
+		WithDestination(console, formatter),
+		WithAllOrNothing(Destination{Writer: audit, Formatter: formatter}),
+		WithDestinationGroup("shipping", &DestinationGroupSettings{
+			Destinations: []Destination{{Writer: shipping, Formatter: formatter}},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	capture := &bytes.Buffer{}
+	restore := logger.(DestinationSwapper).SwapDestinations(map[io.Writer]LogLineFormatter{capture: formatter})
+	logger.Info("one")
+	logger.Info("two")
+	restore()
+	logger.Info("three")
+	logger.Flush()
+
+	if got := capture.String(); got != "one\ntwo\n" {
+		t.Errorf("swapped-in destination received %q, want %q", got, "one\ntwo\n")
+	}
+	for name, w := range map[string]*toggleWriter{"console": console, "audit": audit, "shipping": shipping} {
+		if got := w.received(); len(got) != 1 || got[0] != "three\n" {
+			t.Errorf("%s received %q, want only the line logged after restore", name, got)
+		}
+	}
+}
+
+func TestSwapDestinations_concurrentLogging(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+	logger, err := NewLoggerWithOptions(
+		WithDestination(&toggleWriter{}, formatter),
+		WithDestinationGroup("shipping", &DestinationGroupSettings{
+			Destinations: []Destination{{Writer: &toggleWriter{}, Formatter: formatter}},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					logger.Info("hello")
+					logger.InfoMsg("hello")
+				}
+			}
+		}()
+	}
+
+	for range 50 {
+		capture := &toggleWriter{}
+		restore := logger.(DestinationSwapper).SwapDestinations(map[io.Writer]LogLineFormatter{capture: formatter})
+		logger.Info("captured")
+		restore()
+	}
+	close(done)
+	wg.Wait()
+	logger.Flush()
+}
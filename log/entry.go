@@ -0,0 +1,87 @@
+package log
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// Entry is a line built programmatically rather than from the data of a Log call, e.g. by a bridge from another
+// logging library, or a tool replaying stored lines. Log it with Logger.LogEntry.
+//
+// The Message and Fields of an entry go straight to their fields, instead of to the first fields matching their type:
+//
+//	entry := log.NewEntry(log.Warn, "slow query").
+//	    At(startedAt).
+//	    WithField("took", 1500*time.Millisecond)
+//	logger.LogEntry(entry)
+type Entry struct {
+	Level Level
+	// Time is when the line happened. The zero value means now.
+	Time time.Time
+	// Tag, if set, is the tag of the line instead of the tag of the logger.
+	Tag string
+	// Message is the data of the message fields of the formatters, if set.
+	Message string
+	// Fields is the data of the fields of the formatters, by field name. A field whose name isn't in Fields matches the
+	// Data as usual.
+	Fields map[string]any
+	// Data is the rest of the data of the line, matched to the fields by type like the data of Log.
+	Data []any
+}
+
+// NewEntry returns an Entry at the level, with the message.
+func NewEntry(level Level, message string) Entry {
+	return Entry{Level: level, Message: message}
+}
+
+// At returns a copy of the entry that happened at t.
+func (e Entry) At(t time.Time) Entry {
+	e.Time = t
+	return e
+}
+
+// WithTag returns a copy of the entry with the tag.
+func (e Entry) WithTag(tag string) Entry {
+	e.Tag = tag
+	return e
+}
+
+// WithField returns a copy of the entry with the data of the field named name.
+func (e Entry) WithField(name string, value any) Entry {
+	e.Fields = maps.Clone(e.Fields)
+	if e.Fields == nil {
+		e.Fields = map[string]any{}
+	}
+	e.Fields[name] = value
+	return e
+}
+
+// WithData returns a copy of the entry with the data added to its Data.
+func (e Entry) WithData(data ...any) Entry {
+	e.Data = append(slices.Clip(e.Data), data...)
+	return e
+}
+
+// datum returns the data of the field from the entry, if it has any.
+func (e *Entry) datum(field Field) (any, bool) {
+	if e == nil {
+		return nil, false
+	}
+	if value, ok := e.Fields[field.Name()]; ok {
+		return value, true
+	}
+	if _, ok := field.(*messageField); ok && e.Message != "" {
+		return e.Message, true
+	}
+	return nil, false
+}
+
+// LogEntry logs the entry. Like Log, it's subject to the minimum level of the logger.
+func (l *ultraLogger) LogEntry(entry Entry) {
+	tag := entry.Tag
+	if tag == "" {
+		tag = l.tag
+	}
+	l.logArgs(LogLineArgs{Level: entry.Level, Tag: tag, Time: entry.Time, entry: &entry}, entry.Data, true)
+}
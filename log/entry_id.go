@@ -0,0 +1,91 @@
+package log
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"time"
+)
+
+// EntryID uniquely identifies a line. It's a ULID: a 48-bit millisecond timestamp followed by 80 random bits, so IDs
+// sort by the time they were assigned. The zero EntryID means the line has none.
+type EntryID [16]byte
+
+// crockfordAlphabet is the Crockford base32 alphabet ULIDs are encoded with.
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// newEntryID returns a new EntryID for a line logged at t, or now if t is zero.
+func newEntryID(t time.Time) EntryID {
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	var id EntryID
+	ms := uint64(t.UnixMilli())
+	id[0], id[1], id[2], id[3], id[4], id[5] = byte(ms>>40), byte(ms>>32), byte(ms>>24), byte(ms>>16), byte(ms>>8), byte(ms)
+	_, _ = rand.Read(id[6:])
+	return id
+}
+
+// IsZero reports whether the ID is the zero EntryID.
+func (id EntryID) IsZero() bool {
+	return id == EntryID{}
+}
+
+// Time returns the time the ID was assigned, to the millisecond.
+func (id EntryID) Time() time.Time {
+	ms := uint64(id[0])<<40 | uint64(id[1])<<32 | uint64(id[2])<<24 | uint64(id[3])<<16 | uint64(id[4])<<8 | uint64(id[5])
+	return time.UnixMilli(int64(ms))
+}
+
+// String returns the ID in the canonical ULID encoding: 26 characters of Crockford base32.
+func (id EntryID) String() string {
+	hi, lo := binary.BigEndian.Uint64(id[:8]), binary.BigEndian.Uint64(id[8:])
+
+	// 26 characters hold 130 bits; the first one only encodes the top 3 bits of the ID.
+	var dst [26]byte
+	for i := len(dst) - 1; i >= 0; i-- {
+		dst[i] = crockfordAlphabet[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(dst[:])
+}
+
+// EntryHook is called with every line the logger dispatches, before it is formatted, e.g. to attach its EntryID to the
+// current trace span or to metrics. It must not log through the logger.
+type EntryHook func(args LogLineArgs, data []any)
+
+// WithEntryIDs stamps every line with a unique EntryID (LogLineArgs.EntryID), shared by all of its destinations, so
+// duplicates introduced by at-least-once delivery can be dropped downstream. The destination formatters need a
+// NewEntryIDField to write it. Default=false.
+func WithEntryIDs(enabled bool) LoggerOption {
+	return func(l *ultraLogger) error {
+		l.entryIDs = enabled
+		return nil
+	}
+}
+
+// WithEntryHook calls the hook with every line the logger dispatches. With WithEntryIDs, the arguments of the line
+// carry its EntryID.
+func WithEntryHook(hook EntryHook) LoggerOption {
+	return func(l *ultraLogger) error {
+		l.entryHooks = append(l.entryHooks, hook)
+		return nil
+	}
+}
+
+// NewEntryIDField returns a new Field that formats the EntryID of the line, with the key "entry_id". Lines without
+// one, i.e. of loggers without WithEntryIDs, don't have the field.
+func NewEntryIDField() Field {
+	field, _ := NewLineArgsField(
+		"entry_id",
+		func(args LogLineArgs) (any, error) {
+			if args.EntryID.IsZero() {
+				return nil, nil
+			}
+			return args.EntryID.String(), nil
+		},
+		WithHideKey(false),
+	)
+	return field
+}
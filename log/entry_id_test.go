@@ -0,0 +1,59 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithEntryIDs(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField(), NewEntryIDField()})
+
+	first, second := &bytes.Buffer{}, &bytes.Buffer{}
+	var hooked []EntryID
+	logger, _ := NewLoggerWithOptions(
+		WithAsync(false),
+		WithEntryIDs(true),
+		WithEntryHook(func(args LogLineArgs, data []any) { hooked = append(hooked, args.EntryID) }),
+		WithDestination(first, formatter),
+		WithDestination(second, formatter),
+	)
+
+	logger.Info("one")
+	logger.Info("two")
+
+	if len(hooked) != 2 || hooked[0] == hooked[1] || hooked[0].IsZero() {
+		t.Fatalf("hooked IDs = %v, want two distinct IDs", hooked)
+	}
+	want := "one entry_id=" + hooked[0].String() + "\ntwo entry_id=" + hooked[1].String() + "\n"
+	if got := first.String(); got != want {
+		t.Errorf("first = %q, want %q", got, want)
+	}
+	if got := second.String(); got != want {
+		t.Errorf("second = %q, want %q", got, want)
+	}
+}
+
+func TestEntryID_String(t *testing.T) {
+	if got, want := (EntryID{}).String(), strings.Repeat("0", 26); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	var maxID EntryID
+	for i := range maxID {
+		maxID[i] = 0xff
+	}
+	if got, want := maxID.String(), "7"+strings.Repeat("Z", 25); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	at := time.UnixMilli(1_700_000_000_123)
+	id := newEntryID(at)
+	if !id.Time().Equal(at) {
+		t.Errorf("Time() = %v, want %v", id.Time(), at)
+	}
+	if later := newEntryID(at.Add(time.Millisecond)); later.String() <= id.String() {
+		t.Errorf("String() of a later ID = %q, want it to sort after %q", later, id)
+	}
+}
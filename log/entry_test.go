@@ -0,0 +1,57 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogger_LogEntry(t *testing.T) {
+	userField, _ := NewStringField("user")
+	requestField, _ := NewStringField("request")
+	tagField, _ := NewTagField(&TagFieldSettings{Bracket: Brackets.None})
+	fields := []Field{
+		NewCurrentTimeField(&CurrentTimeFieldSettings{Format: time.DateTime}),
+		NewLevelField(&LevelFieldSettings{Bracket: Brackets.None}),
+		tagField,
+		NewMessageField(),
+		userField,
+		requestField,
+	}
+	formatter, _ := NewFormatter(OutputFormatText, fields)
+
+	buf := &bytes.Buffer{}
+	logger, _ := NewLoggerWithOptions(WithAsync(false), WithDestination(buf, formatter), WithMinLevel(Info))
+	logger.SetTag("app")
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+	entries := []Entry{
+		NewEntry(Warn, "slow query").At(at).WithField("request", "r-1"),
+		NewEntry(Error, "failed").WithTag("bridge").WithField("user", "ann").WithData("r-2"),
+		NewEntry(Debug, "hidden"),
+	}
+	for _, entry := range entries {
+		logger.LogEntry(entry)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("LogEntry() logged %d lines, want 2: %q", len(lines), lines)
+	}
+	if want := "2024-01-02 03:04:05 WARN app slow query request=r-1"; lines[0] != want {
+		t.Errorf("LogEntry() = %q, want %q", lines[0], want)
+	}
+	// The Data is matched as usual: the message field takes the string.
+	if want := " ERROR bridge failed r-2 user=ann"; !strings.HasSuffix(lines[1], want) {
+		t.Errorf("LogEntry() = %q, want suffix %q", lines[1], want)
+	}
+}
+
+func TestEntry_WithField(t *testing.T) {
+	base := NewEntry(Info, "hi").WithField("a", 1)
+	derived := base.WithField("b", 2)
+	if len(base.Fields) != 1 || len(derived.Fields) != 2 {
+		t.Errorf("WithField() modified the original entry: %v, %v", base.Fields, derived.Fields)
+	}
+}
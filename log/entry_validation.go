@@ -0,0 +1,93 @@
+package log
+
+import "strings"
+
+// EntryValidator checks an assembled entry against invariants that span fields, e.g. that a line with a status also
+// has a method. It returns an error describing the violation, or nil if the entry is valid.
+type EntryValidator func(args LogLineArgs, results FieldResults) error
+
+// ValidationPolicy determines what happens to entries that fail validation.
+type ValidationPolicy int
+
+const (
+	// ValidationReject fails the line with an ErrorInvalidEntry. The line is not written, and the error is reported as
+	// an internal error of the logger. This is the default.
+	ValidationReject ValidationPolicy = iota
+	// ValidationAnnotate writes the line with an additional ValidationErrorKey field describing the violations.
+	ValidationAnnotate
+)
+
+func (p ValidationPolicy) String() string {
+	switch p {
+	case ValidationReject:
+		return "reject"
+	case ValidationAnnotate:
+		return "annotate"
+	default:
+		return "unknown"
+	}
+}
+
+// ValidationErrorKey is the key of the field that describes the violations of an entry, if the ValidationPolicy is
+// ValidationAnnotate.
+const ValidationErrorKey = "validation_error"
+
+// WithEntryValidator adds validators that check every entry of the formatter once all of its fields are formatted, to
+// enforce logging standards across a codebase. All validators run on every entry; the policy determines what happens to
+// entries that fail any of them. Applying the option again adds more validators, and replaces the policy.
+//
+// Validation needs the results of every field, so text formatters skip their fast path for level and message lines.
+func WithEntryValidator(policy ValidationPolicy, validators ...EntryValidator) FormatterOption {
+	return func(f LogLineFormatter) LogLineFormatter {
+		if tf, ok := unwrapFormatter[*textFormatter](f); ok {
+			tf.Validation = tf.Validation.with(policy, validators)
+		}
+		if jf, ok := unwrapFormatter[*jsonFormatter](f); ok {
+			jf.Validation = jf.Validation.with(policy, validators)
+		}
+		return f
+	}
+}
+
+// entryValidation is the validation stage of a formatter.
+type entryValidation struct {
+	policy     ValidationPolicy
+	validators []EntryValidator
+}
+
+func (v *entryValidation) with(policy ValidationPolicy, validators []EntryValidator) *entryValidation {
+	updated := &entryValidation{policy: policy}
+	if v != nil {
+		updated.validators = append(updated.validators, v.validators...)
+	}
+	updated.validators = append(updated.validators, validators...)
+	return updated
+}
+
+// validate runs the validators over the results of an entry. It returns the error of a rejected entry, or the
+// annotation of an annotated one.
+func (v *entryValidation) validate(args LogLineArgs, results map[string]any) (annotation string, err error) {
+	var errs []error
+	for _, validator := range v.validators {
+		if err := validator(args, FieldResults{results: results}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) == 0 {
+		return "", nil
+	}
+	if v.policy == ValidationAnnotate {
+		return joinErrorMessages(errs), nil
+	}
+	return "", &ErrorInvalidEntry{errs: errs}
+}
+
+// joinErrorMessages joins the messages of the errors on a single line.
+func joinErrorMessages(errs []error) string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
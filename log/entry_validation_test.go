@@ -0,0 +1,81 @@
+package log
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithEntryValidator(t *testing.T) {
+	statusField, _ := NewObjectField[int]("status", func(args LogLineArgs, data int) (any, error) {
+		return data, nil
+	})
+	retriedField, _ := NewObjectField[bool]("retried", func(args LogLineArgs, data bool) (any, error) {
+		return data, nil
+	})
+	requireRetried := func(args LogLineArgs, results FieldResults) error {
+		if _, ok := results.Get("status"); !ok {
+			return nil
+		}
+		if _, ok := results.Get("retried"); !ok {
+			return errors.New("status without retried")
+		}
+		return nil
+	}
+	fields := []Field{NewMessageField(), statusField, retriedField}
+
+	tests := []struct {
+		name    string
+		format  OutputFormat
+		policy  ValidationPolicy
+		data    []any
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			format: OutputFormatText,
+			data:   []any{"done", 200, false},
+			want:   "done status=200 retried=false",
+		},
+		{
+			name:    "reject",
+			format:  OutputFormatText,
+			data:    []any{"done", 200},
+			wantErr: true,
+		},
+		{
+			name:   "annotate text",
+			format: OutputFormatText,
+			policy: ValidationAnnotate,
+			data:   []any{"done", 200},
+			want:   "done status=200 validation_error=status without retried",
+		},
+		{
+			name:   "annotate json",
+			format: OutputFormatJSON,
+			policy: ValidationAnnotate,
+			data:   []any{"done", 200},
+			want:   `{"message":"done","status":200,"validation_error":"status without retried"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter, _ := NewFormatter(tt.format, fields, WithEntryValidator(tt.policy, requireRetried))
+
+			result := formatter.FormatLogLine(LogLineArgs{Level: Info}, tt.data)
+			if tt.wantErr {
+				var invalidEntry *ErrorInvalidEntry
+				if !errors.As(result.err, &invalidEntry) {
+					t.Errorf("FormatLogLine() error = %v, want ErrorInvalidEntry", result.err)
+				}
+				return
+			}
+			if result.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", result.err)
+			}
+			if string(result.bytes) != tt.want {
+				t.Errorf("FormatLogLine() = %s, want %s", result.bytes, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package log
 import (
     "errors"
     "fmt"
+    "io"
+    "strings"
 )
 
 type ErrorLoggerInitialization struct {
@@ -17,6 +19,17 @@ func (e *ErrorLoggerInitialization) Unwrap() error {
     return e.err
 }
 
+// ErrorOptionConflict is wrapped in the ErrorLoggerInitialization returned by NewLoggerWithOptions when options
+// conflict, e.g. a WithDestinations that replaces the destinations set by the options before it.
+type ErrorOptionConflict struct {
+    options []string
+    reason  string
+}
+
+func (e *ErrorOptionConflict) Error() string {
+    return fmt.Sprintf("conflicting options %s: %s", strings.Join(e.options, " and "), e.reason)
+}
+
 var ErrorFileNotSpecified = errors.New("filename not provided to NewFileLogger")
 
 type ErrorFileNotFound struct {
@@ -89,6 +102,16 @@ func (e *ErrorInvalidFieldDataType) Error() string {
 
 var ErrorEmptyFieldName = errors.New("field name cannot be empty")
 
+// ErrorMultipleMessages is returned for lines with several strings by message fields with the MessagesStrict policy.
+type ErrorMultipleMessages struct {
+    fieldName string
+    count     int
+}
+
+func (e *ErrorMultipleMessages) Error() string {
+    return fmt.Sprintf("%d strings passed to message field %s, want 1", e.count, e.fieldName)
+}
+
 var ErrorNilFormatter = errors.New("formatter cannot be nil")
 
 type ErrorMissingFieldFormatter struct {
@@ -113,3 +136,264 @@ func (e *ErrorNonFatalFormatterError) Error() string {
 }
 
 var ErrorTagFieldActiveButNoTag = errors.New("tag field is active but the logger has no tag set. disable the tag field, or add a tag to the logger")
+
+// ErrorDestinationWrite is reported when writing to a destination fails. Destinations added with WithDestination are
+// disabled afterwards if fallback is enabled; the destinations of groups aren't.
+type ErrorDestinationWrite struct {
+    writer io.Writer
+    err    error
+}
+
+func (e *ErrorDestinationWrite) Error() string {
+    return fmt.Sprintf("failed to write log line. writer=%s, err=%v", describeWriter(e.writer), e.err)
+}
+
+func (e *ErrorDestinationWrite) Unwrap() error {
+    return e.err
+}
+
+// Writer returns the destination writer that failed.
+func (e *ErrorDestinationWrite) Writer() io.Writer {
+    return e.writer
+}
+
+// ErrorLineFormat is reported when a formatter fails to format a log line. The line is not written.
+type ErrorLineFormat struct {
+    formatter LogLineFormatter
+    data      []any
+    err       error
+}
+
+func (e *ErrorLineFormat) Error() string {
+    return fmt.Sprintf("failed to format log line. formatter=%v, data=%v, err=%v", e.formatter, e.data, e.err)
+}
+
+func (e *ErrorLineFormat) Unwrap() error {
+    return e.err
+}
+
+// Formatter returns the formatter that failed.
+func (e *ErrorLineFormat) Formatter() LogLineFormatter {
+    return e.formatter
+}
+
+var ErrorWALPathNotSpecified = errors.New("path not provided to NewWALWriter")
+
+var ErrorWALFlushTimeout = errors.New("timed out waiting for the WAL to be delivered")
+
+var ErrorAsyncQueueFull = errors.New("async queue is full")
+
+var ErrorTagFileDirNotSpecified = errors.New("dir not provided to NewTagFileWriter")
+
+var ErrorDatedFilePathNotSpecified = errors.New("path with a {date} placeholder not provided to NewDatedFileWriter")
+
+var ErrorParquetSinkNotSpecified = errors.New("sink not provided to NewParquetWriter")
+
+var ErrorArchiverUploaderNotSpecified = errors.New("uploader not provided to NewArchiver")
+
+var ErrorNoRingBuffer = errors.New("logger has no ring buffer destination")
+
+// ErrorExpvarNameTaken is returned by PublishExpvar for a name that is already registered.
+type ErrorExpvarNameTaken struct {
+    name string
+}
+
+func (e *ErrorExpvarNameTaken) Error() string {
+    return fmt.Sprintf("expvar name already registered: %s", e.name)
+}
+
+var ErrorQueryFieldsUnavailable = errors.New("ring buffer formatter has no fields to query; use a RecordFormatter")
+
+var ErrorLoggerFrozen = errors.New("logger is frozen")
+
+// ErrorArchiveUpload is returned by an Archiver that failed to upload an archive, after retrying.
+type ErrorArchiveUpload struct {
+    key string
+    err error
+}
+
+func (e *ErrorArchiveUpload) Error() string {
+    return fmt.Sprintf("failed to upload archive. key=%s, err=%v", e.key, e.err)
+}
+
+func (e *ErrorArchiveUpload) Unwrap() error {
+    return e.err
+}
+
+var ErrorTokenizerKeyNotSpecified = errors.New("key not provided to NewTokenizer")
+
+var ErrorTokenizerUnsupportedFormatter = errors.New("tokenizer middleware requires a RecordFormatter")
+
+var ErrorAvroUnsupportedFormatter = errors.New("avro formatter requires a RecordFormatter")
+
+var ErrorAvroSubjectNotSpecified = errors.New("subject not provided to NewAvroFormatter with a schema registry")
+
+var ErrorSyslogUnsupportedFormatter = errors.New("syslog formatter requires a RecordFormatter")
+
+var ErrorCEFUnsupportedFormatter = errors.New("cef formatter requires a RecordFormatter")
+
+var ErrorConsoleUnsupportedFormatter = errors.New("console formatter requires a RecordFormatter")
+
+var ErrorOTLPUnsupportedFormatter = errors.New("otlp formatter requires a RecordFormatter")
+
+var ErrorOTLPURLNotSpecified = errors.New("url not provided to OTLPWriter")
+
+// ErrorOTLPExport is returned by an OTLPWriter for a request the endpoint rejected.
+type ErrorOTLPExport struct {
+    status int
+    msg    string
+}
+
+func (e *ErrorOTLPExport) Error() string {
+    return fmt.Sprintf("otlp endpoint returned %d: %s", e.status, e.msg)
+}
+
+// ErrorAvroSchema is returned by ParseAvroSchema and NewAvroFormatter for an invalid or unsupported schema.
+type ErrorAvroSchema struct {
+    msg string
+}
+
+func (e *ErrorAvroSchema) Error() string {
+    return fmt.Sprintf("invalid avro schema: %s", e.msg)
+}
+
+// ErrorAvroEncode is the result of an Avro formatter whose entry doesn't match its schema.
+type ErrorAvroEncode struct {
+    field string
+    err   error
+}
+
+func (e *ErrorAvroEncode) Error() string {
+    return fmt.Sprintf("failed to encode avro field %s: %v", e.field, e.err)
+}
+
+func (e *ErrorAvroEncode) Unwrap() error {
+    return e.err
+}
+
+// ErrorSchemaRegistry is returned by a SchemaRegistry for a request the registry rejected.
+type ErrorSchemaRegistry struct {
+    status int
+    msg    string
+}
+
+func (e *ErrorSchemaRegistry) Error() string {
+    return fmt.Sprintf("schema registry returned %d: %s", e.status, e.msg)
+}
+
+// ErrorFieldFormatterPanic is the result of a field formatter that panicked. The field is written with the error
+// message as its value, so the line isn't lost.
+type ErrorFieldFormatterPanic struct {
+    fieldName string
+    value     any
+}
+
+func (e *ErrorFieldFormatterPanic) Error() string {
+    return fmt.Sprintf("field formatter panicked: field=%v, panic=%v", e.fieldName, e.value)
+}
+
+// ErrorNonFiniteFloat is returned for lines with a NaN or ±Inf float value, if the formatter's NonFiniteFloatPolicy is
+// NonFiniteFloatError.
+type ErrorNonFiniteFloat struct {
+    path  string
+    value float64
+}
+
+func (e *ErrorNonFiniteFloat) Error() string {
+    return fmt.Sprintf("non-finite float value: %v, path=%v", e.value, e.path)
+}
+
+var ErrorSchemaVersionNotSpecified = errors.New("version not provided to LogSchema.Register")
+
+// ErrorDuplicateSchemaVersion is returned when a version is registered twice in a LogSchema.
+type ErrorDuplicateSchemaVersion struct {
+    version string
+}
+
+func (e *ErrorDuplicateSchemaVersion) Error() string {
+    return fmt.Sprintf("schema version already registered: %v", e.version)
+}
+
+// ErrorUnknownSchemaVersion is returned by NewFormatter when WithSchema selects a version that isn't registered.
+type ErrorUnknownSchemaVersion struct {
+    version string
+}
+
+func (e *ErrorUnknownSchemaVersion) Error() string {
+    return fmt.Sprintf("unknown schema version: %v", e.version)
+}
+
+// ErrorInvalidFieldAlias is returned by NewFormatter when WithFieldAlias renames a field to an empty key.
+type ErrorInvalidFieldAlias struct {
+    oldName string
+    newName string
+}
+
+func (e *ErrorInvalidFieldAlias) Error() string {
+    return fmt.Sprintf("invalid field alias: %q -> %q", e.oldName, e.newName)
+}
+
+// ErrorKeyCollision is returned by NewFormatter when two fields would be written under the same key, and the
+// KeyCollisionPolicy is KeyCollisionError.
+type ErrorKeyCollision struct {
+    fieldName string
+    key       string
+}
+
+func (e *ErrorKeyCollision) Error() string {
+    return fmt.Sprintf(
+        "field %q collides with an earlier field under key %q; rename it, or see WithKeyCollisionPolicy",
+        e.fieldName,
+        e.key,
+    )
+}
+
+// ErrorInvalidEntry is returned for lines that fail the entry validators of the formatter, if its ValidationPolicy is
+// ValidationReject.
+type ErrorInvalidEntry struct {
+    errs []error
+}
+
+func (e *ErrorInvalidEntry) Error() string {
+    return fmt.Sprintf("invalid log entry: %v", joinErrorMessages(e.errs))
+}
+
+func (e *ErrorInvalidEntry) Unwrap() []error {
+    return e.errs
+}
+
+// ErrorPartialDelivery is reported as an internal error of the logger when a line reached some of its destinations,
+// but not all. It unwraps to the errors of the destinations that didn't receive the line.
+type ErrorPartialDelivery struct {
+    delivered []io.Writer
+    failed    []error
+    total     int
+}
+
+func (e *ErrorPartialDelivery) Error() string {
+    return fmt.Sprintf(
+        "line delivered to %d of %d destinations: %v",
+        len(e.delivered),
+        e.total,
+        joinErrorMessages(e.failed),
+    )
+}
+
+// Delivered returns the destinations that received the line.
+func (e *ErrorPartialDelivery) Delivered() []io.Writer {
+    return e.delivered
+}
+
+func (e *ErrorPartialDelivery) Unwrap() []error {
+    return e.failed
+}
+
+// ErrorUnknownDestinationGroup is returned by NewLoggerWithOptions when a route names a destination group that wasn't
+// added with WithDestinationGroup.
+type ErrorUnknownDestinationGroup struct {
+    name string
+}
+
+func (e *ErrorUnknownDestinationGroup) Error() string {
+    return fmt.Sprintf("unknown destination group: %q", e.name)
+}
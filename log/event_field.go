@@ -0,0 +1,31 @@
+package log
+
+// Event is implemented by the values that integrations log alongside their message, e.g. the [*RequestSummary] of a
+// RequestLogger, so that they are all written by the same kind of field. See NewEventField.
+type Event interface {
+	// String returns the event for text output.
+	String() string
+	// EventFields returns the event for JSON output, as the members of an object.
+	EventFields() map[string]any
+}
+
+// NewEventField returns a new Field that formats the events of type E. The key of the field is hidden, so the event
+// reads as part of the line.
+//
+// If the name is empty, an error is returned.
+//
+// OutputFormats:
+//   - OutputFormatText => the String of the event.
+//   - OutputFormatJSON => an object with the EventFields of the event.
+func NewEventField[E Event](name string) (Field, error) {
+	return NewObjectField[E](
+		name,
+		func(args LogLineArgs, data E) (any, error) {
+			if args.OutputFormat == OutputFormatText {
+				return data.String(), nil
+			}
+			return data.EventFields(), nil
+		},
+		WithHideKey(true),
+	)
+}
@@ -0,0 +1,46 @@
+package log
+
+import (
+	"testing"
+)
+
+type testEvent struct {
+	name string
+}
+
+func (e *testEvent) String() string {
+	return "event=" + e.name
+}
+
+func (e *testEvent) EventFields() map[string]any {
+	return map[string]any{"event": e.name}
+}
+
+func TestNewEventField(t *testing.T) {
+	field, err := NewEventField[*testEvent]("event")
+	if err != nil {
+		t.Fatalf("NewEventField() error = %v", err)
+	}
+
+	tests := []struct {
+		format OutputFormat
+		want   string
+	}{
+		{OutputFormatText, "done event=deploy"},
+		{OutputFormatJSON, `{"event":{"event":"deploy"},"message":"done"}`},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			formatter, _ := NewFormatter(tt.format, []Field{NewMessageField(), field})
+
+			result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{"done", &testEvent{name: "deploy"}})
+			if string(result.bytes) != tt.want {
+				t.Errorf("FormatLogLine() = %q, want %q", result.bytes, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewEventField[*testEvent](""); err == nil {
+		t.Error("NewEventField(\"\") error = nil, want an error")
+	}
+}
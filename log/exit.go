@@ -0,0 +1,98 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+)
+
+// exitHandlers is the registry of handlers run by RunExitHandlers and Exit.
+var exitHandlers = struct {
+	mu       sync.Mutex
+	handlers []*exitHandler
+}{}
+
+type exitHandler struct {
+	fn func()
+}
+
+// osExit is os.Exit, replaced in tests.
+var osExit = os.Exit
+
+// RegisterExitHandler registers a handler to be run by RunExitHandlers and Exit, e.g. to flush a logger before a
+// short-lived CLI exits. Handlers run in the order they were registered. The returned func unregisters the handler.
+//
+// Go doesn't run anything when main returns or os.Exit is called, so exit handlers only run if the program calls
+// RunExitHandlers (typically deferred at the top of main) or exits through Exit.
+func RegisterExitHandler(handler func()) (unregister func()) {
+	h := &exitHandler{fn: handler}
+
+	exitHandlers.mu.Lock()
+	exitHandlers.handlers = append(exitHandlers.handlers, h)
+	exitHandlers.mu.Unlock()
+
+	return func() {
+		exitHandlers.mu.Lock()
+		defer exitHandlers.mu.Unlock()
+		exitHandlers.handlers = slices.DeleteFunc(exitHandlers.handlers, func(other *exitHandler) bool {
+			return other == h
+		})
+	}
+}
+
+// RunExitHandlers runs the registered exit handlers, and unregisters them so that they run at most once. A handler that
+// panics doesn't keep the others from running; the panic is printed to stderr.
+//
+//	func main() {
+//		defer log.RunExitHandlers()
+//		...
+//	}
+func RunExitHandlers() {
+	exitHandlers.mu.Lock()
+	handlers := exitHandlers.handlers
+	exitHandlers.handlers = nil
+	exitHandlers.mu.Unlock()
+
+	for _, h := range handlers {
+		runExitHandler(h.fn)
+	}
+}
+
+func runExitHandler(fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "ultra/log: exit handler panicked: %v\n", r)
+		}
+	}()
+
+	fn()
+}
+
+// Exit runs the registered exit handlers, and then exits the program with the status code. Use it in place of
+// os.Exit, so that loggers registered with WithFlushOnExit don't lose their last async lines.
+func Exit(code int) {
+	RunExitHandlers()
+	osExit(code)
+}
+
+// WithFlushOnExit registers an exit handler that flushes the logger, so the lines still in flight are written when the
+// program exits through Exit or calls RunExitHandlers. The handler is unregistered when the logger is closed.
+// Default=false.
+func WithFlushOnExit(enabled bool) LoggerOption {
+	return func(l *ultraLogger) error {
+		if !enabled {
+			return nil
+		}
+		l.closers = append(l.closers, exitRegistration(RegisterExitHandler(l.Flush)))
+		return nil
+	}
+}
+
+// exitRegistration unregisters an exit handler when closed.
+type exitRegistration func()
+
+func (r exitRegistration) Close() error {
+	r()
+	return nil
+}
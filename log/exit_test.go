@@ -0,0 +1,73 @@
+package log
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRunExitHandlers(t *testing.T) {
+	var ran []int
+	RegisterExitHandler(func() { ran = append(ran, 1) })
+	RegisterExitHandler(func() { panic("boom") })
+	unregister := RegisterExitHandler(func() { ran = append(ran, 3) })
+	RegisterExitHandler(func() { ran = append(ran, 4) })
+	unregister()
+
+	RunExitHandlers()
+	if want := []int{1, 4}; !slices.Equal(ran, want) {
+		t.Errorf("ran handlers %v, want %v", ran, want)
+	}
+
+	RunExitHandlers()
+	if want := []int{1, 4}; !slices.Equal(ran, want) {
+		t.Errorf("ran handlers %v after a second run, want them to run once", ran)
+	}
+}
+
+func TestExit(t *testing.T) {
+	t.Cleanup(func() { osExit = os.Exit })
+	exitCode := -1
+	osExit = func(code int) { exitCode = code }
+
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+	w := &slowWriter{delay: 10 * time.Millisecond}
+	logger, _ := NewLoggerWithOptions(
+		WithAsync(true),
+		WithDestination(w, formatter),
+		WithFlushOnExit(true),
+	)
+
+	logger.Info("last words")
+	Exit(3)
+
+	if exitCode != 3 {
+		t.Errorf("exit code = %d, want 3", exitCode)
+	}
+	if got := w.received(); len(got) != 1 || got[0] != "last words\n" {
+		t.Errorf("destination received %q, want the line flushed before exiting", got)
+	}
+}
+
+func TestWithFlushOnExit_close(t *testing.T) {
+	flushed := false
+	logger, _ := NewLoggerWithOptions(WithDestination(&toggleWriter{}, nil), WithFlushOnExit(true))
+	RegisterExitHandler(func() { flushed = true })
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	exitHandlers.mu.Lock()
+	registered := len(exitHandlers.handlers)
+	exitHandlers.mu.Unlock()
+	if registered != 1 {
+		t.Errorf("%d exit handlers registered after Close, want only the test's handler", registered)
+	}
+
+	RunExitHandlers()
+	if !flushed {
+		t.Error("exit handler registered after the logger's didn't run")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 var defaultDateTimeFormat = "2006-01-02 15:04:05"
@@ -26,6 +27,29 @@ type Field interface {
 type FieldSettings struct {
 	HideKey     bool
 	AlwaysMatch bool
+
+	// Cacheable marks an AlwaysMatch field whose result only depends on the level, tag, and output format of the line.
+	// Formatters memoize the results of cacheable fields instead of re-formatting them for every line.
+	Cacheable bool
+
+	// LinkTemplate is a URL template used to render the field value as a terminal hyperlink (OSC 8) when the
+	// formatter has hyperlinks enabled. The placeholder {value} is replaced with the field value, path-escaped in the
+	// path of the URL and query-escaped in its query or fragment.
+	LinkTemplate string
+
+	// Condition suppresses the field on lines where it returns false. See [WithFieldCondition].
+	Condition FieldCondition
+
+	// Default is emitted when none of the data of a line matches the field, or a field that always matches formats to
+	// nil. Nil means the field is omitted instead. See [WithDefaultValue].
+	Default any
+
+	// Sensitivity classifies the data of the field. Formatters created with WithMaxSensitivity strip the fields
+	// classified above their maximum. Defaults to SensitivityPublic. See [WithSensitivity].
+	Sensitivity Sensitivity
+
+	// derived marks a field created with NewDerivedField, which is formatted from the results of the fields before it.
+	derived bool
 }
 
 // FieldFormatter is a function that formats a field. It takes a LogLineArgs and the data to be formatted, and returns
@@ -66,6 +90,11 @@ func (f ObjectField[T]) Settings() FieldSettings {
 	return f.options
 }
 
+func (f ObjectField[T]) matchesData(data any) bool {
+	_, ok := data.(T)
+	return ok
+}
+
 // ObjectFieldFormatter is a function that formats a struct of type T and returns the formatted data. Note that this
 // does not (presently) return a FieldResult, but it may in the future.
 type ObjectFieldFormatter[T any] func(
@@ -125,19 +154,128 @@ func WithAlwaysMatch(formatWithoutData bool) FieldOption {
 	}
 }
 
+// WithCacheable marks the field as cacheable. See [FieldSettings.Cacheable].
+func WithCacheable(cacheable bool) FieldOption {
+	return func(s *FieldSettings) error {
+		s.Cacheable = cacheable
+		return nil
+	}
+}
+
+// WithLinkTemplate renders the field as a clickable terminal hyperlink when the formatter has hyperlinks enabled. See
+// [FieldSettings.LinkTemplate] and [WithHyperlinks].
+func WithLinkTemplate(template string) FieldOption {
+	return func(s *FieldSettings) error {
+		s.LinkTemplate = template
+		return nil
+	}
+}
+
+// FieldCondition reports whether a field is emitted on a line. value is the datum the field matched, or the line's data
+// ([]any) for fields that always match.
+type FieldCondition func(args LogLineArgs, value any) bool
+
+// WithFieldCondition emits the field only on lines where the condition passes, so that a field can be suppressed based
+// on the level, the tag, or the value itself. E.g. only emit a stack trace at Error and above, or a latency only when
+// it exceeds 100ms.
+//
+// The condition is evaluated before the field is formatted, so suppressed fields cost no formatting work. Fields created
+// by this package are only asked about data of their own type; fields implementing Field themselves are asked once the
+// datum has been formatted, as formatting is the only way to tell whether they match it.
+//
+// A datum whose field is suppressed is still consumed by the field; it isn't matched to any later field.
+func WithFieldCondition(condition FieldCondition) FieldOption {
+	return func(s *FieldSettings) error {
+		s.Condition = condition
+		return nil
+	}
+}
+
+// WithDefaultValue emits value when none of the data of a line matches the field, or when a field that always matches
+// formats to nil, rather than omitting the field, to keep downstream schemas stable. E.g. a "request_id" field can emit
+// "unknown" for lines logged outside a request.
+//
+// The default is written as is, for every output format. A field suppressed by its FieldCondition isn't replaced by the
+// default.
+func WithDefaultValue(value any) FieldOption {
+	return func(s *FieldSettings) error {
+		s.Default = value
+		return nil
+	}
+}
+
+// passes reports whether a field with the settings is emitted for the value.
+func (s FieldSettings) passes(args LogLineArgs, value any) bool {
+	return s.Condition == nil || s.Condition(args, value)
+}
+
+// dataMatcher is implemented by fields that can tell whether a datum matches them without formatting it.
+type dataMatcher interface {
+	matchesData(data any) bool
+}
+
+// conditionCheck is the outcome of checkCondition.
+type conditionCheck int
+
+const (
+	// conditionFormat means the datum is formatted, and the field is emitted if it matches.
+	conditionFormat conditionCheck = iota
+	// conditionDeferred means the datum is formatted, and the condition is evaluated if it matches.
+	conditionDeferred
+	// conditionSkip means the datum doesn't match the field.
+	conditionSkip
+	// conditionSuppress means the datum matches the field, but the condition suppresses it.
+	conditionSuppress
+)
+
+// checkCondition evaluates the condition of a data-matching field for the datum, before the datum is formatted.
+func checkCondition(field Field, args LogLineArgs, datum any) conditionCheck {
+	settings := field.Settings()
+	if settings.Condition == nil {
+		return conditionFormat
+	}
+
+	matcher, ok := field.(dataMatcher)
+	switch {
+	case !ok:
+		return conditionDeferred
+	case !matcher.matchesData(datum):
+		return conditionSkip
+	case !settings.passes(args, datum):
+		return conditionSuppress
+	default:
+		return conditionFormat
+	}
+}
+
 type LineArgsField struct {
-	name   string
-	format FieldFormatter
+	name     string
+	format   FieldFormatter
+	settings FieldSettings
 }
 
 type LineArgsFormatter func(args LogLineArgs) (any, error)
 
-func NewLineArgsField(name string, formatter LineArgsFormatter) (Field, error) {
+// NewLineArgsField returns a new Field that is formatted using only the LogLineArgs of the line. LineArgsFields always
+// match and hide their key by default; opts are applied on top of those defaults.
+func NewLineArgsField(name string, formatter LineArgsFormatter, opts ...FieldOption) (Field, error) {
+	settings := FieldSettings{
+		HideKey:     true,
+		AlwaysMatch: true,
+	}
+
+	for _, opt := range opts {
+		if err := opt(&settings); err != nil {
+			return nil, err
+		}
+	}
+
 	return &LineArgsField{
 		name: name,
 		format: func(args LogLineArgs, _ any) (any, error) {
 			return formatter(args)
 		},
+		settings: settings,
 	}, nil
 }
 
@@ -146,10 +284,7 @@ func (f *LineArgsField) Name() string {
 }
 
 func (f *LineArgsField) Settings() FieldSettings {
-	return FieldSettings{
-		HideKey:     true,
-		AlwaysMatch: true,
-	}
+	return f.settings
 }
 
 func (f *LineArgsField) NewFieldFormatter() (FieldFormatter, error) {
@@ -241,10 +376,16 @@ func NewIntField(name string) (Field, error) {
 // OutputFormats:
 //   - OutputFormatText => float64 is formatted as a string with the format '%f'.
 //   - OutputFormatJSON => float64 is formatted as a float64.
+//
+// NaN and ±Inf are handled according to the formatter's NonFiniteFloatPolicy.
 func NewFloatField(name string) (Field, error) {
 	return NewObjectField[float64](
 		name,
 		func(args LogLineArgs, data float64) (any, error) {
+			if isNonFinite(data) {
+				return nonFiniteFloatResult(args, name, data)
+			}
+
 			if args.OutputFormat == OutputFormatText {
 				return strconv.FormatFloat(data, 'f', -1, 64), nil
 			}
@@ -260,16 +401,10 @@ func NewFloatField(name string) (Field, error) {
 // OutputFormats:
 //   - OutputFormatText => time.Duration is formatted as a string with the format %s.
 //   - OutputFormatJSON => time.Duration is formatted as a time.Duration.
+//
+// See NewDurationFieldWithSettings to round durations, or write them in a fixed unit.
 func NewDurationField(name string) (Field, error) {
-	return NewObjectField[time.Duration](
-		name,
-		func(args LogLineArgs, data time.Duration) (any, error) {
-			if args.OutputFormat == OutputFormatText {
-				return data.String(), nil
-			}
-			return data, nil
-		},
-	)
+	return NewDurationFieldWithSettings(name, nil)
 }
 
 // NewErrorField returns a new Field that formats an error into a string. The field will format the error using the
@@ -303,20 +438,65 @@ func NewErrorField(name string) (Field, error) {
 //     only one element, the element is returned in brackets.
 //   - OutputFormatJSON => slice is formatted as a slice.
 func NewArrayField[T any](name string, formatter ObjectFieldFormatter[T]) (Field, error) {
+	return NewArrayFieldWithSettings[T](name, formatter, nil)
+}
+
+// ArrayFieldSettings are the settings for NewArrayFieldWithSettings.
+type ArrayFieldSettings struct {
+	// ElementSeparator separates the elements in text output. Defaults to ", ".
+	ElementSeparator string
+	// Bracket wraps the elements in text output. Defaults to Brackets.Square.
+	Bracket Bracket
+	// MaxElements is the maximum number of elements rendered. The remaining elements are summarized, e.g. as
+	// "...(+3 more)". 0 means no limit.
+	MaxElements int
+}
+
+var defaultArrayFieldSettings = ArrayFieldSettings{
+	ElementSeparator: ", ",
+	Bracket:          Brackets.Square,
+}
+
+func (s *ArrayFieldSettings) mergeDefault() {
+	if s.ElementSeparator == "" {
+		s.ElementSeparator = defaultArrayFieldSettings.ElementSeparator
+	}
+	if s.Bracket == nil {
+		s.Bracket = defaultArrayFieldSettings.Bracket
+	}
+}
+
+// NewArrayFieldWithSettings is NewArrayField, with the text rendering and the number of elements configured by the
+// settings, so that it can match existing log conventions. Nil settings behave like NewArrayField.
+func NewArrayFieldWithSettings[T any](
+	name string,
+	formatter ObjectFieldFormatter[T],
+	settings *ArrayFieldSettings,
+) (Field, error) {
 	if name == "" {
 		return ObjectField[[]T]{}, ErrorEmptyFieldName
 	}
+	if settings == nil {
+		settings = &ArrayFieldSettings{}
+	}
+	settings.mergeDefault()
+	limits := NestingLimits{MaxElements: settings.MaxElements}
+
 	return NewObjectField[[]T](
 		name,
 		func(args LogLineArgs, data []T) (any, error) {
-			res := make([]any, len(data))
+			shown := limits.shown(len(data))
+			res := make([]any, shown, shown+1)
 			var err error
-			for i, v := range data {
+			for i, v := range data[:shown] {
 				res[i], err = formatter(args, v)
 				if err != nil {
 					return nil, err
 				}
 			}
+			if shown < len(data) {
+				res = append(res, moreMarker(len(data)-shown))
+			}
 
 			if args.OutputFormat == OutputFormatText {
 				if len(res) == 0 {
@@ -326,7 +506,7 @@ func NewArrayField[T any](name string, formatter ObjectFieldFormatter[T]) (Field
 				for i, v := range res {
 					stringRes[i] = fmt.Sprintf("%v", v)
 				}
-				return fmt.Sprintf("[%s]", strings.Join(stringRes, ", ")), nil
+				return settings.Bracket.Wrap(strings.Join(stringRes, settings.ElementSeparator)), nil
 			}
 
 			return res, err
@@ -340,11 +520,54 @@ func NewArrayField[T any](name string, formatter ObjectFieldFormatter[T]) (Field
 // If the name is empty or the formatters are nil, an error is returned.
 //
 // OutputFormats:
-//   - OutputFormatText => map is formatted into a string with curly brackets and comma separated key-value pairs. Each
-//     key-value pair is formatted using the keyFormatter and valueFormatter. If the map is empty, an empty string is
-//     returned. If the map has only one key-value pair, the key-value pair is returned in brackets.
+//   - OutputFormatText => map is formatted like %v formats a map, e.g. "map[a:1 b:2]". Each key and value is formatted
+//     using the keyFormatter and valueFormatter.
 //   - OutputFormatJSON => map is formatted as a map.
+//
+// The keys are rendered in sorted order if the formatter sorts map keys; see WithSortedMapKeys.
 func NewMapField[K comparable, V any](name string, keyFormatter ObjectFieldFormatter[K], valueFormatter ObjectFieldFormatter[V]) (Field, error) {
+	return NewMapFieldWithSettings[K, V](name, keyFormatter, valueFormatter, nil)
+}
+
+// MapFieldSettings are the settings for NewMapFieldWithSettings.
+type MapFieldSettings struct {
+	// EntrySeparator separates the key-value pairs in text output. Defaults to " ".
+	EntrySeparator string
+	// KeyValueSeparator separates each key from its value in text output. Defaults to ":".
+	KeyValueSeparator string
+	// Bracket wraps the key-value pairs in text output. Defaults to "map[" and "]", like %v formats a map.
+	Bracket Bracket
+	// MaxElements is the maximum number of key-value pairs rendered. The first pairs in sorted key order are kept, and
+	// the remaining pairs are summarized, e.g. as "...(+3 more)". 0 means no limit.
+	MaxElements int
+}
+
+var defaultMapFieldSettings = MapFieldSettings{
+	EntrySeparator:    " ",
+	KeyValueSeparator: ":",
+	Bracket:           SimpleBracket{"map[", "]"},
+}
+
+func (s *MapFieldSettings) mergeDefault() {
+	if s.EntrySeparator == "" {
+		s.EntrySeparator = defaultMapFieldSettings.EntrySeparator
+	}
+	if s.KeyValueSeparator == "" {
+		s.KeyValueSeparator = defaultMapFieldSettings.KeyValueSeparator
+	}
+	if s.Bracket == nil {
+		s.Bracket = defaultMapFieldSettings.Bracket
+	}
+}
+
+// NewMapFieldWithSettings is NewMapField, with the text rendering and the number of key-value pairs configured by the
+// settings, so that it can match existing log conventions. Nil settings behave like NewMapField.
+func NewMapFieldWithSettings[K comparable, V any](
+	name string,
+	keyFormatter ObjectFieldFormatter[K],
+	valueFormatter ObjectFieldFormatter[V],
+	settings *MapFieldSettings,
+) (Field, error) {
 	if name == "" {
 		return ObjectField[map[K]V]{}, ErrorEmptyFieldName
 	}
@@ -354,6 +577,10 @@ func NewMapField[K comparable, V any](name string, keyFormatter ObjectFieldForma
 	if valueFormatter == nil {
 		return ObjectField[map[K]V]{}, ErrorNilFormatter
 	}
+	if settings == nil {
+		settings = &MapFieldSettings{}
+	}
+	settings.mergeDefault()
 
 	return NewObjectField[map[K]V](
 		name,
@@ -371,17 +598,20 @@ func NewMapField[K comparable, V any](name string, keyFormatter ObjectFieldForma
 				res[key] = value
 			}
 
+			// Keep the same pairs on every line.
+			truncated := settings.MaxElements > 0 && len(res) > settings.MaxElements
+
+			if args.OutputFormat == OutputFormatText || args.SortMapKeys || truncated {
+				return newOrderedMap(res, args.SortMapKeys || truncated, args.OutputFormat != OutputFormatText, settings), nil
+			}
+
 			// At least for JSON (the only currently non-text output format), we need to return a map[string]any.
 			// Otherwise, the JSON formatter will try to marshal the map[any]any into JSON, which will fail.
-			if args.OutputFormat != OutputFormatText {
-				validMap := make(map[string]any)
-				for k, v := range res {
-					validMap[fmt.Sprintf("%v", k)] = v
-				}
-				return validMap, nil
+			validMap := make(map[string]any)
+			for k, v := range res {
+				validMap[fmt.Sprintf("%v", k)] = v
 			}
-
-			return res, nil
+			return validMap, nil
 		},
 	)
 }
@@ -403,7 +633,10 @@ func NewCurrentTimeField(settings *CurrentTimeFieldSettings) Field {
 	currentTimeField, err := NewLineArgsField(
 		settings.Name,
 		func(args LogLineArgs) (any, error) {
-			now := time.Now()
+			now := args.Time
+			if now.IsZero() {
+				now = time.Now()
+			}
 
 			// This would be better if we could inject a fake clock into the field formatter. As is we're wasting a
 			// compare operation here.
@@ -411,14 +644,10 @@ func NewCurrentTimeField(settings *CurrentTimeFieldSettings) Field {
 				now = *settings.fakeNow
 			}
 
-			switch args.OutputFormat {
-			case OutputFormatJSON:
-				return now, nil
-			case OutputFormatText:
+			if args.OutputFormat == OutputFormatText {
 				return now.Format(settings.Format), nil
 			}
-
-			return nil, nil
+			return now, nil
 		},
 	)
 
@@ -458,10 +687,6 @@ func (s *CurrentTimeFieldSettings) mergeDefault() {
 	}
 }
 
-// TODO: May want different behavior when serializing to non-text output formats. Currently we're returning the string
-//  value of the Level. Do we want to keep the brackets? Or maybe we want to output the integer value of the level?
-//  Maybe we just want to make the whole thing configurable? ¯\_(ツ)_/¯
-
 // NewLevelField returns a new Field that formats a level into a string. The field will format the level using the
 // String() method of the level.
 //
@@ -471,7 +696,8 @@ func (s *CurrentTimeFieldSettings) mergeDefault() {
 //
 // OutputFormats:
 //   - OutputFormatText => level is formatted as a string with the format %v and wrapped in the bracket type.
-//   - OutputFormatJSON => level is formatted as a level. Not wrapped in the bracket type.
+//   - OutputFormatJSON => level is formatted as a string, not wrapped in the bracket type, or as a number; see
+//     LevelFieldSettings.JSONValue.
 func NewLevelField(settings *LevelFieldSettings) Field {
 	if settings == nil {
 		settings = &LevelFieldSettings{}
@@ -485,14 +711,30 @@ func NewLevelField(settings *LevelFieldSettings) Field {
 		textLevelStrings[lvl] = settings.Bracket.Wrap(settings.StringsForLevels[lvl])
 	}
 
-	levelField, err := NewLineArgsField(
+	lineArgsField, err := NewLineArgsField(
 		settings.Name,
 		func(args LogLineArgs) (any, error) {
 			if args.OutputFormat == OutputFormatText {
 				return textLevelStrings[args.Level], nil
 			}
-			return settings.StringsForLevels[args.Level], nil
+			profile := settings.Profile
+			if profile == nil {
+				profile = args.SeverityProfile
+			}
+			switch {
+			case settings.JSONValue == LevelJSONSyslog:
+				return args.Level.SyslogSeverity(), nil
+			case settings.JSONValue == LevelJSONNumber && profile != nil:
+				return profile.Numbers[args.Level], nil
+			case settings.JSONValue == LevelJSONNumber:
+				return int(args.Level), nil
+			case profile != nil:
+				return profile.Names[args.Level], nil
+			default:
+				return settings.StringsForLevels[args.Level], nil
+			}
 		},
+		WithCacheable(true),
 	)
 
 	if err != nil {
@@ -500,7 +742,17 @@ func NewLevelField(settings *LevelFieldSettings) Field {
 		return nil
 	}
 
-	return levelField
+	return &levelField{
+		Field:       lineArgsField,
+		textStrings: textLevelStrings,
+	}
+}
+
+// levelField is the Field returned by NewLevelField. Formatters use the precomputed text strings to skip field
+// processing for common layouts.
+type levelField struct {
+	Field
+	textStrings map[any]string
 }
 
 func NewDefaultLevelField() Field {
@@ -519,8 +771,28 @@ type LevelFieldSettings struct {
 	Name             string
 	Bracket          Bracket
 	StringsForLevels map[Level]string
+	// JSONValue is how levels are written in JSON output. Defaults to LevelJSONString. To write a numeric level
+	// alongside the string one, add a second level field with another Name.
+	JSONValue LevelJSONValue
+	// Profile, if set, maps the levels to the severity names and numbers of a target system in JSON output, e.g.
+	// SeverityProfiles.OTel. Defaults to the profile of the formatter, if any; see WithSeverityProfile.
+	Profile *SeverityProfile
 }
 
+// LevelJSONValue is how a field created with NewLevelField writes levels in JSON output.
+type LevelJSONValue int
+
+const (
+	// LevelJSONString writes the string of the level, e.g. "INFO", or its severity name in the SeverityProfile.
+	LevelJSONString LevelJSONValue = iota
+	// LevelJSONNumber writes the Level as a number, e.g. 1 for Info, for consumers that sort and filter levels
+	// numerically. Higher is more severe. With a SeverityProfile, the severity number of the level is written instead.
+	LevelJSONNumber
+	// LevelJSONSyslog writes the syslog severity of the level, e.g. 6 for Info. Lower is more severe. See
+	// Level.SyslogSeverity.
+	LevelJSONSyslog
+)
+
 var defaultLevelFieldSettings = LevelFieldSettings{
 	Name:             "level",
 	Bracket:          Brackets.Angle,
@@ -542,7 +814,7 @@ func (s *LevelFieldSettings) MergeDefault() {
 }
 
 // NewMessageField returns a new Field that formats a message into a string. The field will format the message using the
-// String() method of the message.
+// String() method of the message. The strings of a line are joined into one message, separated by spaces.
 //
 // name: "message"
 //
@@ -550,22 +822,127 @@ func (s *LevelFieldSettings) MergeDefault() {
 //   - OutputFormatText => message is formatted as a string with the format %v.
 //   - OutputFormatJSON => message is formatted as a message.
 func NewMessageField() Field {
-	msgFieldName := "message"
+	return NewMessageFieldWithSettings(nil)
+}
+
+// MessageFieldSettings are the settings for NewMessageFieldWithSettings.
+type MessageFieldSettings struct {
+	// Name is the name of the field, and the key it is written under, e.g. "msg" or "short_message". Defaults to
+	// "message".
+	Name string
+	// ShowKey writes the key of the field in text output, e.g. "message=hello". JSON output always has it.
+	ShowKey bool
+	// MaxLength, if positive, is the maximum length of messages, in characters. Longer messages are cut, and end with
+	// "...".
+	MaxLength int
+	// Separator separates the strings of a line joined into one message. Defaults to " ".
+	Separator string
+	// Policy is how the field handles lines with several strings. Defaults to MessagesJoin.
+	Policy MessagePolicy
+}
+
+// MessagePolicy is how a message field handles lines with several strings. The message field formats every string of
+// a line, so that no other string field swallows extra strings depending on the order of the fields.
+type MessagePolicy int
+
+const (
+	// MessagesJoin joins the strings into one message, separated by the Separator of the settings.
+	MessagesJoin MessagePolicy = iota
+	// MessagesNumbered writes the first string as the message, and the others under the name of the field numbered
+	// from 2, e.g. "message2" and "message3". Their keys are written in text output too.
+	MessagesNumbered
+	// MessagesStrict fails lines with several strings with an ErrorMultipleMessages, to catch callers passing extra
+	// strings by mistake.
+	MessagesStrict
+)
+
+var defaultMessageFieldSettings = MessageFieldSettings{
+	Name:      "message",
+	Separator: " ",
+}
+
+func (s *MessageFieldSettings) mergeDefault() {
+	if s.Name == "" {
+		s.Name = defaultMessageFieldSettings.Name
+	}
+	if s.Separator == "" {
+		s.Separator = defaultMessageFieldSettings.Separator
+	}
+}
+
+// NewMessageFieldWithSettings is NewMessageField, with the key, its visibility, the length of messages and how the
+// strings of a line are joined configured by the settings, since many pipelines require "msg" or "short_message". Nil
+// settings behave like NewMessageField.
+func NewMessageFieldWithSettings(settings *MessageFieldSettings) Field {
+	var s MessageFieldSettings
+	if settings != nil {
+		s = *settings
+	}
+	s.mergeDefault()
 
 	msgField, err := NewObjectField[string](
-		msgFieldName,
+		s.Name,
 		func(args LogLineArgs, msg string) (any, error) {
 			return msg, nil
 		},
-		WithHideKey(true),
+		WithHideKey(!s.ShowKey),
 	)
 
 	if err != nil {
-		printSkippingFieldErr(msgFieldName, err)
+		printSkippingFieldErr(s.Name, err)
 		return nil
 	}
 
-	return msgField
+	return &messageField{ObjectField: msgField, settings: s}
+}
+
+// messageField is the Field returned by NewMessageField. It marks the field as the built-in message field, so
+// formatters can recognize it.
+type messageField struct {
+	ObjectField[string]
+	settings MessageFieldSettings
+}
+
+// joinResults handles the strings of a line according to the MessagePolicy of the field.
+func (f *messageField) joinResults(args LogLineArgs, results []any) ([]joinedResult, error) {
+	msgs := make([]string, len(results))
+	for i, result := range results {
+		msgs[i] = fmt.Sprint(result)
+	}
+
+	switch f.settings.Policy {
+	case MessagesNumbered:
+		joined := make([]joinedResult, len(msgs))
+		for i, msg := range msgs {
+			joined[i] = joinedResult{name: f.settings.Name, value: f.cut(msg)}
+			if i > 0 {
+				joined[i].name += strconv.Itoa(i + 1)
+				joined[i].showKey = true
+			}
+		}
+		return joined, nil
+	case MessagesStrict:
+		if len(msgs) > 1 {
+			return nil, &ErrorMultipleMessages{fieldName: f.settings.Name, count: len(msgs)}
+		}
+	}
+
+	return []joinedResult{{name: f.settings.Name, value: f.cut(strings.Join(msgs, f.settings.Separator))}}, nil
+}
+
+// cut cuts the message to the MaxLength of the field.
+func (f *messageField) cut(msg string) string {
+	if f.settings.MaxLength > 0 && utf8.RuneCountInString(msg) > f.settings.MaxLength {
+		return string([]rune(msg)[:f.settings.MaxLength]) + "..."
+	}
+	return msg
+}
+
+// fastPathCompatible reports whether the field writes messages like the level+message fast path of the text formatter
+// does: without a key, joined with spaces, and not cut.
+func (f *messageField) fastPathCompatible() bool {
+	return !f.settings.ShowKey && f.settings.MaxLength <= 0 && f.settings.Separator == " " &&
+		f.settings.Policy == MessagesJoin
 }
 
 // NewTagField returns a new Field for the logger tag. The field will format the tag using the provided settings.
@@ -596,6 +973,7 @@ func NewTagField(settings *TagFieldSettings) (Field, error) {
 			}
 			return args.Tag, nil
 		},
+		WithCacheable(true),
 	)
 }
 
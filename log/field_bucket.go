@@ -0,0 +1,86 @@
+package log
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// NewLatencyBucketField returns a new Field that formats a time.Duration as the label of the bucket it falls in, for
+// cheap latency distributions straight from the logs. The buckets are their upper bounds, exclusive: with buckets of
+// 10ms, 100ms and 1s, the labels are "<10ms", "10-100ms", "100ms-1s" and ">=1s".
+//
+// If the name is empty, or there are no buckets, or a bucket isn't positive, or two buckets are equal, an error is
+// returned.
+//
+// OutputFormats:
+//   - OutputFormatText => the label of the bucket.
+//   - OutputFormatJSON => the label of the bucket, as a string.
+func NewLatencyBucketField(name string, buckets []time.Duration) (Field, error) {
+	bounds := slices.Clone(buckets)
+	slices.Sort(bounds)
+	switch {
+	case len(bounds) == 0:
+		return nil, &ErrorFieldInitialization{fieldName: name, err: errors.New("no buckets")}
+	case bounds[0] <= 0:
+		return nil, &ErrorFieldInitialization{fieldName: name, err: errors.New("buckets must be positive")}
+	case len(slices.Compact(slices.Clone(bounds))) != len(bounds):
+		return nil, &ErrorFieldInitialization{fieldName: name, err: errors.New("duplicate buckets")}
+	}
+
+	labels := make([]string, len(bounds)+1)
+	labels[0] = "<" + shortDuration(bounds[0])
+	for i := 1; i < len(bounds); i++ {
+		labels[i] = bucketRange(bounds[i-1], bounds[i])
+	}
+	labels[len(bounds)] = ">=" + shortDuration(bounds[len(bounds)-1])
+
+	return NewObjectField[time.Duration](
+		name,
+		func(args LogLineArgs, data time.Duration) (any, error) {
+			i, found := slices.BinarySearch(bounds, data)
+			if found {
+				// Upper bounds are exclusive.
+				i++
+			}
+			return labels[i], nil
+		},
+	)
+}
+
+// bucketRange returns the label of the bucket from lower to upper, with the unit written once if they share it, e.g.
+// "10-100ms".
+func bucketRange(lower, upper time.Duration) string {
+	l, u := shortDuration(lower), shortDuration(upper)
+	lNumber, lUnit := splitDurationUnit(l)
+	_, uUnit := splitDurationUnit(u)
+	if lUnit != "" && lUnit == uUnit {
+		return lNumber + "-" + u
+	}
+	return l + "-" + u
+}
+
+// shortDuration formats the duration like time.Duration.String, without its trailing zero units: "1h" instead of
+// "1h0m0s".
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// splitDurationUnit splits a duration of a single unit, like "100ms", into its number and unit. The unit is empty for
+// durations of several units, like "1m30s".
+func splitDurationUnit(s string) (string, string) {
+	i := strings.LastIndexAny(s, "0123456789") + 1
+	number, unit := s[:i], s[i:]
+	if strings.ContainsAny(number, "hmsµn") {
+		return s, ""
+	}
+	return number, unit
+}
@@ -0,0 +1,58 @@
+package log
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestNewLatencyBucketField(t *testing.T) {
+	field, err := NewLatencyBucketField("latency", []time.Duration{time.Second, 10 * time.Millisecond, 100 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewLatencyBucketField() error = %v", err)
+	}
+	text, _ := NewFormatter(OutputFormatText, []Field{field})
+	jsonFormatter, _ := NewFormatter(OutputFormatJSON, []Field{field})
+
+	tests := []struct {
+		duration time.Duration
+		want     string
+	}{
+		{time.Millisecond, "<10ms"},
+		{10 * time.Millisecond, "10-100ms"},
+		{99 * time.Millisecond, "10-100ms"},
+		{500 * time.Millisecond, "100ms-1s"},
+		{time.Minute, ">=1s"},
+	}
+	for _, tt := range tests {
+		if got := text.FormatLogLine(LogLineArgs{Level: Info}, []any{tt.duration}); string(got.bytes) != "latency="+tt.want {
+			t.Errorf("text(%v) = %s, want latency=%s", tt.duration, got.bytes, tt.want)
+		}
+		var got map[string]string
+		_ = json.Unmarshal(jsonFormatter.FormatLogLine(LogLineArgs{Level: Info}, []any{tt.duration}).bytes, &got)
+		if got["latency"] != tt.want {
+			t.Errorf("json(%v) = %q, want %q", tt.duration, got["latency"], tt.want)
+		}
+	}
+
+	for _, buckets := range [][]time.Duration{nil, {0}, {time.Second, time.Second}} {
+		if _, err := NewLatencyBucketField("latency", buckets); err == nil {
+			t.Errorf("NewLatencyBucketField(%v) succeeded, want an error", buckets)
+		}
+	}
+}
+
+func TestShortDuration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		time.Hour:                  "1h",
+		90 * time.Minute:           "1h30m",
+		time.Minute:                "1m",
+		1500 * time.Microsecond:    "1.5ms",
+		time.Hour + 30*time.Second: "1h0m30s",
+		250 * time.Millisecond:     "250ms",
+	} {
+		if got := shortDuration(d); got != want {
+			t.Errorf("shortDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// maxFieldCacheEntries bounds the number of results a fieldResultCache holds. Level and tag fields only ever produce a
+// handful of distinct results, so hitting this limit means a field was marked cacheable by mistake.
+const maxFieldCacheEntries = 1024
+
+// fieldCacheKey identifies the result of a cacheable field. Only the static parts of the LogLineArgs are part of the
+// key; anything that changes from line to line would make the cache useless.
+type fieldCacheKey struct {
+	fieldName    string
+	level        Level
+	tag          string
+	outputFormat OutputFormat
+}
+
+// fieldResultCache memoizes the results of cacheable AlwaysMatch fields, like the level and tag fields, which produce
+// the same output for the same (level, tag, format) every time. It is safe for concurrent use.
+type fieldResultCache struct {
+	results sync.Map
+	size    atomic.Int64
+}
+
+func newFieldResultCache() *fieldResultCache {
+	return &fieldResultCache{}
+}
+
+func newFieldCacheKey(fieldName string, args LogLineArgs) fieldCacheKey {
+	return fieldCacheKey{
+		fieldName:    fieldName,
+		level:        args.Level,
+		tag:          args.Tag,
+		outputFormat: args.OutputFormat,
+	}
+}
+
+func (c *fieldResultCache) load(key fieldCacheKey) (any, bool) {
+	return c.results.Load(key)
+}
+
+func (c *fieldResultCache) store(key fieldCacheKey, result any) {
+	if c.size.Load() >= maxFieldCacheEntries {
+		return
+	}
+
+	if _, loaded := c.results.LoadOrStore(key, result); !loaded {
+		c.size.Add(1)
+	}
+}
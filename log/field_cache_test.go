@@ -0,0 +1,73 @@
+package log
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestFieldResultCache(t *testing.T) {
+	tests := []struct {
+		name      string
+		cacheable bool
+		lines     []LogLineArgs
+		wantCalls int32
+	}{
+		{
+			name:      "Cacheable field is formatted once per args",
+			cacheable: true,
+			lines:     []LogLineArgs{{Level: Info}, {Level: Info}, {Level: Warn}, {Level: Info}},
+			wantCalls: 2,
+		},
+		{
+			name:      "Tag is part of the key",
+			cacheable: true,
+			lines:     []LogLineArgs{{Level: Info, Tag: "a"}, {Level: Info, Tag: "b"}, {Level: Info, Tag: "a"}},
+			wantCalls: 2,
+		},
+		{
+			name:      "Non-cacheable field is formatted every line",
+			cacheable: false,
+			lines:     []LogLineArgs{{Level: Info}, {Level: Info}, {Level: Info}},
+			wantCalls: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			field, _ := NewLineArgsField(
+				"counted",
+				func(args LogLineArgs) (any, error) {
+					calls.Add(1)
+					return args.Level.String() + args.Tag, nil
+				},
+				WithCacheable(tt.cacheable),
+			)
+
+			f, _ := NewFormatter(OutputFormatText, []Field{field})
+			for _, args := range tt.lines {
+				got := f.FormatLogLine(args, nil)
+				if want := args.Level.String() + args.Tag; string(got.bytes) != want {
+					t.Errorf("FormatLogLine() = %q, want %q", got.bytes, want)
+				}
+			}
+
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("field formatted %d times, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestFieldResultCache_sizeLimit(t *testing.T) {
+	c := newFieldResultCache()
+	for i := 0; i < maxFieldCacheEntries+10; i++ {
+		c.store(fieldCacheKey{fieldName: "f", level: Level(i)}, i)
+	}
+
+	if got := c.size.Load(); got != maxFieldCacheEntries {
+		t.Errorf("cache size = %d, want %d", got, maxFieldCacheEntries)
+	}
+	if _, ok := c.load(fieldCacheKey{fieldName: "f", level: Level(maxFieldCacheEntries + 1)}); ok {
+		t.Errorf("cache stored an entry beyond its size limit")
+	}
+}
@@ -0,0 +1,153 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// compositeField is a field composed of sub-fields, which each format a part of the same datum.
+type compositeField struct {
+	name       string
+	children   []Field
+	formatters []FieldFormatter
+}
+
+// NewCompositeField returns a new Field composed of the children, so that complex objects can be broken down into
+// reusable per-property fields. Every child is given the same datum; the composite field matches the datum if any child
+// without AlwaysMatch matches it, and renders the results of the children that matched.
+//
+// Keys mapped by the formatter, e.g. with WithFieldAlias, apply to the composite field, but not its children.
+//
+// If the name is empty, there are no children, or two children share a name, an error is returned.
+//
+// OutputFormats:
+//   - OutputFormatText => each child is written as a dotted key, e.g. "user.id=1 user.name=alice".
+//   - OutputFormatJSON => the children are formatted as a nested object, keyed by their names.
+func NewCompositeField(name string, children ...Field) (Field, error) {
+	if name == "" {
+		return nil, ErrorEmptyFieldName
+	}
+	if len(children) == 0 {
+		return nil, &ErrorFieldInitialization{fieldName: name, err: errors.New("no child fields")}
+	}
+
+	field := &compositeField{name: name, children: children}
+	seen := map[string]bool{}
+	for _, child := range children {
+		if seen[child.Name()] {
+			return nil, &ErrorFieldInitialization{
+				fieldName: name,
+				err:       fmt.Errorf("duplicate child field %q", child.Name()),
+			}
+		}
+		seen[child.Name()] = true
+
+		formatter, err := child.NewFieldFormatter()
+		if err != nil {
+			return nil, &ErrorFieldFormatterInit{field: child, err: err}
+		}
+		field.formatters = append(field.formatters, formatter)
+	}
+
+	return field, nil
+}
+
+func (f *compositeField) Name() string {
+	return f.name
+}
+
+func (f *compositeField) Settings() FieldSettings {
+	return FieldSettings{}
+}
+
+func (f *compositeField) NewFieldFormatter() (FieldFormatter, error) {
+	return f.format, nil
+}
+
+func (f *compositeField) format(args LogLineArgs, data any) (any, error) {
+	value := &compositeValue{}
+	matched := false
+
+	for i, child := range f.children {
+		check := conditionFormat
+		if child.Settings().AlwaysMatch {
+			if !child.Settings().passes(args, []any{data}) {
+				continue
+			}
+		} else {
+			check = checkCondition(child, args, data)
+		}
+		switch check {
+		case conditionSkip:
+			continue
+		case conditionSuppress:
+			matched = true
+			continue
+		}
+
+		result, err := callFieldFormatter(child, f.formatters[i], args, data)
+		if err != nil {
+			nonFatalError := &ErrorNonFatalFormatterError{}
+			panicError := &ErrorFieldFormatterPanic{}
+			switch {
+			case errors.As(err, &nonFatalError), errors.As(err, &panicError):
+				result = err.Error()
+			case errors.As(err, new(*ErrorInvalidFieldDataType)):
+				continue
+			default:
+				return nil, err
+			}
+		}
+		if result == nil {
+			continue
+		}
+		if check == conditionDeferred && !child.Settings().passes(args, data) {
+			matched = true
+			continue
+		}
+
+		matched = matched || !child.Settings().AlwaysMatch
+		value.names = append(value.names, child.Name())
+		value.values = append(value.values, result)
+		value.settings = append(value.settings, child.Settings())
+	}
+
+	if !matched {
+		return nil, &ErrorInvalidFieldDataType{field: f.name}
+	}
+
+	if args.OutputFormat == OutputFormatText {
+		return value, nil
+	}
+
+	object := make(map[string]any, len(value.names))
+	for i, name := range value.names {
+		object[name] = value.values[i]
+	}
+	return object, nil
+}
+
+// compositeValue is the text result of a composite field: the results of its children, in order.
+type compositeValue struct {
+	names    []string
+	values   []any
+	settings []FieldSettings
+}
+
+// String returns the children as space separated key=value pairs, for when the value is rendered as a whole rather
+// than as dotted keys.
+func (v *compositeValue) String() string {
+	var b strings.Builder
+	for i, name := range v.names {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		if !v.settings[i].HideKey {
+			b.WriteString(name)
+			b.WriteByte('=')
+		}
+		b.WriteString(formatTextValue(v.values[i], NestingLimits{}))
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package log
+
+import "testing"
+
+type compositeTestAddress struct {
+	City string
+}
+
+type compositeTestUser struct {
+	ID      int
+	Name    string
+	Address compositeTestAddress
+}
+
+func TestNewCompositeField(t *testing.T) {
+	idField, _ := NewObjectField[compositeTestUser]("id", func(args LogLineArgs, u compositeTestUser) (any, error) {
+		return u.ID, nil
+	})
+	nameField, _ := NewObjectField[compositeTestUser]("name", func(args LogLineArgs, u compositeTestUser) (any, error) {
+		return u.Name, nil
+	})
+	cityField, _ := NewObjectField[compositeTestUser]("city", func(args LogLineArgs, u compositeTestUser) (any, error) {
+		return u.Address.City, nil
+	})
+	addressField, _ := NewCompositeField("address", cityField)
+	userField, err := NewCompositeField("user", idField, nameField, addressField)
+	if err != nil {
+		t.Fatalf("NewCompositeField() error = %v", err)
+	}
+	countField, _ := NewObjectField[int]("count", func(args LogLineArgs, n int) (any, error) { return n, nil })
+
+	user := compositeTestUser{ID: 1, Name: "alice", Address: compositeTestAddress{City: "Paris"}}
+
+	tests := []struct {
+		name   string
+		format OutputFormat
+		want   string
+	}{
+		{
+			name:   "text",
+			format: OutputFormatText,
+			want:   "user.id=1 user.name=alice user.address.city=Paris count=3",
+		},
+		{
+			name:   "json",
+			format: OutputFormatJSON,
+			want:   `{"count":3,"user":{"address":{"city":"Paris"},"id":1,"name":"alice"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter, _ := NewFormatter(tt.format, []Field{userField, countField})
+
+			// The composite field doesn't match the int, so it is left for the count field.
+			result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{3, user})
+			if result.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", result.err)
+			}
+			if string(result.bytes) != tt.want {
+				t.Errorf("FormatLogLine() = %s, want %s", result.bytes, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCompositeField_invalid(t *testing.T) {
+	child, _ := NewObjectField[int]("child", func(args LogLineArgs, n int) (any, error) { return n, nil })
+
+	if _, err := NewCompositeField("", child); err != ErrorEmptyFieldName {
+		t.Errorf("NewCompositeField() with empty name error = %v, want %v", err, ErrorEmptyFieldName)
+	}
+	if _, err := NewCompositeField("parent"); err == nil {
+		t.Error("NewCompositeField() without children error = nil, want error")
+	}
+	if _, err := NewCompositeField("parent", child, child); err == nil {
+		t.Error("NewCompositeField() with duplicate children error = nil, want error")
+	}
+}
@@ -0,0 +1,145 @@
+package log
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+)
+
+// FixedDecimal is implemented by exact decimal types, like github.com/shopspring/decimal.Decimal. NewDecimalField
+// formats values of these types, and *big.Rat values, without rounding them through a float.
+type FixedDecimal interface {
+	// StringFixed returns the decimal rounded to places digits after the decimal point, e.g. "-12.50".
+	StringFixed(places int32) string
+}
+
+// DecimalFieldSettings are the settings for NewDecimalField.
+type DecimalFieldSettings struct {
+	// Places is the number of digits after the decimal point. Values are rounded half away from zero. 0 rounds to an
+	// integer, e.g. for JPY amounts.
+	Places int
+	// Symbol is written before the amount in text output, e.g. "$" for "$12.50".
+	Symbol string
+	// Code is an ISO 4217 currency code written after the amount in text output, e.g. "USD" for "12.50 USD".
+	Code string
+	// ThousandsSeparator separates groups of three digits in text output, e.g. "," for "1,234.50". Empty means no
+	// grouping.
+	ThousandsSeparator string
+	// DecimalSeparator separates the integer part from the fraction in text output. Defaults to ".".
+	DecimalSeparator string
+	// JSONString writes the amount as a string in JSON output, for consumers that would parse a JSON number into a
+	// float and lose precision. By default, the amount is written as a JSON number with exactly Places digits.
+	JSONString bool
+}
+
+var defaultDecimalFieldSettings = DecimalFieldSettings{
+	Places:           2,
+	DecimalSeparator: ".",
+}
+
+func (s *DecimalFieldSettings) mergeDefault() {
+	if s.DecimalSeparator == "" {
+		s.DecimalSeparator = defaultDecimalFieldSettings.DecimalSeparator
+	}
+	if s.Places < 0 {
+		s.Places = 0
+	}
+}
+
+// decimalField is a field that matches both *big.Rat and FixedDecimal values, which a single ObjectField can't.
+type decimalField struct {
+	name     string
+	format   FieldFormatter
+	settings FieldSettings
+}
+
+func (f *decimalField) Name() string {
+	return f.name
+}
+
+func (f *decimalField) Settings() FieldSettings {
+	return f.settings
+}
+
+func (f *decimalField) NewFieldFormatter() (FieldFormatter, error) {
+	return f.format, nil
+}
+
+func (f *decimalField) matchesData(data any) bool {
+	switch data := data.(type) {
+	case *big.Rat:
+		return data != nil
+	case FixedDecimal:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewDecimalField returns a new Field that formats exact decimal amounts, e.g. financial amounts, without float
+// rounding artifacts. It matches *big.Rat and FixedDecimal values. If settings is nil, amounts are written with 2
+// decimal places and no currency.
+//
+// If the name is empty, an error is returned.
+//
+// OutputFormats:
+//   - OutputFormatText => the amount with the currency symbol and code of the settings, e.g. "$1,234.50 USD".
+//   - OutputFormatJSON => the amount as a number with exactly Places digits, e.g. 1234.50, or a string if JSONString
+//     is set.
+func NewDecimalField(name string, settings *DecimalFieldSettings) (Field, error) {
+	if name == "" {
+		return nil, ErrorEmptyFieldName
+	}
+	if settings == nil {
+		defaults := defaultDecimalFieldSettings
+		settings = &defaults
+	}
+	settings.mergeDefault()
+
+	numberFormat := NumberFormat{
+		ThousandsSeparator: settings.ThousandsSeparator,
+		DecimalSeparator:   settings.DecimalSeparator,
+	}
+
+	return &decimalField{
+		name: name,
+		format: func(args LogLineArgs, data any) (any, error) {
+			var amount string
+			switch data := data.(type) {
+			case *big.Rat:
+				if data == nil {
+					return nil, nil
+				}
+				amount = data.FloatString(settings.Places)
+			case FixedDecimal:
+				amount = data.StringFixed(int32(settings.Places))
+			default:
+				return nil, &ErrorInvalidFieldDataType{field: name}
+			}
+
+			if args.OutputFormat == OutputFormatText {
+				return formatCurrency(numberFormat.localize(amount), settings.Symbol, settings.Code), nil
+			}
+			if settings.JSONString {
+				return amount, nil
+			}
+			return json.Number(amount), nil
+		},
+	}, nil
+}
+
+// formatCurrency adds the currency symbol and code to a formatted amount, keeping the sign in front: "-$12.50 USD".
+func formatCurrency(amount, symbol, code string) string {
+	var b strings.Builder
+	if negative, found := strings.CutPrefix(amount, "-"); found {
+		b.WriteByte('-')
+		amount = negative
+	}
+	b.WriteString(symbol)
+	b.WriteString(amount)
+	if code != "" {
+		b.WriteByte(' ')
+		b.WriteString(code)
+	}
+	return b.String()
+}
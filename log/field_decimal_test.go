@@ -0,0 +1,56 @@
+package log
+
+import (
+	"math/big"
+	"testing"
+)
+
+// testDecimal is a FixedDecimal like shopspring's decimal.Decimal, for a fixed amount in cents.
+type testDecimal struct {
+	cents int64
+}
+
+func (d testDecimal) StringFixed(places int32) string {
+	return new(big.Rat).SetFrac64(d.cents, 100).FloatString(int(places))
+}
+
+func TestNewDecimalField(t *testing.T) {
+	// 0.1 + 0.2 is 0.30000000000000004 as a float.
+	sum := new(big.Rat).Add(big.NewRat(1, 10), big.NewRat(2, 10))
+
+	tests := []struct {
+		name     string
+		settings *DecimalFieldSettings
+		amount   any
+		wantText string
+		wantJSON string
+	}{
+		{"default", nil, sum, "amount=0.30", `{"amount":0.30}`},
+		{"currency", &DecimalFieldSettings{Places: 2, Symbol: "$", Code: "USD", ThousandsSeparator: ","},
+			big.NewRat(123456789, 100), "amount=$1,234,567.89 USD", `{"amount":1234567.89}`},
+		{"negative", &DecimalFieldSettings{Places: 2, Symbol: "€", DecimalSeparator: ","},
+			big.NewRat(-1250, 100), "amount=-€12,50", `{"amount":-12.50}`},
+		{"rounding", &DecimalFieldSettings{Places: 0, Code: "JPY"}, big.NewRat(2995, 10), "amount=300 JPY", `{"amount":300}`},
+		{"fixed decimal", &DecimalFieldSettings{Places: 3}, testDecimal{cents: 1999}, "amount=19.990", `{"amount":19.990}`},
+		{"JSON string", &DecimalFieldSettings{Places: 2, JSONString: true}, sum, "amount=0.30", `{"amount":"0.30"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, err := NewDecimalField("amount", tt.settings)
+			if err != nil {
+				t.Fatalf("NewDecimalField() error = %v", err)
+			}
+
+			for format, want := range map[OutputFormat]string{OutputFormatText: tt.wantText, OutputFormatJSON: tt.wantJSON} {
+				formatter, _ := NewFormatter(format, []Field{field})
+				result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{"unmatched", tt.amount})
+				if result.err != nil {
+					t.Fatalf("FormatLogLine(%s) error = %v", format, result.err)
+				}
+				if string(result.bytes) != want {
+					t.Errorf("FormatLogLine(%s) = %s, want %s", format, result.bytes, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package log
+
+// FieldResults are the results of the fields formatted so far on a line, keyed by field name. The results are in the
+// form of the line's OutputFormat, e.g. a duration field may have formatted its value as a string for text output.
+type FieldResults struct {
+	results map[string]any
+}
+
+// Get returns the result of the field with the name, and false if the field hasn't been formatted on the line.
+func (r FieldResults) Get(name string) (any, bool) {
+	result, ok := r.results[name]
+	return result, ok
+}
+
+// DeriveFunc computes the value of a derived field from the results of the fields before it. A nil value omits the
+// field.
+type DeriveFunc func(args LogLineArgs, results FieldResults) (any, error)
+
+// NewDerivedField returns a new Field whose value is computed from the results of the fields before it in the
+// formatter, e.g. a "latency_bucket" from a "latency_ms" field, or an "is_error" from a status code. Derived fields
+// enrich lines without touching call sites; they always match, and never consume any data.
+//
+// If the name is empty or derive is nil, an error is returned.
+//
+// OutputFormats:
+//   - All OutputFormats => the value returned by derive.
+func NewDerivedField(name string, derive DeriveFunc, opts ...FieldOption) (Field, error) {
+	if name == "" {
+		return nil, ErrorEmptyFieldName
+	}
+	if derive == nil {
+		return nil, ErrorNilFormatter
+	}
+
+	settings := FieldSettings{}
+	for _, opt := range opts {
+		if err := opt(&settings); err != nil {
+			return nil, err
+		}
+	}
+	settings.AlwaysMatch = true
+	settings.Cacheable = false
+	settings.derived = true
+
+	return &LineArgsField{
+		name:     name,
+		settings: settings,
+		format: func(args LogLineArgs, data any) (any, error) {
+			results, ok := data.(FieldResults)
+			if !ok {
+				return nil, &ErrorInvalidFieldDataType{field: name}
+			}
+			return derive(args, results)
+		},
+	}, nil
+}
@@ -0,0 +1,70 @@
+package log
+
+import "testing"
+
+func TestNewDerivedField(t *testing.T) {
+	latencyField, _ := NewObjectField[int]("latency_ms", func(args LogLineArgs, data int) (any, error) {
+		return data, nil
+	})
+	bucketField, err := NewDerivedField("latency_bucket", func(args LogLineArgs, results FieldResults) (any, error) {
+		latency, ok := results.Get("latency_ms")
+		if !ok {
+			return nil, nil
+		}
+		if latency.(int) >= 100 {
+			return "slow", nil
+		}
+		return "fast", nil
+	})
+	if err != nil {
+		t.Fatalf("NewDerivedField() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		format OutputFormat
+		data   []any
+		want   string
+	}{
+		{
+			name:   "text",
+			format: OutputFormatText,
+			data:   []any{"done", 150},
+			want:   "done latency_ms=150 latency_bucket=slow",
+		},
+		{
+			name:   "json",
+			format: OutputFormatJSON,
+			data:   []any{"done", 20},
+			want:   `{"latency_bucket":"fast","latency_ms":20,"message":"done"}`,
+		},
+		{
+			name:   "missing result",
+			format: OutputFormatText,
+			data:   []any{"done"},
+			want:   "done",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter, _ := NewFormatter(tt.format, []Field{NewMessageField(), latencyField, bucketField})
+
+			result := formatter.FormatLogLine(LogLineArgs{Level: Info}, tt.data)
+			if result.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", result.err)
+			}
+			if string(result.bytes) != tt.want {
+				t.Errorf("FormatLogLine() = %s, want %s", result.bytes, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDerivedField_invalid(t *testing.T) {
+	if _, err := NewDerivedField("", func(LogLineArgs, FieldResults) (any, error) { return nil, nil }); err != ErrorEmptyFieldName {
+		t.Errorf("NewDerivedField() error = %v, want %v", err, ErrorEmptyFieldName)
+	}
+	if _, err := NewDerivedField("derived", nil); err != ErrorNilFormatter {
+		t.Errorf("NewDerivedField() error = %v, want %v", err, ErrorNilFormatter)
+	}
+}
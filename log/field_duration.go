@@ -0,0 +1,116 @@
+package log
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DurationJSONFormat is how a field created with NewDurationFieldWithSettings writes durations in JSON output.
+type DurationJSONFormat int
+
+const (
+	// DurationJSONNanos writes durations as an integer number of nanoseconds, like encoding/json writes a
+	// time.Duration.
+	DurationJSONNanos DurationJSONFormat = iota
+	// DurationJSONSeconds writes durations as a number of seconds, e.g. 1.5.
+	DurationJSONSeconds
+	// DurationJSONUnit writes durations as a number of the Unit of the settings, e.g. 1500 for time.Millisecond.
+	DurationJSONUnit
+	// DurationJSONText writes durations as a string, like in text output.
+	DurationJSONText
+)
+
+// DurationFieldSettings are the settings for NewDurationFieldWithSettings.
+type DurationFieldSettings struct {
+	// RoundTo rounds durations to a multiple of it, half away from zero, before they are written, e.g.
+	// time.Millisecond. 0 doesn't round.
+	RoundTo time.Duration
+	// Unit, if set, writes text durations as a number of the unit followed by its symbol, e.g. "1500ms" for
+	// time.Millisecond, instead of Go's mixed units, e.g. "1.5s". It must be one of time.Nanosecond,
+	// time.Microsecond, time.Millisecond, time.Second, time.Minute or time.Hour.
+	Unit time.Duration
+	// JSON is how durations are written in JSON output. Defaults to DurationJSONNanos.
+	JSON DurationJSONFormat
+	// MaxPrecision is the maximum number of digits after the decimal point of durations written as a number of a unit
+	// or of seconds; trailing zeros are trimmed. 0 means no limit; round to the unit with RoundTo to write whole
+	// numbers.
+	MaxPrecision int
+}
+
+// durationUnitSymbols are the symbols of the units of DurationFieldSettings.Unit, as written by time.Duration.String.
+var durationUnitSymbols = map[time.Duration]string{
+	time.Nanosecond:  "ns",
+	time.Microsecond: "µs",
+	time.Millisecond: "ms",
+	time.Second:      "s",
+	time.Minute:      "m",
+	time.Hour:        "h",
+}
+
+// NewDurationFieldWithSettings is NewDurationField, with the rounding, units and JSON representation configured by the
+// settings, for downstream systems that can't parse Go's mixed "1h2m3.4s" durations. Nil settings behave like
+// NewDurationField.
+//
+// If the name is empty, or the Unit is unsupported, an error is returned.
+func NewDurationFieldWithSettings(name string, settings *DurationFieldSettings) (Field, error) {
+	if settings == nil {
+		settings = &DurationFieldSettings{}
+	}
+	s := *settings
+
+	symbol, ok := durationUnitSymbols[s.Unit]
+	if !ok && s.Unit != 0 {
+		return nil, &ErrorFieldInitialization{fieldName: name, err: fmt.Errorf("unsupported duration unit: %v", s.Unit)}
+	}
+	if s.JSON == DurationJSONUnit && s.Unit == 0 {
+		return nil, &ErrorFieldInitialization{fieldName: name, err: fmt.Errorf("DurationJSONUnit requires a Unit")}
+	}
+
+	text := func(d time.Duration) string {
+		if s.Unit == 0 {
+			return d.String()
+		}
+		return formatDurationNumber(d, s.Unit, s.MaxPrecision) + symbol
+	}
+
+	return NewObjectField[time.Duration](
+		name,
+		func(args LogLineArgs, data time.Duration) (any, error) {
+			if s.RoundTo > 0 {
+				data = data.Round(s.RoundTo)
+			}
+
+			if args.OutputFormat == OutputFormatText {
+				return text(data), nil
+			}
+			switch s.JSON {
+			case DurationJSONSeconds:
+				return strconv.ParseFloat(formatDurationNumber(data, time.Second, s.MaxPrecision), 64)
+			case DurationJSONUnit:
+				return strconv.ParseFloat(formatDurationNumber(data, s.Unit, s.MaxPrecision), 64)
+			case DurationJSONText:
+				return text(data), nil
+			default:
+				return data, nil
+			}
+		},
+	)
+}
+
+// formatDurationNumber formats the duration as a number of the unit, with at most precision digits after the decimal
+// point, or as many as needed if precision is 0.
+func formatDurationNumber(d, unit time.Duration, precision int) string {
+	whole, frac := d/unit, d%unit
+	if frac == 0 {
+		return strconv.FormatInt(int64(whole), 10)
+	}
+
+	value := float64(whole) + float64(frac)/float64(unit)
+	if precision <= 0 {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	s := strconv.FormatFloat(value, 'f', precision, 64)
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
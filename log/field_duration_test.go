@@ -0,0 +1,67 @@
+package log
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewDurationFieldWithSettings(t *testing.T) {
+	d := time.Hour + 2*time.Minute + 3456789*time.Microsecond
+
+	tests := []struct {
+		name     string
+		settings *DurationFieldSettings
+		duration time.Duration
+		wantText string
+		wantJSON string
+	}{
+		{"default", nil, d, "took=1h2m3.456789s", `{"took":3723456789000}`},
+		{"rounded", &DurationFieldSettings{RoundTo: time.Second}, d, "took=1h2m3s", `{"took":3723000000000}`},
+		{"milliseconds", &DurationFieldSettings{Unit: time.Millisecond}, d, "took=3723456.789ms", `{"took":3723456789000}`},
+		{
+			"milliseconds, rounded",
+			&DurationFieldSettings{Unit: time.Millisecond, RoundTo: time.Millisecond},
+			d,
+			"took=3723457ms",
+			`{"took":3723457000000}`,
+		},
+		{
+			"seconds",
+			&DurationFieldSettings{JSON: DurationJSONSeconds, MaxPrecision: 3},
+			d,
+			"took=1h2m3.456789s",
+			`{"took":3723.457}`,
+		},
+		{
+			"unit",
+			&DurationFieldSettings{Unit: time.Millisecond, JSON: DurationJSONUnit, MaxPrecision: 1},
+			1500 * time.Microsecond,
+			"took=1.5ms",
+			`{"took":1.5}`,
+		},
+		{"text", &DurationFieldSettings{Unit: time.Second, JSON: DurationJSONText}, 90 * time.Second, "took=90s", `{"took":"90s"}`},
+		{"negative", &DurationFieldSettings{Unit: time.Second}, -1500 * time.Millisecond, "took=-1.5s", `{"took":-1500000000}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, err := NewDurationFieldWithSettings("took", tt.settings)
+			if err != nil {
+				t.Fatalf("NewDurationFieldWithSettings() error = %v", err)
+			}
+
+			for format, want := range map[OutputFormat]string{OutputFormatText: tt.wantText, OutputFormatJSON: tt.wantJSON} {
+				formatter, _ := NewFormatter(format, []Field{field})
+				result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{tt.duration})
+				if string(result.bytes) != want {
+					t.Errorf("FormatLogLine(%s) = %s, want %s", format, result.bytes, want)
+				}
+			}
+		})
+	}
+
+	for _, settings := range []*DurationFieldSettings{{Unit: 3 * time.Second}, {JSON: DurationJSONUnit}} {
+		if _, err := NewDurationFieldWithSettings("took", settings); err == nil {
+			t.Errorf("NewDurationFieldWithSettings(%+v) succeeded, want an error", settings)
+		}
+	}
+}
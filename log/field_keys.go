@@ -0,0 +1,203 @@
+package log
+
+import (
+	"slices"
+	"strconv"
+)
+
+// KeyCollisionPolicy determines what NewFormatter does when two fields would be written under the same key, e.g. a
+// user field named "message" next to the message field. See WithKeyCollisionPolicy.
+type KeyCollisionPolicy int
+
+const (
+	// KeyCollisionError makes NewFormatter return an ErrorKeyCollision. This is the default.
+	KeyCollisionError KeyCollisionPolicy = iota
+	// KeyCollisionSuffix writes the later field under its key with a numeric suffix, e.g. "message_2".
+	KeyCollisionSuffix
+)
+
+// WithKeyCollisionPolicy sets the KeyCollisionPolicy of the formatter.
+//
+// Keys collide if two fields are written under the same key, after aliases and schema keys are applied. In text
+// output, only fields that don't hide their key can collide.
+func WithKeyCollisionPolicy(policy KeyCollisionPolicy) FormatterOption {
+	return func(f LogLineFormatter) LogLineFormatter {
+		if keys := formatterKeys(f); keys != nil {
+			keys.collisions = policy
+		}
+		return f
+	}
+}
+
+// fieldKeys is the key mapping stage of the text and JSON formatters: it maps the name of a field to the keys it is
+// written under. Formatter options configure it, and NewFormatter builds it once all options are applied. The keys of
+// every field are computed up front, so mapping them costs a single map lookup per field.
+type fieldKeys struct {
+	aliases    map[string]string
+	schema     *SchemaVersion
+	collisions KeyCollisionPolicy
+	normalize  func(key string) string
+	err        error // The first error of an option that configured the stage. Returned by NewFormatter.
+
+	keys map[string][]string // The keys of the fields that aren't written under just their name. Nil if there are none.
+}
+
+// setErr records the error of an option, unless an earlier option already failed.
+func (k *fieldKeys) setErr(err error) {
+	if k.err == nil {
+		k.err = err
+	}
+}
+
+// build computes the keys of the fields, and resolves collisions between them according to the KeyCollisionPolicy. A
+// field that shares its name with an earlier field is replaced in fields with a renamedField if its key is suffixed,
+// since the fields of a formatter are told apart by name.
+func (k *fieldKeys) build(fields []Field, outputFormat OutputFormat) error {
+	if k.err != nil {
+		return k.err
+	}
+
+	names := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		names[field.Name()] = true
+	}
+
+	k.keys = nil
+	used := make(map[string]bool, len(fields))
+	seen := make(map[string]bool, len(fields))
+
+	for i, field := range fields {
+		name := field.Name()
+		keys := k.mapName(name)
+		suffixed := false
+
+		// Fields with a hidden key are written without one in text output, so they can't collide.
+		if outputFormat != OutputFormatText || !field.Settings().HideKey {
+			for j, key := range keys {
+				if used[key] {
+					if k.collisions != KeyCollisionSuffix {
+						return &ErrorKeyCollision{fieldName: name, key: key}
+					}
+					keys[j] = suffixedKey(key, used, names)
+					suffixed = suffixed || j == 0
+				}
+				used[keys[j]] = true
+			}
+		}
+
+		if seen[name] && suffixed {
+			name = keys[0]
+			names[name] = true
+			fields[i] = &renamedField{Field: field, name: name}
+		}
+		seen[name] = true
+
+		if len(keys) == 1 && keys[0] == name {
+			continue
+		}
+		if k.keys == nil {
+			k.keys = map[string][]string{}
+		}
+		k.keys[name] = keys
+	}
+
+	return nil
+}
+
+// suffixedKey returns the first of key_2, key_3, ... that isn't used as a key or a field name.
+func suffixedKey(key string, used, names map[string]bool) string {
+	for n := 2; ; n++ {
+		suffixed := key + "_" + strconv.Itoa(n)
+		if !used[suffixed] && !names[suffixed] {
+			return suffixed
+		}
+	}
+}
+
+// renamedField is a field that shares its name with an earlier field of the formatter, renamed to its suffixed key.
+type renamedField struct {
+	Field
+	name string
+}
+
+func (f *renamedField) Name() string {
+	return f.name
+}
+
+func (k *fieldKeys) mapName(name string) []string {
+	key := name
+	alias, explicit := k.aliases[name]
+	if explicit {
+		key = alias
+	}
+
+	var legacyKeys []string
+	if k.schema != nil {
+		legacyKeys = k.schema.LegacyKeys[key]
+		if mapped, ok := k.schema.Keys[key]; ok {
+			key = mapped
+			explicit = true
+		}
+	}
+
+	if !explicit && k.normalize != nil {
+		if normalized := k.normalize(key); normalized != "" {
+			key = normalized
+		}
+	}
+
+	keys := []string{key}
+	for _, legacyKey := range legacyKeys {
+		if !slices.Contains(keys, legacyKey) {
+			keys = append(keys, legacyKey)
+		}
+	}
+	return keys
+}
+
+// lookup returns the keys of the field, and false if the field is written under just its name.
+func (k *fieldKeys) lookup(name string) ([]string, bool) {
+	keys, ok := k.keys[name]
+	return keys, ok
+}
+
+// schemaVersion returns the version of the schema the keys follow, if any.
+func (k *fieldKeys) schemaVersion() string {
+	if k.schema == nil {
+		return ""
+	}
+	return k.schema.Version
+}
+
+// formatterKeys returns the key mapping stage of the text or JSON formatter wrapped by f, or nil if there is none.
+func formatterKeys(f LogLineFormatter) *fieldKeys {
+	if tf, ok := unwrapFormatter[*textFormatter](f); ok {
+		return &tf.Keys
+	}
+	if jf, ok := unwrapFormatter[*jsonFormatter](f); ok {
+		return &jf.Keys
+	}
+	return nil
+}
+
+// WithFieldAlias writes the field named oldName under newName, so the same field definitions can satisfy consumers
+// that expect different keys (msg vs message, ts vs timestamp). Aliases are applied before the keys of a schema
+// selected with WithSchema. If the field is aliased more than once, the last alias applies.
+func WithFieldAlias(oldName, newName string) FormatterOption {
+	return func(f LogLineFormatter) LogLineFormatter {
+		keys := formatterKeys(f)
+		if keys == nil {
+			return f
+		}
+
+		if newName == "" {
+			keys.setErr(&ErrorInvalidFieldAlias{oldName: oldName, newName: newName})
+			return f
+		}
+		if keys.aliases == nil {
+			keys.aliases = map[string]string{}
+		}
+		keys.aliases[oldName] = newName
+		return f
+	}
+}
@@ -0,0 +1,157 @@
+package log
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithFieldAlias(t *testing.T) {
+	timeField, _ := NewLineArgsField("timestamp", func(args LogLineArgs) (any, error) {
+		return "2024-01-02", nil
+	}, WithHideKey(false))
+	fields := []Field{timeField, NewMessageField()}
+
+	tests := []struct {
+		name   string
+		format OutputFormat
+		opts   []FormatterOption
+		want   string
+	}{
+		{
+			name:   "json",
+			format: OutputFormatJSON,
+			opts:   []FormatterOption{WithFieldAlias("message", "msg"), WithFieldAlias("timestamp", "ts")},
+			want:   `{"msg":"hello","ts":"2024-01-02"}`,
+		},
+		{
+			name:   "text",
+			format: OutputFormatText,
+			opts:   []FormatterOption{WithFieldAlias("timestamp", "ts")},
+			want:   `ts=2024-01-02 hello`,
+		},
+		{
+			name:   "last alias applies",
+			format: OutputFormatJSON,
+			opts:   []FormatterOption{WithFieldAlias("message", "msg"), WithFieldAlias("message", "text")},
+			want:   `{"text":"hello","timestamp":"2024-01-02"}`,
+		},
+		{
+			name:   "unknown field",
+			format: OutputFormatJSON,
+			opts:   []FormatterOption{WithFieldAlias("level", "lvl")},
+			want:   `{"message":"hello","timestamp":"2024-01-02"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter, err := NewFormatter(tt.format, fields, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+
+			result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{"hello"})
+			if result.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", result.err)
+			}
+			if string(result.bytes) != tt.want {
+				t.Errorf("FormatLogLine() = %s, want %s", result.bytes, tt.want)
+			}
+		})
+	}
+
+	t.Run("empty alias", func(t *testing.T) {
+		var invalid *ErrorInvalidFieldAlias
+		if _, err := NewFormatter(OutputFormatJSON, fields, WithFieldAlias("message", "")); !errors.As(err, &invalid) {
+			t.Errorf("NewFormatter() error = %v, want ErrorInvalidFieldAlias", err)
+		}
+	})
+
+	t.Run("with schema", func(t *testing.T) {
+		schema, _ := NewLogSchema(SchemaVersion{Version: "1", Keys: map[string]string{"ts": "time"}})
+		formatter, _ := NewFormatter(OutputFormatJSON, fields, WithFieldAlias("timestamp", "ts"), WithSchema(schema, "1"))
+
+		result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{"hello"})
+		if want := `{"message":"hello","time":"2024-01-02"}`; string(result.bytes) != want {
+			t.Errorf("FormatLogLine() = %s, want %s", result.bytes, want)
+		}
+	})
+}
+
+func TestWithKeyCollisionPolicy(t *testing.T) {
+	userMessageField, _ := NewObjectField[*ErrorKeyCollision]("message", func(args LogLineArgs, data *ErrorKeyCollision) (any, error) {
+		return data.key, nil
+	}, WithHideKey(false))
+	msgField, _ := NewIntField("msg")
+
+	tests := []struct {
+		name    string
+		format  OutputFormat
+		fields  []Field
+		opts    []FormatterOption
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "duplicate name",
+			format:  OutputFormatJSON,
+			fields:  []Field{NewMessageField(), userMessageField},
+			wantErr: true,
+		},
+		{
+			name:    "alias onto existing key",
+			format:  OutputFormatJSON,
+			fields:  []Field{msgField, NewMessageField()},
+			opts:    []FormatterOption{WithFieldAlias("message", "msg")},
+			wantErr: true,
+		},
+		{
+			name:   "duplicate name with suffix",
+			format: OutputFormatJSON,
+			fields: []Field{NewMessageField(), userMessageField},
+			opts:   []FormatterOption{WithKeyCollisionPolicy(KeyCollisionSuffix)},
+			want:   `{"message":"hello","message_2":"k"}`,
+		},
+		{
+			name:   "alias with suffix",
+			format: OutputFormatJSON,
+			fields: []Field{msgField, NewMessageField()},
+			opts:   []FormatterOption{WithFieldAlias("message", "msg"), WithKeyCollisionPolicy(KeyCollisionSuffix)},
+			want:   `{"msg":42,"msg_2":"hello"}`,
+		},
+		{
+			name:   "text hidden keys don't collide",
+			format: OutputFormatText,
+			fields: []Field{NewDefaultLevelField(), NewMessageField(), NewDefaultLevelField()},
+			want:   `<INFO> hello <INFO>`,
+		},
+		{
+			name:    "text visible keys collide",
+			format:  OutputFormatText,
+			fields:  []Field{msgField, msgField},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter, err := NewFormatter(tt.format, tt.fields, tt.opts...)
+			if tt.wantErr {
+				var collision *ErrorKeyCollision
+				if !errors.As(err, &collision) {
+					t.Errorf("NewFormatter() error = %v, want ErrorKeyCollision", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+
+			result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{"hello", 42, &ErrorKeyCollision{key: "k"}})
+			if result.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", result.err)
+			}
+			if string(result.bytes) != tt.want {
+				t.Errorf("FormatLogLine() = %s, want %s", result.bytes, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package log
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MemStatsDetail is how many of the runtime.MemStats a field created with NewMemStatsField writes.
+type MemStatsDetail int
+
+const (
+	// MemStatsBasic writes the allocated heap, the number of GC cycles, and the total GC pause time.
+	MemStatsBasic MemStatsDetail = iota
+	// MemStatsHeap also writes the breakdown of the heap: in use, idle, released to the OS, and its number of objects,
+	// and the memory obtained from the OS.
+	MemStatsHeap
+	// MemStatsFull also writes the cumulative allocations and frees, the heap size of the next GC cycle, and the pause
+	// time of the last one.
+	MemStatsFull
+)
+
+// memStatKind is how a memory stat is written in text output.
+type memStatKind int
+
+const (
+	memStatBytes memStatKind = iota
+	memStatCount
+	memStatNanos
+)
+
+// memStat is one of the runtime.MemStats written by a mem stats field.
+type memStat struct {
+	name     string // The key in text output.
+	jsonName string // The key in JSON output, with the unit of the value.
+	kind     memStatKind
+	detail   MemStatsDetail // The least detail the stat is written at.
+	value    func(m *runtime.MemStats) uint64
+}
+
+// memStats are the stats written by mem stats fields, in order.
+var memStats = []memStat{
+	{"heap_alloc", "heap_alloc_bytes", memStatBytes, MemStatsBasic, func(m *runtime.MemStats) uint64 {
+		return m.HeapAlloc
+	}},
+	{"num_gc", "num_gc", memStatCount, MemStatsBasic, func(m *runtime.MemStats) uint64 {
+		return uint64(m.NumGC)
+	}},
+	{"gc_pause_total", "gc_pause_total_ns", memStatNanos, MemStatsBasic, func(m *runtime.MemStats) uint64 {
+		return m.PauseTotalNs
+	}},
+	{"heap_inuse", "heap_inuse_bytes", memStatBytes, MemStatsHeap, func(m *runtime.MemStats) uint64 {
+		return m.HeapInuse
+	}},
+	{"heap_idle", "heap_idle_bytes", memStatBytes, MemStatsHeap, func(m *runtime.MemStats) uint64 {
+		return m.HeapIdle
+	}},
+	{"heap_released", "heap_released_bytes", memStatBytes, MemStatsHeap, func(m *runtime.MemStats) uint64 {
+		return m.HeapReleased
+	}},
+	{"heap_objects", "heap_objects", memStatCount, MemStatsHeap, func(m *runtime.MemStats) uint64 {
+		return m.HeapObjects
+	}},
+	{"sys", "sys_bytes", memStatBytes, MemStatsHeap, func(m *runtime.MemStats) uint64 {
+		return m.Sys
+	}},
+	{"total_alloc", "total_alloc_bytes", memStatBytes, MemStatsFull, func(m *runtime.MemStats) uint64 {
+		return m.TotalAlloc
+	}},
+	{"mallocs", "mallocs", memStatCount, MemStatsFull, func(m *runtime.MemStats) uint64 {
+		return m.Mallocs
+	}},
+	{"frees", "frees", memStatCount, MemStatsFull, func(m *runtime.MemStats) uint64 {
+		return m.Frees
+	}},
+	{"next_gc", "next_gc_bytes", memStatBytes, MemStatsFull, func(m *runtime.MemStats) uint64 {
+		return m.NextGC
+	}},
+	{"gc_pause_last", "gc_pause_last_ns", memStatNanos, MemStatsFull, func(m *runtime.MemStats) uint64 {
+		if m.NumGC == 0 {
+			return 0
+		}
+		return m.PauseNs[(m.NumGC+255)%256]
+	}},
+}
+
+// MemStatsFieldSettings are the settings for NewMemStatsField.
+type MemStatsFieldSettings struct {
+	// Name is the name of the field. Defaults to "mem".
+	Name string
+	// Detail is how many stats are written. Defaults to MemStatsBasic.
+	Detail MemStatsDetail
+	// HeartbeatsOnly writes the stats on the heartbeats of WithHeartbeat only, instead of on every line.
+	HeartbeatsOnly bool
+}
+
+var defaultMemStatsFieldSettings = MemStatsFieldSettings{
+	Name: "mem",
+}
+
+func (s *MemStatsFieldSettings) mergeDefault() {
+	if s.Name == "" {
+		s.Name = defaultMemStatsFieldSettings.Name
+	}
+}
+
+// NewMemStatsField returns a new Field with the memory and GC stats of the runtime (see runtime.MemStats) when the line
+// is formatted, e.g. to track the memory of memory-sensitive services on their heartbeats.
+//
+// Reading the stats briefly stops the world, so prefer HeartbeatsOnly, or a formatter for infrequent lines, over
+// writing them on every line of a busy logger.
+//
+// OutputFormats:
+//   - OutputFormatText => space separated key=value pairs, with sizes in binary units and times as durations, e.g.
+//     "heap_alloc=12.5MiB num_gc=42 gc_pause_total=3.2ms".
+//   - OutputFormatJSON => an object of the stats, with their unit in their keys, e.g.
+//     {"heap_alloc_bytes":13107200,"num_gc":42,"gc_pause_total_ns":3200000}.
+func NewMemStatsField(settings *MemStatsFieldSettings) (Field, error) {
+	s := MemStatsFieldSettings{}
+	if settings != nil {
+		s = *settings
+	}
+	s.mergeDefault()
+
+	var stats []memStat
+	for _, stat := range memStats {
+		if stat.detail <= s.Detail {
+			stats = append(stats, stat)
+		}
+	}
+
+	opts := []FieldOption{WithHideKey(false)}
+	if s.HeartbeatsOnly {
+		opts = append(opts, WithFieldCondition(onHeartbeats))
+	}
+
+	return NewLineArgsField(
+		s.Name,
+		func(args LogLineArgs) (any, error) {
+			var m runtime.MemStats
+			runtime.ReadMemStats(&m)
+
+			if args.OutputFormat == OutputFormatText {
+				var b strings.Builder
+				for i, stat := range stats {
+					if i > 0 {
+						b.WriteByte(' ')
+					}
+					b.WriteString(stat.name)
+					b.WriteByte('=')
+					b.WriteString(stat.text(stat.value(&m)))
+				}
+				return b.String(), nil
+			}
+
+			object := make(map[string]any, len(stats))
+			for _, stat := range stats {
+				object[stat.jsonName] = stat.value(&m)
+			}
+			return object, nil
+		},
+		opts...,
+	)
+}
+
+// text returns the value of the stat for text output.
+func (s memStat) text(value uint64) string {
+	switch s.kind {
+	case memStatBytes:
+		return formatBinarySize(value)
+	case memStatNanos:
+		return time.Duration(value).String()
+	default:
+		return strconv.FormatUint(value, 10)
+	}
+}
+
+// formatBinarySize formats the number of bytes in the largest binary unit it has at least one of, with at most one
+// digit after the decimal point, e.g. "12.5MiB".
+func formatBinarySize(n uint64) string {
+	const units = "KMGTPE"
+	if n < 1024 {
+		return strconv.FormatUint(n, 10) + "B"
+	}
+
+	value, unit := float64(n)/1024, 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	return strconv.FormatFloat(float64(int64(value*10+0.5))/10, 'f', -1, 64) + units[unit:unit+1] + "iB"
+}
@@ -0,0 +1,92 @@
+package log
+
+import (
+	"encoding/json"
+	"maps"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestNewMemStatsField(t *testing.T) {
+	tests := []struct {
+		detail MemStatsDetail
+		text   string
+		keys   []string
+	}{
+		{
+			MemStatsBasic,
+			`^mem=heap_alloc=[0-9.]+[KMG]?i?B num_gc=\d+ gc_pause_total=[0-9.]+\S*s$`,
+			[]string{"gc_pause_total_ns", "heap_alloc_bytes", "num_gc"},
+		},
+		{
+			MemStatsHeap,
+			`^mem=heap_alloc=\S+ num_gc=\d+ gc_pause_total=\S+ heap_inuse=\S+ heap_idle=\S+ heap_released=\S+ ` +
+				`heap_objects=\d+ sys=\S+$`,
+			[]string{
+				"gc_pause_total_ns", "heap_alloc_bytes", "heap_idle_bytes", "heap_inuse_bytes", "heap_objects",
+				"heap_released_bytes", "num_gc", "sys_bytes",
+			},
+		},
+		{
+			MemStatsFull,
+			`^mem=heap_alloc=\S+ .* total_alloc=\S+ mallocs=\d+ frees=\d+ next_gc=\S+ gc_pause_last=\S+$`,
+			[]string{
+				"frees", "gc_pause_last_ns", "gc_pause_total_ns", "heap_alloc_bytes", "heap_idle_bytes",
+				"heap_inuse_bytes", "heap_objects", "heap_released_bytes", "mallocs", "next_gc_bytes", "num_gc",
+				"sys_bytes", "total_alloc_bytes",
+			},
+		},
+	}
+	for _, tt := range tests {
+		field, err := NewMemStatsField(&MemStatsFieldSettings{Detail: tt.detail})
+		if err != nil {
+			t.Fatalf("NewMemStatsField() error = %v", err)
+		}
+
+		text, _ := NewFormatter(OutputFormatText, []Field{field})
+		if got := string(text.FormatLogLine(LogLineArgs{}, nil).bytes); !regexp.MustCompile(tt.text).MatchString(got) {
+			t.Errorf("detail %d: text = %q, want match for %q", tt.detail, got, tt.text)
+		}
+
+		object, _ := NewFormatter(OutputFormatJSON, []Field{field})
+		var line map[string]map[string]uint64
+		if err := json.Unmarshal(object.FormatLogLine(LogLineArgs{}, nil).bytes, &line); err != nil {
+			t.Fatalf("detail %d: json.Unmarshal() error = %v", tt.detail, err)
+		}
+		if got := slices.Sorted(maps.Keys(line["mem"])); !slices.Equal(got, tt.keys) {
+			t.Errorf("detail %d: keys = %v, want %v", tt.detail, got, tt.keys)
+		}
+	}
+}
+
+func TestNewMemStatsField_heartbeatsOnly(t *testing.T) {
+	field, _ := NewMemStatsField(&MemStatsFieldSettings{HeartbeatsOnly: true})
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField(), field})
+
+	if got := string(formatter.FormatLogLine(LogLineArgs{}, []any{"hello"}).bytes); got != "hello" {
+		t.Errorf("FormatLogLine() = %q, want %q", got, "hello")
+	}
+	got := string(formatter.FormatLogLine(LogLineArgs{}, []any{"alive", &Heartbeat{}}).bytes)
+	if !regexp.MustCompile(`^alive mem=heap_alloc=`).MatchString(got) {
+		t.Errorf("FormatLogLine() = %q, want the stats on the heartbeat", got)
+	}
+}
+
+func TestFormatBinarySize(t *testing.T) {
+	tests := []struct {
+		n    uint64
+		want string
+	}{
+		{0, "0B"},
+		{1023, "1023B"},
+		{1024, "1KiB"},
+		{13107200, "12.5MiB"},
+		{3 << 30, "3GiB"},
+	}
+	for _, tt := range tests {
+		if got := formatBinarySize(tt.n); got != tt.want {
+			t.Errorf("formatBinarySize(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
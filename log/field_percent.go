@@ -0,0 +1,56 @@
+package log
+
+import "strconv"
+
+// PercentOption configures a field created with NewPercentField.
+type PercentOption func(s *percentSettings)
+
+type percentSettings struct {
+	clamp    bool
+	min, max float64
+}
+
+// WithPercentClamp clamps the ratio to [min, max] before it is written, in all output formats. Use it for ratios that
+// must stay in range even when the measurement is off, e.g. WithPercentClamp(0, 1) so a rounding error never logs as
+// "100.1%".
+func WithPercentClamp(min, max float64) PercentOption {
+	return func(s *percentSettings) {
+		s.clamp = true
+		s.min, s.max = min, max
+	}
+}
+
+// NewPercentField returns a new Field that formats a float64 ratio as a percentage: 0.425 is "42.5%". decimals is the
+// number of digits after the decimal point; -1 writes as many as needed.
+//
+// If the name is empty, an error is returned.
+//
+// OutputFormats:
+//   - OutputFormatText => the ratio is formatted as a percentage, e.g. "42.5%".
+//   - OutputFormatJSON => the ratio is formatted as a float64, e.g. 0.425.
+//
+// NaN and ±Inf are handled according to the formatter's NonFiniteFloatPolicy.
+func NewPercentField(name string, decimals int, opts ...PercentOption) (Field, error) {
+	settings := &percentSettings{}
+	for _, opt := range opts {
+		opt(settings)
+	}
+
+	return NewObjectField[float64](
+		name,
+		func(args LogLineArgs, data float64) (any, error) {
+			if isNonFinite(data) {
+				return nonFiniteFloatResult(args, name, data)
+			}
+
+			if settings.clamp {
+				data = min(max(data, settings.min), settings.max)
+			}
+
+			if args.OutputFormat == OutputFormatText {
+				return strconv.FormatFloat(data*100, 'f', decimals, 64) + "%", nil
+			}
+			return data, nil
+		},
+	)
+}
@@ -0,0 +1,41 @@
+package log
+
+import "testing"
+
+func TestNewPercentField(t *testing.T) {
+	tests := []struct {
+		name     string
+		decimals int
+		opts     []PercentOption
+		ratio    float64
+		wantText string
+		wantJSON string
+	}{
+		{"one decimal", 1, nil, 0.425, "cpu=42.5%", `{"cpu":0.425}`},
+		{"no decimals", 0, nil, 0.425, "cpu=42%", `{"cpu":0.425}`},
+		{"shortest", -1, nil, 0.12345, "cpu=12.345%", `{"cpu":0.12345}`},
+		{"over 100%", 1, nil, 1.5, "cpu=150.0%", `{"cpu":1.5}`},
+		{"clamped", 1, []PercentOption{WithPercentClamp(0, 1)}, 1.001, "cpu=100.0%", `{"cpu":1}`},
+		{"clamped below", 1, []PercentOption{WithPercentClamp(0, 1)}, -0.2, "cpu=0.0%", `{"cpu":0}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, err := NewPercentField("cpu", tt.decimals, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewPercentField() error = %v", err)
+			}
+
+			for format, want := range map[OutputFormat]string{OutputFormatText: tt.wantText, OutputFormatJSON: tt.wantJSON} {
+				formatter, _ := NewFormatter(format, []Field{field})
+				result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{tt.ratio})
+				if string(result.bytes) != want {
+					t.Errorf("FormatLogLine(%s) = %s, want %s", format, result.bytes, want)
+				}
+			}
+		})
+	}
+
+	if _, err := NewPercentField("", 1); err == nil {
+		t.Error("NewPercentField() with an empty name succeeded, want an error")
+	}
+}
@@ -0,0 +1,65 @@
+package log
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// RuntimeStatsFieldSettings are the settings for NewRuntimeStatsField.
+type RuntimeStatsFieldSettings struct {
+	// Name is the name of the field. Defaults to "runtime".
+	Name string
+	// HeartbeatsOnly writes the stats on the heartbeats of WithHeartbeat only, instead of on every line.
+	HeartbeatsOnly bool
+}
+
+var defaultRuntimeStatsFieldSettings = RuntimeStatsFieldSettings{
+	Name: "runtime",
+}
+
+func (s *RuntimeStatsFieldSettings) mergeDefault() {
+	if s.Name == "" {
+		s.Name = defaultRuntimeStatsFieldSettings.Name
+	}
+}
+
+// NewRuntimeStatsField returns a new Field with the scheduler stats of the runtime when the line is formatted: the
+// number of goroutines, of CPUs, GOMAXPROCS, and the number of cgo calls made by the process, e.g. to spot goroutine
+// leaks on heartbeats or diagnostic lines. Unlike NewMemStatsField, reading them doesn't stop the world.
+//
+// OutputFormats:
+//   - OutputFormatText => space separated key=value pairs, e.g. "goroutines=12 cpus=8 gomaxprocs=8 cgo_calls=0".
+//   - OutputFormatJSON => an object of the stats, e.g. {"cgo_calls":0,"cpus":8,"gomaxprocs":8,"goroutines":12}.
+func NewRuntimeStatsField(settings *RuntimeStatsFieldSettings) (Field, error) {
+	s := RuntimeStatsFieldSettings{}
+	if settings != nil {
+		s = *settings
+	}
+	s.mergeDefault()
+
+	opts := []FieldOption{WithHideKey(false)}
+	if s.HeartbeatsOnly {
+		opts = append(opts, WithFieldCondition(onHeartbeats))
+	}
+
+	return NewLineArgsField(
+		s.Name,
+		func(args LogLineArgs) (any, error) {
+			goroutines, cpus, maxProcs := runtime.NumGoroutine(), runtime.NumCPU(), runtime.GOMAXPROCS(0)
+			cgoCalls := runtime.NumCgoCall()
+
+			if args.OutputFormat == OutputFormatText {
+				return fmt.Sprintf(
+					"goroutines=%d cpus=%d gomaxprocs=%d cgo_calls=%d", goroutines, cpus, maxProcs, cgoCalls,
+				), nil
+			}
+			return map[string]any{
+				"goroutines": goroutines,
+				"cpus":       cpus,
+				"gomaxprocs": maxProcs,
+				"cgo_calls":  cgoCalls,
+			}, nil
+		},
+		opts...,
+	)
+}
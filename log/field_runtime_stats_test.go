@@ -0,0 +1,52 @@
+package log
+
+import (
+	"encoding/json"
+	"regexp"
+	"runtime"
+	"testing"
+)
+
+func TestNewRuntimeStatsField(t *testing.T) {
+	field, err := NewRuntimeStatsField(nil)
+	if err != nil {
+		t.Fatalf("NewRuntimeStatsField() error = %v", err)
+	}
+
+	text, _ := NewFormatter(OutputFormatText, []Field{field})
+	got := string(text.FormatLogLine(LogLineArgs{}, nil).bytes)
+	want := `^runtime=goroutines=\d+ cpus=\d+ gomaxprocs=\d+ cgo_calls=\d+$`
+	if !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("text = %q, want match for %q", got, want)
+	}
+
+	object, _ := NewFormatter(OutputFormatJSON, []Field{field})
+	var line struct {
+		Runtime struct {
+			Goroutines int   `json:"goroutines"`
+			CPUs       int   `json:"cpus"`
+			GOMAXPROCS int   `json:"gomaxprocs"`
+			CgoCalls   int64 `json:"cgo_calls"`
+		} `json:"runtime"`
+	}
+	if err := json.Unmarshal(object.FormatLogLine(LogLineArgs{}, nil).bytes, &line); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if line.Runtime.Goroutines < 1 || line.Runtime.CPUs != runtime.NumCPU() ||
+		line.Runtime.GOMAXPROCS != runtime.GOMAXPROCS(0) {
+		t.Errorf("runtime = %+v, want the stats of the runtime", line.Runtime)
+	}
+}
+
+func TestNewRuntimeStatsField_heartbeatsOnly(t *testing.T) {
+	field, _ := NewRuntimeStatsField(&RuntimeStatsFieldSettings{Name: "sched", HeartbeatsOnly: true})
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField(), field})
+
+	if got := string(formatter.FormatLogLine(LogLineArgs{}, []any{"hello"}).bytes); got != "hello" {
+		t.Errorf("FormatLogLine() = %q, want %q", got, "hello")
+	}
+	got := string(formatter.FormatLogLine(LogLineArgs{}, []any{"alive", &Heartbeat{}}).bytes)
+	if !regexp.MustCompile(`^alive sched=goroutines=\d+ `).MatchString(got) {
+		t.Errorf("FormatLogLine() = %q, want the stats on the heartbeat", got)
+	}
+}
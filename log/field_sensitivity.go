@@ -0,0 +1,67 @@
+package log
+
+// Sensitivity classifies the data of a field, e.g. to keep PII out of destinations that aren't allowed to store it.
+// Classes are ordered from least to most sensitive. See WithSensitivity and WithMaxSensitivity.
+type Sensitivity int
+
+const (
+	// SensitivityPublic is data that may be shared outside the organization. This is the default class of fields.
+	SensitivityPublic Sensitivity = iota
+	// SensitivityInternal is data that may be shared inside the organization.
+	SensitivityInternal
+	// SensitivityPII is personally identifiable information, e.g. emails and IP addresses.
+	SensitivityPII
+	// SensitivitySecret is data that must never be logged in the clear, e.g. credentials.
+	SensitivitySecret
+)
+
+func (s Sensitivity) String() string {
+	switch s {
+	case SensitivityPublic:
+		return "public"
+	case SensitivityInternal:
+		return "internal"
+	case SensitivityPII:
+		return "pii"
+	case SensitivitySecret:
+		return "secret"
+	default:
+		return "unknown"
+	}
+}
+
+// WithSensitivity classifies the data of the field. See [FieldSettings.Sensitivity].
+func WithSensitivity(sensitivity Sensitivity) FieldOption {
+	return func(s *FieldSettings) error {
+		s.Sensitivity = sensitivity
+		return nil
+	}
+}
+
+// WithMaxSensitivity strips the fields classified above max from the lines of the formatter, so that e.g. PII fields are written to
+// an encrypted audit destination, but not to stdout:
+//
+//	auditFormatter, _ := log.NewFormatter(log.OutputFormatJSON, fields)
+//	stdoutFormatter, _ := log.NewFormatter(log.OutputFormatText, fields, log.WithMaxSensitivity(log.SensitivityInternal))
+//
+// The policy is enforced by the formatter of each destination, whatever the fields it's given. Stripped fields still
+// match their data, so that it isn't picked up by the fields after them; only their results are dropped. If the option
+// is applied several times, the lowest maximum applies. Default=no maximum.
+func WithMaxSensitivity(max Sensitivity) FormatterOption {
+	lowest := func(current *Sensitivity) *Sensitivity {
+		if current != nil && *current < max {
+			return current
+		}
+		return &max
+	}
+
+	return func(f LogLineFormatter) LogLineFormatter {
+		if tf, ok := unwrapFormatter[*textFormatter](f); ok {
+			tf.MaxSensitivity = lowest(tf.MaxSensitivity)
+		}
+		if jf, ok := unwrapFormatter[*jsonFormatter](f); ok {
+			jf.MaxSensitivity = lowest(jf.MaxSensitivity)
+		}
+		return f
+	}
+}
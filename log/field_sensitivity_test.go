@@ -0,0 +1,123 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+)
+
+type testEmail string
+
+func TestWithMaxSensitivity(t *testing.T) {
+	emailField, err := NewObjectField[testEmail](
+		"email",
+		func(args LogLineArgs, email testEmail) (any, error) { return string(email), nil },
+		WithSensitivity(SensitivityPII),
+	)
+	if err != nil {
+		t.Fatalf("NewObjectField() error = %v", err)
+	}
+	fields := []Field{NewDefaultLevelField(), NewMessageField(), emailField}
+
+	audit, stdout := &bytes.Buffer{}, &bytes.Buffer{}
+	auditFormatter, _ := NewFormatter(OutputFormatJSON, fields)
+	stdoutFormatter, _ := NewFormatter(OutputFormatText, fields, WithMaxSensitivity(SensitivityInternal))
+
+	logger, _ := NewLoggerWithOptions(
+		WithAsync(false),
+		WithDestination(audit, auditFormatter),
+		WithDestination(stdout, stdoutFormatter),
+	)
+	logger.Info("signed up", testEmail("ann@example.com"))
+
+	if got, want := audit.String(), `{"email":"ann@example.com","level":"INFO","message":"signed up"}`+"\n"; got != want {
+		t.Errorf("audit = %q, want %q", got, want)
+	}
+	if got, want := stdout.String(), "<INFO> signed up\n"; got != want {
+		t.Errorf("stdout = %q, want %q", got, want)
+	}
+}
+
+type testSSN string
+
+func TestWithMaxSensitivity_strippedFieldKeepsItsData(t *testing.T) {
+	ssnField, err := NewObjectField[string](
+		"ssn",
+		func(args LogLineArgs, ssn string) (any, error) { return ssn, nil },
+		WithSensitivity(SensitivityPII),
+	)
+	if err != nil {
+		t.Fatalf("NewObjectField() error = %v", err)
+	}
+	typedSSNField, err := NewObjectField[testSSN](
+		"ssn",
+		func(args LogLineArgs, ssn testSSN) (any, error) { return string(ssn), nil },
+		WithSensitivity(SensitivityPII),
+	)
+	if err != nil {
+		t.Fatalf("NewObjectField() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		outputFormat OutputFormat
+		ssnField     Field
+		data         []any
+		want         string
+	}{
+		// The string field matches both strings, as it does without the maximum, so the message field has none.
+		{"json", OutputFormatJSON, ssnField, []any{"123-45-6789", "user created"}, `{"level":"INFO"}` + "\n"},
+		{"text", OutputFormatText, ssnField, []any{"123-45-6789", "user created"}, "<INFO>\n"},
+		{
+			"typed",
+			OutputFormatJSON,
+			typedSSNField,
+			[]any{testSSN("123-45-6789"), "user created"},
+			`{"level":"INFO","message":"user created"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := []Field{NewDefaultLevelField(), tt.ssnField, NewMessageField()}
+			formatter, _ := NewFormatter(tt.outputFormat, fields, WithMaxSensitivity(SensitivityInternal))
+			out := &bytes.Buffer{}
+			logger, _ := NewLoggerWithOptions(WithAsync(false), WithDestination(out, formatter))
+			logger.Info(tt.data...)
+
+			if got := out.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithMaxSensitivity_csvColumns(t *testing.T) {
+	ssnField, _ := NewObjectField[string](
+		"ssn",
+		func(args LogLineArgs, ssn string) (any, error) { return ssn, nil },
+		WithSensitivity(SensitivityPII),
+	)
+	formatter, _ := NewFormatter(
+		OutputFormatCSV,
+		[]Field{NewDefaultLevelField(), ssnField, NewMessageField()},
+		WithMaxSensitivity(SensitivitySecret),
+		WithMaxSensitivity(SensitivityInternal),
+	)
+
+	if header, _ := CSVHeader(formatter); header != "level,message" {
+		t.Errorf("CSVHeader() = %q, want %q", header, "level,message")
+	}
+}
+
+func TestSensitivity_String(t *testing.T) {
+	for s, want := range map[Sensitivity]string{
+		SensitivityPublic:   "public",
+		SensitivityInternal: "internal",
+		SensitivityPII:      "pii",
+		SensitivitySecret:   "secret",
+		Sensitivity(42):     "unknown",
+	} {
+		if got := s.String(); got != want {
+			t.Errorf("%d.String() = %q, want %q", int(s), got, want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package log
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// TableFieldSettings are the settings for NewTableField.
+type TableFieldSettings struct {
+	// Columns are the names of the struct fields shown as columns, in order. Defaults to all exported fields.
+	Columns []string
+	// MaxRows is the maximum number of rows rendered. The remaining rows are summarized, e.g. as "...(+3 more rows)".
+	// Defaults to 10.
+	MaxRows int
+	// MaxCellWidth is the maximum width of a cell, in runes. Longer values are cut short with "…". Defaults to 40.
+	MaxCellWidth int
+}
+
+var defaultTableFieldSettings = TableFieldSettings{
+	MaxRows:      10,
+	MaxCellWidth: 40,
+}
+
+func (s *TableFieldSettings) mergeDefault() {
+	if s.MaxRows <= 0 {
+		s.MaxRows = defaultTableFieldSettings.MaxRows
+	}
+	if s.MaxCellWidth <= 0 {
+		s.MaxCellWidth = defaultTableFieldSettings.MaxCellWidth
+	}
+}
+
+// NewTableField returns a new Field that formats a slice of structs (or pointers to structs) of type T as a table in
+// text output, with a column per struct field. Logging a handful of records during development is then readable,
+// instead of a long bracketed blob.
+//
+// If the name is empty, T isn't a struct or pointer to a struct, or a column isn't an exported field of T, an error
+// is returned.
+//
+// OutputFormats:
+//   - OutputFormatText => the slice is formatted as a table starting on a new line, with a header row.
+//   - OutputFormatJSON => the slice is formatted as a slice.
+func NewTableField[T any](name string, settings *TableFieldSettings) (Field, error) {
+	if settings == nil {
+		settings = &TableFieldSettings{}
+	}
+	settings.mergeDefault()
+
+	rowType := reflect.TypeFor[T]()
+	if rowType.Kind() == reflect.Pointer {
+		rowType = rowType.Elem()
+	}
+	if rowType.Kind() != reflect.Struct {
+		return nil, &ErrorFieldInitialization{
+			fieldName: name,
+			err:       fmt.Errorf("table rows must be structs, got %v", rowType),
+		}
+	}
+
+	columns := settings.Columns
+	if len(columns) == 0 {
+		for i := range rowType.NumField() {
+			if field := rowType.Field(i); field.IsExported() {
+				columns = append(columns, field.Name)
+			}
+		}
+	}
+
+	indices := make([][]int, len(columns))
+	for i, column := range columns {
+		field, ok := rowType.FieldByName(column)
+		if !ok || !field.IsExported() {
+			return nil, &ErrorFieldInitialization{
+				fieldName: name,
+				err:       fmt.Errorf("%v has no exported field %q", rowType, column),
+			}
+		}
+		indices[i] = field.Index
+	}
+
+	table := &tableRenderer{columns: columns, indices: indices, settings: *settings}
+
+	return NewObjectField[[]T](
+		name,
+		func(args LogLineArgs, data []T) (any, error) {
+			if args.OutputFormat == OutputFormatText {
+				return table.render(reflect.ValueOf(data)), nil
+			}
+			return data, nil
+		},
+	)
+}
+
+// tableRenderer renders the rows of a table field.
+type tableRenderer struct {
+	columns  []string
+	indices  [][]int // Index of each column's struct field, for reflect.Value.FieldByIndex.
+	settings TableFieldSettings
+}
+
+func (t *tableRenderer) render(rows reflect.Value) string {
+	shown := min(rows.Len(), t.settings.MaxRows)
+
+	cells := make([][]string, 0, shown+1)
+	cells = append(cells, t.columns)
+	for i := range shown {
+		cells = append(cells, t.rowCells(rows.Index(i)))
+	}
+
+	widths := make([]int, len(t.columns))
+	for _, row := range cells {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range cells {
+		b.WriteByte('\n')
+		for i, cell := range row {
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			b.WriteString(cell)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+			}
+		}
+	}
+	if shown < rows.Len() {
+		b.WriteByte('\n')
+		fmt.Fprintf(&b, "...(+%d more rows)", rows.Len()-shown)
+	}
+
+	return b.String()
+}
+
+func (t *tableRenderer) rowCells(row reflect.Value) []string {
+	cells := make([]string, len(t.indices))
+
+	if row.Kind() == reflect.Pointer {
+		if row.IsNil() {
+			for i := range cells {
+				cells[i] = "-"
+			}
+			return cells
+		}
+		row = row.Elem()
+	}
+
+	for i, index := range t.indices {
+		field, err := row.FieldByIndexErr(index)
+		if err != nil {
+			// A nil embedded pointer on the path to the field.
+			cells[i] = "-"
+			continue
+		}
+		cells[i] = t.truncate(formatTextValue(field.Interface(), NestingLimits{}))
+	}
+
+	return cells
+}
+
+// truncate cuts a cell short at the max cell width, and keeps it on a single line.
+func (t *tableRenderer) truncate(cell string) string {
+	cell = strings.ReplaceAll(cell, "\n", " ")
+	if utf8.RuneCountInString(cell) <= t.settings.MaxCellWidth {
+		return cell
+	}
+	runes := []rune(cell)
+	return string(runes[:t.settings.MaxCellWidth-1]) + "…"
+}
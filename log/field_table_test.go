@@ -0,0 +1,67 @@
+package log
+
+import (
+	"testing"
+)
+
+type tableTestUser struct {
+	ID     int
+	Name   string
+	Admin  bool
+	secret string
+}
+
+func TestNewTableField(t *testing.T) {
+	users := []tableTestUser{
+		{ID: 1, Name: "alice", Admin: true, secret: "x"},
+		{ID: 2, Name: "bob"},
+		{ID: 300, Name: "a very long name that is cut short"},
+	}
+
+	tests := []struct {
+		name     string
+		settings *TableFieldSettings
+		want     string
+	}{
+		{
+			name: "default",
+			want: "users=\n" +
+				"ID   Name                                Admin\n" +
+				"1    alice                               true\n" +
+				"2    bob                                 false\n" +
+				"300  a very long name that is cut short  false",
+		},
+		{
+			name:     "columns and limits",
+			settings: &TableFieldSettings{Columns: []string{"Name", "ID"}, MaxRows: 2, MaxCellWidth: 4},
+			want: "users=\n" +
+				"Name  ID\n" +
+				"ali…  1\n" +
+				"bob   2\n" +
+				"...(+1 more rows)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, err := NewTableField[tableTestUser]("users", tt.settings)
+			if err != nil {
+				t.Fatalf("NewTableField() error = %v", err)
+			}
+			formatter, _ := NewFormatter(OutputFormatText, []Field{field})
+
+			result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{users})
+			if string(result.bytes) != tt.want {
+				t.Errorf("FormatLogLine() = %q, want %q", result.bytes, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTableField_invalid(t *testing.T) {
+	if _, err := NewTableField[int]("ints", nil); err == nil {
+		t.Error("NewTableField[int]() error = nil, want error")
+	}
+	if _, err := NewTableField[*tableTestUser]("users", &TableFieldSettings{Columns: []string{"secret"}}); err == nil {
+		t.Error("NewTableField() with unexported column error = nil, want error")
+	}
+}
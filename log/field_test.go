@@ -8,6 +8,7 @@ import (
     "net/http"
     "net/url"
     "os"
+    "slices"
     "testing"
     "time"
 )
@@ -483,6 +484,29 @@ func TestLevelField(t *testing.T) {
     }
 }
 
+func TestLevelField_JSONValue(t *testing.T) {
+    tests := []struct {
+        name      string
+        jsonValue LevelJSONValue
+        want      string
+    }{
+        {"String", LevelJSONString, `{"level":"WARN","message":"hi"}`},
+        {"Number", LevelJSONNumber, `{"level":2,"message":"hi"}`},
+        {"Syslog", LevelJSONSyslog, `{"level":4,"message":"hi"}`},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            levelField := NewLevelField(&LevelFieldSettings{JSONValue: tt.jsonValue})
+            formatter, _ := NewFormatter(OutputFormatJSON, []Field{levelField, NewMessageField()})
+
+            result := formatter.FormatLogLine(LogLineArgs{Level: Warn}, []any{"hi"})
+            if string(result.bytes) != tt.want {
+                t.Errorf("FormatLogLine() = %s, want %s", result.bytes, tt.want)
+            }
+        })
+    }
+}
+
 func TestDateTimeField(t *testing.T) {
     tests := []struct {
         name                     string
@@ -697,3 +721,405 @@ func Test_QuickTest(t *testing.T) {
         fmt.Println(buf.String())
     })
 }
+
+func TestNewArrayFieldWithSettings(t *testing.T) {
+    identity := func(args LogLineArgs, data int) (any, error) { return data, nil }
+
+    tests := []struct {
+        name     string
+        format   OutputFormat
+        settings *ArrayFieldSettings
+        want     string
+    }{
+        {
+            name:   "Default",
+            format: OutputFormatText,
+            want:   "ints=[1, 2, 3, 4]",
+        },
+        {
+            name:     "Separator and Bracket",
+            format:   OutputFormatText,
+            settings: &ArrayFieldSettings{ElementSeparator: "|", Bracket: Brackets.Round},
+            want:     "ints=(1|2|3|4)",
+        },
+        {
+            name:     "Max Elements",
+            format:   OutputFormatText,
+            settings: &ArrayFieldSettings{MaxElements: 2},
+            want:     "ints=[1, 2, ...(+2 more)]",
+        },
+        {
+            name:     "Max Elements JSON",
+            format:   OutputFormatJSON,
+            settings: &ArrayFieldSettings{MaxElements: 2, Bracket: Brackets.Round},
+            want:     `{"ints":[1,2,"...(+2 more)"]}`,
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            field, err := NewArrayFieldWithSettings[int]("ints", identity, tt.settings)
+            if err != nil {
+                t.Fatalf("NewArrayFieldWithSettings() error = %v", err)
+            }
+            formatter, _ := NewFormatter(tt.format, []Field{field})
+
+            result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{[]int{1, 2, 3, 4}})
+            if string(result.bytes) != tt.want {
+                t.Errorf("FormatLogLine() = %s, want %s", result.bytes, tt.want)
+            }
+        })
+    }
+}
+
+func TestNewMapFieldWithSettings(t *testing.T) {
+    identity := func(args LogLineArgs, data string) (any, error) { return data, nil }
+    data := map[string]string{"b": "2", "a": "1", "c": "3"}
+
+    tests := []struct {
+        name     string
+        format   OutputFormat
+        settings *MapFieldSettings
+        want     string
+    }{
+        {
+            name:   "Default",
+            format: OutputFormatText,
+            want:   "m=map[a:1 b:2 c:3]",
+        },
+        {
+            name:   "Separators and Bracket",
+            format: OutputFormatText,
+            settings: &MapFieldSettings{
+                EntrySeparator:    ", ",
+                KeyValueSeparator: "=",
+                Bracket:           Brackets.Curly,
+            },
+            want: "m={a=1, b=2, c=3}",
+        },
+        {
+            name:     "Max Elements",
+            format:   OutputFormatText,
+            settings: &MapFieldSettings{MaxElements: 2},
+            want:     "m=map[a:1 b:2 ...(+1 more)]",
+        },
+        {
+            name:     "Max Elements JSON",
+            format:   OutputFormatJSON,
+            settings: &MapFieldSettings{MaxElements: 2},
+            want:     `{"m":{"a":"1","b":"2","...":"(+1 more)"}}`,
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            field, err := NewMapFieldWithSettings[string, string]("m", identity, identity, tt.settings)
+            if err != nil {
+                t.Fatalf("NewMapFieldWithSettings() error = %v", err)
+            }
+            formatter, _ := NewFormatter(tt.format, []Field{field})
+
+            result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{data})
+            if string(result.bytes) != tt.want {
+                t.Errorf("FormatLogLine() = %s, want %s", result.bytes, tt.want)
+            }
+        })
+    }
+}
+
+func TestWithFieldCondition(t *testing.T) {
+    latencyField, _ := NewObjectField[time.Duration](
+        "latency",
+        func(args LogLineArgs, data time.Duration) (any, error) {
+            return data.String(), nil
+        },
+        WithFieldCondition(func(args LogLineArgs, value any) bool {
+            return value.(time.Duration) > 100*time.Millisecond
+        }),
+    )
+    stackField, _ := NewLineArgsField(
+        "stack",
+        func(args LogLineArgs) (any, error) {
+            return "stack", nil
+        },
+        WithFieldCondition(func(args LogLineArgs, value any) bool {
+            return args.Level >= Error
+        }),
+    )
+
+    formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField(), latencyField, stackField})
+
+    tests := []struct {
+        name  string
+        level Level
+        data  []any
+        want  string
+    }{
+        {
+            name:  "Suppressed",
+            level: Info,
+            data:  []any{"fast", 50 * time.Millisecond},
+            want:  "fast",
+        },
+        {
+            name:  "Value Passes",
+            level: Info,
+            data:  []any{"slow", 150 * time.Millisecond},
+            want:  "slow latency=150ms",
+        },
+        {
+            name:  "Level Passes",
+            level: Error,
+            data:  []any{"failed", 50 * time.Millisecond},
+            want:  "failed stack",
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            result := formatter.FormatLogLine(LogLineArgs{Level: tt.level}, tt.data)
+            if string(result.bytes) != tt.want {
+                t.Errorf("FormatLogLine() = %q, want %q", result.bytes, tt.want)
+            }
+        })
+    }
+}
+
+func TestWithFieldCondition_beforeFormatting(t *testing.T) {
+    formatted := 0
+    latencyField, _ := NewObjectField[time.Duration](
+        "latency",
+        func(args LogLineArgs, data time.Duration) (any, error) {
+            formatted++
+            return data.String(), nil
+        },
+        WithFieldCondition(func(args LogLineArgs, value any) bool {
+            return value.(time.Duration) > 100*time.Millisecond
+        }),
+    )
+    requestField, _ := NewLineArgsField(
+        "request",
+        func(args LogLineArgs) (any, error) {
+            formatted++
+            return "request", nil
+        },
+        WithFieldCondition(func(args LogLineArgs, value any) bool {
+            data, _ := value.([]any)
+            return slices.Contains(data, any("GET"))
+        }),
+    )
+
+    formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField(), latencyField, requestField})
+
+    result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{"fast", 50 * time.Millisecond})
+    if string(result.bytes) != "fast" {
+        t.Errorf("FormatLogLine() = %q, want %q", result.bytes, "fast")
+    }
+    if formatted != 0 {
+        t.Errorf("suppressed fields were formatted %d times, want 0", formatted)
+    }
+
+    result = formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{"GET", 150 * time.Millisecond})
+    if want := "GET latency=150ms request"; string(result.bytes) != want {
+        t.Errorf("FormatLogLine() = %q, want %q", result.bytes, want)
+    }
+}
+
+func TestWithDefaultValue(t *testing.T) {
+    requestIDField, _ := NewObjectField[int](
+        "request_id",
+        func(args LogLineArgs, data int) (any, error) {
+            return data, nil
+        },
+        WithDefaultValue("unknown"),
+    )
+
+    tests := []struct {
+        name   string
+        format OutputFormat
+        data   []any
+        want   string
+    }{
+        {
+            name:   "Matched",
+            format: OutputFormatText,
+            data:   []any{"hello", 42},
+            want:   "hello request_id=42",
+        },
+        {
+            name:   "Missing",
+            format: OutputFormatText,
+            data:   []any{"hello"},
+            want:   "hello request_id=unknown",
+        },
+        {
+            name:   "Missing JSON",
+            format: OutputFormatJSON,
+            data:   []any{"hello"},
+            want:   `{"message":"hello","request_id":"unknown"}`,
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            formatter, _ := NewFormatter(tt.format, []Field{NewMessageField(), requestIDField})
+
+            result := formatter.FormatLogLine(LogLineArgs{Level: Info}, tt.data)
+            if string(result.bytes) != tt.want {
+                t.Errorf("FormatLogLine() = %q, want %q", result.bytes, tt.want)
+            }
+        })
+    }
+}
+
+func TestWithDefaultValue_alwaysMatch(t *testing.T) {
+    requestID := ""
+    requestIDField, _ := NewLineArgsField(
+        "request_id",
+        func(args LogLineArgs) (any, error) {
+            if requestID == "" {
+                return nil, nil
+            }
+            return requestID, nil
+        },
+        WithDefaultValue("unknown"),
+    )
+    formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField(), requestIDField})
+
+    result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{"hello"})
+    if want := "hello unknown"; string(result.bytes) != want {
+        t.Errorf("FormatLogLine() = %q, want %q", result.bytes, want)
+    }
+
+    requestID = "abc"
+    result = formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{"hello"})
+    if want := "hello abc"; string(result.bytes) != want {
+        t.Errorf("FormatLogLine() = %q, want %q", result.bytes, want)
+    }
+}
+
+func TestLevelField_Profile(t *testing.T) {
+    tests := []struct {
+        name     string
+        settings *LevelFieldSettings
+        opts     []FormatterOption
+        want     string
+    }{
+        {"Field Names", &LevelFieldSettings{Profile: SeverityProfiles.Stackdriver}, nil, `{"level":"WARNING"}`},
+        {
+            "Field Numbers",
+            &LevelFieldSettings{Profile: SeverityProfiles.OTel, JSONValue: LevelJSONNumber},
+            nil,
+            `{"level":13}`,
+        },
+        {
+            "Formatter",
+            &LevelFieldSettings{JSONValue: LevelJSONNumber},
+            []FormatterOption{WithSeverityProfile(SeverityProfiles.Python)},
+            `{"level":30}`,
+        },
+        {
+            "Field Over Formatter",
+            &LevelFieldSettings{Profile: SeverityProfiles.Syslog},
+            []FormatterOption{WithSeverityProfile(SeverityProfiles.Python)},
+            `{"level":"warning"}`,
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            formatter, _ := NewFormatter(OutputFormatJSON, []Field{NewLevelField(tt.settings)}, tt.opts...)
+
+            result := formatter.FormatLogLine(LogLineArgs{Level: Warn}, nil)
+            if string(result.bytes) != tt.want {
+                t.Errorf("FormatLogLine() = %s, want %s", result.bytes, tt.want)
+            }
+        })
+    }
+
+    // Text output keeps the level strings.
+    formatter, _ := NewFormatter(
+        OutputFormatText,
+        []Field{NewLevelField(nil), NewMessageField()},
+        WithSeverityProfile(SeverityProfiles.Stackdriver),
+    )
+    if result := formatter.FormatLogLine(LogLineArgs{Level: Warn}, []any{"hi"}); string(result.bytes) != "<WARN> hi" {
+        t.Errorf("FormatLogLine() = %s, want <WARN> hi", result.bytes)
+    }
+}
+
+func TestMessageFieldWithSettings(t *testing.T) {
+    tests := []struct {
+        name     string
+        settings *MessageFieldSettings
+        format   OutputFormat
+        data     []any
+        want     string
+    }{
+        {"Default Text", nil, OutputFormatText, []any{"a", "b"}, "<INFO> a b"},
+        {"Default JSON", nil, OutputFormatJSON, []any{"a", "b"}, `{"level":"INFO","message":"a b"}`},
+        {"Name", &MessageFieldSettings{Name: "msg"}, OutputFormatJSON, []any{"hi"}, `{"level":"INFO","msg":"hi"}`},
+        {"Show Key", &MessageFieldSettings{Name: "msg", ShowKey: true}, OutputFormatText, []any{"hi"}, "<INFO> msg=hi"},
+        {"Separator", &MessageFieldSettings{Separator: "; "}, OutputFormatText, []any{"a", "b"}, "<INFO> a; b"},
+        {"Max Length", &MessageFieldSettings{MaxLength: 5}, OutputFormatText, []any{"héllo world"}, "<INFO> héllo..."},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            fields := []Field{NewLevelField(nil), NewMessageFieldWithSettings(tt.settings)}
+            formatter, _ := NewFormatter(tt.format, fields)
+
+            result := formatter.FormatLogLine(LogLineArgs{Level: Info}, tt.data)
+            if string(result.bytes) != tt.want {
+                t.Errorf("FormatLogLine() = %s, want %s", result.bytes, tt.want)
+            }
+        })
+    }
+}
+
+func TestMessageFieldWithSettings_LogMsg(t *testing.T) {
+    buf := &bytes.Buffer{}
+    fields := []Field{NewLevelField(nil), NewMessageFieldWithSettings(&MessageFieldSettings{MaxLength: 3})}
+    formatter, _ := NewFormatter(OutputFormatText, fields)
+    logger, _ := NewLoggerWithOptions(WithAsync(false), WithDestination(buf, formatter))
+
+    logger.InfoMsg("hello")
+
+    if got, want := buf.String(), "<INFO> hel...\n"; got != want {
+        t.Errorf("line = %q, want %q", got, want)
+    }
+}
+
+func TestMessageFieldWithSettings_Policy(t *testing.T) {
+    tests := []struct {
+        name    string
+        policy  MessagePolicy
+        format  OutputFormat
+        want    string
+        wantErr bool
+    }{
+        {"Join", MessagesJoin, OutputFormatJSON, `{"message":"a b c"}`, false},
+        {"Numbered JSON", MessagesNumbered, OutputFormatJSON, `{"message":"a","message2":"b","message3":"c"}`, false},
+        {"Numbered Text", MessagesNumbered, OutputFormatText, "a message2=b message3=c", false},
+        {"Strict", MessagesStrict, OutputFormatJSON, "", true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            fields := []Field{NewMessageFieldWithSettings(&MessageFieldSettings{Policy: tt.policy})}
+            formatter, _ := NewFormatter(tt.format, fields)
+
+            result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{"a", "b", "c"})
+            if tt.wantErr {
+                if !errors.As(result.err, new(*ErrorMultipleMessages)) {
+                    t.Errorf("FormatLogLine() error = %v, want an ErrorMultipleMessages", result.err)
+                }
+                return
+            }
+            if string(result.bytes) != tt.want {
+                t.Errorf("FormatLogLine() = %s, want %s", result.bytes, tt.want)
+            }
+        })
+    }
+
+    // A single string passes in strict mode.
+    formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageFieldWithSettings(&MessageFieldSettings{
+        Policy: MessagesStrict,
+    })})
+    if result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{"a"}); result.err != nil {
+        t.Errorf("FormatLogLine() error = %v, want nil", result.err)
+    }
+}
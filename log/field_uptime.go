@@ -0,0 +1,90 @@
+package log
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// processStarted is when the package was initialized, which is as close to the start of the process as it gets.
+var processStarted = time.Now()
+
+// UptimeOrigin is the start of the time logged by an uptime field.
+type UptimeOrigin int
+
+const (
+	// UptimeSinceLogger logs the time since the logger was created. Lines formatted without a logger fall back to
+	// UptimeSinceProcess.
+	UptimeSinceLogger UptimeOrigin = iota
+	// UptimeSinceProcess logs the time since the process started.
+	UptimeSinceProcess
+)
+
+// UptimeFieldSettings are the settings for NewUptimeField.
+type UptimeFieldSettings struct {
+	// Name is the name of the field. Defaults to "uptime".
+	Name string
+	// Since is the start of the uptime. Defaults to UptimeSinceLogger.
+	Since UptimeOrigin
+	// Unit is the unit of the uptime, which is written as a whole number of it, e.g. 1500 for time.Millisecond. It must
+	// be one of time.Nanosecond, time.Microsecond, time.Millisecond, time.Second, time.Minute or time.Hour. Defaults to
+	// time.Millisecond.
+	Unit time.Duration
+}
+
+var defaultUptimeFieldSettings = UptimeFieldSettings{
+	Name: "uptime",
+	Unit: time.Millisecond,
+}
+
+func (s *UptimeFieldSettings) mergeDefault() {
+	if s.Name == "" {
+		s.Name = defaultUptimeFieldSettings.Name
+	}
+	if s.Unit == 0 {
+		s.Unit = defaultUptimeFieldSettings.Unit
+	}
+}
+
+// NewUptimeField returns a new Field with the time elapsed since the logger was created, or since the process started,
+// when the line was logged, e.g. for boot-sequence analysis or on devices without a reliable wall clock. The uptime is
+// measured with the monotonic clock, so it's unaffected by changes to the wall clock.
+//
+// If the Unit is unsupported, an error is returned.
+//
+// OutputFormats:
+//   - OutputFormatText => the uptime is formatted as a number of the unit followed by its symbol, e.g. "1500ms".
+//   - OutputFormatJSON => the uptime is formatted as an int64 number of the unit, e.g. 1500.
+func NewUptimeField(settings *UptimeFieldSettings) (Field, error) {
+	s := UptimeFieldSettings{}
+	if settings != nil {
+		s = *settings
+	}
+	s.mergeDefault()
+
+	symbol, ok := durationUnitSymbols[s.Unit]
+	if !ok {
+		return nil, &ErrorFieldInitialization{fieldName: s.Name, err: fmt.Errorf("unsupported uptime unit: %v", s.Unit)}
+	}
+
+	return NewLineArgsField(
+		s.Name,
+		func(args LogLineArgs) (any, error) {
+			now := args.Time
+			if now.IsZero() {
+				now = time.Now()
+			}
+			started := processStarted
+			if s.Since == UptimeSinceLogger && !args.loggerStarted.IsZero() {
+				started = args.loggerStarted
+			}
+
+			uptime := int64(max(now.Sub(started), 0) / s.Unit)
+			if args.OutputFormat == OutputFormatText {
+				return strconv.FormatInt(uptime, 10) + symbol, nil
+			}
+			return uptime, nil
+		},
+		WithHideKey(false),
+	)
+}
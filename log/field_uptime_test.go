@@ -0,0 +1,93 @@
+package log
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewUptimeField(t *testing.T) {
+	started := time.Now()
+	tests := []struct {
+		name     string
+		settings *UptimeFieldSettings
+		format   OutputFormat
+		args     LogLineArgs
+		want     string
+	}{
+		{
+			"text",
+			nil,
+			OutputFormatText,
+			LogLineArgs{Time: started.Add(1500 * time.Millisecond), loggerStarted: started},
+			"uptime=1500ms",
+		},
+		{
+			"json",
+			&UptimeFieldSettings{Name: "boot", Unit: time.Second},
+			OutputFormatJSON,
+			LogLineArgs{Time: started.Add(1500 * time.Millisecond), loggerStarted: started},
+			`{"boot":1}`,
+		},
+		{
+			"since process",
+			&UptimeFieldSettings{Since: UptimeSinceProcess, Unit: time.Hour},
+			OutputFormatText,
+			LogLineArgs{Time: processStarted.Add(90 * time.Minute), loggerStarted: processStarted.Add(time.Hour)},
+			"uptime=1h",
+		},
+		{
+			"without logger",
+			&UptimeFieldSettings{Unit: time.Minute},
+			OutputFormatText,
+			LogLineArgs{Time: processStarted.Add(2 * time.Minute)},
+			"uptime=2m",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, err := NewUptimeField(tt.settings)
+			if err != nil {
+				t.Fatalf("NewUptimeField() error = %v", err)
+			}
+			formatter, _ := NewFormatter(tt.format, []Field{field})
+
+			res := formatter.FormatLogLine(tt.args, nil)
+			if res.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", res.err)
+			}
+			if got := string(res.bytes); got != tt.want {
+				t.Errorf("FormatLogLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewUptimeField_unsupportedUnit(t *testing.T) {
+	if _, err := NewUptimeField(&UptimeFieldSettings{Unit: 3 * time.Second}); err == nil {
+		t.Error("NewUptimeField() error = nil, want an error")
+	}
+}
+
+func TestNewUptimeField_logger(t *testing.T) {
+	field, _ := NewUptimeField(&UptimeFieldSettings{Unit: time.Nanosecond})
+	formatter, _ := NewFormatter(OutputFormatJSON, []Field{field})
+	var buf bytes.Buffer
+	logger, err := NewLoggerWithOptions(WithAsync(false), WithDestination(&buf, formatter))
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	logger.Info()
+
+	line := strings.TrimSuffix(strings.TrimPrefix(buf.String(), `{"uptime":`), "}\n")
+	uptime, err := strconv.ParseInt(line, 10, 64)
+	if err != nil {
+		t.Fatalf("line = %q, want an uptime", buf.String())
+	}
+	if since := time.Since(processStarted); uptime < int64(time.Millisecond) || uptime >= int64(since) {
+		t.Errorf("uptime = %v, want the time since the logger was created", time.Duration(uptime))
+	}
+}
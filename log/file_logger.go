@@ -0,0 +1,139 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// timePlaceholder is replaced with the time the file is opened in the separator line of a file.
+const timePlaceholder = "{time}"
+
+// FileLoggerOption configures a logger created with NewFileLogger.
+type FileLoggerOption func(s *fileLoggerSettings)
+
+type fileLoggerSettings struct {
+	mode             os.FileMode
+	createDirs       bool
+	truncate         bool
+	separator        string
+	rotation         *DatedFileSettings
+	guard            *ResourceGuardSettings
+	fields           []Field
+	formatterOptions []FormatterOption
+	loggerOptions    []LoggerOption
+}
+
+var defaultFileLoggerSettings = fileLoggerSettings{
+	mode:   0644,
+	fields: defaultFields,
+}
+
+// WithFileMode sets the permissions of the log file, if it's created. Defaults to 0644.
+func WithFileMode(mode os.FileMode) FileLoggerOption {
+	return func(s *fileLoggerSettings) {
+		s.mode = mode
+	}
+}
+
+// WithFileCreateDirs creates the missing directories of the log file, instead of returning ErrorFileNotFound.
+func WithFileCreateDirs(enabled bool) FileLoggerOption {
+	return func(s *fileLoggerSettings) {
+		s.createDirs = enabled
+	}
+}
+
+// WithFileTruncate truncates the log file when it's opened, instead of appending to it, e.g. for a log of the last run
+// only. It conflicts with WithFileRotation, whose files are always appended to.
+func WithFileTruncate(enabled bool) FileLoggerOption {
+	return func(s *fileLoggerSettings) {
+		s.truncate = enabled
+	}
+}
+
+// WithFileSeparator writes the line to the log file when it's opened, to mark where a run starts in a file appended to
+// by many runs, e.g. "--- started {time} ---". A "{time}" placeholder is replaced with the time the file is opened, in
+// RFC 3339 format. With WithFileRotation, the line starts every file, unless the settings have a Separator already.
+func WithFileSeparator(line string) FileLoggerOption {
+	return func(s *fileLoggerSettings) {
+		s.separator = line
+	}
+}
+
+// WithFileRotation writes to a file per date, like WithDatedFileDestination, instead of a single file. The date is
+// inserted before the extension of the filename, e.g. "app.log" is written as "app-2025-01-02.log", unless the filename
+// has a "{date}" placeholder. The Path of the settings is ignored; the directories of the files are always created.
+func WithFileRotation(settings *DatedFileSettings) FileLoggerOption {
+	return func(s *fileLoggerSettings) {
+		rotation := DatedFileSettings{}
+		if settings != nil {
+			rotation = *settings
+		}
+		s.rotation = &rotation
+	}
+}
+
+// WithFileResourceGuard degrades the logger when the disk of the log file or the file descriptors of the process run
+// low, like WithResourceGuard. The Path of the settings defaults to the directory of the file.
+func WithFileResourceGuard(settings *ResourceGuardSettings) FileLoggerOption {
+	return func(s *fileLoggerSettings) {
+		guard := ResourceGuardSettings{}
+		if settings != nil {
+			guard = *settings
+		}
+		s.guard = &guard
+	}
+}
+
+// WithFileFields sets the fields of the lines, instead of the default fields.
+func WithFileFields(fields ...Field) FileLoggerOption {
+	return func(s *fileLoggerSettings) {
+		s.fields = fields
+	}
+}
+
+// WithFileFormatterOptions applies the options to the formatter of the file, e.g. WithNestingLimits.
+func WithFileFormatterOptions(opts ...FormatterOption) FileLoggerOption {
+	return func(s *fileLoggerSettings) {
+		s.formatterOptions = append(s.formatterOptions, opts...)
+	}
+}
+
+// WithFileLoggerOptions applies the options to the logger, after the file destination is added, e.g. WithMinLevel or
+// WithAsyncQueue.
+func WithFileLoggerOptions(opts ...LoggerOption) FileLoggerOption {
+	return func(s *fileLoggerSettings) {
+		s.loggerOptions = append(s.loggerOptions, opts...)
+	}
+}
+
+// separatorLine returns the separator line, with its placeholder replaced with the current time and a trailing
+// newline.
+func separatorLine(separator string, now time.Time) []byte {
+	line := strings.ReplaceAll(separator, timePlaceholder, now.Format(time.RFC3339))
+	if !strings.HasSuffix(line, "\n") {
+		line += "\n"
+	}
+	return []byte(line)
+}
+
+// guardPath returns the directory of the log file checked by its resource guard: the closest one without a date
+// placeholder, since those are only created when the files are opened.
+func guardPath(filename string) string {
+	dir := filepath.Dir(filename)
+	for strings.Contains(dir, datePlaceholder) {
+		dir = filepath.Dir(dir)
+	}
+	return dir
+}
+
+// datedFilePath returns the path of the dated files of filename: the filename itself if it has a date placeholder, or
+// the filename with the placeholder inserted before its extension.
+func datedFilePath(filename string) string {
+	if strings.Contains(filename, datePlaceholder) {
+		return filename
+	}
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + "-" + datePlaceholder + ext
+}
@@ -0,0 +1,195 @@
+package log
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewFileLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	countField, _ := NewIntField("count")
+
+	logger, err := NewFileLogger(path, OutputFormatJSON,
+		WithFileCreateDirs(true),
+		WithFileMode(0600),
+		WithFileFields(NewMessageField(), countField),
+		WithFileLoggerOptions(WithAsync(false), WithMinLevel(Warn)),
+	)
+	if err != nil {
+		t.Fatalf("NewFileLogger() error = %v", err)
+	}
+
+	logger.Info("skipped", 1)
+	logger.Warn("kept", 2)
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if got, want := readFile(t, path), `{"count":2,"message":"kept"}`+"\n"; got != want {
+		t.Errorf("%s = %q, want %q", path, got, want)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Stat() = %v, %v, want mode 0600", info, err)
+	}
+}
+
+func TestNewFileLogger_missingDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+
+	_, err := NewFileLogger(path, OutputFormatText)
+	if !errors.As(err, new(*ErrorFileNotFound)) {
+		t.Errorf("NewFileLogger() error = %v, want ErrorFileNotFound", err)
+	}
+}
+
+func TestNewFileLogger_rotation(t *testing.T) {
+	dir := t.TempDir()
+
+	logger, err := NewFileLogger(filepath.Join(dir, "app.log"), OutputFormatText,
+		WithFileRotation(&DatedFileSettings{Location: time.UTC}),
+		WithFileFields(NewMessageField()),
+		WithFileLoggerOptions(WithAsync(false)),
+	)
+	if err != nil {
+		t.Fatalf("NewFileLogger() error = %v", err)
+	}
+
+	logger.Info("hello")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	path := filepath.Join(dir, "app-"+time.Now().UTC().Format(time.DateOnly)+".log")
+	if got := readFile(t, path); got != "hello\n" {
+		t.Errorf("%s = %q, want %q", path, got, "hello\n")
+	}
+}
+
+func TestDatedFilePath(t *testing.T) {
+	tests := []struct {
+		filename string
+		want     string
+	}{
+		{"logs/app.log", "logs/app-{date}.log"},
+		{"logs/app", "logs/app-{date}"},
+		{"logs/{date}/app.log", "logs/{date}/app.log"},
+	}
+	for _, tt := range tests {
+		if got := datedFilePath(tt.filename); got != tt.want {
+			t.Errorf("datedFilePath(%q) = %q, want %q", tt.filename, got, tt.want)
+		}
+	}
+}
+
+func TestNewFileLogger_truncateAndSeparator(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	tests := []struct {
+		name string
+		opts []FileLoggerOption
+		want string
+	}{
+		{"append", nil, "previous run\nhello\n"},
+		{"truncate", []FileLoggerOption{WithFileTruncate(true)}, "hello\n"},
+		{"separator", []FileLoggerOption{WithFileTruncate(true), WithFileSeparator("---")}, "---\nhello\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(path, []byte("previous run\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			opts := append(tt.opts, WithFileFields(NewMessageField()), WithFileLoggerOptions(WithAsync(false)))
+			logger, err := NewFileLogger(path, OutputFormatText, opts...)
+			if err != nil {
+				t.Fatalf("NewFileLogger() error = %v", err)
+			}
+
+			logger.Info("hello")
+			if err := logger.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if got := readFile(t, path); got != tt.want {
+				t.Errorf("%s = %q, want %q", path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFileLogger_truncateRotation(t *testing.T) {
+	_, err := NewFileLogger(filepath.Join(t.TempDir(), "app.log"), OutputFormatText,
+		WithFileTruncate(true),
+		WithFileRotation(nil),
+	)
+	if !errors.As(err, new(*ErrorOptionConflict)) {
+		t.Errorf("NewFileLogger() error = %v, want ErrorOptionConflict", err)
+	}
+}
+
+func TestSeparatorLine(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	want := "--- started 2025-01-02T03:04:05Z ---\n"
+	if got := string(separatorLine("--- started {time} ---", now)); got != want {
+		t.Errorf("separatorLine() = %q, want %q", got, want)
+	}
+}
+
+func TestNewFileLogger_resourceGuard(t *testing.T) {
+	var checked string
+	defer func(read func(string) resourceUsage) { readResourceUsage = read }(readResourceUsage)
+	readResourceUsage = func(path string) resourceUsage {
+		checked = path
+		return resourceUsage{}
+	}
+
+	dir := t.TempDir()
+	logger, err := NewFileLogger(filepath.Join(dir, "app.log"), OutputFormatText,
+		WithFileResourceGuard(&ResourceGuardSettings{Interval: time.Hour}),
+	)
+	if err != nil {
+		t.Fatalf("NewFileLogger() error = %v", err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if checked != dir {
+		t.Errorf("checked %q, want %q", checked, dir)
+	}
+}
+
+func TestGuardPath(t *testing.T) {
+	tests := map[string]string{
+		"logs/app.log":               "logs",
+		"logs/{date}/app.log":        "logs",
+		"app-{date}.log":             ".",
+		"/var/log/{date}/{date}.log": "/var/log",
+	}
+	for filename, want := range tests {
+		if got := guardPath(filename); got != want {
+			t.Errorf("guardPath(%q) = %q, want %q", filename, got, want)
+		}
+	}
+}
+
+func TestNewFileLogger_closesFileOnError(t *testing.T) {
+	var file *os.File
+	failing := func(l *ultraLogger) error {
+		for w := range l.destinations {
+			file, _ = w.(*os.File)
+		}
+		return errors.New("bad option")
+	}
+
+	_, err := NewFileLogger(filepath.Join(t.TempDir(), "app.log"), OutputFormatText, WithFileLoggerOptions(failing))
+	if err == nil {
+		t.Fatal("NewFileLogger() error = nil, want an error")
+	}
+	if file == nil {
+		t.Fatal("the file wasn't a destination")
+	}
+	if _, err := file.Write([]byte("x")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Write() error = %v, want %v", err, os.ErrClosed)
+	}
+}
@@ -0,0 +1,127 @@
+package log
+
+import (
+	"sync"
+	"time"
+)
+
+// FlightRecorderSettings configures the flight recorder of a logger. See WithFlightRecorder.
+type FlightRecorderSettings struct {
+	// CaptureLevel is the lowest level captured by the recorder. Lines below the logger's minimum level, but at or
+	// above the CaptureLevel, are captured. Defaults to Debug.
+	CaptureLevel Level
+	// TriggerLevel is the level that flushes the captured lines to the destinations. Defaults to Error if nil; see
+	// LevelPtr.
+	TriggerLevel *Level
+	// Capacity is the maximum number of captured lines. When the recorder is full, the oldest line is discarded.
+	// Defaults to 1000.
+	Capacity int
+	// Window is how far back captured lines are flushed; older lines are discarded. Defaults to 30 seconds.
+	Window time.Duration
+}
+
+var defaultFlightRecorderSettings = FlightRecorderSettings{
+	CaptureLevel: Debug,
+	TriggerLevel: LevelPtr(Error),
+	Capacity:     1000,
+	Window:       30 * time.Second,
+}
+
+func (s *FlightRecorderSettings) mergeDefault() {
+	if s.TriggerLevel == nil {
+		s.TriggerLevel = LevelPtr(*defaultFlightRecorderSettings.TriggerLevel)
+	}
+	if s.Capacity <= 0 {
+		s.Capacity = defaultFlightRecorderSettings.Capacity
+	}
+	if s.Window <= 0 {
+		s.Window = defaultFlightRecorderSettings.Window
+	}
+}
+
+// WithFlightRecorder enables the flight recorder. Lines below the logger's minimum level are captured into an
+// in-memory ring instead of being discarded, and are only written to the destinations when a line at the TriggerLevel
+// is logged, right before that line. This gives Debug-level context for errors without paying for Debug volume all
+// the time.
+//
+// Captured lines are formatted when they are flushed, with the time they were logged. Data passed to the logger is
+// retained until then, so it must not be modified after logging.
+func WithFlightRecorder(settings *FlightRecorderSettings) LoggerOption {
+	return func(l *ultraLogger) error {
+		if settings == nil {
+			settings = &FlightRecorderSettings{}
+		}
+		settings.mergeDefault()
+
+		l.recorder = newFlightRecorder(*settings)
+		return nil
+	}
+}
+
+type capturedLine struct {
+	args LogLineArgs
+	data []any
+}
+
+// flightRecorder is a ring of captured lines. It is safe for concurrent use.
+type flightRecorder struct {
+	settings FlightRecorderSettings
+	trigger  Level
+	now      func() time.Time
+
+	mu    sync.Mutex
+	lines []capturedLine
+	next  int
+	count int
+}
+
+func newFlightRecorder(settings FlightRecorderSettings) *flightRecorder {
+	return &flightRecorder{
+		settings: settings,
+		trigger:  *settings.TriggerLevel,
+		now:      time.Now,
+		lines:    make([]capturedLine, settings.Capacity),
+	}
+}
+
+// capture records the line, if its level is captured.
+func (r *flightRecorder) capture(args LogLineArgs, data []any) {
+	if args.Level < r.settings.CaptureLevel {
+		return
+	}
+	if args.Time.IsZero() {
+		args.Time = r.now()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lines[r.next] = capturedLine{args: args, data: data}
+	r.next = (r.next + 1) % len(r.lines)
+	r.count = min(r.count+1, len(r.lines))
+}
+
+// drain empties the recorder, and returns the captured lines within the window, oldest first.
+func (r *flightRecorder) drain() []capturedLine {
+	cutoff := r.now().Add(-r.settings.Window)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.count == 0 {
+		return nil
+	}
+
+	drained := make([]capturedLine, 0, r.count)
+	start := (r.next - r.count + len(r.lines)) % len(r.lines)
+	for i := 0; i < r.count; i++ {
+		idx := (start + i) % len(r.lines)
+		if line := r.lines[idx]; !line.args.Time.Before(cutoff) {
+			drained = append(drained, line)
+		}
+		r.lines[idx] = capturedLine{}
+	}
+	r.count = 0
+
+	return drained
+}
@@ -0,0 +1,123 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func newFlightRecorderTestLogger(t *testing.T, settings *FlightRecorderSettings) (*ultraLogger, *bytes.Buffer) {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+	logger, err := NewLoggerWithOptions(
+		WithAsync(false),
+		WithMinLevel(Info),
+		WithDestination(buf, formatter),
+		WithFlightRecorder(settings),
+	)
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	return logger.(*ultraLogger), buf
+}
+
+func TestWithFlightRecorder(t *testing.T) {
+	logger, buf := newFlightRecorderTestLogger(t, nil)
+
+	logger.Debug("connecting")
+	logger.DebugMsg("retrying")
+	logger.Info("started")
+
+	if got := buf.String(); got != "started\n" {
+		t.Fatalf("output = %q, want captured lines to be held back", got)
+	}
+
+	logger.Error("failed")
+	logger.Error("failed again")
+
+	want := "started\nconnecting\nretrying\nfailed\nfailed again\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestWithFlightRecorder_capacity(t *testing.T) {
+	logger, buf := newFlightRecorderTestLogger(t, &FlightRecorderSettings{Capacity: 2})
+
+	logger.Debug("one")
+	logger.Debug("two")
+	logger.Debug("three")
+	logger.Error("failed")
+
+	if got, want := buf.String(), "two\nthree\nfailed\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestWithFlightRecorder_window(t *testing.T) {
+	logger, buf := newFlightRecorderTestLogger(t, &FlightRecorderSettings{Window: time.Minute})
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	logger.recorder.now = func() time.Time { return now }
+
+	logger.Debug("stale")
+	now = now.Add(2 * time.Minute)
+	logger.Debug("recent")
+	logger.Error("failed")
+
+	if got, want := buf.String(), "recent\nfailed\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestWithFlightRecorder_levels(t *testing.T) {
+	logger, buf := newFlightRecorderTestLogger(t, &FlightRecorderSettings{CaptureLevel: Debug, TriggerLevel: LevelPtr(Warn)})
+
+	logger.Debug("detail")
+	logger.Warn("slow")
+
+	if got, want := buf.String(), "detail\nslow\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestWithFlightRecorder_capturedTime(t *testing.T) {
+	buf := &bytes.Buffer{}
+	formatter, _ := NewFormatter(OutputFormatText, []Field{
+		NewCurrentTimeField(&CurrentTimeFieldSettings{Format: time.RFC3339}),
+		NewMessageField(),
+	})
+	logger, _ := NewLoggerWithOptions(
+		WithAsync(false),
+		WithDestination(buf, formatter),
+		WithFlightRecorder(nil),
+	)
+
+	captured := time.Now().Add(-10 * time.Second)
+	logger.(*ultraLogger).recorder.now = func() time.Time { return captured }
+	logger.Debug("detail")
+
+	logger.(*ultraLogger).recorder.now = time.Now
+	logger.Error("failed")
+
+	if want := captured.Format(time.RFC3339) + " detail\n"; !bytes.HasPrefix(buf.Bytes(), []byte(want)) {
+		t.Errorf("output = %q, want the captured line to keep its original time", buf.String())
+	}
+}
+
+func TestFlightRecorderSettings_mergeDefault(t *testing.T) {
+	settings := &FlightRecorderSettings{}
+	settings.mergeDefault()
+	if *settings.TriggerLevel != Error {
+		t.Errorf("TriggerLevel = %v, want %v", *settings.TriggerLevel, Error)
+	}
+
+	settings = &FlightRecorderSettings{TriggerLevel: LevelPtr(Debug)}
+	settings.mergeDefault()
+	if *settings.TriggerLevel != Debug {
+		t.Errorf("TriggerLevel = %v, want %v", *settings.TriggerLevel, Debug)
+	}
+}
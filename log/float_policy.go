@@ -0,0 +1,84 @@
+package log
+
+import (
+	"math"
+	"strconv"
+)
+
+// NonFiniteFloatPolicy determines how NaN and ±Inf float values are logged. encoding/json can't represent them, so
+// without a policy a single NaN would cost the whole JSON line.
+type NonFiniteFloatPolicy int
+
+const (
+	// NonFiniteFloatString logs non-finite floats as the strings "NaN", "+Inf", and "-Inf". This is the default.
+	NonFiniteFloatString NonFiniteFloatPolicy = iota
+	// NonFiniteFloatDrop omits fields, map entries, and struct fields with non-finite float values. Non-finite array
+	// elements are logged as null, so the positions of the other elements are kept.
+	NonFiniteFloatDrop
+	// NonFiniteFloatError fails the line with an ErrorNonFiniteFloat. The line is not written, and the error is reported
+	// as an internal error of the logger.
+	NonFiniteFloatError
+)
+
+func (p NonFiniteFloatPolicy) String() string {
+	switch p {
+	case NonFiniteFloatString:
+		return "string"
+	case NonFiniteFloatDrop:
+		return "drop"
+	case NonFiniteFloatError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// WithNonFiniteFloatPolicy sets the NonFiniteFloatPolicy of the formatter. The policy applies to the float fields, and
+// to floats nested in any other JSON field value.
+func WithNonFiniteFloatPolicy(policy NonFiniteFloatPolicy) FormatterOption {
+	return func(f LogLineFormatter) LogLineFormatter {
+		if tf, ok := unwrapFormatter[*textFormatter](f); ok {
+			tf.NonFiniteFloats = policy
+		}
+		if jf, ok := unwrapFormatter[*jsonFormatter](f); ok {
+			jf.NonFiniteFloats = policy
+		}
+		return f
+	}
+}
+
+func isNonFinite(f float64) bool {
+	return math.IsNaN(f) || math.IsInf(f, 0)
+}
+
+// nonFiniteFloatValue returns the value as a float64, and whether it's a NaN or ±Inf float32 or float64.
+func nonFiniteFloatValue(value any) (float64, bool) {
+	var f float64
+	switch value := value.(type) {
+	case float64:
+		f = value
+	case float32:
+		f = float64(value)
+	default:
+		return 0, false
+	}
+	return f, isNonFinite(f)
+}
+
+// formatNonFiniteFloat returns the string form of a non-finite float: "NaN", "+Inf", or "-Inf".
+func formatNonFiniteFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// nonFiniteFloatResult is the result of a float field for a NaN or ±Inf value, according to the NonFiniteFloatPolicy of
+// the formatter.
+func nonFiniteFloatResult(args LogLineArgs, fieldName string, value float64) (any, error) {
+	switch args.NonFiniteFloats {
+	case NonFiniteFloatDrop:
+		return nil, nil
+	case NonFiniteFloatError:
+		return nil, &ErrorNonFiniteFloat{path: fieldName, value: value}
+	default:
+		return formatNonFiniteFloat(value), nil
+	}
+}
@@ -0,0 +1,111 @@
+package log
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+type floatPolicyTestStruct struct {
+	Score   float64 `json:"score"`
+	Ratio   float64
+	private float64
+}
+
+func TestWithNonFiniteFloatPolicy_json(t *testing.T) {
+	floatField, _ := NewFloatField("float")
+	mapField, _ := NewMapField[string, float64](
+		"map",
+		func(args LogLineArgs, data string) (any, error) { return data, nil },
+		func(args LogLineArgs, data float64) (any, error) { return data, nil },
+	)
+	arrayField, _ := NewArrayField[float64]("array", func(args LogLineArgs, data float64) (any, error) {
+		return data, nil
+	})
+	structField, _ := NewObjectField[floatPolicyTestStruct]("struct", func(args LogLineArgs, data floatPolicyTestStruct) (any, error) {
+		return data, nil
+	})
+	fields := []Field{NewMessageField(), floatField, mapField, arrayField, structField}
+	data := []any{
+		"hello",
+		math.Inf(1),
+		map[string]float64{"a": 1, "b": math.NaN()},
+		[]float64{1, math.Inf(-1)},
+		floatPolicyTestStruct{Score: math.NaN(), Ratio: 0.5},
+	}
+
+	tests := []struct {
+		name    string
+		policy  NonFiniteFloatPolicy
+		want    string
+		wantErr bool
+	}{
+		{
+			"String",
+			NonFiniteFloatString,
+			`{"array":[1,"-Inf"],"float":"+Inf","map":{"a":1,"b":"NaN"},"message":"hello","struct":{"Ratio":0.5,"score":"NaN"}}`,
+			false,
+		},
+		{
+			"Drop",
+			NonFiniteFloatDrop,
+			`{"array":[1,null],"map":{"a":1},"message":"hello","struct":{"Ratio":0.5}}`,
+			false,
+		},
+		{"Error", NonFiniteFloatError, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter, _ := NewFormatter(OutputFormatJSON, fields, WithNonFiniteFloatPolicy(tt.policy))
+
+			result := formatter.FormatLogLine(LogLineArgs{Level: Info}, data)
+			if tt.wantErr {
+				var nonFinite *ErrorNonFiniteFloat
+				if !errors.As(result.err, &nonFinite) {
+					t.Errorf("FormatLogLine() error = %v, want ErrorNonFiniteFloat", result.err)
+				}
+				return
+			}
+
+			if result.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", result.err)
+			}
+			if string(result.bytes) != tt.want {
+				t.Errorf("FormatLogLine() = %s, want %s", result.bytes, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithNonFiniteFloatPolicy_text(t *testing.T) {
+	floatField, _ := NewFloatField("float")
+
+	tests := []struct {
+		policy  NonFiniteFloatPolicy
+		want    string
+		wantErr bool
+	}{
+		{NonFiniteFloatString, "hello float=NaN", false},
+		{NonFiniteFloatDrop, "hello", false},
+		{NonFiniteFloatError, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			formatter, _ := NewFormatter(
+				OutputFormatText,
+				[]Field{NewMessageField(), floatField},
+				WithDefaultColorization(),
+				WithColorPolicy(ColorPolicyNever),
+				WithNonFiniteFloatPolicy(tt.policy),
+			)
+
+			result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{"hello", math.NaN()})
+			if (result.err != nil) != tt.wantErr {
+				t.Fatalf("FormatLogLine() error = %v, wantErr %v", result.err, tt.wantErr)
+			}
+			if string(result.bytes) != tt.want {
+				t.Errorf("FormatLogLine() = %q, want %q", result.bytes, tt.want)
+			}
+		})
+	}
+}
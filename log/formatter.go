@@ -1,17 +1,46 @@
 package log
 
+import (
+    "slices"
+    "time"
+)
+
 // OutputFormat is a type representing the output format of a formatter.
 //
 // It can be one of the following:
 //   - JSON
 //   - Text
+//   - Logfmt
+//   - XML
+//   - CBOR
+//   - MessagePack
 //
-// TODO: Add more output formats [YAML, XML, etc.]
+// TODO: Add more output formats [YAML, etc.]
 type OutputFormat string
 
 const (
     OutputFormatJSON OutputFormat = "json"
     OutputFormatText OutputFormat = "text"
+    // OutputFormatLogfmt writes lines as logfmt key=value pairs, with every field keyed and values quoted as needed,
+    // e.g. `level=INFO message="user signed up" user.id=42`. Fields format their values like for JSON output; nested
+    // values are written as JSON strings, or as dotted keys for objects of fields, like events.
+    OutputFormatLogfmt OutputFormat = "logfmt"
+    // OutputFormatXML writes lines as a <log> element, with a child element per field, e.g.
+    // `<log><level>INFO</level><message>user signed up</message></log>`. Fields format their values like for JSON
+    // output; objects are written as nested elements, and the elements of arrays as <item> elements. See
+    // WithXMLAttributes to write fields as attributes instead.
+    OutputFormatXML OutputFormat = "xml"
+    // OutputFormatCBOR writes lines as CBOR maps (RFC 8949), with the same structure as the objects of JSON lines, but
+    // encoded directly from the values of the fields. Times are written as RFC 3339 strings tagged as date/times, and
+    // byte slices as byte strings.
+    OutputFormatCBOR OutputFormat = "cbor"
+    // OutputFormatMsgpack writes lines as MessagePack maps, like OutputFormatCBOR. Times are written with the
+    // timestamp extension type, and byte slices as binary data.
+    OutputFormatMsgpack OutputFormat = "msgpack"
+    // OutputFormatCSV writes lines as CSV records (RFC 4180), with a column per field, in the order of the fields.
+    // Fields format their values like for JSON output; nested values are written as JSON strings, and fields without
+    // a value as empty columns. See CSVHeader for the header row.
+    OutputFormatCSV OutputFormat = "csv"
 )
 
 // LogLineArgs are the arguments that are passed to the FormatLogLine function of a LogLineFormatter, and further to the
@@ -21,6 +50,40 @@ type LogLineArgs struct {
     Level        Level
     Tag          string
     OutputFormat OutputFormat
+    // Time is when the line was logged, if it differs from the time it is formatted, e.g. for lines replayed by a
+    // flight recorder. The zero value means now.
+    Time time.Time
+    // NonFiniteFloats is the NonFiniteFloatPolicy of the formatter. Like the OutputFormat, it is set by the formatter.
+    NonFiniteFloats NonFiniteFloatPolicy
+    // SchemaVersion is the version of the schema the formatter writes, as selected with WithSchema. Like the
+    // OutputFormat, it is set by the formatter.
+    SchemaVersion string
+    // SortMapKeys reports whether map fields should render their keys in sorted order, as selected with
+    // WithSortedMapKeys. Like the OutputFormat, it is set by the formatter.
+    SortMapKeys bool
+    // SeverityProfile is the SeverityProfile of the formatter, as selected with WithSeverityProfile. Like the
+    // OutputFormat, it is set by the formatter.
+    SeverityProfile *SeverityProfile
+    // Sequence is the sequence number of the line in the logger, and DestinationSequence its sequence number in the
+    // destination it is formatted for. Both are zero unless the logger has WithSequenceNumbers.
+    Sequence            uint64
+    DestinationSequence uint64
+    // EntryID uniquely identifies the line. It is zero unless the logger has WithEntryIDs.
+    EntryID EntryID
+
+    // entry is the Entry the line was logged from with LogEntry, if any. Its Message and Fields go straight to their
+    // fields.
+    entry *Entry
+    // loggerStarted is when the logger that logged the line was created, for uptime fields. It is zero for lines
+    // formatted without a logger.
+    loggerStarted time.Time
+
+    // line memoizes the results of the formatters for the line, when the logger dispatches it to several
+    // destinations.
+    line *lineCache
+    // delivery tracks the outcome of the line at each destination, when the logger dispatches it to several
+    // destinations.
+    delivery *deliveryTracker
 }
 
 // FormatResult is a struct that contains the formatted log line and any errors that may have occurred.
@@ -38,6 +101,30 @@ type LogLineFormatter interface {
     FormatLogLine(args LogLineArgs, data []any) FormatResult
 }
 
+// wrappingFormatter is implemented by formatters that decorate another formatter, like the ColorizedFormatter. It
+// allows formatter options to reach the formatter they configure regardless of how many layers wrap it.
+type wrappingFormatter interface {
+    Unwrap() LogLineFormatter
+}
+
+// unwrapFormatter walks the chain of wrapping formatters, starting at f, and returns the first formatter of type T.
+func unwrapFormatter[T LogLineFormatter](f LogLineFormatter) (T, bool) {
+    for f != nil {
+        if target, ok := f.(T); ok {
+            return target, true
+        }
+
+        wrapper, ok := f.(wrappingFormatter)
+        if !ok {
+            break
+        }
+        f = wrapper.Unwrap()
+    }
+
+    var zero T
+    return zero, false
+}
+
 // FormatterOption is a function that takes a LogLineFormatter and returns a new LogLineFormatter that has an option
 // applied to it. This is useful for creating custom formatters that have additional options.
 type FormatterOption func(f LogLineFormatter) LogLineFormatter
@@ -45,20 +132,36 @@ type FormatterOption func(f LogLineFormatter) LogLineFormatter
 func NewFormatter(outputFormat OutputFormat, fields []Field, opts ...FormatterOption) (LogLineFormatter, error) {
     var f LogLineFormatter
 
+    // Fields whose keys collide may be renamed in place, so don't modify the caller's slice.
+    fields = slices.Clone(fields)
     fieldFormatters := make(map[string]FieldFormatter)
-    for _, field := range fields {
-        fieldFormatter, err := field.NewFieldFormatter()
-        if err != nil {
-            return nil, &ErrorFieldFormatterInit{field: field, err: err}
-        }
-        fieldFormatters[field.Name()] = fieldFormatter
-    }
 
     switch outputFormat {
     case OutputFormatJSON:
-        f = &jsonFormatter{Fields: fields, FieldFormatters: fieldFormatters}
+        f = &jsonFormatter{Fields: fields, FieldFormatters: fieldFormatters, FieldCache: newFieldResultCache()}
     case OutputFormatText:
-        f = &textFormatter{Fields: fields, FieldFormatters: fieldFormatters}
+        f = &textFormatter{
+            Fields:          fields,
+            FieldFormatters: fieldFormatters,
+            FieldCache:      newFieldResultCache(),
+            LevelPrefixes:   levelMessagePrefixes(fields),
+            SortMapKeys:     true,
+        }
+    case OutputFormatLogfmt:
+        f = &textFormatter{
+            Fields:          fields,
+            FieldFormatters: fieldFormatters,
+            FieldCache:      newFieldResultCache(),
+            SortMapKeys:     true,
+            Logfmt:          true,
+        }
+    case OutputFormatXML, OutputFormatCBOR, OutputFormatMsgpack, OutputFormatCSV:
+        f = &jsonFormatter{
+            Fields:          fields,
+            FieldFormatters: fieldFormatters,
+            FieldCache:      newFieldResultCache(),
+            Output:          outputFormat,
+        }
     default:
         return nil, &ErrorInvalidOutput{outputFormat: outputFormat}
     }
@@ -67,6 +170,20 @@ func NewFormatter(outputFormat OutputFormat, fields []Field, opts ...FormatterOp
         f = opt(f)
     }
 
+    if keys := formatterKeys(f); keys != nil {
+        if err := keys.build(fields, outputFormat); err != nil {
+            return nil, err
+        }
+    }
+
+    for _, field := range fields {
+        fieldFormatter, err := field.NewFieldFormatter()
+        if err != nil {
+            return nil, &ErrorFieldFormatterInit{field: field, err: err}
+        }
+        fieldFormatters[field.Name()] = fieldFormatter
+    }
+
     return f, nil
 }
 
@@ -0,0 +1,140 @@
+package log
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// AccessLogFormat is the format of the lines of an access log formatter.
+type AccessLogFormat int
+
+const (
+	// AccessLogCombined is the Combined Log Format of Apache and Nginx: the Common Log Format, followed by the referer
+	// and user agent of the request.
+	AccessLogCombined AccessLogFormat = iota
+	// AccessLogCommon is the Common Log Format of Apache and Nginx.
+	AccessLogCommon
+)
+
+// accessLogTimeFormat is the format of the timestamps of access logs.
+const accessLogTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// NewAccessLogFormatter returns a formatter that writes the requests logged with an [*http.Request] or an
+// [*http.Response], the data of NewRequestField and NewResponseField, as access log lines, for tools that read Apache
+// and Nginx access logs, like GoAccess and awstats:
+//
+//	203.0.113.7 - alice [02/Jan/2025:15:04:05 +0000] "GET /index.html HTTP/1.1" 200 2326 "-" "curl/8.5.0"
+//
+// The status and size are those of the response: log a response with the request in its Request field, e.g. an
+// *http.Response built by a middleware from what the handler wrote. Without a response, they are written as "-".
+//
+// Lines without a request or a response are dropped, so the formatter can share a logger with other destinations.
+func NewAccessLogFormatter(format AccessLogFormat) LogLineFormatter {
+	return &accessLogFormatter{format: format}
+}
+
+// accessLogFormatter formats the requests of lines as access log lines.
+type accessLogFormatter struct {
+	format AccessLogFormat
+}
+
+// FormatLogLine formats the log line using the provided data and returns a FormatResult which contains the formatted
+// log line and any errors that may have occurred.
+func (f *accessLogFormatter) FormatLogLine(args LogLineArgs, data []any) FormatResult {
+	var req *http.Request
+	var resp *http.Response
+	for _, datum := range data {
+		switch datum := datum.(type) {
+		case *http.Request:
+			req = datum
+		case *http.Response:
+			resp = datum
+		}
+	}
+	if req == nil && resp != nil {
+		req = resp.Request
+	}
+	if req == nil {
+		return FormatResult{nil, nil}
+	}
+
+	now := args.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	host := req.RemoteAddr
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	user := ""
+	if u, _, ok := req.BasicAuth(); ok {
+		user = u
+	} else if req.URL != nil && req.URL.User != nil {
+		user = req.URL.User.Username()
+	}
+	uri := req.RequestURI
+	if uri == "" && req.URL != nil {
+		uri = req.URL.RequestURI()
+	}
+
+	line := appendAccessLogValue(nil, host)
+	line = append(line, " - "...)
+	line = appendAccessLogValue(line, user)
+	line = append(line, " ["...)
+	line = now.AppendFormat(line, accessLogTimeFormat)
+	line = append(line, `] "`...)
+	line = appendAccessLogEscaped(line, req.Method+" "+uri+" "+req.Proto)
+	line = append(line, `" `...)
+	if resp != nil {
+		line = strconv.AppendInt(line, int64(resp.StatusCode), 10)
+	} else {
+		line = append(line, '-')
+	}
+	line = append(line, ' ')
+	if resp != nil && resp.ContentLength > 0 {
+		line = strconv.AppendInt(line, resp.ContentLength, 10)
+	} else {
+		line = append(line, '-')
+	}
+
+	if f.format == AccessLogCombined {
+		line = appendAccessLogQuoted(append(line, ' '), req.Referer())
+		line = appendAccessLogQuoted(append(line, ' '), req.UserAgent())
+	}
+
+	return FormatResult{line, nil}
+}
+
+// appendAccessLogValue appends an unquoted value, or "-" if it's empty.
+func appendAccessLogValue(dst []byte, value string) []byte {
+	if value == "" {
+		return append(dst, '-')
+	}
+	return appendAccessLogEscaped(dst, value)
+}
+
+// appendAccessLogQuoted appends a quoted value, or "-" quoted if it's empty.
+func appendAccessLogQuoted(dst []byte, value string) []byte {
+	dst = append(dst, '"')
+	return append(appendAccessLogValue(dst, value), '"')
+}
+
+// appendAccessLogEscaped appends the value with quotes and backslashes escaped with a backslash, and control characters
+// escaped as \xhh, like Apache escapes the values of its logs.
+func appendAccessLogEscaped(dst []byte, value string) []byte {
+	const hex = "0123456789abcdef"
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case c == '"' || c == '\\':
+			dst = append(dst, '\\', c)
+		case c < ' ' || c == 0x7f:
+			dst = append(dst, '\\', 'x', hex[c>>4], hex[c&0xf])
+		default:
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,80 @@
+package log
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewAccessLogFormatter(t *testing.T) {
+	at := time.Date(2025, 1, 2, 15, 4, 5, 0, time.FixedZone("", -7*60*60))
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/index.html?q=1", nil)
+		req.RemoteAddr = "203.0.113.7:51234"
+		req.Header.Set("Referer", "https://example.com/")
+		req.Header.Set("User-Agent", `curl/8.5.0 "quoted"`)
+		return req
+	}
+	authenticated := newRequest()
+	authenticated.SetBasicAuth("alice", "secret")
+
+	tests := []struct {
+		name   string
+		format AccessLogFormat
+		data   []any
+		want   string
+	}{
+		{
+			"combined",
+			AccessLogCombined,
+			[]any{&http.Response{Request: newRequest(), StatusCode: 200, ContentLength: 2326}},
+			`203.0.113.7 - - [02/Jan/2025:15:04:05 -0700] "GET /index.html?q=1 HTTP/1.1" 200 2326 ` +
+				`"https://example.com/" "curl/8.5.0 \"quoted\""`,
+		},
+		{
+			"common",
+			AccessLogCommon,
+			[]any{"served", authenticated, &http.Response{StatusCode: 304}},
+			`203.0.113.7 - alice [02/Jan/2025:15:04:05 -0700] "GET /index.html?q=1 HTTP/1.1" 304 -`,
+		},
+		{
+			"request only",
+			AccessLogCommon,
+			[]any{newRequest()},
+			`203.0.113.7 - - [02/Jan/2025:15:04:05 -0700] "GET /index.html?q=1 HTTP/1.1" - -`,
+		},
+		{"no request", AccessLogCombined, []any{"starting"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := NewAccessLogFormatter(tt.format).FormatLogLine(LogLineArgs{Time: at}, tt.data)
+			if res.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", res.err)
+			}
+			if got := string(res.bytes); got != tt.want {
+				t.Errorf("FormatLogLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewAccessLogFormatter_logger(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLoggerWithOptions(WithAsync(false), WithDestination(&buf, NewAccessLogFormatter(AccessLogCommon)))
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	logger.Info("starting")
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	logger.Info(&http.Response{Request: req, StatusCode: 401})
+
+	got := buf.String()
+	if strings.Count(got, "\n") != 1 || !strings.HasPrefix(got, "192.0.2.1 - - [") ||
+		!strings.HasSuffix(got, `] "POST /login HTTP/1.1" 401 -`+"\n") {
+		t.Errorf("lines = %q, want a single access log line", got)
+	}
+}
@@ -0,0 +1,370 @@
+package log
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// binaryFormat appends the data items of a binary output format with the data model of JSON, like CBOR and
+// MessagePack.
+type binaryFormat interface {
+	appendNull(dst []byte) []byte
+	appendBool(dst []byte, b bool) []byte
+	appendInt(dst []byte, n int64) []byte
+	appendUint(dst []byte, n uint64) []byte
+	appendFloat32(dst []byte, f float32) []byte
+	appendFloat64(dst []byte, f float64) []byte
+	appendString(dst []byte, s string) []byte
+	appendBytes(dst []byte, b []byte) []byte
+	appendTime(dst []byte, t time.Time) []byte
+	// appendArrayHead and appendMapHead append the head of an array of n elements, and of a map of n entries. The
+	// elements, or the keys and values of the entries, follow.
+	appendArrayHead(dst []byte, n int) []byte
+	appendMapHead(dst []byte, n int) []byte
+}
+
+// encodeBinary renders the Record as a map of the binary format, with the fields in the order of the Record. Values
+// are encoded like encoding/json would marshal them, including the NonFiniteFloatPolicy and NestingLimits of the
+// formatter. Values that can't be encoded (channels, failing MarshalJSON methods, ...) are replaced with the error
+// message, so one bad value doesn't cost the whole line.
+func (f *jsonFormatter) encodeBinary(record *Record, format binaryFormat) ([]byte, error) {
+	values := make(map[string]any, len(record.Fields))
+	for _, field := range record.Fields {
+		values[field.Key] = field.Value
+	}
+	if f.NestingLimits != (NestingLimits{}) {
+		sanitizer := &jsonSanitizer{nonFiniteFloats: f.NonFiniteFloats, limits: f.NestingLimits}
+		var err error
+		if values, err = sanitizer.sanitizeFields(values); err != nil {
+			return nil, err
+		}
+	}
+
+	encoder := &binaryEncoder{format: format, nonFiniteFloats: f.NonFiniteFloats, refs: refPath{}}
+	keys := make([]string, 0, len(record.Fields))
+	for _, field := range record.Fields {
+		if value, ok := values[field.Key]; ok && !encoder.dropped(reflect.ValueOf(value)) {
+			keys = append(keys, field.Key)
+		}
+	}
+
+	line := format.appendMapHead(nil, len(keys))
+	for _, key := range keys {
+		line = format.appendString(line, key)
+		encoded, err := encoder.encode(line, key, reflect.ValueOf(values[key]), 0)
+		if _, ok := err.(*ErrorNonFiniteFloat); ok {
+			return nil, err
+		}
+		if err != nil {
+			encoded = format.appendString(line, (&ErrorNonFatalFormatterError{fieldName: key, err: err}).Error())
+		}
+		line = encoded
+	}
+	return line, nil
+}
+
+// binaryEncoder encodes values in a binary format, with the structure encoding/json gives them: structs are encoded
+// as maps keyed by their JSON field names, maps with their keys sorted, and values implementing json.Marshaler as the
+// JSON they marshal to.
+type binaryEncoder struct {
+	format          binaryFormat
+	nonFiniteFloats NonFiniteFloatPolicy
+
+	refs refPath // References on the path to the value being encoded, to truncate values that contain themselves.
+}
+
+// encode appends the value at path to dst.
+func (e *binaryEncoder) encode(dst []byte, path string, v reflect.Value, depth int) ([]byte, error) {
+	if !v.IsValid() {
+		return e.format.appendNull(dst), nil
+	}
+	if depth > maxReflectDepth {
+		return e.format.appendString(dst, depthMarker), nil
+	}
+
+	if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+		return e.format.appendNull(dst), nil
+	}
+	if v.Kind() == reflect.Interface {
+		return e.encode(dst, path, v.Elem(), depth+1)
+	}
+
+	// Values of unexported fields of embedded structs can't be passed to their methods.
+	if v.CanInterface() {
+		switch value := v.Interface().(type) {
+		case time.Time:
+			return e.format.appendTime(dst, value), nil
+		case *orderedMap:
+			return e.encodeOrderedMap(dst, path, value, depth)
+		case json.Marshaler:
+			return e.encodeJSON(dst, value)
+		case encoding.TextMarshaler:
+			text, err := value.MarshalText()
+			if err != nil {
+				return nil, err
+			}
+			return e.format.appendString(dst, string(text)), nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return e.format.appendBool(dst, v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return e.format.appendInt(dst, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return e.format.appendUint(dst, v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return e.encodeFloat(dst, path, v)
+	case reflect.String:
+		return e.format.appendString(dst, v.String()), nil
+
+	case reflect.Pointer:
+		ref, ok := e.refs.enter(v)
+		if !ok {
+			return e.format.appendString(dst, cycleMarker), nil
+		}
+		defer e.refs.leave(ref)
+		return e.encode(dst, path, v.Elem(), depth+1)
+
+	case reflect.Map:
+		if v.IsNil() {
+			return e.format.appendNull(dst), nil
+		}
+		return e.encodeMap(dst, path, v, depth)
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return e.format.appendNull(dst), nil
+		}
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return e.format.appendBytes(dst, v.Bytes()), nil
+		}
+		return e.encodeArray(dst, path, v, depth)
+
+	case reflect.Struct:
+		return e.encodeStruct(dst, path, v, depth)
+
+	default:
+		return nil, &json.UnsupportedTypeError{Type: v.Type()}
+	}
+}
+
+// encodeFloat appends a float, or its replacement if it's NaN or ±Inf, according to the NonFiniteFloatPolicy.
+// Dropped floats are skipped by the maps and structs that contain them, and encoded as null elsewhere.
+func (e *binaryEncoder) encodeFloat(dst []byte, path string, v reflect.Value) ([]byte, error) {
+	f := v.Float()
+	if isNonFinite(f) {
+		switch e.nonFiniteFloats {
+		case NonFiniteFloatDrop:
+			return e.format.appendNull(dst), nil
+		case NonFiniteFloatError:
+			return nil, &ErrorNonFiniteFloat{path: path, value: f}
+		default:
+			return e.format.appendString(dst, formatNonFiniteFloat(f)), nil
+		}
+	}
+
+	if v.Kind() == reflect.Float32 {
+		return e.format.appendFloat32(dst, float32(f)), nil
+	}
+	return e.format.appendFloat64(dst, f), nil
+}
+
+// dropped reports whether the value is dropped from the map or struct containing it: a NaN or ±Inf float, with the
+// NonFiniteFloatDrop policy.
+func (e *binaryEncoder) dropped(v reflect.Value) bool {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+	if !v.IsValid() || e.nonFiniteFloats != NonFiniteFloatDrop {
+		return false
+	}
+	return (v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64) && isNonFinite(v.Float())
+}
+
+// binaryEntry is an entry of a map.
+type binaryEntry struct {
+	key   string
+	value reflect.Value
+}
+
+// encodeEntries appends a map of the entries that aren't dropped, in order.
+func (e *binaryEncoder) encodeEntries(dst []byte, path string, entries []binaryEntry, depth int) ([]byte, error) {
+	entries = slices.DeleteFunc(entries, func(entry binaryEntry) bool { return e.dropped(entry.value) })
+
+	dst = e.format.appendMapHead(dst, len(entries))
+	for _, entry := range entries {
+		dst = e.format.appendString(dst, entry.key)
+		var err error
+		if dst, err = e.encode(dst, path+"."+entry.key, entry.value, depth+1); err != nil {
+			return nil, err
+		}
+	}
+	return dst, nil
+}
+
+// encodeMap appends a map with its keys sorted, like encoding/json writes them.
+func (e *binaryEncoder) encodeMap(dst []byte, path string, v reflect.Value, depth int) ([]byte, error) {
+	ref, ok := e.refs.enter(v)
+	if !ok {
+		return e.format.appendString(dst, cycleMarker), nil
+	}
+	defer e.refs.leave(ref)
+
+	entries := make([]binaryEntry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key := iter.Key()
+		name := fmt.Sprint(key.Interface())
+		if marshaler, ok := key.Interface().(encoding.TextMarshaler); ok && key.Kind() != reflect.String {
+			text, err := marshaler.MarshalText()
+			if err != nil {
+				return nil, err
+			}
+			name = string(text)
+		}
+		entries = append(entries, binaryEntry{key: name, value: iter.Value()})
+	}
+	slices.SortFunc(entries, func(a, b binaryEntry) int { return strings.Compare(a.key, b.key) })
+
+	return e.encodeEntries(dst, path, entries, depth)
+}
+
+// encodeOrderedMap appends a map with the entries of the orderedMap, in order.
+func (e *binaryEncoder) encodeOrderedMap(dst []byte, path string, m *orderedMap, depth int) ([]byte, error) {
+	entries := make([]binaryEntry, 0, len(m.keys)+1)
+	for i, key := range m.keys {
+		entries = append(entries, binaryEntry{key: fmt.Sprintf("%v", key), value: reflect.ValueOf(m.values[i])})
+	}
+	if m.more > 0 {
+		entries = append(entries, binaryEntry{key: moreKey, value: reflect.ValueOf(fmt.Sprintf("(+%d more)", m.more))})
+	}
+	return e.encodeEntries(dst, path, entries, depth)
+}
+
+func (e *binaryEncoder) encodeArray(dst []byte, path string, v reflect.Value, depth int) ([]byte, error) {
+	ref, ok := e.refs.enter(v)
+	if !ok {
+		return e.format.appendString(dst, cycleMarker), nil
+	}
+	defer e.refs.leave(ref)
+
+	dst = e.format.appendArrayHead(dst, v.Len())
+	for i := range v.Len() {
+		var err error
+		if dst, err = e.encode(dst, fmt.Sprintf("%s[%d]", path, i), v.Index(i), depth+1); err != nil {
+			return nil, err
+		}
+	}
+	return dst, nil
+}
+
+// encodeStruct appends a map of the exported fields of the struct, under their JSON names. Fields tagged "-" are
+// skipped, as are empty fields tagged omitempty, and the fields of embedded structs are promoted.
+func (e *binaryEncoder) encodeStruct(dst []byte, path string, v reflect.Value, depth int) ([]byte, error) {
+	return e.encodeEntries(dst, path, appendStructEntries(nil, v), depth)
+}
+
+// appendStructEntries appends the entries of the fields of the struct. Fields of embedded structs come after the
+// fields of the struct, and are skipped if the struct has a field of the same name.
+func appendStructEntries(entries []binaryEntry, v reflect.Value) []binaryEntry {
+	var embedded []reflect.Value
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag, hasTag := field.Tag.Lookup("json")
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "-" && options == "" {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			elem := v.Field(i)
+			if elem.Kind() == reflect.Pointer {
+				if elem.IsNil() {
+					continue
+				}
+				elem = elem.Elem()
+			}
+			if elem.Kind() == reflect.Struct {
+				embedded = append(embedded, elem)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" || !hasTag {
+			name = field.Name
+		}
+		if slices.Contains(strings.Split(options, ","), "omitempty") && v.Field(i).IsZero() {
+			continue
+		}
+		entries = append(entries, binaryEntry{key: name, value: v.Field(i)})
+	}
+
+	for _, elem := range embedded {
+		for _, entry := range appendStructEntries(nil, elem) {
+			if !slices.ContainsFunc(entries, func(e binaryEntry) bool { return e.key == entry.key }) {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries
+}
+
+// encodeJSON appends the JSON the value marshals to, decoded into maps, slices, and scalars.
+func (e *binaryEncoder) encodeJSON(dst []byte, marshaler json.Marshaler) ([]byte, error) {
+	b, err := marshalJSON(marshaler)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return e.appendDecodedJSON(dst, value), nil
+}
+
+// appendDecodedJSON appends a value decoded from JSON, with numbers decoded as json.Number.
+func (e *binaryEncoder) appendDecodedJSON(dst []byte, value any) []byte {
+	switch value := value.(type) {
+	case map[string]any:
+		dst = e.format.appendMapHead(dst, len(value))
+		for _, key := range slices.Sorted(maps.Keys(value)) {
+			dst = e.format.appendString(dst, key)
+			dst = e.appendDecodedJSON(dst, value[key])
+		}
+		return dst
+	case []any:
+		dst = e.format.appendArrayHead(dst, len(value))
+		for _, elem := range value {
+			dst = e.appendDecodedJSON(dst, elem)
+		}
+		return dst
+	case json.Number:
+		if n, err := value.Int64(); err == nil {
+			return e.format.appendInt(dst, n)
+		}
+		f, _ := value.Float64()
+		return e.format.appendFloat64(dst, f)
+	case string:
+		return e.format.appendString(dst, value)
+	case bool:
+		return e.format.appendBool(dst, value)
+	default:
+		return e.format.appendNull(dst)
+	}
+}
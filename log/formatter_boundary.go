@@ -0,0 +1,87 @@
+package log
+
+import (
+	"strconv"
+)
+
+// EntryBoundaryMode determines how entry boundaries are made explicit in the output. See [WithEntryBoundary].
+type EntryBoundaryMode int
+
+const (
+	// EntryBoundaryMarker prefixes each entry with a marker sequence that never appears in regular log content.
+	EntryBoundaryMarker EntryBoundaryMode = iota
+	// EntryBoundaryLengthHeader prefixes each entry with its length in bytes followed by a space, a.k.a. octet
+	// counting (RFC 6587). The length does not include the header itself or the trailing newline.
+	EntryBoundaryLengthHeader
+)
+
+// EntryBoundarySettings are the settings for [WithEntryBoundary].
+type EntryBoundarySettings struct {
+	// Mode is the kind of boundary to emit. Defaults to EntryBoundaryMarker.
+	Mode EntryBoundaryMode
+	// Marker is the marker prefixed to each entry in EntryBoundaryMarker mode. Defaults to the ASCII record separator
+	// character (0x1E).
+	Marker string
+}
+
+var defaultEntryBoundarySettings = EntryBoundarySettings{
+	Mode:   EntryBoundaryMarker,
+	Marker: "\x1e",
+}
+
+func (s *EntryBoundarySettings) mergeDefault() {
+	if s.Marker == "" {
+		s.Marker = defaultEntryBoundarySettings.Marker
+	}
+}
+
+// WithEntryBoundary makes the boundaries between entries explicit, so log shippers can reassemble entries that span
+// multiple lines (multi-line values, indented JSON, etc.).
+func WithEntryBoundary(settings *EntryBoundarySettings) FormatterOption {
+	if settings == nil {
+		settings = &EntryBoundarySettings{}
+	}
+	settings.mergeDefault()
+
+	return func(f LogLineFormatter) LogLineFormatter {
+		return &entryBoundaryFormatter{
+			BaseFormatter: f,
+			Settings:      *settings,
+		}
+	}
+}
+
+// entryBoundaryFormatter prefixes the output of the base formatter with an entry boundary.
+type entryBoundaryFormatter struct {
+	BaseFormatter LogLineFormatter
+	Settings      EntryBoundarySettings
+}
+
+// FormatLogLine formats the log line using the provided data and returns a FormatResult which contains the formatted
+// log line and any errors that may have occurred.
+func (f *entryBoundaryFormatter) FormatLogLine(args LogLineArgs, data []any) FormatResult {
+	res := f.BaseFormatter.FormatLogLine(args, data)
+	if res.err != nil || len(res.bytes) == 0 {
+		return res
+	}
+
+	var prefix []byte
+	switch f.Settings.Mode {
+	case EntryBoundaryLengthHeader:
+		prefix = strconv.AppendInt(nil, int64(len(res.bytes)), 10)
+		prefix = append(prefix, ' ')
+	default:
+		prefix = []byte(f.Settings.Marker)
+	}
+
+	line := make([]byte, 0, len(prefix)+len(res.bytes))
+	line = append(line, prefix...)
+	line = append(line, res.bytes...)
+
+	return FormatResult{line, nil}
+}
+
+// Unwrap returns the base formatter.
+func (f *entryBoundaryFormatter) Unwrap() LogLineFormatter {
+	return f.BaseFormatter
+}
@@ -0,0 +1,64 @@
+package log
+
+import (
+	"testing"
+)
+
+func TestWithEntryBoundary(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings *EntryBoundarySettings
+		data     []any
+		want     string
+	}{
+		{
+			name:     "Default marker",
+			settings: nil,
+			data:     []any{"msg"},
+			want:     "\x1e<INFO> msg",
+		},
+		{
+			name:     "Custom marker",
+			settings: &EntryBoundarySettings{Marker: "--8<-- "},
+			data:     []any{"msg"},
+			want:     "--8<-- <INFO> msg",
+		},
+		{
+			name:     "Length header",
+			settings: &EntryBoundarySettings{Mode: EntryBoundaryLengthHeader},
+			data:     []any{"multi\nline"},
+			want:     "17 <INFO> multi\nline",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFormatter(
+				OutputFormatText,
+				[]Field{NewDefaultLevelField(), NewMessageField()},
+				WithEntryBoundary(tt.settings),
+			)
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+
+			got := f.FormatLogLine(LogLineArgs{Level: Info}, tt.data)
+			if string(got.bytes) != tt.want {
+				t.Errorf("FormatLogLine() = %q, want %q", got.bytes, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithEntryBoundary_reachesWrappedFormatter(t *testing.T) {
+	f, _ := NewFormatter(
+		OutputFormatText,
+		[]Field{NewDefaultLevelField(), NewMessageField()},
+		WithEntryBoundary(nil),
+		WithMultilineIndent("| "),
+	)
+
+	got := f.FormatLogLine(LogLineArgs{Level: Info}, []any{"a\nb"})
+	if want := "\x1e<INFO> a\n| b"; string(got.bytes) != want {
+		t.Errorf("FormatLogLine() = %q, want %q", got.bytes, want)
+	}
+}
@@ -0,0 +1,97 @@
+package log
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// CBOR major types.
+const (
+	cborUnsigned = 0 << 5
+	cborNegative = 1 << 5
+	cborBytes    = 2 << 5
+	cborText     = 3 << 5
+	cborArray    = 4 << 5
+	cborMap      = 5 << 5
+	cborTag      = 6 << 5
+)
+
+// CBOR simple values, and the tag of RFC 3339 date/time strings.
+const (
+	cborFalse       = 0xf4
+	cborTrue        = 0xf5
+	cborNull        = 0xf6
+	cborFloat32     = 0xfa
+	cborFloat64     = 0xfb
+	cborTagDateTime = 0
+)
+
+// cborFormat is the binaryFormat of CBOR (RFC 8949). Times are written as RFC 3339 strings tagged as date/times.
+type cborFormat struct{}
+
+func (cborFormat) appendNull(dst []byte) []byte {
+	return append(dst, cborNull)
+}
+
+func (cborFormat) appendBool(dst []byte, b bool) []byte {
+	if b {
+		return append(dst, cborTrue)
+	}
+	return append(dst, cborFalse)
+}
+
+func (cborFormat) appendInt(dst []byte, n int64) []byte {
+	if n < 0 {
+		return appendCBORHead(dst, cborNegative, uint64(-1-n))
+	}
+	return appendCBORHead(dst, cborUnsigned, uint64(n))
+}
+
+func (cborFormat) appendUint(dst []byte, n uint64) []byte {
+	return appendCBORHead(dst, cborUnsigned, n)
+}
+
+func (cborFormat) appendFloat32(dst []byte, f float32) []byte {
+	return binary.BigEndian.AppendUint32(append(dst, cborFloat32), math.Float32bits(f))
+}
+
+func (cborFormat) appendFloat64(dst []byte, f float64) []byte {
+	return binary.BigEndian.AppendUint64(append(dst, cborFloat64), math.Float64bits(f))
+}
+
+func (cborFormat) appendString(dst []byte, s string) []byte {
+	return append(appendCBORHead(dst, cborText, uint64(len(s))), s...)
+}
+
+func (cborFormat) appendBytes(dst []byte, b []byte) []byte {
+	return append(appendCBORHead(dst, cborBytes, uint64(len(b))), b...)
+}
+
+func (f cborFormat) appendTime(dst []byte, t time.Time) []byte {
+	return f.appendString(appendCBORHead(dst, cborTag, cborTagDateTime), t.Format(time.RFC3339Nano))
+}
+
+func (cborFormat) appendArrayHead(dst []byte, n int) []byte {
+	return appendCBORHead(dst, cborArray, uint64(n))
+}
+
+func (cborFormat) appendMapHead(dst []byte, n int) []byte {
+	return appendCBORHead(dst, cborMap, uint64(n))
+}
+
+// appendCBORHead appends the head of a data item of the major type, with the argument n: a value, a length, or a tag.
+func appendCBORHead(dst []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(dst, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(dst, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, major|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(dst, major|27), n)
+	}
+}
@@ -0,0 +1,89 @@
+package log
+
+import (
+	"encoding/hex"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewFormatter_cbor(t *testing.T) {
+	formatter, err := NewFormatter(OutputFormatCBOR, []Field{NewLevelField(nil), NewMessageField()})
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+
+	result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{"hi"})
+	if result.err != nil {
+		t.Fatalf("FormatLogLine() error = %v", result.err)
+	}
+	// {"level": "INFO", "message": "hi"}
+	want := "a2" + "656c6576656c" + "64494e464f" + "676d657373616765" + "626869"
+	if got := hex.EncodeToString(result.bytes); got != want {
+		t.Errorf("FormatLogLine() = %s, want %s", got, want)
+	}
+}
+
+func TestCBOREncoder(t *testing.T) {
+	type inner struct {
+		B string `json:"b"`
+	}
+	type outer struct {
+		A       int    `json:"a"`
+		Skipped string `json:"-"`
+		Empty   string `json:"empty,omitempty"`
+		inner
+	}
+	cyclic := map[string]any{}
+	cyclic["self"] = cyclic
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"nil", nil, "f6"},
+		{"bool", true, "f5"},
+		{"small int", 10, "0a"},
+		{"int", 1000, "1903e8"},
+		{"negative int", -500, "3901f3"},
+		{"uint", uint64(math.MaxUint64), "1bffffffffffffffff"},
+		{"float", 1.5, "fb3ff8000000000000"},
+		{"float32", float32(1.5), "fa3fc00000"},
+		{"non-finite float", math.Inf(1), "642b496e66"},
+		{"string", "a", "6161"},
+		{"bytes", []byte{1, 2}, "420102"},
+		{"duration", 1500 * time.Millisecond, "1a59682f00"},
+		{"time", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), "c074323032342d30312d30325430333a30343a30355a"},
+		{"slice", []string{"a", "b"}, "8261616162"},
+		{"nil slice", []string(nil), "f6"},
+		{"map", map[int]bool{2: true, 1: false}, "a26131f46132f5"},
+		{"struct", outer{A: 1, inner: inner{B: "x"}}, "a261610161626178"},
+		{"json marshaler", &orderedMap{keys: []any{"z", "a"}, values: []any{1, 2}}, "a2617a01616102"},
+		{"cycle", cyclic, "a16473656c6667286379636c6529"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoder := &binaryEncoder{format: cborFormat{}, refs: refPath{}}
+			got, err := encoder.encode(nil, "value", reflect.ValueOf(tt.value), 0)
+			if err != nil {
+				t.Fatalf("encode() error = %v", err)
+			}
+			if hex.EncodeToString(got) != tt.want {
+				t.Errorf("encode() = %x, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCBOREncoder_droppedFloats(t *testing.T) {
+	encoder := &binaryEncoder{format: cborFormat{}, nonFiniteFloats: NonFiniteFloatDrop, refs: refPath{}}
+	got, err := encoder.encode(nil, "value", reflect.ValueOf(map[string]float64{"a": math.NaN(), "b": 1}), 0)
+	if err != nil {
+		t.Fatalf("encode() error = %v", err)
+	}
+	if want := "a16162fb3ff0000000000000"; hex.EncodeToString(got) != want {
+		t.Errorf("encode() = %x, want %s", got, want)
+	}
+}
@@ -0,0 +1,190 @@
+package log
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// CEFSettings are the settings of a CEF formatter.
+type CEFSettings struct {
+	// DeviceVendor, DeviceProduct and DeviceVersion identify the application in the header of the lines. DeviceProduct
+	// defaults to the name of the executable.
+	DeviceVendor  string
+	DeviceProduct string
+	DeviceVersion string
+	// SignatureID is the Signature ID of the lines, the type of event. Defaults to the tag of each line, or "log" if it
+	// has no tag.
+	SignatureID string
+	// MessageKey is the key of the field whose value is the Name of the lines. Defaults to "message".
+	MessageKey string
+	// Extensions maps the keys of fields to the CEF extension keys they are written under, e.g.
+	// {"user": "suser", "client_ip": "src"}. Other fields are written under their own keys, with objects flattened to
+	// dotted keys.
+	Extensions map[string]string
+}
+
+var defaultCEFSettings = CEFSettings{
+	SignatureID: "log",
+	MessageKey:  "message",
+}
+
+func (s *CEFSettings) mergeDefault() {
+	if s.DeviceProduct == "" && len(os.Args) > 0 {
+		s.DeviceProduct = filepath.Base(os.Args[0])
+	}
+	if s.MessageKey == "" {
+		s.MessageKey = defaultCEFSettings.MessageKey
+	}
+}
+
+// NewCEFFormatter returns a formatter that writes the entries of the base formatter as ArcSight Common Event Format
+// lines, for SIEMs that ingest CEF, e.g.
+//
+//	CEF:0|Acme|checkout|1.2.0|auth|login failed|5|rt=1704164645000 suser=alice src=203.0.113.7
+//
+// The Severity is the Level.CEFSeverity of the line, and the rt extension is the time of the line, in milliseconds
+// since the epoch. The field under settings.MessageKey is the Name; the other fields of the base formatter are
+// extensions, under the keys of settings.Extensions. Leave the level and time fields out of the base formatter, since
+// the line has them already.
+//
+// The base formatter must be a RecordFormatter; JSON formatters give the most faithful values.
+func NewCEFFormatter(base LogLineFormatter, settings *CEFSettings) (LogLineFormatter, error) {
+	recordFormatter, ok := base.(RecordFormatter)
+	if !ok {
+		return nil, ErrorCEFUnsupportedFormatter
+	}
+	s := CEFSettings{}
+	if settings != nil {
+		s = *settings
+	}
+	s.mergeDefault()
+
+	return &cefFormatter{BaseFormatter: recordFormatter, settings: s}, nil
+}
+
+// cefFormatter encodes the Records of the base formatter as CEF lines.
+type cefFormatter struct {
+	BaseFormatter RecordFormatter
+	settings      CEFSettings
+}
+
+// FormatLogLine formats the log line using the provided data and returns a FormatResult which contains the formatted
+// log line and any errors that may have occurred.
+func (f *cefFormatter) FormatLogLine(args LogLineArgs, data []any) FormatResult {
+	record, err := f.BaseFormatter.BuildRecord(args, data)
+	if err != nil {
+		return FormatResult{nil, err}
+	}
+
+	now := args.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+	signatureID := f.settings.SignatureID
+	if signatureID == "" {
+		signatureID = args.Tag
+	}
+	if signatureID == "" {
+		signatureID = defaultCEFSettings.SignatureID
+	}
+
+	var name string
+	extensions := []byte("rt=")
+	extensions = strconv.AppendInt(extensions, now.UnixMilli(), 10)
+	for _, field := range record.Fields {
+		if field.Key == f.settings.MessageKey {
+			name = flatValueString(field.Key, field.Value, NonFiniteFloatString, NestingLimits{})
+			continue
+		}
+		key := field.Key
+		if mapped, ok := f.settings.Extensions[key]; ok {
+			key = mapped
+		}
+		extensions = appendCEFExtensions(extensions, key, field.Value)
+	}
+
+	line := []byte("CEF:0")
+	for _, value := range []string{
+		f.settings.DeviceVendor, f.settings.DeviceProduct, f.settings.DeviceVersion, signatureID, name,
+	} {
+		line = append(line, '|')
+		line = appendCEFHeaderValue(line, value)
+	}
+	line = append(line, '|')
+	line = strconv.AppendInt(line, int64(args.Level.CEFSeverity()), 10)
+	line = append(line, '|')
+	line = append(line, extensions...)
+
+	return FormatResult{line, nil}
+}
+
+// Unwrap returns the base formatter.
+func (f *cefFormatter) Unwrap() LogLineFormatter {
+	return f.BaseFormatter
+}
+
+// appendCEFExtensions appends the extension of the field, or an extension per entry for objects, with dotted keys.
+func appendCEFExtensions(dst []byte, key string, value any) []byte {
+	if object, ok := value.(map[string]any); ok {
+		for _, k := range slices.Sorted(maps.Keys(object)) {
+			dst = appendCEFExtensions(dst, key+"."+k, object[k])
+		}
+		return dst
+	}
+	if value == nil {
+		return dst
+	}
+
+	dst = append(dst, ' ')
+	dst = appendCEFKey(dst, key)
+	dst = append(dst, '=')
+	for _, r := range flatValueString(key, value, NonFiniteFloatString, NestingLimits{}) {
+		switch r {
+		case '\\', '=':
+			dst = append(dst, '\\', byte(r))
+		case '\n':
+			dst = append(dst, `\n`...)
+		case '\r':
+			dst = append(dst, `\r`...)
+		default:
+			dst = append(dst, string(r)...)
+		}
+	}
+	return dst
+}
+
+// appendCEFKey appends an extension key: letters, digits, underscores and dots, with other characters replaced with
+// underscores.
+func appendCEFKey(dst []byte, key string) []byte {
+	if key == "" {
+		return append(dst, '_')
+	}
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.') {
+			c = '_'
+		}
+		dst = append(dst, c)
+	}
+	return dst
+}
+
+// appendCEFHeaderValue appends a header field, with pipes and backslashes escaped with a backslash, and line breaks
+// replaced with spaces.
+func appendCEFHeaderValue(dst []byte, value string) []byte {
+	for _, r := range value {
+		switch r {
+		case '\\', '|':
+			dst = append(dst, '\\', byte(r))
+		case '\n', '\r':
+			dst = append(dst, ' ')
+		default:
+			dst = append(dst, string(r)...)
+		}
+	}
+	return dst
+}
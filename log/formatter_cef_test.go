@@ -0,0 +1,81 @@
+package log
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewCEFFormatter(t *testing.T) {
+	countField, _ := NewIntField("count")
+	userField, _ := NewStringField("user")
+	cachedField, _ := NewBoolField("cached")
+	httpField, _ := NewCompositeField("http", cachedField)
+	base, err := NewFormatter(OutputFormatJSON, []Field{NewMessageField(), countField, userField, httpField})
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	device := CEFSettings{DeviceVendor: "Acme", DeviceProduct: "checkout", DeviceVersion: "1.2.0"}
+
+	tests := []struct {
+		name     string
+		settings CEFSettings
+		args     LogLineArgs
+		data     []any
+		want     string
+	}{
+		{
+			"message only",
+			device,
+			LogLineArgs{Level: Info, Time: at},
+			[]any{"started"},
+			"CEF:0|Acme|checkout|1.2.0|log|started|3|rt=1704164645000",
+		},
+		{
+			"mapped extensions",
+			CEFSettings{
+				DeviceVendor: "Acme", DeviceProduct: "checkout", DeviceVersion: "1.2.0",
+				Extensions: map[string]string{"user": "suser"},
+			},
+			LogLineArgs{Level: Warn, Time: at, Tag: "auth", entry: &Entry{Fields: map[string]any{"user": "alice"}}},
+			[]any{"login failed", 3},
+			"CEF:0|Acme|checkout|1.2.0|auth|login failed|5|rt=1704164645000 count=3 suser=alice",
+		},
+		{
+			"escaped values",
+			CEFSettings{DeviceVendor: `Ac|me`, DeviceProduct: "checkout", SignatureID: "pay"},
+			LogLineArgs{Level: Error, Time: at, entry: &Entry{Fields: map[string]any{"user": "a=b\\c\nd"}}},
+			[]any{"odd|user"},
+			`CEF:0|Ac\|me|checkout||pay|odd\|user|7|rt=1704164645000 user=a\=b\\c\nd`,
+		},
+		{
+			"nested",
+			device,
+			LogLineArgs{Level: Debug, Time: at},
+			[]any{"upstream", true},
+			"CEF:0|Acme|checkout|1.2.0|log|upstream|1|rt=1704164645000 http.cached=true",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter, err := NewCEFFormatter(base, &tt.settings)
+			if err != nil {
+				t.Fatalf("NewCEFFormatter() error = %v", err)
+			}
+			res := formatter.FormatLogLine(tt.args, tt.data)
+			if res.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", res.err)
+			}
+			if got := string(res.bytes); got != tt.want {
+				t.Errorf("FormatLogLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCEFFormatter_unsupportedFormatter(t *testing.T) {
+	if _, err := NewCEFFormatter(&transformFormatter{}, nil); !errors.Is(err, ErrorCEFUnsupportedFormatter) {
+		t.Errorf("NewCEFFormatter() error = %v, want %v", err, ErrorCEFUnsupportedFormatter)
+	}
+}
@@ -0,0 +1,169 @@
+package log
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"unicode/utf8"
+)
+
+// FormatterMiddleware wraps a formatter in another, which transforms the entries of the formatter it wraps.
+// ColorizedFormatter is the canonical example.
+type FormatterMiddleware func(next LogLineFormatter) LogLineFormatter
+
+// ChainFormatters wraps the base formatter in the middlewares, so that combinations of entry transformations compose
+// cleanly. The first middleware is the outermost: it transforms the output of all the others. E.g. to redact entries
+// before they are colorized:
+//
+//	log.ChainFormatters(base, log.ColorizeMiddleware(nil), log.RedactMiddleware(tokenPattern))
+//
+// Formatter options passed to NewFormatter still reach the base formatter through the chain.
+func ChainFormatters(base LogLineFormatter, middlewares ...FormatterMiddleware) LogLineFormatter {
+	f := base
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		f = middlewares[i](f)
+	}
+	return f
+}
+
+// WithMiddleware wraps the formatter in the middlewares, like ChainFormatters.
+func WithMiddleware(middlewares ...FormatterMiddleware) FormatterOption {
+	return func(f LogLineFormatter) LogLineFormatter {
+		return ChainFormatters(f, middlewares...)
+	}
+}
+
+// EntryTransform transforms the bytes of a formatted entry.
+type EntryTransform func(args LogLineArgs, entry []byte) ([]byte, error)
+
+// TransformMiddleware returns a FormatterMiddleware that applies the transform to every entry formatted without error.
+func TransformMiddleware(transform EntryTransform) FormatterMiddleware {
+	return func(next LogLineFormatter) LogLineFormatter {
+		return &transformFormatter{BaseFormatter: next, Transform: transform}
+	}
+}
+
+// transformFormatter applies an EntryTransform to the output of the base formatter.
+type transformFormatter struct {
+	BaseFormatter LogLineFormatter
+	Transform     EntryTransform
+}
+
+// FormatLogLine formats the log line using the provided data and returns a FormatResult which contains the formatted
+// log line and any errors that may have occurred.
+func (f *transformFormatter) FormatLogLine(args LogLineArgs, data []any) FormatResult {
+	res := f.BaseFormatter.FormatLogLine(args, data)
+	if res.err != nil {
+		return res
+	}
+
+	entry, err := f.Transform(args, res.bytes)
+	return FormatResult{entry, err}
+}
+
+// Unwrap returns the base formatter.
+func (f *transformFormatter) Unwrap() LogLineFormatter {
+	return f.BaseFormatter
+}
+
+// ColorizeMiddleware returns a FormatterMiddleware that colorizes entries, like NewColorizedFormatter.
+func ColorizeMiddleware(levelColors map[Level]Color) FormatterMiddleware {
+	return func(next LogLineFormatter) LogLineFormatter {
+		return NewColorizedFormatter(next, levelColors)
+	}
+}
+
+// RedactedText replaces the matches of RedactMiddleware.
+const RedactedText = "[REDACTED]"
+
+// RedactMiddleware returns a FormatterMiddleware that replaces every match of the patterns in an entry with
+// RedactedText, e.g. to keep tokens out of the logs whichever field they end up in.
+func RedactMiddleware(patterns ...*regexp.Regexp) FormatterMiddleware {
+	return TransformMiddleware(func(args LogLineArgs, entry []byte) ([]byte, error) {
+		for _, pattern := range patterns {
+			entry = pattern.ReplaceAll(entry, []byte(RedactedText))
+		}
+		return entry, nil
+	})
+}
+
+// TruncateMiddleware returns a FormatterMiddleware that cuts entries longer than maxBytes short, and marks them, e.g.
+// "...(truncated 1024 bytes)". Multi-byte characters aren't split. A maxBytes <= 0 doesn't truncate.
+func TruncateMiddleware(maxBytes int) FormatterMiddleware {
+	return TransformMiddleware(func(args LogLineArgs, entry []byte) ([]byte, error) {
+		if maxBytes <= 0 || len(entry) <= maxBytes {
+			return entry, nil
+		}
+
+		cut := maxBytes
+		for cut > 0 && !utf8.RuneStart(entry[cut]) {
+			cut--
+		}
+		return fmt.Appendf(entry[:cut:cut], "...(truncated %d bytes)", len(entry)-cut), nil
+	})
+}
+
+// RepeatKey identifies the entries CompressRepeatsMiddleware considers repeats of each other.
+type RepeatKey func(args LogLineArgs, data []any) string
+
+// DefaultRepeatKey is the RepeatKey of CompressRepeatsMiddleware if none is given: entries repeat each other if they
+// have the same level, tag, and data. Fields the logger adds to every entry, like the time, are ignored.
+func DefaultRepeatKey(args LogLineArgs, data []any) string {
+	return fmt.Sprintf("%v\x00%s\x00%s", args.Level, args.Tag, formatTextValue(data, NestingLimits{}))
+}
+
+// CompressRepeatsMiddleware returns a FormatterMiddleware that compresses runs of repeated entries. Entries with the
+// same key as the previous entry aren't formatted or written at all, and the next different entry is preceded by a
+// marker line, e.g. "(previous entry repeated 3 times)". The marker of a run is only written once a different entry is
+// logged. If key is nil, DefaultRepeatKey is used.
+//
+// Every formatter the middleware wraps tracks its own runs, in the order it formats entries.
+func CompressRepeatsMiddleware(key RepeatKey) FormatterMiddleware {
+	if key == nil {
+		key = DefaultRepeatKey
+	}
+	return func(next LogLineFormatter) LogLineFormatter {
+		return &repeatFormatter{BaseFormatter: next, key: key}
+	}
+}
+
+// repeatFormatter drops the entries of the base formatter that repeat the previous entry.
+type repeatFormatter struct {
+	BaseFormatter LogLineFormatter
+	key           RepeatKey
+
+	mu      sync.Mutex
+	last    string
+	started bool
+	repeats int
+}
+
+// FormatLogLine formats the log line using the provided data and returns a FormatResult which contains the formatted
+// log line and any errors that may have occurred.
+func (f *repeatFormatter) FormatLogLine(args LogLineArgs, data []any) FormatResult {
+	key := f.key(args, data)
+
+	f.mu.Lock()
+	if f.started && key == f.last {
+		f.repeats++
+		f.mu.Unlock()
+		return FormatResult{}
+	}
+	f.started, f.last = true, key
+	repeats := f.repeats
+	f.repeats = 0
+	f.mu.Unlock()
+
+	res := f.BaseFormatter.FormatLogLine(args, data)
+	if res.err != nil || repeats == 0 {
+		return res
+	}
+
+	marker := fmt.Appendf(nil, "(previous entry repeated %d times)\n", repeats)
+	return FormatResult{append(marker, res.bytes...), nil}
+}
+
+// Unwrap returns the base formatter.
+func (f *repeatFormatter) Unwrap() LogLineFormatter {
+	return f.BaseFormatter
+}
@@ -0,0 +1,109 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChainFormatters(t *testing.T) {
+	base, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+
+	upper := TransformMiddleware(func(args LogLineArgs, entry []byte) ([]byte, error) {
+		return bytes.ToUpper(entry), nil
+	})
+	formatter := ChainFormatters(
+		base,
+		TruncateMiddleware(20),
+		RedactMiddleware(regexp.MustCompile(`(?i)token=\w+`)),
+		upper,
+	)
+
+	// The first middleware is the outermost, so the entry is upper-cased, redacted, and then truncated.
+	result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{"login token=abc123 from somewhere"})
+	if want := "LOGIN [REDACTED] FRO...(truncated 11 bytes)"; string(result.bytes) != want {
+		t.Errorf("FormatLogLine() = %q, want %q", result.bytes, want)
+	}
+
+	if _, ok := unwrapFormatter[*textFormatter](formatter); !ok {
+		t.Error("unwrapFormatter() can't reach the base formatter through the chain")
+	}
+}
+
+func TestTruncateMiddleware_multiByte(t *testing.T) {
+	base, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+	formatter := ChainFormatters(base, TruncateMiddleware(2))
+
+	result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{"héllo"})
+	if want := "h...(truncated 5 bytes)"; string(result.bytes) != want {
+		t.Errorf("FormatLogLine() = %q, want %q", result.bytes, want)
+	}
+}
+
+func TestCompressRepeatsMiddleware(t *testing.T) {
+	base, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+	buf := &bytes.Buffer{}
+	logger, _ := NewLoggerWithOptions(
+		WithDestination(buf, ChainFormatters(base, CompressRepeatsMiddleware(nil))),
+		WithAsync(false),
+	)
+
+	for _, msg := range []string{"retrying", "retrying", "retrying", "connected", "connected"} {
+		logger.Info(msg)
+	}
+
+	want := "retrying\n(previous entry repeated 2 times)\nconnected\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestCompressRepeatsMiddleware_timestamps(t *testing.T) {
+	base, _ := NewFormatter(OutputFormatText, []Field{
+		NewCurrentTimeField(&CurrentTimeFieldSettings{Format: time.RFC3339Nano}),
+		NewMessageField(),
+	})
+	compress := CompressRepeatsMiddleware(nil)
+	buf, other := &bytes.Buffer{}, &bytes.Buffer{}
+	logger, _ := NewLoggerWithOptions(
+		WithDestination(buf, ChainFormatters(base, compress)),
+		WithDestination(other, ChainFormatters(base, compress)),
+		WithAsync(false),
+	)
+
+	for _, msg := range []string{"retrying", "retrying", "retrying", "connected"} {
+		logger.Info(msg)
+	}
+
+	// Each wrapped formatter tracks its own runs, so both destinations get the whole output.
+	for name, output := range map[string]string{"buf": buf.String(), "other": other.String()} {
+		lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+		if len(lines) != 3 || lines[1] != "(previous entry repeated 2 times)" || !strings.HasSuffix(lines[2], "connected") {
+			t.Errorf("%s output = %q, want the repeats compressed", name, output)
+		}
+	}
+}
+
+func TestCompressRepeatsMiddleware_key(t *testing.T) {
+	base, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+	byMessage := func(args LogLineArgs, data []any) string {
+		return fmt.Sprint(data[0])
+	}
+	buf := &bytes.Buffer{}
+	logger, _ := NewLoggerWithOptions(
+		WithDestination(buf, ChainFormatters(base, CompressRepeatsMiddleware(byMessage))),
+		WithAsync(false),
+	)
+
+	logger.Info("retrying", 1)
+	logger.Warn("retrying", 2)
+	logger.Info("connected")
+
+	want := "retrying\n(previous entry repeated 1 times)\nconnected\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
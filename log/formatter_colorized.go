@@ -16,13 +16,17 @@ var defaultLevelColors = map[Level]Color{
 type ColorizedFormatter struct {
     BaseFormatter LogLineFormatter
     LevelColors   map[Level]Color
+
+    // Policy determines whether colors are emitted at all. The default, ColorPolicyAuto, honors NO_COLOR, CLICOLOR,
+    // and CLICOLOR_FORCE. See [ColorPolicy].
+    Policy ColorPolicy
 }
 
 // FormatLogLine formats the log line using the provided data and returns a FormatResult which contains the formatted
 // log line and any errors that may have occurred.
 func (f *ColorizedFormatter) FormatLogLine(args LogLineArgs, data []any) FormatResult {
     res := f.BaseFormatter.FormatLogLine(args, data)
-    if res.err != nil {
+    if res.err != nil || !f.Policy.colorsEnabled() {
         return res
     }
 
@@ -34,6 +38,32 @@ func (f *ColorizedFormatter) FormatLogLine(args LogLineArgs, data []any) FormatR
     return FormatResult{color.Colorize(res.bytes), nil}
 }
 
+// appendMessageLine implements messageLineFormatter if the base formatter does, and the level color is a ColorAnsi.
+func (f *ColorizedFormatter) appendMessageLine(dst []byte, args LogLineArgs, msg string) ([]byte, bool) {
+    base, ok := f.BaseFormatter.(messageLineFormatter)
+    if !ok {
+        return nil, false
+    }
+
+    if !f.Policy.colorsEnabled() {
+        return base.appendMessageLine(dst, args, msg)
+    }
+
+    color, ok := f.LevelColors[args.Level].(ColorAnsi)
+    if !ok {
+        return nil, false
+    }
+
+    return color.appendColorized(dst, func(dst []byte) ([]byte, bool) {
+        return base.appendMessageLine(dst, args, msg)
+    })
+}
+
+// Unwrap returns the base formatter.
+func (f *ColorizedFormatter) Unwrap() LogLineFormatter {
+    return f.BaseFormatter
+}
+
 // NewColorizedFormatter returns a new ColorizedFormatter that formats the provided base formatter with the provided
 // colors.
 func NewColorizedFormatter(baseFormatter LogLineFormatter, levelColors map[Level]Color) *ColorizedFormatter {
@@ -0,0 +1,168 @@
+package log
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// ConsoleSettings are the settings of a console formatter.
+type ConsoleSettings struct {
+	// TimeFormat is the format of the time of the lines. Defaults to "15:04:05.000".
+	TimeFormat string
+	// TagWidth is the width the tags of the lines are padded to. Defaults to 10. Longer tags are written as-is.
+	TagWidth int
+	// MessageKey is the key of the field whose value is the message of the lines. Defaults to "message".
+	MessageKey string
+	// LevelColors are the colors of the levels. Defaults to the default colors of NewColorizedFormatter.
+	LevelColors map[Level]Color
+	// KeyColor and ValueColor are the colors of the keys and values of the fields. Default to dimmed and cyan.
+	KeyColor   Color
+	ValueColor Color
+	// Policy determines whether colors are emitted at all. See [ColorPolicy].
+	Policy ColorPolicy
+}
+
+var defaultConsoleSettings = ConsoleSettings{
+	TimeFormat:  "15:04:05.000",
+	TagWidth:    10,
+	MessageKey:  "message",
+	LevelColors: defaultLevelColors,
+	KeyColor:    Colors.Default.Dim(),
+	ValueColor:  Colors.Cyan,
+}
+
+func (s *ConsoleSettings) mergeDefault() {
+	if s.TimeFormat == "" {
+		s.TimeFormat = defaultConsoleSettings.TimeFormat
+	}
+	if s.TagWidth == 0 {
+		s.TagWidth = defaultConsoleSettings.TagWidth
+	}
+	if s.MessageKey == "" {
+		s.MessageKey = defaultConsoleSettings.MessageKey
+	}
+	if s.LevelColors == nil {
+		s.LevelColors = maps.Clone(defaultConsoleSettings.LevelColors)
+	}
+	if s.KeyColor == nil {
+		s.KeyColor = defaultConsoleSettings.KeyColor
+	}
+	if s.ValueColor == nil {
+		s.ValueColor = defaultConsoleSettings.ValueColor
+	}
+}
+
+// consoleLevelWidth is the width levels are padded to, the width of the longest one.
+const consoleLevelWidth = 5
+
+// NewConsoleFormatter returns a formatter that writes the entries of the base formatter as aligned, colorized lines for
+// reading in a terminal during development, like the ConsoleWriter of zerolog:
+//
+//	15:04:05.000 INFO  checkout   payment started user=42 amount=9.99
+//	15:04:05.120 ERROR checkout   payment failed user=42 err="card declined"
+//
+// The time, level and tag of the line start it, with the levels and tags padded so that the messages line up. The
+// field under settings.MessageKey follows, then the other fields of the base formatter as key=value pairs with dimmed
+// keys and colorized values, and objects flattened to dotted keys. The tag column is left out of lines without a tag.
+// Leave the level, time and tag fields out of the base formatter, since the line has them already.
+//
+// The base formatter must be a RecordFormatter; JSON formatters give the most faithful values.
+func NewConsoleFormatter(base LogLineFormatter, settings *ConsoleSettings) (LogLineFormatter, error) {
+	recordFormatter, ok := base.(RecordFormatter)
+	if !ok {
+		return nil, ErrorConsoleUnsupportedFormatter
+	}
+	s := ConsoleSettings{}
+	if settings != nil {
+		s = *settings
+	}
+	s.mergeDefault()
+
+	return &consoleFormatter{BaseFormatter: recordFormatter, settings: s}, nil
+}
+
+// consoleFormatter encodes the Records of the base formatter as aligned, colorized lines.
+type consoleFormatter struct {
+	BaseFormatter RecordFormatter
+	settings      ConsoleSettings
+}
+
+// FormatLogLine formats the log line using the provided data and returns a FormatResult which contains the formatted
+// log line and any errors that may have occurred.
+func (f *consoleFormatter) FormatLogLine(args LogLineArgs, data []any) FormatResult {
+	record, err := f.BaseFormatter.BuildRecord(args, data)
+	if err != nil {
+		return FormatResult{nil, err}
+	}
+
+	colors := f.settings.Policy.colorsEnabled()
+	now := args.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	line := now.AppendFormat(nil, f.settings.TimeFormat)
+	line = append(line, ' ')
+	level := appendPadded(nil, args.Level.String(), consoleLevelWidth)
+	if color, ok := f.settings.LevelColors[args.Level]; ok && colors {
+		level = color.Colorize(level)
+	}
+	line = append(line, level...)
+	if args.Tag != "" {
+		line = append(line, ' ')
+		line = appendPadded(line, args.Tag, f.settings.TagWidth)
+	}
+
+	var pairs []byte
+	for _, field := range record.Fields {
+		if field.Key == f.settings.MessageKey {
+			message := flatValueString(field.Key, field.Value, record.Args.NonFiniteFloats, NestingLimits{})
+			line = append(append(line, ' '), message...)
+			continue
+		}
+		pairs = f.appendPair(pairs, field.Key, field.Value, record.Args.NonFiniteFloats, colors)
+	}
+
+	return FormatResult{append(line, pairs...), nil}
+}
+
+// appendPair appends the pair of the key and value, preceded by a space, or a pair per entry for objects, with dotted
+// keys.
+func (f *consoleFormatter) appendPair(
+	dst []byte, key string, value any, nonFiniteFloats NonFiniteFloatPolicy, colors bool,
+) []byte {
+	if object, ok := value.(map[string]any); ok {
+		for _, name := range slices.Sorted(maps.Keys(object)) {
+			dst = f.appendPair(dst, key+"."+name, object[name], nonFiniteFloats, colors)
+		}
+		return dst
+	}
+	if value == nil {
+		return dst
+	}
+
+	k := appendLogfmtKey(nil, key)
+	v := appendLogfmtValue(nil, flatValueString(key, value, nonFiniteFloats, NestingLimits{}))
+	if colors {
+		k = f.settings.KeyColor.Colorize(append(k, '='))
+		v = f.settings.ValueColor.Colorize(v)
+	} else {
+		k = append(k, '=')
+	}
+	return append(append(append(dst, ' '), k...), v...)
+}
+
+// Unwrap returns the base formatter.
+func (f *consoleFormatter) Unwrap() LogLineFormatter {
+	return f.BaseFormatter
+}
+
+// appendPadded appends the value, right-padded with spaces to the width.
+func appendPadded(dst []byte, value string, width int) []byte {
+	dst = append(dst, value...)
+	for n := len(value); n < width; n++ {
+		dst = append(dst, ' ')
+	}
+	return dst
+}
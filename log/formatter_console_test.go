@@ -0,0 +1,77 @@
+package log
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewConsoleFormatter(t *testing.T) {
+	userField, _ := NewStringField("user")
+	cachedField, _ := NewBoolField("cached")
+	httpField, _ := NewCompositeField("http", cachedField)
+	base, err := NewFormatter(OutputFormatJSON, []Field{NewMessageField(), userField, httpField})
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	at := time.Date(2024, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	withUser := &Entry{Fields: map[string]any{"user": "alice smith"}}
+
+	tests := []struct {
+		name     string
+		settings ConsoleSettings
+		args     LogLineArgs
+		data     []any
+		want     string
+	}{
+		{
+			"message only",
+			ConsoleSettings{Policy: ColorPolicyNever},
+			LogLineArgs{Level: Info, Time: at},
+			[]any{"started"},
+			"03:04:05.006 INFO  started",
+		},
+		{
+			"padded tag",
+			ConsoleSettings{Policy: ColorPolicyNever},
+			LogLineArgs{Level: Warn, Time: at, Tag: "checkout", entry: withUser},
+			[]any{"payment failed"},
+			`03:04:05.006 WARN  checkout   payment failed user="alice smith"`,
+		},
+		{
+			"nested",
+			ConsoleSettings{Policy: ColorPolicyNever, TimeFormat: time.Kitchen, TagWidth: 4},
+			LogLineArgs{Level: Error, Time: at, Tag: "checkout"},
+			[]any{"upstream", true},
+			"3:04AM ERROR checkout upstream http.cached=true",
+		},
+		{
+			"colors",
+			ConsoleSettings{Policy: ColorPolicyAlways},
+			LogLineArgs{Level: Error, Time: at, entry: withUser},
+			[]any{"failed"},
+			"03:04:05.006 \x1b[31mERROR\x1b[0m failed \x1b[2;39muser=\x1b[0m\x1b[36m\"alice smith\"\x1b[0m",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter, err := NewConsoleFormatter(base, &tt.settings)
+			if err != nil {
+				t.Fatalf("NewConsoleFormatter() error = %v", err)
+			}
+			res := formatter.FormatLogLine(tt.args, tt.data)
+			if res.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", res.err)
+			}
+			if got := string(res.bytes); got != tt.want {
+				t.Errorf("FormatLogLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewConsoleFormatter_unsupportedFormatter(t *testing.T) {
+	if _, err := NewConsoleFormatter(&transformFormatter{}, nil); !errors.Is(err, ErrorConsoleUnsupportedFormatter) {
+		t.Errorf("NewConsoleFormatter() error = %v, want %v", err, ErrorConsoleUnsupportedFormatter)
+	}
+}
@@ -0,0 +1,69 @@
+package log
+
+import "strings"
+
+// CSVHeader returns the header row of the CSV formatter f: the keys of its fields, in the order of its columns. It
+// returns false if f isn't a CSV formatter. Write it as the first line of the files of the formatter, e.g. with
+// WithFileSeparator and WithFileTruncate.
+func CSVHeader(f LogLineFormatter) (string, bool) {
+	jf, ok := unwrapFormatter[*jsonFormatter](f)
+	if !ok || jf.Output != OutputFormatCSV {
+		return "", false
+	}
+
+	var line []byte
+	for i, column := range jf.csvColumns() {
+		if i > 0 {
+			line = append(line, ',')
+		}
+		line = appendCSVValue(line, column)
+	}
+	return string(line), true
+}
+
+// csvColumns returns the keys of the fields, in order. A field written under several keys, e.g. legacy keys of a
+// schema, has a column per key.
+func (f *jsonFormatter) csvColumns() []string {
+	columns := make([]string, 0, len(f.Fields))
+	for _, field := range f.Fields {
+		if f.MaxSensitivity != nil && field.Settings().Sensitivity > *f.MaxSensitivity {
+			continue
+		}
+		if keys, ok := f.Keys.lookup(field.Name()); ok {
+			columns = append(columns, keys...)
+			continue
+		}
+		columns = append(columns, field.Name())
+	}
+	return columns
+}
+
+// encodeCSV renders the Record as a CSV record, with the value of every column, or nothing for the fields without one.
+func (f *jsonFormatter) encodeCSV(record *Record) []byte {
+	values := make(map[string]any, len(record.Fields))
+	for _, field := range record.Fields {
+		values[field.Key] = field.Value
+	}
+
+	var line []byte
+	for i, column := range f.csvColumns() {
+		if i > 0 {
+			line = append(line, ',')
+		}
+		if value, ok := values[column]; ok && value != nil {
+			line = appendCSVValue(line, flatValueString(column, value, f.NonFiniteFloats, f.NestingLimits))
+		}
+	}
+	return line
+}
+
+// appendCSVValue appends the value, quoted if it contains a comma, a quote or a line break, with its quotes doubled.
+func appendCSVValue(dst []byte, value string) []byte {
+	if !strings.ContainsAny(value, ",\"\r\n") {
+		return append(dst, value...)
+	}
+
+	dst = append(dst, '"')
+	dst = append(dst, strings.ReplaceAll(value, `"`, `""`)...)
+	return append(dst, '"')
+}
@@ -0,0 +1,68 @@
+package log
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewFormatter_csv(t *testing.T) {
+	durationField, _ := NewDurationField("took")
+	errorField, _ := NewErrorField("err")
+	tagsField, _ := NewArrayField[string]("tags", func(args LogLineArgs, data string) (any, error) { return data, nil })
+	fields := []Field{NewLevelField(nil), NewMessageField(), durationField, errorField, tagsField}
+
+	tests := []struct {
+		name string
+		data []any
+		want string
+	}{
+		{"plain", []any{"started"}, "INFO,started,,,"},
+		{"quoted", []any{`say "hi", then leave`}, `INFO,"say ""hi"", then leave",,,`},
+		{"line break", []any{"two\nlines"}, "INFO,\"two\nlines\",,,"},
+		{
+			"typed values",
+			[]any{"done", 1500 * time.Millisecond, errors.New("not found")},
+			"INFO,done,1.5s,not found,",
+		},
+		{"array", []any{"tagged", []string{"a", "b"}}, `INFO,tagged,,,"[""a"",""b""]"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter, err := NewFormatter(OutputFormatCSV, fields)
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+
+			res := formatter.FormatLogLine(LogLineArgs{Level: Info}, tt.data)
+			if res.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", res.err)
+			}
+			if got := string(res.bytes); got != tt.want {
+				t.Errorf("FormatLogLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVHeader(t *testing.T) {
+	countField, _ := NewIntField("count")
+	fields := []Field{NewLevelField(nil), NewMessageField(), countField}
+
+	formatter, err := NewFormatter(OutputFormatCSV, fields, WithFieldAlias("message", "msg"))
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	if got, ok := CSVHeader(formatter); !ok || got != "level,msg,count" {
+		t.Errorf("CSVHeader() = %q, %v, want %q", got, ok, "level,msg,count")
+	}
+	res := formatter.FormatLogLine(LogLineArgs{Level: Warn}, []any{"low disk", 3})
+	if got := string(res.bytes); got != "WARN,low disk,3" {
+		t.Errorf("FormatLogLine() = %q, want %q", got, "WARN,low disk,3")
+	}
+
+	jsonFormatter, _ := NewFormatter(OutputFormatJSON, fields)
+	if _, ok := CSVHeader(jsonFormatter); ok {
+		t.Error("CSVHeader() of a JSON formatter = true, want false")
+	}
+}
@@ -0,0 +1,171 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// fuzzFields are the fields of the formatters under fuzz. They cover every built-in data-matching field type.
+func fuzzFields(t testing.TB) []Field {
+	t.Helper()
+
+	stringField, _ := NewStringField("string")
+	intField, _ := NewIntField("int")
+	floatField, _ := NewFloatField("float")
+	boolField, _ := NewBoolField("bool")
+	errorField, _ := NewErrorField("error")
+	arrayField, _ := NewArrayField[float64]("array", func(args LogLineArgs, data float64) (any, error) {
+		return data, nil
+	})
+	mapField, _ := NewMapField[string, any](
+		"map",
+		func(args LogLineArgs, data string) (any, error) { return data, nil },
+		func(args LogLineArgs, data any) (any, error) { return data, nil },
+	)
+	tagField, _ := NewTagField(nil)
+
+	return []Field{
+		NewDefaultCurrentTimeField(),
+		NewDefaultLevelField(),
+		tagField,
+		NewMessageField(),
+		stringField,
+		intField,
+		floatField,
+		boolField,
+		errorField,
+		arrayField,
+		mapField,
+	}
+}
+
+// fuzzData turns fuzz inputs into log line data, including values that are notoriously hard to format.
+func fuzzData(msg, key string, n int64, bits uint64, b bool) []any {
+	f := math.Float64frombits(bits)
+
+	nested := map[string]any{key: msg}
+	for i := 0; i < 64; i++ {
+		nested = map[string]any{key: nested}
+	}
+
+	return []any{
+		msg,
+		msg + key,
+		int(n),
+		f,
+		b,
+		errors.New(msg),
+		[]float64{f, math.NaN(), math.Inf(1)},
+		map[string]any{key: f, "nested": nested, "channel": make(chan int)},
+	}
+}
+
+func addFuzzSeeds(f *testing.F) {
+	f.Add("hello", "key", int64(42), math.Float64bits(1.5), true)
+	f.Add("", "", int64(0), math.Float64bits(math.NaN()), false)
+	f.Add("multi\nline\x00\xff", "\"quoted\"", int64(math.MinInt64), math.Float64bits(math.Inf(-1)), true)
+	f.Add("\x1b[31mred\x1b[0m", "ключ", int64(math.MaxInt64), math.Float64bits(-0.0), false)
+}
+
+func FuzzTextFormatter(f *testing.F) {
+	addFuzzSeeds(f)
+
+	formatter, err := NewFormatter(OutputFormatText, fuzzFields(f))
+	if err != nil {
+		f.Fatalf("NewFormatter() error = %v", err)
+	}
+
+	f.Fuzz(func(t *testing.T, msg, key string, n int64, bits uint64, b bool) {
+		result := formatter.FormatLogLine(LogLineArgs{Level: Info, Tag: key}, fuzzData(msg, key, n, bits, b))
+		if result.err != nil {
+			t.Fatalf("FormatLogLine() error = %v", result.err)
+		}
+		if !strings.Contains(string(result.bytes), msg) {
+			t.Errorf("FormatLogLine() = %q, want it to contain the message %q", result.bytes, msg)
+		}
+	})
+}
+
+func FuzzJSONFormatter(f *testing.F) {
+	addFuzzSeeds(f)
+
+	formatter, err := NewFormatter(OutputFormatJSON, fuzzFields(f))
+	if err != nil {
+		f.Fatalf("NewFormatter() error = %v", err)
+	}
+
+	f.Fuzz(func(t *testing.T, msg, key string, n int64, bits uint64, b bool) {
+		result := formatter.FormatLogLine(LogLineArgs{Level: Info, Tag: key}, fuzzData(msg, key, n, bits, b))
+		if result.err != nil {
+			t.Fatalf("FormatLogLine() error = %v", result.err)
+		}
+		if !json.Valid(result.bytes) {
+			t.Fatalf("FormatLogLine() = %q, want valid JSON", result.bytes)
+		}
+
+		var line map[string]any
+		if err := json.Unmarshal(result.bytes, &line); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+		if _, ok := line["message"]; !ok {
+			t.Errorf("FormatLogLine() = %q, want the message to survive", result.bytes)
+		}
+	})
+}
+
+func TestFormatter_fieldFormatterPanic(t *testing.T) {
+	panicky, _ := NewObjectField[int]("panicky", func(args LogLineArgs, data int) (any, error) {
+		panic("boom")
+	})
+
+	for _, format := range []OutputFormat{OutputFormatText, OutputFormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			formatter, _ := NewFormatter(format, []Field{NewMessageField(), panicky})
+
+			result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{"hello", 1})
+			if result.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", result.err)
+			}
+			if !strings.Contains(string(result.bytes), "hello") || !strings.Contains(string(result.bytes), "boom") {
+				t.Errorf("FormatLogLine() = %q, want the message and the panic", result.bytes)
+			}
+		})
+	}
+}
+
+// panickingMarshaler panics when marshalled to JSON.
+type panickingMarshaler struct{}
+
+func (panickingMarshaler) MarshalJSON() ([]byte, error) {
+	panic("boom")
+}
+
+func TestJSONFormatter_unsupportedValues(t *testing.T) {
+	floatField, _ := NewFloatField("float")
+	marshalerField, _ := NewObjectField[panickingMarshaler]("marshaler", func(args LogLineArgs, data panickingMarshaler) (any, error) {
+		return data, nil
+	})
+	formatter, _ := NewFormatter(OutputFormatJSON, []Field{NewMessageField(), floatField, marshalerField})
+
+	result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{"hello", math.NaN(), panickingMarshaler{}})
+	if result.err != nil {
+		t.Fatalf("FormatLogLine() error = %v", result.err)
+	}
+
+	var line map[string]any
+	if err := json.Unmarshal(result.bytes, &line); err != nil {
+		t.Fatalf("FormatLogLine() = %q, want valid JSON: %v", result.bytes, err)
+	}
+	if line["message"] != "hello" {
+		t.Errorf("message = %v, want hello", line["message"])
+	}
+	if line["float"] != "NaN" {
+		t.Errorf("float = %v, want NaN as a string", line["float"])
+	}
+	if s, ok := line["marshaler"].(string); !ok || !strings.Contains(s, "marshaler") {
+		t.Errorf("marshaler = %v, want an error message in its place", line["marshaler"])
+	}
+}
@@ -1,13 +1,40 @@
 package log
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 )
 
-// jsonFormatter is a formatter that formats log lines as JSON.
+// jsonFormatter is a formatter that formats log lines as JSON, or as formats with the same structure: XML, CBOR,
+// MessagePack and CSV.
 type jsonFormatter struct {
 	Fields          []Field // Keep these in an array to preserve the order of the fields.
 	FieldFormatters map[string]FieldFormatter
+	FieldCache      *fieldResultCache
+	NonFiniteFloats NonFiniteFloatPolicy
+	NestingLimits   NestingLimits
+	Keys            fieldKeys
+	SortMapKeys     bool
+	Validation      *entryValidation
+	SeverityProfile *SeverityProfile
+	Output          OutputFormat // XML, CBOR, MessagePack or CSV. The zero value writes JSON.
+	XMLAttributes   bool         // Write the scalar fields of XML lines as attributes.
+	Indent          string       // Indent JSON lines over multiple lines with this prefix per level, if not empty.
+	MaxSensitivity  *Sensitivity // Fields classified above it aren't written. Nil when there's no maximum.
+}
+
+// WithJSONIndent writes the objects of a JSON formatter over multiple lines, with each level indented by the indent,
+// e.g. WithJSONIndent("  "), for readable lines during development. Indented lines can't be read by line-oriented
+// tools, like Query, TailFile, or most collectors; keep single-line JSON in production. It has no effect on other
+// formatters.
+func WithJSONIndent(indent string) FormatterOption {
+	return func(f LogLineFormatter) LogLineFormatter {
+		if jf, ok := unwrapFormatter[*jsonFormatter](f); ok && jf.Output == "" {
+			jf.Indent = indent
+		}
+		return f
+	}
 }
 
 // TODO: Provide a way to specify behavior on nil data. I.e. if the field should be omitted, or if we should include
@@ -17,31 +44,121 @@ type jsonFormatter struct {
 // FormatLogLine formats the log line using the provided data and returns a FormatResult which contains the formatted
 // log line and any errors that may have occurred.
 func (f *jsonFormatter) FormatLogLine(args LogLineArgs, data []any) FormatResult {
+	if args.line != nil {
+		line := args.line
+		args.line = nil
+		return line.format(f, func() FormatResult { return f.FormatLogLine(args, data) })
+	}
+
+	record, err := f.BuildRecord(args, data)
+	if err != nil {
+		return FormatResult{nil, err}
+	}
+
+	jBytes, err := f.Encode(record)
+	return FormatResult{jBytes, err}
+}
+
+// BuildRecord runs the fields of the formatter over the data, and returns their results as a Record.
+func (f *jsonFormatter) BuildRecord(args LogLineArgs, data []any) (*Record, error) {
 	args.OutputFormat = OutputFormatJSON
+	if f.Output != "" {
+		args.OutputFormat = f.Output
+	}
+	args.NonFiniteFloats = f.NonFiniteFloats
+	args.SchemaVersion = f.Keys.schemaVersion()
+	args.SortMapKeys = f.SortMapKeys
+	args.SeverityProfile = f.SeverityProfile
 
-	jsonMap := make(map[string]any)
-	fieldResultChan := make(chan fieldProcessingResult)
+	builder := recordBuilder{
+		fields:         f.Fields,
+		formatters:     f.FieldFormatters,
+		cache:          f.FieldCache,
+		keys:           &f.Keys,
+		validation:     f.Validation,
+		maxSensitivity: f.MaxSensitivity,
+	}
+	return builder.build(args, data)
+}
 
-	// Guaranteed to close on error result and once all fields have been processed.
-	// TODO: Could potentially optimize this by moving the goroutine *into* the processor, spinning up goroutines for
-	//  each field we need to process, and using a shared structure for the checked fields/written data... That will
-	//  make field-to-data-type mappings a bit more complex, but we'd just need to make sure that all data of the same
-	//  type is processed in-order. :thinking:
-	go processFieldsWithData(fieldResultChan, args, f.Fields, f.FieldFormatters, data)
+// Encode renders the Record as a JSON object, an XML element, a CBOR or MessagePack map, or a CSV record.
+func (f *jsonFormatter) Encode(record *Record) ([]byte, error) {
+	switch f.Output {
+	case OutputFormatCBOR:
+		return f.encodeBinary(record, cborFormat{})
+	case OutputFormatMsgpack:
+		return f.encodeBinary(record, msgpackFormat{})
+	case OutputFormatCSV:
+		return f.encodeCSV(record), nil
+	}
 
-	for {
-		result, ok := <-fieldResultChan
-		if !ok {
-			break
+	jsonMap := make(map[string]any, len(record.Fields))
+	for _, field := range record.Fields {
+		jsonMap[field.Key] = field.Value
+		// JSON writes most errors as empty objects, which would leave empty elements.
+		if err, ok := field.Value.(error); ok && f.Output == OutputFormatXML {
+			if _, ok := err.(json.Marshaler); !ok {
+				jsonMap[field.Key] = err.Error()
+			}
 		}
+	}
+	line, err := f.marshalJSONLine(jsonMap)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case f.Output == OutputFormatXML:
+		return encodeXML(record, line, f.XMLAttributes)
+	case f.Indent != "":
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, line, "", f.Indent); err != nil {
+			return nil, err
+		}
+		return indented.Bytes(), nil
+	}
+	return line, nil
+}
 
-		if result.err != nil {
-			return FormatResult{nil, result.err}
+// marshalJSONLine marshals the fields of a line. If that fails, or if the formatter has NestingLimits, the fields are
+// sanitized (see jsonSanitizer) and marshalled again. Fields that still can't be marshalled (channels, failing
+// MarshalJSON methods, ...) are replaced with the error message, so one bad value doesn't cost the whole line.
+func (f *jsonFormatter) marshalJSONLine(jsonMap map[string]any) ([]byte, error) {
+	if f.NestingLimits == (NestingLimits{}) {
+		if b, err := marshalJSON(jsonMap); err == nil {
+			return b, nil
 		}
+	}
 
-		jsonMap[result.fieldName] = result.fieldData
+	sanitizer := &jsonSanitizer{nonFiniteFloats: f.NonFiniteFloats, limits: f.NestingLimits}
+	jsonMap, err := sanitizer.sanitizeFields(jsonMap)
+	if err != nil {
+		return nil, err
+	}
+	if b, err := marshalJSON(jsonMap); err == nil {
+		return b, nil
 	}
 
-	jBytes, err := json.Marshal(jsonMap)
-	return FormatResult{jBytes, err}
+	safeMap := make(map[string]any, len(jsonMap))
+	for name, value := range jsonMap {
+		b, err := marshalJSON(value)
+		if err != nil {
+			safeMap[name] = (&ErrorNonFatalFormatterError{fieldName: name, err: err}).Error()
+			continue
+		}
+		safeMap[name] = json.RawMessage(b)
+	}
+
+	// Strings and valid raw messages always marshal.
+	return json.Marshal(safeMap)
+}
+
+// marshalJSON is json.Marshal, but turns panics in MarshalJSON methods into errors.
+func marshalJSON(v any) (b []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			b, err = nil, fmt.Errorf("json: panic while marshalling %T: %v", v, r)
+		}
+	}()
+
+	return json.Marshal(v)
 }
@@ -0,0 +1,34 @@
+package log
+
+import "testing"
+
+func TestWithJSONIndent(t *testing.T) {
+	tagsField, _ := NewArrayField[string]("tags", func(args LogLineArgs, data string) (any, error) { return data, nil })
+	fields := []Field{NewMessageField(), tagsField}
+
+	tests := []struct {
+		name   string
+		format OutputFormat
+		want   string
+	}{
+		{"json", OutputFormatJSON, "{\n  \"message\": \"hello\",\n  \"tags\": [\n    \"a\",\n    \"b\"\n  ]\n}"},
+		{"xml", OutputFormatXML, "<log><message>hello</message><tags><item>a</item><item>b</item></tags></log>"},
+		{"text", OutputFormatText, "hello tags=[a, b]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter, err := NewFormatter(tt.format, fields, WithJSONIndent("  "))
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+
+			res := formatter.FormatLogLine(LogLineArgs{}, []any{"hello", []string{"a", "b"}})
+			if res.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", res.err)
+			}
+			if got := string(res.bytes); got != tt.want {
+				t.Errorf("FormatLogLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// encodeLogfmt renders the Record as a logfmt line: a key=value pair per field, separated by spaces. Every field is
+// keyed, even with HideKey, since logfmt parsers drop unkeyed values.
+func (f *textFormatter) encodeLogfmt(record *Record) ([]byte, error) {
+	var line []byte
+	var err error
+	for _, field := range record.Fields {
+		if line, err = f.appendLogfmtPair(line, field.Key, field.Value); err != nil {
+			return nil, err
+		}
+	}
+	return line, nil
+}
+
+// appendLogfmtPair appends the pair of the key and value to the line. Objects, like the EventFields of events and the
+// children of composite fields, are written as a pair per entry, with dotted keys. NaN and ±Inf floats are written,
+// dropped or fail the line according to the NonFiniteFloatPolicy of the formatter.
+func (f *textFormatter) appendLogfmtPair(line []byte, key string, value any) ([]byte, error) {
+	if object, ok := value.(map[string]any); ok {
+		var err error
+		for _, name := range slices.Sorted(maps.Keys(object)) {
+			if line, err = f.appendLogfmtPair(line, key+"."+name, object[name]); err != nil {
+				return nil, err
+			}
+		}
+		return line, nil
+	}
+	if value == nil {
+		return line, nil
+	}
+	if float, ok := nonFiniteFloatValue(value); ok {
+		switch f.NonFiniteFloats {
+		case NonFiniteFloatDrop:
+			return line, nil
+		case NonFiniteFloatError:
+			return nil, &ErrorNonFiniteFloat{path: key, value: float}
+		}
+	}
+
+	if len(line) > 0 {
+		line = append(line, ' ')
+	}
+	line = appendLogfmtKey(line, key)
+	line = append(line, '=')
+	return appendLogfmtValue(line, flatValueString(key, value, f.NonFiniteFloats, f.NestingLimits)), nil
+}
+
+// flatValueString returns the string written for the value of a field by formats without nested values, like logfmt.
+// Values without a natural string form, like slices and structs, are written as JSON.
+func flatValueString(key string, value any, nonFiniteFloats NonFiniteFloatPolicy, limits NestingLimits) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	case float32, float64:
+		if float, ok := nonFiniteFloatValue(value); ok {
+			switch nonFiniteFloats {
+			case NonFiniteFloatDrop:
+				return ""
+			case NonFiniteFloatError:
+				err := &ErrorNonFiniteFloat{path: key, value: float}
+				return (&ErrorNonFatalFormatterError{fieldName: key, err: err}).Error()
+			}
+			return formatNonFiniteFloat(float)
+		}
+		return fmt.Sprint(value)
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(value)
+	case error:
+		return value.Error()
+	case fmt.Stringer:
+		return value.String()
+	}
+
+	// Marshalled like the JSON formatter would, including its handling of cycles and non-finite floats.
+	jsonFormatter := &jsonFormatter{NonFiniteFloats: nonFiniteFloats, NestingLimits: limits}
+	b, err := jsonFormatter.marshalJSONLine(map[string]any{key: value})
+	var object map[string]json.RawMessage
+	if err == nil {
+		err = json.Unmarshal(b, &object)
+	}
+	if err != nil {
+		return (&ErrorNonFatalFormatterError{fieldName: key, err: err}).Error()
+	}
+	return string(object[key])
+}
+
+// appendLogfmtKey appends the key, with the characters logfmt doesn't allow in keys (spaces, '=', '"' and control
+// characters) replaced with underscores.
+func appendLogfmtKey(line []byte, key string) []byte {
+	if key == "" {
+		return append(line, '_')
+	}
+	for _, r := range key {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			r = '_'
+		}
+		line = utf8.AppendRune(line, r)
+	}
+	return line
+}
+
+// appendLogfmtValue appends the value, quoted if it's empty, or contains spaces, '=', '"' or characters that must be
+// escaped.
+func appendLogfmtValue(line []byte, value string) []byte {
+	if value != "" && !strings.ContainsFunc(value, func(r rune) bool {
+		return r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || !unicode.IsPrint(r)
+	}) {
+		return append(line, value...)
+	}
+	return strconv.AppendQuote(line, value)
+}
@@ -0,0 +1,117 @@
+package log
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestNewFormatter_logfmt(t *testing.T) {
+	durationField, _ := NewDurationField("took")
+	errorField, _ := NewErrorField("err")
+	tagsField, _ := NewArrayField[string]("tags", func(args LogLineArgs, data string) (any, error) { return data, nil })
+	fields := []Field{
+		NewLevelField(nil),
+		NewMessageField(),
+		durationField,
+		errorField,
+		tagsField,
+		NewHeartbeatField(),
+	}
+	formatter, err := NewFormatter(OutputFormatLogfmt, fields)
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data []any
+		want string
+	}{
+		{"plain", []any{"started"}, "level=INFO message=started"},
+		{"spaces", []any{"user signed up"}, `level=INFO message="user signed up"`},
+		{"equals and quotes", []any{`a=b "c"`}, `level=INFO message="a=b \"c\""`},
+		{"newline", []any{"two\nlines"}, `level=INFO message="two\nlines"`},
+		{"empty", []any{""}, `level=INFO message=""`},
+		{
+			"typed values",
+			[]any{"done", 1500 * time.Millisecond, errors.New("not found")},
+			`level=INFO message=done took=1.5s err="not found"`,
+		},
+		{"nested", []any{"tagged", []string{"a", "b c"}}, `level=INFO message=tagged tags="[\"a\",\"b c\"]"`},
+		{
+			"object",
+			[]any{"alive", &Heartbeat{Uptime: time.Minute, Stats: LoggerStats{Lines: 3}}},
+			"level=INFO message=alive heartbeat.dropped=0 heartbeat.errors=0 heartbeat.lines=3 heartbeat.uptime_seconds=60",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatter.FormatLogLine(LogLineArgs{Level: Info}, tt.data)
+			if result.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", result.err)
+			}
+			if got := string(result.bytes); got != tt.want {
+				t.Errorf("FormatLogLine() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFormatter_logfmtNonFiniteFloats(t *testing.T) {
+	ratioField, _ := NewObjectField[float64](
+		"ratio",
+		func(args LogLineArgs, ratio float64) (any, error) { return ratio, nil },
+	)
+	statsField, _ := NewObjectField[map[string]float32](
+		"stats",
+		func(args LogLineArgs, stats map[string]float32) (any, error) {
+			object := map[string]any{}
+			for k, v := range stats {
+				object[k] = v
+			}
+			return object, nil
+		},
+	)
+	fields := []Field{NewMessageField(), ratioField, statsField}
+	stats := map[string]float32{"p99": float32(math.Inf(-1))}
+
+	tests := []struct {
+		policy  NonFiniteFloatPolicy
+		want    string
+		wantErr bool
+	}{
+		{NonFiniteFloatString, "message=computed ratio=NaN stats.p99=-Inf", false},
+		{NonFiniteFloatDrop, "message=computed", false},
+		{NonFiniteFloatError, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			formatter, _ := NewFormatter(OutputFormatLogfmt, fields, WithNonFiniteFloatPolicy(tt.policy))
+
+			result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{"computed", math.NaN(), stats})
+
+			if tt.wantErr {
+				if !errors.As(result.err, new(*ErrorNonFiniteFloat)) {
+					t.Errorf("FormatLogLine() error = %v, want an ErrorNonFiniteFloat", result.err)
+				}
+				return
+			}
+			if result.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", result.err)
+			}
+			if got := string(result.bytes); got != tt.want {
+				t.Errorf("FormatLogLine() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendLogfmtKey(t *testing.T) {
+	for key, want := range map[string]string{"user.id": "user.id", "user name": "user_name", "a=b": "a_b", "": "_"} {
+		if got := string(appendLogfmtKey(nil, key)); got != want {
+			t.Errorf("appendLogfmtKey(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
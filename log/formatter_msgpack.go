@@ -0,0 +1,112 @@
+package log
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// msgpackTimestamp is the extension type of MessagePack timestamps.
+const msgpackTimestamp = 0xff // -1
+
+// msgpackFormat is the binaryFormat of MessagePack. Times are written with the timestamp extension type, integers and
+// lengths in their smallest encoding.
+type msgpackFormat struct{}
+
+func (msgpackFormat) appendNull(dst []byte) []byte {
+	return append(dst, 0xc0)
+}
+
+func (msgpackFormat) appendBool(dst []byte, b bool) []byte {
+	if b {
+		return append(dst, 0xc3)
+	}
+	return append(dst, 0xc2)
+}
+
+func (f msgpackFormat) appendInt(dst []byte, n int64) []byte {
+	switch {
+	case n >= 0:
+		return f.appendUint(dst, uint64(n))
+	case n >= -32:
+		return append(dst, byte(n)) // Negative fixint.
+	case n >= math.MinInt8:
+		return append(dst, 0xd0, byte(n))
+	case n >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xd1), uint16(n))
+	case n >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(dst, 0xd2), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(dst, 0xd3), uint64(n))
+	}
+}
+
+func (msgpackFormat) appendUint(dst []byte, n uint64) []byte {
+	switch {
+	case n <= 0x7f:
+		return append(dst, byte(n)) // Positive fixint.
+	case n <= math.MaxUint8:
+		return append(dst, 0xcc, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xcd), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, 0xce), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(dst, 0xcf), n)
+	}
+}
+
+func (msgpackFormat) appendFloat32(dst []byte, f float32) []byte {
+	return binary.BigEndian.AppendUint32(append(dst, 0xca), math.Float32bits(f))
+}
+
+func (msgpackFormat) appendFloat64(dst []byte, f float64) []byte {
+	return binary.BigEndian.AppendUint64(append(dst, 0xcb), math.Float64bits(f))
+}
+
+func (msgpackFormat) appendString(dst []byte, s string) []byte {
+	return append(appendMsgpackHead(dst, len(s), 0xa0, 32, 0xd9, 0xda, 0xdb), s...)
+}
+
+func (msgpackFormat) appendBytes(dst []byte, b []byte) []byte {
+	// There's no fixed-length form of binary data, hence a limit of 0.
+	return append(appendMsgpackHead(dst, len(b), 0, 0, 0xc4, 0xc5, 0xc6), b...)
+}
+
+// appendTime appends the time as a timestamp: 32-bit with seconds only, 64-bit with nanoseconds until 2514, and 96-bit
+// for any other time.
+func (msgpackFormat) appendTime(dst []byte, t time.Time) []byte {
+	sec, nsec := t.Unix(), uint64(t.Nanosecond())
+	switch {
+	case sec >= 0 && sec <= math.MaxUint32 && nsec == 0:
+		return binary.BigEndian.AppendUint32(append(dst, 0xd6, msgpackTimestamp), uint32(sec))
+	case sec >= 0 && sec>>34 == 0:
+		return binary.BigEndian.AppendUint64(append(dst, 0xd7, msgpackTimestamp), nsec<<34|uint64(sec))
+	default:
+		dst = binary.BigEndian.AppendUint32(append(dst, 0xc7, 12, msgpackTimestamp), uint32(nsec))
+		return binary.BigEndian.AppendUint64(dst, uint64(sec))
+	}
+}
+
+func (msgpackFormat) appendArrayHead(dst []byte, n int) []byte {
+	return appendMsgpackHead(dst, n, 0x90, 16, 0, 0xdc, 0xdd)
+}
+
+func (msgpackFormat) appendMapHead(dst []byte, n int) []byte {
+	return appendMsgpackHead(dst, n, 0x80, 16, 0, 0xde, 0xdf)
+}
+
+// appendMsgpackHead appends the head of a value of n elements or bytes: fixed, if n is below the limit of the fixed
+// form, or with an 8-bit (if the type has one), 16-bit or 32-bit length.
+func appendMsgpackHead(dst []byte, n int, fixed byte, limit int, head8, head16, head32 byte) []byte {
+	switch {
+	case n < limit:
+		return append(dst, fixed|byte(n))
+	case head8 != 0 && n <= math.MaxUint8:
+		return append(dst, head8, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, head16), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(dst, head32), uint32(n))
+	}
+}
@@ -0,0 +1,67 @@
+package log
+
+import (
+	"encoding/hex"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewFormatter_msgpack(t *testing.T) {
+	formatter, err := NewFormatter(OutputFormatMsgpack, []Field{NewLevelField(nil), NewMessageField()})
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+
+	result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{"hi"})
+	if result.err != nil {
+		t.Fatalf("FormatLogLine() error = %v", result.err)
+	}
+	// {"level": "INFO", "message": "hi"}
+	want := "82" + "a56c6576656c" + "a4494e464f" + "a76d657373616765" + "a26869"
+	if got := hex.EncodeToString(result.bytes); got != want {
+		t.Errorf("FormatLogLine() = %s, want %s", got, want)
+	}
+}
+
+func TestMsgpackFormat(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"nil", nil, "c0"},
+		{"bool", false, "c2"},
+		{"fixint", 127, "7f"},
+		{"uint8", 200, "ccc8"},
+		{"uint16", 1000, "cd03e8"},
+		{"negative fixint", -32, "e0"},
+		{"int8", -100, "d09c"},
+		{"int32", -100000, "d2fffe7960"},
+		{"uint64", uint64(math.MaxUint64), "cfffffffffffffffff"},
+		{"float", 1.5, "cb3ff8000000000000"},
+		{"float32", float32(1.5), "ca3fc00000"},
+		{"string", "a", "a161"},
+		{"str8", strings.Repeat("a", 32), "d920" + strings.Repeat("61", 32)},
+		{"bytes", []byte{1, 2}, "c4020102"},
+		{"timestamp32", time.Unix(1, 0), "d6ff00000001"},
+		{"timestamp64", time.Unix(1, 1), "d7ff0000000400000001"},
+		{"timestamp96", time.Unix(-1, 0), "c70cff00000000ffffffffffffffff"},
+		{"array", []int{1, 2}, "920102"},
+		{"map", map[string]int{"b": 2, "a": 1}, "82a16101a16202"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoder := &binaryEncoder{format: msgpackFormat{}, refs: refPath{}}
+			got, err := encoder.encode(nil, "value", reflect.ValueOf(tt.value), 0)
+			if err != nil {
+				t.Fatalf("encode() error = %v", err)
+			}
+			if hex.EncodeToString(got) != tt.want {
+				t.Errorf("encode() = %x, want %s", got, tt.want)
+			}
+		})
+	}
+}
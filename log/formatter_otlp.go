@@ -0,0 +1,272 @@
+package log
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// OTLPSettings are the settings of an OTLP formatter.
+type OTLPSettings struct {
+	// ServiceName is the service.name attribute of the resource. Defaults to the name of the executable.
+	ServiceName string
+	// Resource are the other attributes of the resource, e.g. {"deployment.environment": "production"}.
+	Resource map[string]any
+	// ScopeName is the name of the instrumentation scope of the records. Defaults to "github.com/fmdunlap/ultra/log".
+	ScopeName string
+	// MessageKey is the key of the field whose value is the body of the records. Defaults to "message".
+	MessageKey string
+	// TraceIDKey and SpanIDKey are the keys of the fields whose values, as hex strings, are the trace and span IDs of
+	// the records. Default to "trace_id" and "span_id". Values that aren't valid IDs are written as attributes.
+	TraceIDKey string
+	SpanIDKey  string
+}
+
+var defaultOTLPSettings = OTLPSettings{
+	ScopeName:  "github.com/fmdunlap/ultra/log",
+	MessageKey: "message",
+	TraceIDKey: "trace_id",
+	SpanIDKey:  "span_id",
+}
+
+func (s *OTLPSettings) mergeDefault() {
+	if s.ServiceName == "" && len(os.Args) > 0 {
+		s.ServiceName = filepath.Base(os.Args[0])
+	}
+	if s.ScopeName == "" {
+		s.ScopeName = defaultOTLPSettings.ScopeName
+	}
+	if s.MessageKey == "" {
+		s.MessageKey = defaultOTLPSettings.MessageKey
+	}
+	if s.TraceIDKey == "" {
+		s.TraceIDKey = defaultOTLPSettings.TraceIDKey
+	}
+	if s.SpanIDKey == "" {
+		s.SpanIDKey = defaultOTLPSettings.SpanIDKey
+	}
+}
+
+// NewOTLPFormatter returns a formatter that writes the entries of the base formatter as OpenTelemetry log records, in
+// the OTLP/JSON encoding. Every line is an ExportLogsServiceRequest with a single LogRecord, which is what the
+// otlpjsonfile receiver of the OpenTelemetry Collector reads; an OTLPWriter sends them to an OTLP/HTTP endpoint.
+//
+// The severity of a record is computed from the level of the line (see SeverityProfiles.OTel), and its time is the
+// time of the line. The field under settings.MessageKey is the body; the trace and span ID fields are the IDs of the
+// record; the other fields of the base formatter are its attributes. Leave the level and time fields out of the base
+// formatter, since the record has them already.
+//
+// The base formatter must be a RecordFormatter; JSON formatters give the most faithful values.
+func NewOTLPFormatter(base LogLineFormatter, settings *OTLPSettings) (LogLineFormatter, error) {
+	recordFormatter, ok := base.(RecordFormatter)
+	if !ok {
+		return nil, ErrorOTLPUnsupportedFormatter
+	}
+	s := OTLPSettings{}
+	if settings != nil {
+		s = *settings
+	}
+	s.mergeDefault()
+
+	resource := maps.Clone(s.Resource)
+	if resource == nil {
+		resource = map[string]any{}
+	}
+	if _, ok := resource["service.name"]; !ok && s.ServiceName != "" {
+		resource["service.name"] = s.ServiceName
+	}
+	attributes, err := otlpAttributes(resource, NonFiniteFloatString)
+	if err != nil {
+		return nil, err
+	}
+
+	return &otlpFormatter{BaseFormatter: recordFormatter, settings: s, resource: attributes}, nil
+}
+
+// otlpFormatter encodes the Records of the base formatter as OTLP log records.
+type otlpFormatter struct {
+	BaseFormatter RecordFormatter
+	settings      OTLPSettings
+	resource      []otlpKeyValue
+}
+
+// FormatLogLine formats the log line using the provided data and returns a FormatResult which contains the formatted
+// log line and any errors that may have occurred.
+func (f *otlpFormatter) FormatLogLine(args LogLineArgs, data []any) FormatResult {
+	record, err := f.BaseFormatter.BuildRecord(args, data)
+	if err != nil {
+		return FormatResult{nil, err}
+	}
+
+	now := time.Now()
+	at := args.Time
+	if at.IsZero() {
+		at = now
+	}
+	logRecord := otlpLogRecord{
+		TimeUnixNano:         strconv.FormatInt(at.UnixNano(), 10),
+		ObservedTimeUnixNano: strconv.FormatInt(now.UnixNano(), 10),
+		SeverityNumber:       SeverityProfiles.OTel.Numbers[args.Level],
+		SeverityText:         SeverityProfiles.OTel.Names[args.Level],
+	}
+
+	fields := make(map[string]any, len(record.Fields))
+	for _, field := range record.Fields {
+		switch value, _ := field.Value.(string); {
+		case field.Key == f.settings.TraceIDKey && isHexID(value, 16):
+			logRecord.TraceID = value
+		case field.Key == f.settings.SpanIDKey && isHexID(value, 8):
+			logRecord.SpanID = value
+		default:
+			fields[field.Key] = field.Value
+		}
+	}
+	attributes, err := otlpAttributes(fields, record.Args.NonFiniteFloats)
+	if err != nil {
+		return FormatResult{nil, err}
+	}
+	for _, attribute := range attributes {
+		if attribute.Key == f.settings.MessageKey {
+			logRecord.Body = attribute.Value
+			continue
+		}
+		logRecord.Attributes = append(logRecord.Attributes, attribute)
+	}
+
+	line, err := json.Marshal(otlpRequest{ResourceLogs: []otlpResourceLogs{{
+		Resource:  otlpResource{Attributes: f.resource},
+		ScopeLogs: []otlpScopeLogs{{Scope: otlpScope{Name: f.settings.ScopeName}, LogRecords: []otlpLogRecord{logRecord}}},
+	}}})
+	if err != nil {
+		return FormatResult{nil, err}
+	}
+	return FormatResult{line, nil}
+}
+
+// Unwrap returns the base formatter.
+func (f *otlpFormatter) Unwrap() LogLineFormatter {
+	return f.BaseFormatter
+}
+
+// isHexID reports whether s is the hex encoding of a non-zero ID of n bytes.
+func isHexID(s string, n int) bool {
+	id, err := hex.DecodeString(s)
+	return err == nil && len(id) == n && slices.ContainsFunc(id, func(b byte) bool { return b != 0 })
+}
+
+// otlpAttributes returns the values as OTLP attributes, sorted by key. The values are marshalled like the JSON
+// formatter would, and null values are omitted.
+func otlpAttributes(values map[string]any, nonFiniteFloats NonFiniteFloatPolicy) ([]otlpKeyValue, error) {
+	b, err := (&jsonFormatter{NonFiniteFloats: nonFiniteFloats}).marshalJSONLine(values)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var object map[string]any
+	if err := decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+	return otlpKeyValues(object), nil
+}
+
+// otlpKeyValues returns the members of the JSON object as OTLP key-values, sorted by key, without the null ones.
+func otlpKeyValues(object map[string]any) []otlpKeyValue {
+	var keyValues []otlpKeyValue
+	for _, key := range slices.Sorted(maps.Keys(object)) {
+		if object[key] != nil {
+			keyValues = append(keyValues, otlpKeyValue{Key: key, Value: newOTLPAnyValue(object[key])})
+		}
+	}
+	return keyValues
+}
+
+// newOTLPAnyValue returns the JSON value, decoded with json.Number numbers, as an OTLP AnyValue.
+func newOTLPAnyValue(value any) *otlpAnyValue {
+	switch value := value.(type) {
+	case string:
+		return &otlpAnyValue{StringValue: &value}
+	case bool:
+		return &otlpAnyValue{BoolValue: &value}
+	case json.Number:
+		if _, err := strconv.ParseInt(string(value), 10, 64); err == nil {
+			return &otlpAnyValue{IntValue: string(value)}
+		}
+		f, _ := value.Float64()
+		return &otlpAnyValue{DoubleValue: &f}
+	case []any:
+		values := make([]*otlpAnyValue, 0, len(value))
+		for _, v := range value {
+			values = append(values, newOTLPAnyValue(v))
+		}
+		return &otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+	case map[string]any:
+		return &otlpAnyValue{KvlistValue: &otlpKeyValueList{Values: otlpKeyValues(value)}}
+	default:
+		// null, in an array.
+		return &otlpAnyValue{}
+	}
+}
+
+// The messages of the OTLP/JSON encoding of an ExportLogsServiceRequest, with a single resource and scope.
+
+type otlpRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber,omitempty"`
+	SeverityText         string         `json:"severityText,omitempty"`
+	Body                 *otlpAnyValue  `json:"body,omitempty"`
+	Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+	TraceID              string         `json:"traceId,omitempty"`
+	SpanID               string         `json:"spanId,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string        `json:"key"`
+	Value *otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string           `json:"stringValue,omitempty"`
+	BoolValue   *bool             `json:"boolValue,omitempty"`
+	IntValue    string            `json:"intValue,omitempty"` // A string, since JSON numbers lose 64-bit precision.
+	DoubleValue *float64          `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpArrayValue   `json:"arrayValue,omitempty"`
+	KvlistValue *otlpKeyValueList `json:"kvlistValue,omitempty"`
+}
+
+type otlpArrayValue struct {
+	Values []*otlpAnyValue `json:"values"`
+}
+
+type otlpKeyValueList struct {
+	Values []otlpKeyValue `json:"values"`
+}
@@ -0,0 +1,113 @@
+package log
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewOTLPFormatter(t *testing.T) {
+	countField, _ := NewIntField("count")
+	traceField, _ := NewStringField("trace_id")
+	base, err := NewFormatter(OutputFormatJSON, []Field{NewMessageField(), countField, traceField})
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	formatter, err := NewOTLPFormatter(base, &OTLPSettings{
+		ServiceName: "checkout",
+		Resource:    map[string]any{"deployment.environment": "test"},
+	})
+	if err != nil {
+		t.Fatalf("NewOTLPFormatter() error = %v", err)
+	}
+	at := time.Unix(1735830245, 6)
+
+	tests := []struct {
+		name string
+		args LogLineArgs
+		data []any
+		want string
+	}{
+		{
+			"message",
+			LogLineArgs{Level: Warn, Time: at},
+			[]any{"low disk", 3},
+			`"severityNumber":13,"severityText":"WARN","body":{"stringValue":"low disk"},` +
+				`"attributes":[{"key":"count","value":{"intValue":"3"}}]}`,
+		},
+		{
+			"trace id",
+			LogLineArgs{Level: Error, Time: at, entry: &Entry{Fields: map[string]any{
+				"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+			}}},
+			[]any{"failed"},
+			`"severityNumber":17,"severityText":"ERROR","body":{"stringValue":"failed"},` +
+				`"traceId":"4bf92f3577b34da6a3ce929d0e0e4736"}`,
+		},
+		{
+			"invalid trace id",
+			LogLineArgs{Level: Info, Time: at, entry: &Entry{Fields: map[string]any{"trace_id": "abc"}}},
+			[]any{"hello"},
+			`"severityNumber":9,"severityText":"INFO","body":{"stringValue":"hello"},` +
+				`"attributes":[{"key":"trace_id","value":{"stringValue":"abc"}}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := formatter.FormatLogLine(tt.args, tt.data)
+			if res.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", res.err)
+			}
+
+			const prefix = `{"resourceLogs":[{"resource":{"attributes":[` +
+				`{"key":"deployment.environment","value":{"stringValue":"test"}},` +
+				`{"key":"service.name","value":{"stringValue":"checkout"}}]},` +
+				`"scopeLogs":[{"scope":{"name":"github.com/fmdunlap/ultra/log"},"logRecords":[` +
+				`{"timeUnixNano":"1735830245000000006","observedTimeUnixNano":"`
+			got := string(res.bytes)
+			if len(got) < len(prefix) || got[:len(prefix)] != prefix {
+				t.Fatalf("FormatLogLine() = %s, want prefix %s", got, prefix)
+			}
+			if suffix := tt.want + "]}]}]}"; len(got) < len(suffix) || got[len(got)-len(suffix):] != suffix {
+				t.Errorf("FormatLogLine() = %s, want suffix %s", got, suffix)
+			}
+		})
+	}
+}
+
+func TestNewOTLPAnyValue(t *testing.T) {
+	base, _ := NewFormatter(OutputFormatJSON, []Field{NewMessageField()})
+	formatter, _ := NewOTLPFormatter(base, nil)
+	attributes, err := otlpAttributes(map[string]any{
+		"float":  1.5,
+		"list":   []any{"a", true, nil},
+		"object": map[string]any{"n": 1},
+		"null":   nil,
+	}, NonFiniteFloatString)
+	if err != nil {
+		t.Fatalf("otlpAttributes() error = %v", err)
+	}
+
+	got := formatter.(*otlpFormatter)
+	if len(got.resource) != 1 || got.resource[0].Key != "service.name" {
+		t.Errorf("resource = %+v, want the service name", got.resource)
+	}
+	if len(attributes) != 3 {
+		t.Fatalf("attributes = %+v, want 3 attributes", attributes)
+	}
+	if v := attributes[0].Value.DoubleValue; v == nil || *v != 1.5 {
+		t.Errorf("float = %+v, want a double", attributes[0].Value)
+	}
+	if v := attributes[1].Value.ArrayValue; v == nil || len(v.Values) != 3 || *v.Values[1].BoolValue != true {
+		t.Errorf("list = %+v, want an array", attributes[1].Value)
+	}
+	if v := attributes[2].Value.KvlistValue; v == nil || v.Values[0].Value.IntValue != "1" {
+		t.Errorf("object = %+v, want a kvlist", attributes[2].Value)
+	}
+}
+
+func TestNewOTLPFormatter_unsupportedFormatter(t *testing.T) {
+	if _, err := NewOTLPFormatter(&transformFormatter{}, nil); !errors.Is(err, ErrorOTLPUnsupportedFormatter) {
+		t.Errorf("NewOTLPFormatter() error = %v, want %v", err, ErrorOTLPUnsupportedFormatter)
+	}
+}
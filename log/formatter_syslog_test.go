@@ -0,0 +1,113 @@
+package log
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewSyslogFormatter(t *testing.T) {
+	countField, _ := NewIntField("count")
+	userField, _ := NewStringField("user")
+	cachedField, _ := NewBoolField("cached")
+	httpField, _ := NewCompositeField("http", cachedField)
+	base, err := NewFormatter(OutputFormatJSON, []Field{NewMessageField(), countField, userField, httpField})
+	if err != nil {
+		t.Fatalf("NewFormatter() error = %v", err)
+	}
+	local4 := SyslogFacilityLocal4
+	at := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)
+	pid := strconv.Itoa(os.Getpid())
+
+	tests := []struct {
+		name     string
+		settings *SyslogSettings
+		args     LogLineArgs
+		data     []any
+		want     string
+	}{
+		{
+			"message only",
+			&SyslogSettings{Hostname: "host", AppName: "app"},
+			LogLineArgs{Level: Info, Time: at},
+			[]any{"started"},
+			"<14>1 2024-01-02T03:04:05.000006Z host app " + pid + " - - started",
+		},
+		{
+			"structured data",
+			&SyslogSettings{Facility: &local4, Hostname: "host", AppName: "app"},
+			LogLineArgs{Level: Error, Time: at, Tag: "checkout"},
+			[]any{"payment failed", 3},
+			"<163>1 2024-01-02T03:04:05.000006Z host app " + pid + ` checkout [ultra@32473 count="3"] payment failed`,
+		},
+		{
+			"escaped values",
+			&SyslogSettings{Hostname: "my host", AppName: "app", MsgID: "pay", SDID: "app@1"},
+			LogLineArgs{Level: Warn, Time: at, entry: &Entry{Fields: map[string]any{"user": `a"b]\c`}}},
+			[]any{"odd user"},
+			"<12>1 2024-01-02T03:04:05.000006Z my_host app " + pid + ` pay [app@1 user="a\"b\]\\c"] odd user`,
+		},
+		{
+			"long header values",
+			&SyslogSettings{
+				Hostname: strings.Repeat("h", 300),
+				AppName:  strings.Repeat("a", 40) + "\tapp/é\x00" + strings.Repeat("a", 10),
+			},
+			LogLineArgs{Level: Info, Time: at, Tag: "checkout-" + strings.Repeat("m", 30)},
+			[]any{"started"},
+			"<14>1 2024-01-02T03:04:05.000006Z " + strings.Repeat("h", 255) + " " + strings.Repeat("a", 40) +
+				"_app/__a " + pid + " checkout-" + strings.Repeat("m", 23) + " - started",
+		},
+		{
+			"multiline message",
+			&SyslogSettings{Hostname: "host", AppName: "app"},
+			LogLineArgs{Level: Error, Time: at},
+			[]any{"panic: boom\r\ngoroutine 1\n"},
+			"<11>1 2024-01-02T03:04:05.000006Z host app " + pid + ` - - panic: boom\r\ngoroutine 1\n`,
+		},
+		{
+			"nested",
+			&SyslogSettings{Hostname: "host", AppName: "app"},
+			LogLineArgs{Level: Debug, Time: at},
+			[]any{"upstream", true},
+			"<15>1 2024-01-02T03:04:05.000006Z host app " + pid + ` - [ultra@32473 http.cached="true"] upstream`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter, err := NewSyslogFormatter(base, tt.settings)
+			if err != nil {
+				t.Fatalf("NewSyslogFormatter() error = %v", err)
+			}
+			res := formatter.FormatLogLine(tt.args, tt.data)
+			if res.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", res.err)
+			}
+			if got := string(res.bytes); got != tt.want {
+				t.Errorf("FormatLogLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSyslogFormatter_defaults(t *testing.T) {
+	base, _ := NewFormatter(OutputFormatJSON, []Field{NewMessageField()})
+	formatter, err := NewSyslogFormatter(base, nil)
+	if err != nil {
+		t.Fatalf("NewSyslogFormatter() error = %v", err)
+	}
+	settings := formatter.(*syslogFormatter).settings
+	hostname, _ := os.Hostname()
+	if *settings.Facility != SyslogFacilityUser || settings.Hostname != hostname || settings.AppName == "" {
+		t.Errorf("settings = %+v, want the user facility, host name and executable name", settings)
+	}
+}
+
+func TestNewSyslogFormatter_unsupportedFormatter(t *testing.T) {
+	if _, err := NewSyslogFormatter(&transformFormatter{}, nil); !errors.Is(err, ErrorSyslogUnsupportedFormatter) {
+		t.Errorf("NewSyslogFormatter() error = %v, want %v", err, ErrorSyslogUnsupportedFormatter)
+	}
+}
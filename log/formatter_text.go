@@ -3,13 +3,27 @@ package log
 import (
     "fmt"
     "strings"
+    "unicode/utf8"
 )
 
 // textFormatter is a formatter that formats log lines as text.
 type textFormatter struct {
     Fields          []Field                   // Keep these in an array to preserve the order of the fields.
     FieldFormatters map[string]FieldFormatter // Map of the field name to its formatter
+    FieldCache      *fieldResultCache         // Memoized results of cacheable fields.
     FieldSeparator  string
+    Hyperlinks      bool                      // Render fields with a LinkTemplate as OSC 8 hyperlinks.
+    Columns         *columnWidths             // Column width cache for aligned-columns mode. Nil when disabled.
+    MultilinePrefix string                    // Prefix for continuation lines of multi-line values. Empty disables.
+    LevelPrefixes   [][]byte                  // Precomputed level prefixes for the level+message layout, by level.
+    NonFiniteFloats NonFiniteFloatPolicy      // How the float fields log NaN and ±Inf.
+    NestingLimits   NestingLimits             // Bounds nested field values. The zero value doesn't limit them.
+    Keys            fieldKeys                 // Maps field names to the keys they are written under.
+    SortMapKeys     bool                      // Render map fields with their keys in sorted order.
+    Validation      *entryValidation          // Checks assembled entries. Nil when there are no validators.
+    SeverityProfile *SeverityProfile          // Severities of the level fields without a profile of their own.
+    Logfmt          bool                      // Write logfmt lines. Columns and hyperlinks don't apply.
+    MaxSensitivity  *Sensitivity              // Fields classified above it aren't written. Nil when there's no maximum.
 }
 
 // TODO: Provide a way to specify the separator between fields.
@@ -18,33 +32,86 @@ type textFormatter struct {
 // FormatLogLine formats the log line using the provided data and returns a FormatResult which contains the formatted
 // log line and any errors that may have occurred.
 func (f *textFormatter) FormatLogLine(args LogLineArgs, data []any) FormatResult {
-    args.OutputFormat = OutputFormatText
+    if args.line != nil {
+        line := args.line
+        args.line = nil
+        return line.format(f, func() FormatResult { return f.FormatLogLine(args, data) })
+    }
 
-    line := make([]byte, 0)
-    procResChan := make(chan fieldProcessingResult)
+    args = f.lineArgs(args)
 
-    go processFieldsWithData(procResChan, args, f.Fields, f.FieldFormatters, data)
-    for {
-        result, ok := <-procResChan
-        if !ok {
-            break
-        }
+    if line, ok := f.appendLevelMessageLine(nil, args.Level, data); ok {
+        return FormatResult{line, nil}
+    }
 
-        if result.err != nil {
-            return FormatResult{nil, result.err}
-        }
+    record, err := f.recordBuilder().build(args, data)
+    if err != nil {
+        return FormatResult{nil, err}
+    }
 
-        line = f.addDataToLogLine(line, result.fieldData, result.fieldName, result.fieldSettings)
+    line, err := f.Encode(record)
+    return FormatResult{line, err}
+}
+
+// BuildRecord runs the fields of the formatter over the data, and returns their results as a Record.
+func (f *textFormatter) BuildRecord(args LogLineArgs, data []any) (*Record, error) {
+    return f.recordBuilder().build(f.lineArgs(args), data)
+}
+
+// Encode renders the Record as a text line, or a logfmt line.
+func (f *textFormatter) Encode(record *Record) ([]byte, error) {
+    if f.Logfmt {
+        return f.encodeLogfmt(record)
     }
 
+    line := make([]byte, 0)
+    lastPadding := 0
+    for _, field := range record.Fields {
+        line, lastPadding = f.addDataToLogLine(line, field.Value, field.Key, field.Settings)
+    }
+
+    // Drop the trailing separator, and any column padding after the last field.
     if len(line) > 0 {
-        line = line[:len(line)-1]
+        line = line[:len(line)-1-lastPadding]
+    }
+
+    return line, nil
+}
+
+// lineArgs sets the formatter-level arguments of a line.
+func (f *textFormatter) lineArgs(args LogLineArgs) LogLineArgs {
+    args.OutputFormat = OutputFormatText
+    if f.Logfmt {
+        args.OutputFormat = OutputFormatLogfmt
     }
+    args.NonFiniteFloats = f.NonFiniteFloats
+    args.SchemaVersion = f.Keys.schemaVersion()
+    args.SortMapKeys = f.SortMapKeys
+    args.SeverityProfile = f.SeverityProfile
+    return args
+}
 
-    return FormatResult{line, nil}
+func (f *textFormatter) recordBuilder() recordBuilder {
+    return recordBuilder{
+        fields:         f.Fields,
+        formatters:     f.FieldFormatters,
+        cache:          f.FieldCache,
+        keys:           &f.Keys,
+        validation:     f.Validation,
+        maxSensitivity: f.MaxSensitivity,
+    }
 }
 
-func (f *textFormatter) addDataToLogLine(line []byte, resultBytes any, fName string, fSettings FieldSettings) []byte {
+func (f *textFormatter) addDataToLogLine(
+    line []byte,
+    resultBytes any,
+    fName string,
+    fSettings FieldSettings,
+) ([]byte, int) {
+    if composite, ok := resultBytes.(*compositeValue); ok {
+        return f.addCompositeToLogLine(line, composite, fName)
+    }
+
     b := strings.Builder{}
 
     if !fSettings.HideKey {
@@ -52,9 +119,113 @@ func (f *textFormatter) addDataToLogLine(line []byte, resultBytes any, fName str
         b.WriteString("=")
     }
 
-    b.WriteString(fmt.Sprintf("%v", resultBytes))
+    value := formatTextValue(resultBytes, f.NestingLimits)
+    if f.MultilinePrefix != "" && strings.Contains(value, "\n") {
+        value = strings.ReplaceAll(value, "\n", "\n"+f.MultilinePrefix)
+    }
+    width := b.Len() + utf8.RuneCountInString(value)
+
+    if f.Hyperlinks && fSettings.LinkTemplate != "" {
+        value = string(AnsiHyperlink(expandLinkTemplate(fSettings.LinkTemplate, value), []byte(value)))
+    }
+    b.WriteString(value)
+
+    padding := 0
+    if f.Columns != nil {
+        padding = f.Columns.padding(fName, fSettings, width)
+        b.WriteString(strings.Repeat(" ", padding))
+    }
 
     b.WriteString(" ")
 
-    return fmt.Append(line, b.String())
+    return fmt.Append(line, b.String()), padding
+}
+
+// addCompositeToLogLine adds the children of a composite field to the line, keyed by the field key and their name, e.g.
+// "user.id=1 user.name=alice".
+func (f *textFormatter) addCompositeToLogLine(
+    line []byte,
+    composite *compositeValue,
+    fName string,
+) ([]byte, int) {
+    padding := 0
+    for i, name := range composite.names {
+        line, padding = f.addDataToLogLine(line, composite.values[i], fName+"."+name, composite.settings[i])
+    }
+    return line, padding
+}
+
+// levelMessagePrefixes precomputes the bracketed level prefix of every level for the common layout of a level field
+// followed by a message field. It returns nil if the fields are not exactly the built-in level and message fields.
+func levelMessagePrefixes(fields []Field) [][]byte {
+    if len(fields) != 2 {
+        return nil
+    }
+
+    lf, ok := fields[0].(*levelField)
+    if !ok {
+        return nil
+    }
+    if mf, ok := fields[1].(*messageField); !ok || !mf.fastPathCompatible() {
+        return nil
+    }
+
+    prefixes := make([][]byte, len(AllLevels()))
+    for _, lvl := range AllLevels() {
+        prefixes[lvl] = []byte(lf.textStrings[lvl] + " ")
+    }
+
+    return prefixes
+}
+
+// appendLevelMessageLine is the fast path for the level+message layout. The per-line cost is copying the precomputed
+// level prefix and the messages into dst; no field processing happens. It returns false if the fast path doesn't
+// apply, either because of the layout, the level, or formatter options that need the full field processor.
+func (f *textFormatter) appendLevelMessageLine(dst []byte, level Level, data []any) ([]byte, bool) {
+    if !f.levelMessageFastPath(level) {
+        return nil, false
+    }
+
+    dst = append(dst, f.LevelPrefixes[level]...)
+    for _, datum := range data {
+        if msg, ok := datum.(string); ok {
+            dst = append(dst, msg...)
+            dst = append(dst, ' ')
+        }
+    }
+
+    // Drop the trailing separator.
+    return dst[:len(dst)-1], true
+}
+
+// appendMessageLine implements messageLineFormatter using the level+message fast path.
+func (f *textFormatter) appendMessageLine(dst []byte, args LogLineArgs, msg string) ([]byte, bool) {
+    if !f.levelMessageFastPath(args.Level) {
+        return nil, false
+    }
+
+    dst = append(dst, f.LevelPrefixes[args.Level]...)
+    dst = append(dst, msg...)
+
+    return dst, true
+}
+
+func (f *textFormatter) levelMessageFastPath(level Level) bool {
+    if f.LevelPrefixes == nil || f.Columns != nil || f.MultilinePrefix != "" || f.Validation != nil ||
+        f.MaxSensitivity != nil {
+        return false
+    }
+    return level >= 0 && int(level) < len(f.LevelPrefixes)
+}
+
+// WithMultilineIndent prefixes every continuation line of a multi-line field value (stack traces, SQL, etc.) with the
+// provided prefix, e.g. "  | ". Line-oriented tools can then tell entry boundaries apart from continuation lines.
+// Non-text formatters ignore this option.
+func WithMultilineIndent(prefix string) FormatterOption {
+    return func(f LogLineFormatter) LogLineFormatter {
+        if tf, ok := unwrapFormatter[*textFormatter](f); ok {
+            tf.MultilinePrefix = prefix
+        }
+        return f
+    }
 }
@@ -0,0 +1,111 @@
+package log
+
+import (
+	"sync"
+	"time"
+)
+
+// ColumnAlignmentSettings configure the aligned-columns mode of the text formatter. See [WithAlignedColumns].
+type ColumnAlignmentSettings struct {
+	// FixedWidths pads the named fields to a fixed width. Fields that are wider than their fixed width are written
+	// as-is.
+	FixedWidths map[string]int
+
+	// Fields are the names of the fields that are padded to the widest value observed so far. If both Fields and
+	// FixedWidths are empty, every AlwaysMatch field (time, level, tag, etc.) is aligned this way.
+	Fields []string
+
+	// ResetInterval is how often the observed widths are forgotten, so that a single unusually wide value doesn't
+	// widen a column forever. Defaults to one minute. A negative interval disables the reset.
+	ResetInterval time.Duration
+}
+
+var defaultColumnAlignmentSettings = ColumnAlignmentSettings{
+	ResetInterval: time.Minute,
+}
+
+func (s *ColumnAlignmentSettings) mergeDefault() {
+	if s.ResetInterval == 0 {
+		s.ResetInterval = defaultColumnAlignmentSettings.ResetInterval
+	}
+}
+
+// WithAlignedColumns pads the fields of a text formatter to fixed or max-observed widths so that columns line up
+// across lines, which makes interleaved output from many goroutines easier to scan. Non-text formatters ignore this
+// option.
+func WithAlignedColumns(settings *ColumnAlignmentSettings) FormatterOption {
+	if settings == nil {
+		settings = &ColumnAlignmentSettings{}
+	}
+	settings.mergeDefault()
+
+	return func(f LogLineFormatter) LogLineFormatter {
+		if tf, ok := unwrapFormatter[*textFormatter](f); ok {
+			tf.Columns = newColumnWidths(settings)
+		}
+		return f
+	}
+}
+
+// columnWidths is the width cache used by the aligned-columns mode. It is safe for concurrent use.
+type columnWidths struct {
+	fixed         map[string]int
+	observed      map[string]bool
+	resetInterval time.Duration
+
+	mu        sync.Mutex
+	widths    map[string]int
+	lastReset time.Time
+
+	// for testing
+	now func() time.Time
+}
+
+func newColumnWidths(settings *ColumnAlignmentSettings) *columnWidths {
+	observed := make(map[string]bool, len(settings.Fields))
+	for _, name := range settings.Fields {
+		observed[name] = true
+	}
+
+	return &columnWidths{
+		fixed:         settings.FixedWidths,
+		observed:      observed,
+		resetInterval: settings.ResetInterval,
+		widths:        make(map[string]int),
+		lastReset:     time.Now(),
+		now:           time.Now,
+	}
+}
+
+// padding returns the number of spaces needed to pad a field with the given visible width to its column width.
+func (c *columnWidths) padding(fieldName string, fieldSettings FieldSettings, width int) int {
+	if fixed, ok := c.fixed[fieldName]; ok {
+		return max(fixed-width, 0)
+	}
+
+	if !c.alignsObserved(fieldName, fieldSettings) {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.resetInterval > 0 {
+		if now := c.now(); now.Sub(c.lastReset) >= c.resetInterval {
+			clear(c.widths)
+			c.lastReset = now
+		}
+	}
+
+	columnWidth := max(c.widths[fieldName], width)
+	c.widths[fieldName] = columnWidth
+
+	return columnWidth - width
+}
+
+func (c *columnWidths) alignsObserved(fieldName string, fieldSettings FieldSettings) bool {
+	if len(c.observed) == 0 && len(c.fixed) == 0 {
+		return fieldSettings.AlwaysMatch
+	}
+	return c.observed[fieldName]
+}
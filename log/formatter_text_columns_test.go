@@ -0,0 +1,111 @@
+package log
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithAlignedColumns(t *testing.T) {
+	levelField := NewDefaultLevelField()
+	tagField := NewDefaultTagField()
+
+	tests := []struct {
+		name     string
+		settings *ColumnAlignmentSettings
+		lines    []LogLineArgs
+		want     []string
+	}{
+		{
+			name:     "Max observed widths of always-match fields",
+			settings: nil,
+			lines: []LogLineArgs{
+				{Level: Info, Tag: "db"},
+				{Level: Error, Tag: "http"},
+				{Level: Info, Tag: "db"},
+			},
+			want: []string{
+				"<INFO> [db] msg",
+				"<ERROR> [http] msg",
+				"<INFO>  [db]   msg",
+			},
+		},
+		{
+			name: "Fixed widths",
+			settings: &ColumnAlignmentSettings{
+				FixedWidths: map[string]int{"level": 8},
+			},
+			lines: []LogLineArgs{
+				{Level: Info, Tag: "db"},
+				{Level: Error, Tag: "http"},
+			},
+			want: []string{
+				"<INFO>   [db] msg",
+				"<ERROR>  [http] msg",
+			},
+		},
+		{
+			name: "Named fields only",
+			settings: &ColumnAlignmentSettings{
+				Fields: []string{"tag"},
+			},
+			lines: []LogLineArgs{
+				{Level: Error, Tag: "http"},
+				{Level: Info, Tag: "db"},
+			},
+			want: []string{
+				"<ERROR> [http] msg",
+				"<INFO> [db]   msg",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFormatter(
+				OutputFormatText,
+				[]Field{levelField, tagField, NewMessageField()},
+				WithAlignedColumns(tt.settings),
+			)
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+
+			for i, args := range tt.lines {
+				got := f.FormatLogLine(args, []any{"msg"})
+				if string(got.bytes) != tt.want[i] {
+					t.Errorf("FormatLogLine() line %d = %q, want %q", i, got.bytes, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWithAlignedColumns_trailingPadding(t *testing.T) {
+	f, _ := NewFormatter(OutputFormatText, []Field{NewMessageField(), NewDefaultLevelField()}, WithAlignedColumns(nil))
+
+	f.FormatLogLine(LogLineArgs{Level: Error}, []any{"msg"})
+	got := f.FormatLogLine(LogLineArgs{Level: Info}, []any{"msg"})
+
+	if strings.HasSuffix(string(got.bytes), " ") {
+		t.Errorf("FormatLogLine() = %q, want no trailing padding", got.bytes)
+	}
+}
+
+func Test_columnWidths_reset(t *testing.T) {
+	now := time.Now()
+	c := newColumnWidths(&ColumnAlignmentSettings{Fields: []string{"f"}, ResetInterval: time.Minute})
+	c.now = func() time.Time { return now }
+	c.lastReset = now
+
+	if got := c.padding("f", FieldSettings{}, 10); got != 0 {
+		t.Errorf("padding() = %v, want 0", got)
+	}
+	if got := c.padding("f", FieldSettings{}, 4); got != 6 {
+		t.Errorf("padding() = %v, want 6", got)
+	}
+
+	now = now.Add(time.Minute)
+	if got := c.padding("f", FieldSettings{}, 4); got != 0 {
+		t.Errorf("padding() after reset = %v, want 0", got)
+	}
+}
@@ -0,0 +1,116 @@
+package log
+
+import (
+	"testing"
+)
+
+func TestWithMultilineIndent(t *testing.T) {
+	stackField, _ := NewErrorField("stack")
+
+	tests := []struct {
+		name   string
+		prefix string
+		data   []any
+		want   string
+	}{
+		{
+			name: "Disabled",
+			data: []any{"msg", errorString("line1\nline2")},
+			want: "<ERROR> msg stack=line1\nline2",
+		},
+		{
+			name:   "Indented",
+			prefix: "  | ",
+			data:   []any{"msg", errorString("line1\nline2\nline3")},
+			want:   "<ERROR> msg stack=line1\n  | line2\n  | line3",
+		},
+		{
+			name:   "Single line value is unchanged",
+			prefix: "  | ",
+			data:   []any{"msg", errorString("line1")},
+			want:   "<ERROR> msg stack=line1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFormatter(
+				OutputFormatText,
+				[]Field{NewDefaultLevelField(), NewMessageField(), stackField},
+				WithMultilineIndent(tt.prefix),
+			)
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+
+			got := f.FormatLogLine(LogLineArgs{Level: Error}, tt.data)
+			if string(got.bytes) != tt.want {
+				t.Errorf("FormatLogLine() = %q, want %q", got.bytes, tt.want)
+			}
+		})
+	}
+}
+
+type errorString string
+
+func (e errorString) Error() string {
+	return string(e)
+}
+
+func TestTextFormatter_levelMessageFastPath(t *testing.T) {
+	tests := []struct {
+		name         string
+		fields       []Field
+		wantFastPath bool
+	}{
+		{
+			name:         "Level and message",
+			fields:       []Field{NewDefaultLevelField(), NewMessageField()},
+			wantFastPath: true,
+		},
+		{
+			name:         "Custom level strings",
+			fields:       []Field{NewLevelField(&LevelFieldSettings{Bracket: Brackets.Square}), NewMessageField()},
+			wantFastPath: true,
+		},
+		{
+			name:   "Message first",
+			fields: []Field{NewMessageField(), NewDefaultLevelField()},
+		},
+		{
+			name:   "Additional fields",
+			fields: []Field{NewDefaultLevelField(), NewMessageField(), NewDefaultTagField()},
+		},
+	}
+
+	dataSets := [][]any{
+		{"msg"},
+		{"msg", "other msg"},
+		{},
+		{42, "msg", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, _ := NewFormatter(OutputFormatText, tt.fields)
+			tf := f.(*textFormatter)
+
+			if gotFastPath := tf.LevelPrefixes != nil; gotFastPath != tt.wantFastPath {
+				t.Fatalf("fast path enabled = %v, want %v", gotFastPath, tt.wantFastPath)
+			}
+
+			slow := *tf
+			slow.LevelPrefixes = nil
+
+			for _, level := range append(AllLevels(), Level(99)) {
+				for _, data := range dataSets {
+					args := LogLineArgs{Level: level, Tag: "tag"}
+					got := tf.FormatLogLine(args, data)
+					want := slow.FormatLogLine(args, data)
+					if string(got.bytes) != string(want.bytes) {
+						t.Errorf("FormatLogLine(%v, %v) = %q, want %q", level, data, got.bytes, want.bytes)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WithXMLAttributes determines whether the fields of an XML formatter with a scalar value (strings, numbers and bools)
+// are written as attributes of the <log> element, e.g. `<log level="INFO" message="user signed up"/>`, rather than as
+// child elements. Objects and arrays are always written as child elements. It has no effect on other formatters.
+func WithXMLAttributes(enabled bool) FormatterOption {
+	return func(f LogLineFormatter) LogLineFormatter {
+		if jf, ok := unwrapFormatter[*jsonFormatter](f); ok && jf.Output == OutputFormatXML {
+			jf.XMLAttributes = enabled
+		}
+		return f
+	}
+}
+
+// encodeXML renders the Record as a <log> element, from the JSON object of its fields, so the values are written like
+// the JSON formatter writes them. The fields are written in the order of the Record, and null values are omitted.
+func encodeXML(record *Record, jsonLine []byte, attributes bool) ([]byte, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(jsonLine, &object); err != nil {
+		return nil, err
+	}
+
+	line := bytes.NewBufferString("<log")
+	var children bytes.Buffer
+	for _, field := range record.Fields {
+		value := bytes.TrimSpace(object[field.Key])
+		if len(value) == 0 || bytes.Equal(value, []byte("null")) {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(value))
+		decoder.UseNumber()
+		if attributes && value[0] != '{' && value[0] != '[' {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			line.WriteByte(' ')
+			appendXMLName(line, field.Key)
+			line.WriteString(`="`)
+			_ = xml.EscapeText(line, fmt.Append(nil, token))
+			line.WriteByte('"')
+			continue
+		}
+		if err := appendXMLElement(&children, field.Key, decoder); err != nil {
+			return nil, err
+		}
+	}
+
+	if children.Len() == 0 {
+		line.WriteString("/>")
+		return line.Bytes(), nil
+	}
+	line.WriteByte('>')
+	line.Write(children.Bytes())
+	line.WriteString("</log>")
+	return line.Bytes(), nil
+}
+
+// appendXMLElement appends the next JSON value of the decoder as an element named name. The entries of objects are
+// written as child elements named after their keys, in order, and the elements of arrays as <item> elements.
+func appendXMLElement(dst *bytes.Buffer, name string, decoder *json.Decoder) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	dst.WriteByte('<')
+	appendXMLName(dst, name)
+	switch token {
+	case nil:
+		dst.WriteString("/>")
+		return nil
+	case json.Delim('{'), json.Delim('['):
+		dst.WriteByte('>')
+		for decoder.More() {
+			childName := "item"
+			if token == json.Delim('{') {
+				key, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				childName = key.(string)
+			}
+			if err := appendXMLElement(dst, childName, decoder); err != nil {
+				return err
+			}
+		}
+		// The closing delimiter.
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+	default:
+		dst.WriteByte('>')
+		_ = xml.EscapeText(dst, fmt.Append(nil, token))
+	}
+	dst.WriteString("</")
+	appendXMLName(dst, name)
+	dst.WriteByte('>')
+	return nil
+}
+
+// appendXMLName appends the name, with the characters XML doesn't allow in names replaced with underscores. Names that
+// don't start with a letter or an underscore are prefixed with one.
+func appendXMLName(dst *bytes.Buffer, name string) {
+	if r, _ := utf8.DecodeRuneInString(name); name == "" || !(unicode.IsLetter(r) || r == '_') {
+		dst.WriteByte('_')
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' && r != '.' {
+			r = '_'
+		}
+		dst.WriteRune(r)
+	}
+}
@@ -0,0 +1,88 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewFormatter_xml(t *testing.T) {
+	durationField, _ := NewDurationField("took")
+	errorField, _ := NewErrorField("err")
+	tagsField, _ := NewArrayField[string]("tags", func(args LogLineArgs, data string) (any, error) { return data, nil })
+	fields := []Field{
+		NewLevelField(nil),
+		NewMessageField(),
+		durationField,
+		errorField,
+		tagsField,
+		NewHeartbeatField(),
+	}
+
+	tests := []struct {
+		name       string
+		attributes bool
+		data       []any
+		want       string
+	}{
+		{"plain", false, []any{"started"}, "<log><level>INFO</level><message>started</message></log>"},
+		{
+			"escaped",
+			false,
+			[]any{`<a & "b">`},
+			"<log><level>INFO</level><message>&lt;a &amp; &#34;b&#34;&gt;</message></log>",
+		},
+		{
+			"typed values",
+			false,
+			[]any{"done", 1500 * time.Millisecond, errors.New("not found")},
+			"<log><level>INFO</level><message>done</message><took>1500000000</took><err>not found</err></log>",
+		},
+		{
+			"array",
+			false,
+			[]any{"tagged", []string{"a", "b"}},
+			"<log><level>INFO</level><message>tagged</message><tags><item>a</item><item>b</item></tags></log>",
+		},
+		{
+			"object",
+			false,
+			[]any{"alive", &Heartbeat{Uptime: time.Minute, Stats: LoggerStats{Lines: 3}}},
+			"<log><level>INFO</level><message>alive</message><heartbeat><dropped>0</dropped><errors>0</errors>" +
+				"<lines>3</lines><uptime_seconds>60</uptime_seconds></heartbeat></log>",
+		},
+		{"attributes", true, []any{"two\nlines"}, `<log level="INFO" message="two&#xA;lines"/>`},
+		{
+			"attributes and elements",
+			true,
+			[]any{"tagged", []string{"a"}},
+			`<log level="INFO" message="tagged"><tags><item>a</item></tags></log>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter, err := NewFormatter(OutputFormatXML, fields, WithXMLAttributes(tt.attributes))
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+			result := formatter.FormatLogLine(LogLineArgs{Level: Info}, tt.data)
+			if result.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", result.err)
+			}
+			if got := string(result.bytes); got != tt.want {
+				t.Errorf("FormatLogLine() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendXMLName(t *testing.T) {
+	for name, want := range map[string]string{"user.id": "user.id", "user name": "user_name", "1st": "_1st", "": "_"} {
+		var got bytes.Buffer
+		appendXMLName(&got, name)
+		if got.String() != want {
+			t.Errorf("appendXMLName(%q) = %q, want %q", name, got.String(), want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package log
+
+import "time"
+
+// Freeze returns a view of the logger that can't change its configuration. See Logger.Freeze.
+func (l *ultraLogger) Freeze() Logger {
+	return &frozenLogger{Logger: l}
+}
+
+// frozenLogger is the Logger returned by Freeze. It logs with the logger it wraps, which can still be configured
+// directly.
+type frozenLogger struct {
+	Logger
+}
+
+// SetMinLevel does nothing.
+func (f *frozenLogger) SetMinLevel(Level) {}
+
+// BoostLevel does nothing, and returns a cancel func that does nothing.
+func (f *frozenLogger) BoostLevel(Level, time.Duration) (cancel func()) {
+	return func() {}
+}
+
+// SetTag does nothing.
+func (f *frozenLogger) SetTag(string) {}
+
+// Silence does nothing.
+func (f *frozenLogger) Silence(bool) {}
+
+// Close returns ErrorLoggerFrozen, without closing the logger.
+func (f *frozenLogger) Close() error {
+	return ErrorLoggerFrozen
+}
+
+// ForTenant returns a frozen view of the child logger of the tenant.
+func (f *frozenLogger) ForTenant(id string) Logger {
+	return &frozenLogger{Logger: f.Logger.ForTenant(id)}
+}
+
+// Freeze returns the logger, which is already frozen.
+func (f *frozenLogger) Freeze() Logger {
+	return f
+}
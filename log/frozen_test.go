@@ -0,0 +1,40 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogger_Freeze(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField(), NewTenantField()})
+	buf := &bytes.Buffer{}
+	logger, _ := NewLoggerWithOptions(WithAsync(false), WithDestination(buf, formatter), WithMinLevel(Info))
+
+	frozen := logger.Freeze()
+	frozen.SetMinLevel(Panic)
+	frozen.Silence(true)
+	frozen.BoostLevel(Debug, time.Minute)()
+	if err := frozen.Close(); !errors.Is(err, ErrorLoggerFrozen) {
+		t.Errorf("Close() error = %v, want %v", err, ErrorLoggerFrozen)
+	}
+
+	frozen.Debug("hidden")
+	frozen.Info("shown")
+	frozen.ForTenant("acme").SetMinLevel(Panic)
+	frozen.ForTenant("acme").Info("tenant")
+
+	want := "shown\ntenant tenant_id=acme\n"
+	if got := buf.String(); got != want {
+		t.Errorf("frozen logger wrote %q, want %q", got, want)
+	}
+
+	// The logger itself can still be configured.
+	logger.Silence(true)
+	frozen.Info("silenced")
+	if got := buf.String(); strings.Contains(got, "silenced") {
+		t.Errorf("frozen logger wrote %q after the logger was silenced", got)
+	}
+}
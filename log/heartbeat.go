@@ -0,0 +1,112 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Heartbeat is logged periodically by loggers created with WithHeartbeat. The destination formatters need a
+// NewHeartbeatField to write it.
+type Heartbeat struct {
+	// Uptime is the time since the logger was created.
+	Uptime time.Duration
+	// Stats are the internal counters of the logger.
+	Stats LoggerStats
+}
+
+// String returns the heartbeat as key=value pairs.
+func (h *Heartbeat) String() string {
+	return fmt.Sprintf(
+		"uptime=%s lines=%d dropped=%d errors=%d",
+		h.Uptime.Round(time.Second),
+		h.Stats.Lines,
+		h.Stats.Dropped,
+		h.Stats.Errors,
+	)
+}
+
+// EventFields returns the heartbeat fields.
+func (h *Heartbeat) EventFields() map[string]any {
+	return map[string]any{
+		"uptime_seconds": int64(h.Uptime / time.Second),
+		"lines":          h.Stats.Lines,
+		"dropped":        h.Stats.Dropped,
+		"errors":         h.Stats.Errors,
+	}
+}
+
+// NewHeartbeatField returns a new Field that formats the [*Heartbeat] logged by loggers created with WithHeartbeat.
+// See NewEventField.
+func NewHeartbeatField() Field {
+	field, _ := NewEventField[*Heartbeat]("heartbeat")
+	return field
+}
+
+// onHeartbeats is a FieldCondition that passes on heartbeats only, for fields that always match.
+func onHeartbeats(args LogLineArgs, value any) bool {
+	data, _ := value.([]any)
+	for _, datum := range data {
+		if _, ok := datum.(*Heartbeat); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// WithHeartbeat logs an "alive" line with a [*Heartbeat] every interval, so log pipelines can tell a service that is
+// alive but has nothing to log from one that is dead. Heartbeats are logged at the Info level, regardless of the
+// minimum level, unless the logger is silenced. They stop when the logger is closed. Default=disabled.
+func WithHeartbeat(interval time.Duration) LoggerOption {
+	return func(l *ultraLogger) error {
+		if interval <= 0 {
+			return &ErrorLoggerInitialization{err: fmt.Errorf("invalid heartbeat interval: %v", interval)}
+		}
+		l.heartbeatInterval = interval
+		return nil
+	}
+}
+
+// heartbeat logs the heartbeats of a logger until it's closed.
+type heartbeat struct {
+	stop    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// startHeartbeat starts logging heartbeats to the logger.
+func startHeartbeat(l *ultraLogger, interval time.Duration) *heartbeat {
+	h := &heartbeat{stop: make(chan struct{}), stopped: make(chan struct{})}
+	start := time.Now()
+
+	go func() {
+		defer close(h.stopped)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-h.stop:
+				return
+			case <-ticker.C:
+				if l.silent {
+					continue
+				}
+				beat := &Heartbeat{Uptime: time.Since(start), Stats: l.stats.snapshot()}
+				l.dispatch(LogLineArgs{Level: Info, Tag: l.tag}, []any{"alive", beat}, true)
+			}
+		}
+	}()
+
+	return h
+}
+
+// stopAndWait stops the heartbeats, and waits for the last one to be dispatched. It's a no-op on a nil *heartbeat.
+func (h *heartbeat) stopAndWait() {
+	if h == nil {
+		return
+	}
+	h.once.Do(func() { close(h.stop) })
+	<-h.stopped
+}
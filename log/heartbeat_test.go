@@ -0,0 +1,51 @@
+package log
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithHeartbeat(t *testing.T) {
+	dest := &toggleWriter{}
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField(), NewHeartbeatField()})
+
+	logger, err := NewLoggerWithOptions(
+		WithAsync(false),
+		WithMinLevel(Error),
+		WithHeartbeat(5*time.Millisecond),
+		WithDestination(dest, formatter),
+	)
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(dest.received()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	lines := dest.received()
+	if len(lines) < 2 {
+		t.Fatalf("got %d heartbeats, want at least 2", len(lines))
+	}
+	if !strings.HasPrefix(lines[0], "alive uptime=") || !strings.Contains(lines[1], "lines=1 dropped=0 errors=0") {
+		t.Errorf("got %q", lines)
+	}
+
+	// No heartbeats after Close.
+	n := len(dest.received())
+	time.Sleep(20 * time.Millisecond)
+	if got := len(dest.received()); got != n {
+		t.Errorf("got %d heartbeats after Close", got-n)
+	}
+}
+
+func TestWithHeartbeat_invalidInterval(t *testing.T) {
+	if _, err := NewLoggerWithOptions(WithHeartbeat(0)); err == nil {
+		t.Error("NewLoggerWithOptions() error = nil, want an error")
+	}
+}
@@ -0,0 +1,182 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MessageInfo identifies a queue message for the instrumentation helpers.
+type MessageInfo struct {
+	// ID is the message ID.
+	ID string
+	// Topic is the topic or queue the message is consumed from, or published to.
+	Topic string
+}
+
+// InstrumentSettings configures InstrumentConsumer and InstrumentProducer.
+type InstrumentSettings struct {
+	// Level is the level of messages that were handled successfully. Defaults to Info if nil; see LevelPtr.
+	Level *Level
+	// ErrorLevel is the level of messages whose handler returned an error or panicked. Defaults to Error if nil.
+	ErrorLevel *Level
+	// Message is the message of the logged lines. Defaults to "message consumed" for consumers, and "message
+	// published" for producers.
+	Message string
+}
+
+var defaultInstrumentSettings = InstrumentSettings{
+	Level:      LevelPtr(Info),
+	ErrorLevel: LevelPtr(Error),
+}
+
+func (s *InstrumentSettings) mergeDefault(operation MessageOperation) {
+	if s.Level == nil {
+		s.Level = LevelPtr(*defaultInstrumentSettings.Level)
+	}
+	if s.ErrorLevel == nil {
+		s.ErrorLevel = LevelPtr(*defaultInstrumentSettings.ErrorLevel)
+	}
+	if s.Message == "" {
+		s.Message = "message " + operation.pastTense()
+	}
+}
+
+// MessageOperation is the operation of a MessageEvent.
+type MessageOperation string
+
+const (
+	MessageOperationConsume MessageOperation = "consume"
+	MessageOperationProduce MessageOperation = "produce"
+)
+
+func (o MessageOperation) pastTense() string {
+	if o == MessageOperationProduce {
+		return "published"
+	}
+	return "consumed"
+}
+
+// MessageOutcome is the outcome of handling a message.
+type MessageOutcome string
+
+const (
+	MessageOutcomeOK    MessageOutcome = "ok"
+	MessageOutcomeError MessageOutcome = "error"
+	MessageOutcomePanic MessageOutcome = "panic"
+)
+
+// MessageEvent describes a message handled by an instrumented consumer or producer.
+type MessageEvent struct {
+	Operation MessageOperation
+	MessageInfo
+	Duration time.Duration
+	Outcome  MessageOutcome
+	// Err is the error returned by the handler, or the recovered panic value wrapped in an error.
+	Err error
+}
+
+// String returns the event as space separated key=value pairs.
+func (e *MessageEvent) String() string {
+	parts := []string{
+		"op=" + string(e.Operation),
+		"topic=" + e.Topic,
+		"id=" + e.ID,
+		"duration=" + e.Duration.String(),
+		"outcome=" + string(e.Outcome),
+	}
+	if e.Err != nil {
+		parts = append(parts, fmt.Sprintf("err=%q", e.Err.Error()))
+	}
+	return strings.Join(parts, " ")
+}
+
+// EventFields returns the operation, topic, id, duration (in nanoseconds), outcome, and error of the event. The error
+// is omitted if there is none.
+func (e *MessageEvent) EventFields() map[string]any {
+	fields := map[string]any{
+		"operation": e.Operation,
+		"topic":     e.Topic,
+		"id":        e.ID,
+		"duration":  e.Duration,
+		"outcome":   e.Outcome,
+	}
+	if e.Err != nil {
+		fields["error"] = e.Err.Error()
+	}
+	return fields
+}
+
+// NewMessageEventField returns a new Field that formats the [*MessageEvent] logged by instrumented consumers and
+// producers. See NewEventField.
+//
+// OutputFormats:
+//   - OutputFormatText => [MessageEvent.String].
+//   - OutputFormatJSON => [MessageEvent.EventFields].
+func NewMessageEventField(name string) (Field, error) {
+	return NewEventField[*MessageEvent](name)
+}
+
+// InstrumentConsumer wraps a message handler so that every message it processes is logged with its ID, topic,
+// processing duration, and outcome. describe extracts the MessageInfo from a message. Panics in the handler are logged
+// and re-panicked.
+//
+// Lines are logged with the message and a [*MessageEvent]; the logger's formatters need a NewMessageEventField to
+// write it.
+func InstrumentConsumer[M any](
+	logger Logger,
+	describe func(M) MessageInfo,
+	handler func(context.Context, M) error,
+	settings *InstrumentSettings,
+) func(context.Context, M) error {
+	return instrument(logger, MessageOperationConsume, describe, handler, settings)
+}
+
+// InstrumentProducer wraps a publish func so that every message it publishes is logged. See InstrumentConsumer.
+func InstrumentProducer[M any](
+	logger Logger,
+	describe func(M) MessageInfo,
+	publish func(context.Context, M) error,
+	settings *InstrumentSettings,
+) func(context.Context, M) error {
+	return instrument(logger, MessageOperationProduce, describe, publish, settings)
+}
+
+func instrument[M any](
+	logger Logger,
+	operation MessageOperation,
+	describe func(M) MessageInfo,
+	handler func(context.Context, M) error,
+	settings *InstrumentSettings,
+) func(context.Context, M) error {
+	if settings == nil {
+		settings = &InstrumentSettings{}
+	}
+	merged := *settings
+	merged.mergeDefault(operation)
+
+	return func(ctx context.Context, msg M) (err error) {
+		event := &MessageEvent{Operation: operation, MessageInfo: describe(msg)}
+		start := time.Now()
+
+		defer func() {
+			event.Duration = time.Since(start)
+			level := *merged.Level
+
+			if r := recover(); r != nil {
+				event.Outcome, event.Err, level = MessageOutcomePanic, fmt.Errorf("panic: %v", r), *merged.ErrorLevel
+				logger.Log(level, merged.Message, event)
+				panic(r)
+			}
+
+			event.Outcome = MessageOutcomeOK
+			if err != nil {
+				event.Outcome, event.Err, level = MessageOutcomeError, err, *merged.ErrorLevel
+			}
+			logger.Log(level, merged.Message, event)
+		}()
+
+		return handler(ctx, msg)
+	}
+}
@@ -0,0 +1,121 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testQueueMessage struct {
+	id    string
+	topic string
+}
+
+func describeTestQueueMessage(m testQueueMessage) MessageInfo {
+	return MessageInfo{ID: m.id, Topic: m.topic}
+}
+
+func newInstrumentTestLogger(t *testing.T) (Logger, *bytes.Buffer) {
+	t.Helper()
+
+	eventField, err := NewMessageEventField("event")
+	if err != nil {
+		t.Fatalf("NewMessageEventField() error = %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewDefaultLevelField(), NewMessageField(), eventField})
+	logger, err := NewLoggerWithOptions(WithAsync(false), WithDestination(buf, formatter))
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	return logger, buf
+}
+
+func TestInstrumentConsumer(t *testing.T) {
+	tests := []struct {
+		name       string
+		handlerErr error
+		wantPrefix string
+		wantSuffix string
+	}{
+		{
+			"OK",
+			nil,
+			"<INFO> message consumed op=consume topic=orders id=42 duration=",
+			" outcome=ok\n",
+		},
+		{
+			"Error",
+			errors.New("out of stock"),
+			"<ERROR> message consumed op=consume topic=orders id=42 duration=",
+			` outcome=error err="out of stock"` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newInstrumentTestLogger(t)
+
+			handler := InstrumentConsumer(logger, describeTestQueueMessage, func(context.Context, testQueueMessage) error {
+				return tt.handlerErr
+			}, nil)
+
+			if err := handler(context.Background(), testQueueMessage{id: "42", topic: "orders"}); !errors.Is(err, tt.handlerErr) {
+				t.Errorf("handler() error = %v, want %v", err, tt.handlerErr)
+			}
+
+			got := buf.String()
+			if !strings.HasPrefix(got, tt.wantPrefix) || !strings.HasSuffix(got, tt.wantSuffix) {
+				t.Errorf("line = %q, want %q...%q", got, tt.wantPrefix, tt.wantSuffix)
+			}
+		})
+	}
+}
+
+func TestInstrumentConsumer_panic(t *testing.T) {
+	logger, buf := newInstrumentTestLogger(t)
+
+	handler := InstrumentConsumer(logger, describeTestQueueMessage, func(context.Context, testQueueMessage) error {
+		panic("boom")
+	}, nil)
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("recover() = %v, want the handler's panic to propagate", r)
+		}
+		if got := buf.String(); !strings.Contains(got, `outcome=panic err="panic: boom"`) {
+			t.Errorf("line = %q, want the panic to be logged", got)
+		}
+	}()
+
+	_ = handler(context.Background(), testQueueMessage{id: "42", topic: "orders"})
+}
+
+func TestInstrumentProducer(t *testing.T) {
+	logger, buf := newInstrumentTestLogger(t)
+
+	publish := InstrumentProducer(logger, describeTestQueueMessage, func(context.Context, testQueueMessage) error {
+		return nil
+	}, &InstrumentSettings{Level: LevelPtr(Warn), Message: "sent"})
+
+	_ = publish(context.Background(), testQueueMessage{id: "7", topic: "emails"})
+
+	if want := "<WARN> sent op=produce topic=emails id=7 duration="; !strings.HasPrefix(buf.String(), want) {
+		t.Errorf("line = %q, want prefix %q", buf.String(), want)
+	}
+}
+
+func TestInstrumentSettings_mergeDefault(t *testing.T) {
+	settings := &InstrumentSettings{Level: LevelPtr(Debug)}
+	settings.mergeDefault(MessageOperationConsume)
+
+	if *settings.Level != Debug {
+		t.Errorf("Level = %v, want %v", *settings.Level, Debug)
+	}
+	if *settings.ErrorLevel != Error {
+		t.Errorf("ErrorLevel = %v, want %v", *settings.ErrorLevel, Error)
+	}
+}
@@ -0,0 +1,303 @@
+package log
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// jsonSanitizer rewrites values that encoding/json can't marshal into values it can, and cuts nested values short
+// according to the NestingLimits. Unless there are limits, it is only used once marshalling a line has failed, so it
+// doesn't cost anything for regular lines.
+//
+// Values are only copied if something inside them had to change; everything else is passed through as is, so custom
+// marshalling is preserved where possible.
+type jsonSanitizer struct {
+	nonFiniteFloats NonFiniteFloatPolicy
+	limits          NestingLimits
+
+	refs refPath // References on the path to the value being sanitized, to truncate values that contain themselves.
+}
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// sanitizeFields sanitizes the fields of a line. Fields whose values are dropped are omitted.
+func (s *jsonSanitizer) sanitizeFields(fields map[string]any) (map[string]any, error) {
+	s.refs = refPath{}
+
+	sanitized := make(map[string]any, len(fields))
+	for name, value := range fields {
+		v, keep, _, err := s.sanitize(name, reflect.ValueOf(value), 0, 0)
+		if err != nil {
+			return nil, err
+		}
+		if keep {
+			sanitized[name] = v
+		}
+	}
+	return sanitized, nil
+}
+
+// sanitize returns the sanitized value at path, whether it should be kept, and whether it differs from the original.
+// depth counts the values descended into, nesting only the containers (arrays, slices, maps, and structs).
+func (s *jsonSanitizer) sanitize(
+	path string,
+	v reflect.Value,
+	depth, nesting int,
+) (result any, keep bool, changed bool, err error) {
+	if !v.IsValid() {
+		return nil, true, false, nil
+	}
+	if depth > maxReflectDepth {
+		return depthMarker, true, true, nil
+	}
+
+	original := func() any {
+		if v.CanInterface() {
+			return v.Interface()
+		}
+		return nil
+	}
+
+	if m, ok := original().(*orderedMap); ok && m != nil {
+		return s.sanitizeOrderedMap(path, m, depth, nesting)
+	}
+	if v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType) {
+		return original(), true, false, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if !isNonFinite(f) {
+			return original(), true, false, nil
+		}
+		switch s.nonFiniteFloats {
+		case NonFiniteFloatDrop:
+			return nil, false, true, nil
+		case NonFiniteFloatError:
+			return nil, false, true, &ErrorNonFiniteFloat{path: path, value: f}
+		default:
+			return formatNonFiniteFloat(f), true, true, nil
+		}
+
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return original(), true, false, nil
+		}
+
+		ref, ok := s.refs.enter(v)
+		if !ok {
+			return cycleMarker, true, true, nil
+		}
+		defer s.refs.leave(ref)
+
+		elem, keep, changed, err := s.sanitize(path, v.Elem(), depth+1, nesting)
+		if !changed {
+			return original(), true, false, err
+		}
+		return elem, keep, true, err
+
+	case reflect.Map:
+		if v.IsNil() {
+			return original(), true, false, nil
+		}
+		if s.limits.tooDeep(nesting) {
+			return depthMarker, true, true, nil
+		}
+		return s.sanitizeMap(path, v, depth, nesting)
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && (v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8) {
+			// Byte slices are marshalled as base64 strings.
+			return original(), true, false, nil
+		}
+		if s.limits.tooDeep(nesting) {
+			return depthMarker, true, true, nil
+		}
+		return s.sanitizeSlice(path, v, depth, nesting)
+
+	case reflect.Struct:
+		if s.limits.tooDeep(nesting) {
+			return depthMarker, true, true, nil
+		}
+		return s.sanitizeStruct(path, v, depth, nesting)
+
+	default:
+		return original(), true, false, nil
+	}
+}
+
+func (s *jsonSanitizer) sanitizeMap(path string, v reflect.Value, depth, nesting int) (any, bool, bool, error) {
+	ref, ok := s.refs.enter(v)
+	if !ok {
+		return cycleMarker, true, true, nil
+	}
+	defer s.refs.leave(ref)
+
+	type entry struct {
+		key   string
+		value reflect.Value
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		entries = append(entries, entry{key: fmt.Sprint(iter.Key().Interface()), value: iter.Value()})
+	}
+
+	shown := s.limits.shown(len(entries))
+	changed := shown < len(entries)
+	if changed {
+		// Keep the same elements on every line.
+		slices.SortFunc(entries, func(a, b entry) int { return strings.Compare(a.key, b.key) })
+	}
+
+	sanitized := make(map[string]any, shown+1)
+	for _, entry := range entries[:shown] {
+		elem, keep, elemChanged, err := s.sanitize(path+"."+entry.key, entry.value, depth+1, nesting+1)
+		if err != nil {
+			return nil, false, true, err
+		}
+		changed = changed || elemChanged
+		if keep {
+			sanitized[entry.key] = elem
+		}
+	}
+	addMoreEntry(sanitized, shown, len(entries))
+
+	if !changed {
+		return v.Interface(), true, false, nil
+	}
+	return sanitized, true, true, nil
+}
+
+func (s *jsonSanitizer) sanitizeSlice(path string, v reflect.Value, depth, nesting int) (any, bool, bool, error) {
+	ref, ok := s.refs.enter(v)
+	if !ok {
+		return cycleMarker, true, true, nil
+	}
+	defer s.refs.leave(ref)
+
+	shown := s.limits.shown(v.Len())
+	sanitized := make([]any, shown, shown+1)
+	changed := shown < v.Len()
+
+	for i := range shown {
+		elem, keep, elemChanged, err := s.sanitize(fmt.Sprintf("%s[%d]", path, i), v.Index(i), depth+1, nesting+1)
+		if err != nil {
+			return nil, false, true, err
+		}
+		changed = changed || elemChanged
+		if keep {
+			sanitized[i] = elem
+		}
+	}
+	if shown < v.Len() {
+		sanitized = append(sanitized, moreMarker(v.Len()-shown))
+	}
+
+	if !changed {
+		return v.Interface(), true, false, nil
+	}
+	return sanitized, true, true, nil
+}
+
+func (s *jsonSanitizer) sanitizeStruct(path string, v reflect.Value, depth, nesting int) (any, bool, bool, error) {
+	t := v.Type()
+	sanitized := make(map[string]any, t.NumField())
+	changed := false
+
+	fields, total := 0, 0
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			tagName, _, _ := strings.Cut(tag, ",")
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+
+		total++
+		if s.limits.MaxElements > 0 && fields == s.limits.MaxElements {
+			continue
+		}
+		fields++
+
+		elem, keep, elemChanged, err := s.sanitize(path+"."+name, v.Field(i), depth+1, nesting+1)
+		if err != nil {
+			return nil, false, true, err
+		}
+		changed = changed || elemChanged
+		if keep {
+			sanitized[name] = elem
+		}
+	}
+	if fields < total {
+		changed = true
+		addMoreEntry(sanitized, fields, total)
+	}
+
+	if !changed {
+		return v.Interface(), true, false, nil
+	}
+	return sanitized, true, true, nil
+}
+
+func (s *jsonSanitizer) sanitizeOrderedMap(path string, m *orderedMap, depth, nesting int) (any, bool, bool, error) {
+	if s.limits.tooDeep(nesting) {
+		return depthMarker, true, true, nil
+	}
+
+	shown := s.limits.shown(len(m.keys))
+	sanitized := &orderedMap{
+		keys:     make([]any, 0, shown),
+		values:   make([]any, 0, shown),
+		more:     len(m.keys) - shown + m.more,
+		settings: m.settings,
+	}
+	changed := shown < len(m.keys)
+
+	for i := range shown {
+		key := fmt.Sprintf("%v", m.keys[i])
+		elem, keep, elemChanged, err := s.sanitize(path+"."+key, reflect.ValueOf(m.values[i]), depth+1, nesting+1)
+		if err != nil {
+			return nil, false, true, err
+		}
+		changed = changed || elemChanged
+		if keep {
+			sanitized.keys = append(sanitized.keys, m.keys[i])
+			sanitized.values = append(sanitized.values, elem)
+		} else {
+			changed = true
+		}
+	}
+
+	if !changed {
+		return m, true, false, nil
+	}
+	return sanitized, true, true, nil
+}
+
+// moreKey is the key of the entry that summarizes the omitted entries of a map or object.
+const moreKey = "..."
+
+// addMoreEntry adds the moreKey entry to an object with total entries, of which shown were kept.
+func addMoreEntry(object map[string]any, shown, total int) {
+	if shown < total {
+		object[moreKey] = fmt.Sprintf("(+%d more)", total-shown)
+	}
+}
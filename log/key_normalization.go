@@ -0,0 +1,126 @@
+package log
+
+import (
+	"strings"
+	"unicode"
+)
+
+// KeyNormalization is a naming convention that the keys of a formatter's fields are normalized to, so that logs from
+// teams with different conventions end up consistent in shared pipelines. See WithKeyNormalization.
+type KeyNormalization int
+
+const (
+	// KeyNormalizationNone writes fields under their names as is. This is the default.
+	KeyNormalizationNone KeyNormalization = iota
+	// KeySnakeCase normalizes keys to snake_case: "userID" and "User-Id" become "user_id".
+	KeySnakeCase
+	// KeyCamelCase normalizes keys to camelCase: "user_id" and "UserID" become "userId".
+	KeyCamelCase
+	// KeyLowercase lowercases keys, without changing their separators: "userID" becomes "userid".
+	KeyLowercase
+)
+
+func (n KeyNormalization) String() string {
+	switch n {
+	case KeyNormalizationNone:
+		return "none"
+	case KeySnakeCase:
+		return "snake_case"
+	case KeyCamelCase:
+		return "camelCase"
+	case KeyLowercase:
+		return "lowercase"
+	default:
+		return "unknown"
+	}
+}
+
+func (n KeyNormalization) mapper() func(key string) string {
+	switch n {
+	case KeySnakeCase:
+		return toSnakeCase
+	case KeyCamelCase:
+		return toCamelCase
+	case KeyLowercase:
+		return strings.ToLower
+	default:
+		return nil
+	}
+}
+
+// WithKeyNormalization normalizes the keys of the formatter's fields to a naming convention.
+//
+// Only keys derived from field names are normalized; keys set explicitly with WithFieldAlias or a schema are written as
+// is. Keys of nested data, like struct fields, are left alone. Fields whose normalized keys are the same collide; see
+// WithKeyCollisionPolicy.
+func WithKeyNormalization(normalization KeyNormalization) FormatterOption {
+	return WithKeyMapper(normalization.mapper())
+}
+
+// WithKeyMapper normalizes the keys of the formatter's fields with a custom mapper, e.g. to add a team prefix. It
+// follows the same rules as WithKeyNormalization. If the mapper returns an empty key, the field name is used.
+func WithKeyMapper(mapper func(key string) string) FormatterOption {
+	return func(f LogLineFormatter) LogLineFormatter {
+		if keys := formatterKeys(f); keys != nil {
+			keys.normalize = mapper
+		}
+		return f
+	}
+}
+
+func toSnakeCase(key string) string {
+	words := splitKeyWords(key)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	return strings.Join(words, "_")
+}
+
+func toCamelCase(key string) string {
+	words := splitKeyWords(key)
+	for i, word := range words {
+		word = strings.ToLower(word)
+		if i > 0 {
+			runes := []rune(word)
+			runes[0] = unicode.ToUpper(runes[0])
+			word = string(runes)
+		}
+		words[i] = word
+	}
+	return strings.Join(words, "")
+}
+
+// splitKeyWords splits a key into words at separators (anything but letters and digits) and case changes. Acronyms are
+// kept together: "HTTPStatusCode" splits into "HTTP", "Status", "Code".
+func splitKeyWords(key string) []string {
+	var words []string
+	runes := []rune(key)
+	start := -1
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+
+		prev := runes[i-1]
+		startsWord := unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+			unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]))
+		if startsWord {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+
+	return words
+}
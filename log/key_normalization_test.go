@@ -0,0 +1,88 @@
+package log
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestKeyNormalization_mapper(t *testing.T) {
+	tests := []struct {
+		key       string
+		snakeCase string
+		camelCase string
+	}{
+		{"message", "message", "message"},
+		{"userID", "user_id", "userId"},
+		{"UserId", "user_id", "userId"},
+		{"user_id", "user_id", "userId"},
+		{"User-Id", "user_id", "userId"},
+		{"HTTPStatusCode", "http_status_code", "httpStatusCode"},
+		{"request.duration_ms", "request_duration_ms", "requestDurationMs"},
+		{"ipv4Addr", "ipv4_addr", "ipv4Addr"},
+		{"__", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := KeySnakeCase.mapper()(tt.key); got != tt.snakeCase {
+				t.Errorf("snake_case(%q) = %q, want %q", tt.key, got, tt.snakeCase)
+			}
+			if got := KeyCamelCase.mapper()(tt.key); got != tt.camelCase {
+				t.Errorf("camelCase(%q) = %q, want %q", tt.key, got, tt.camelCase)
+			}
+		})
+	}
+}
+
+func TestWithKeyNormalization(t *testing.T) {
+	userField, _ := NewIntField("userID")
+	durationField, _ := NewBoolField("request_cached")
+	fields := []Field{NewMessageField(), userField, durationField}
+	data := []any{"hello", 42, true}
+
+	tests := []struct {
+		name   string
+		format OutputFormat
+		opts   []FormatterOption
+		want   string
+	}{
+		{"snake_case", OutputFormatJSON, []FormatterOption{WithKeyNormalization(KeySnakeCase)},
+			`{"message":"hello","request_cached":true,"user_id":42}`},
+		{"camelCase", OutputFormatJSON, []FormatterOption{WithKeyNormalization(KeyCamelCase)},
+			`{"message":"hello","requestCached":true,"userId":42}`},
+		{"lowercase", OutputFormatText, []FormatterOption{WithKeyNormalization(KeyLowercase)},
+			`hello userid=42 request_cached=true`},
+		{"custom mapper", OutputFormatJSON, []FormatterOption{WithKeyMapper(func(key string) string { return "app." + key })},
+			`{"app.message":"hello","app.request_cached":true,"app.userID":42}`},
+		{"alias is explicit", OutputFormatJSON, []FormatterOption{WithKeyNormalization(KeyCamelCase), WithFieldAlias("request_cached", "CACHED")},
+			`{"CACHED":true,"message":"hello","userId":42}`},
+		{"mapper returns empty key", OutputFormatJSON, []FormatterOption{WithKeyMapper(func(key string) string { return "" })},
+			`{"message":"hello","request_cached":true,"userID":42}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter, err := NewFormatter(tt.format, fields, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+
+			result := formatter.FormatLogLine(LogLineArgs{Level: Info}, data)
+			if result.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", result.err)
+			}
+			if string(result.bytes) != tt.want {
+				t.Errorf("FormatLogLine() = %s, want %s", result.bytes, tt.want)
+			}
+		})
+	}
+
+	t.Run("collision", func(t *testing.T) {
+		userIDField, _ := NewStringField("user_id")
+		_, err := NewFormatter(OutputFormatJSON, []Field{userIDField, userField}, WithKeyNormalization(KeySnakeCase))
+
+		var collision *ErrorKeyCollision
+		if !errors.As(err, &collision) || !strings.Contains(err.Error(), `"userID"`) {
+			t.Errorf("NewFormatter() error = %v, want ErrorKeyCollision for userID", err)
+		}
+	})
+}
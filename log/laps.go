@@ -0,0 +1,152 @@
+package log
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Laps is a stopwatch that records the time taken by the stages of a multi-stage operation, e.g. the parsing,
+// validation and storage of a request, as named laps. Log it with a NewLapsField:
+//
+//	laps := log.NewLaps()
+//	parse(req)
+//	laps.Lap("parse")
+//	store(req)
+//	laps.Lap("db")
+//	logger.Info("request handled", laps)
+//	// Output: request handled parse=2ms db=40ms
+//
+// Carry it in a context with ContextWithLaps, so that any code handling the operation can record its stage with
+// RecordLap.
+//
+// Laps are safe for concurrent use. All methods are no-ops on a nil *Laps.
+type Laps struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	start     time.Time
+	last      time.Time
+	names     []string
+	durations map[string]time.Duration
+}
+
+// NewLaps returns Laps whose first lap starts now.
+func NewLaps() *Laps {
+	return newLapsWithClock(time.Now)
+}
+
+func newLapsWithClock(now func() time.Time) *Laps {
+	start := now()
+	return &Laps{now: now, start: start, last: start, durations: map[string]time.Duration{}}
+}
+
+// Lap ends the current lap, recording the time since the previous lap ended, or since the Laps were created, under the
+// name. Laps with a name that was already recorded add to it, e.g. for a "db" lap per query.
+func (l *Laps) Lap(name string) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if _, ok := l.durations[name]; !ok {
+		l.names = append(l.names, name)
+	}
+	l.durations[name] += now.Sub(l.last)
+	l.last = now
+}
+
+// Get returns the time recorded under the name.
+func (l *Laps) Get(name string) (time.Duration, bool) {
+	if l == nil {
+		return 0, false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	d, ok := l.durations[name]
+	return d, ok
+}
+
+// Total returns the time from the creation of the Laps to the end of the last lap.
+func (l *Laps) Total() time.Duration {
+	if l == nil {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.last.Sub(l.start)
+}
+
+// String returns the laps as space separated name=duration pairs, in the order they were first recorded, with the
+// durations rounded to the microsecond, e.g. "parse=2.1ms validate=1ms db=40.02ms".
+func (l *Laps) String() string {
+	if l == nil {
+		return ""
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var b strings.Builder
+	for i, name := range l.names {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(l.durations[name].Round(time.Microsecond).String())
+	}
+	return b.String()
+}
+
+// EventFields returns the laps, by name.
+func (l *Laps) EventFields() map[string]any {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	fields := make(map[string]any, len(l.durations))
+	for name, d := range l.durations {
+		fields[name] = d
+	}
+	return fields
+}
+
+// NewLapsField returns a new Field that formats [*Laps]. See NewEventField.
+//
+// OutputFormats:
+//   - OutputFormatText => space separated name=duration pairs, in the order the laps were first recorded.
+//   - OutputFormatJSON => an object with the duration of each lap, in nanoseconds.
+func NewLapsField(name string) (Field, error) {
+	return NewEventField[*Laps](name)
+}
+
+type lapsContextKey struct{}
+
+// ContextWithLaps returns a copy of ctx that carries the Laps.
+func ContextWithLaps(ctx context.Context, l *Laps) context.Context {
+	return context.WithValue(ctx, lapsContextKey{}, l)
+}
+
+// LapsFromContext returns the Laps carried by ctx, or nil if there are none. The methods of a nil *Laps are no-ops, so
+// the result can be used without checking.
+func LapsFromContext(ctx context.Context) *Laps {
+	l, _ := ctx.Value(lapsContextKey{}).(*Laps)
+	return l
+}
+
+// RecordLap ends the current lap of the Laps carried by ctx, if any, under the name. See Laps.Lap.
+func RecordLap(ctx context.Context, name string) {
+	LapsFromContext(ctx).Lap(name)
+}
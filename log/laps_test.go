@@ -0,0 +1,73 @@
+package log
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// newTestLaps returns Laps whose clock advances by the steps, one per reading after the first.
+func newTestLaps(steps ...time.Duration) *Laps {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	return newLapsWithClock(func() time.Time {
+		t := now
+		if len(steps) > 0 {
+			now, steps = now.Add(steps[0]), steps[1:]
+		}
+		return t
+	})
+}
+
+func TestLaps(t *testing.T) {
+	laps := newTestLaps(2*time.Millisecond, 1500*time.Microsecond, 40*time.Millisecond, 5*time.Millisecond)
+	ctx := ContextWithLaps(context.Background(), laps)
+
+	RecordLap(ctx, "parse")
+	RecordLap(ctx, "validate")
+	RecordLap(ctx, "db")
+	LapsFromContext(ctx).Lap("db")
+
+	if got, want := laps.String(), "parse=2ms validate=1.5ms db=45ms"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got, want := laps.Total(), 48500*time.Microsecond; got != want {
+		t.Errorf("Total() = %v, want %v", got, want)
+	}
+	if got, ok := laps.Get("db"); !ok || got != 45*time.Millisecond {
+		t.Errorf("Get(db) = %v, %v, want 45ms", got, ok)
+	}
+
+	// Without Laps in the context, laps aren't recorded anywhere.
+	RecordLap(context.Background(), "ignored")
+}
+
+func TestNewLapsField(t *testing.T) {
+	field, err := NewLapsField("laps")
+	if err != nil {
+		t.Fatalf("NewLapsField() error = %v", err)
+	}
+
+	tests := []struct {
+		format OutputFormat
+		want   string
+	}{
+		{OutputFormatText, "done parse=2ms db=40ms"},
+		{OutputFormatJSON, `{"laps":{"db":40000000,"parse":2000000},"message":"done"}`},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			laps := newTestLaps(2*time.Millisecond, 40*time.Millisecond)
+			laps.Lap("parse")
+			laps.Lap("db")
+
+			formatter, _ := NewFormatter(tt.format, []Field{NewMessageField(), field})
+			res := formatter.FormatLogLine(LogLineArgs{}, []any{"done", laps})
+			if res.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", res.err)
+			}
+			if got := string(res.bytes); got != tt.want {
+				t.Errorf("FormatLogLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
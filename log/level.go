@@ -28,6 +28,13 @@ const (
     Panic
 )
 
+// LevelPtr returns a pointer to the level, for settings whose level defaults to another level when it is nil, e.g.
+// FlightRecorderSettings.TriggerLevel. Debug is the zero value of a Level, so it couldn't be told apart from an unset
+// level otherwise.
+func LevelPtr(level Level) *Level {
+    return &level
+}
+
 // AllLevels returns a slice of all available levels.
 func AllLevels() []Level {
     return []Level{
@@ -56,6 +63,40 @@ func (l Level) String() string {
     }
 }
 
+// SyslogSeverity returns the RFC 5424 syslog severity of the level: 7 (debug) for Debug, 6 (informational) for Info,
+// 4 (warning) for Warn, 3 (error) for Error and 2 (critical) for Panic.
+func (l Level) SyslogSeverity() int {
+    switch l {
+    case Debug:
+        return 7
+    case Info:
+        return 6
+    case Warn:
+        return 4
+    case Error:
+        return 3
+    default:
+        return 2
+    }
+}
+
+// CEFSeverity returns the ArcSight CEF severity of the level, from 0 to 10: 1 (low) for Debug, 3 (low) for Info, 5
+// (medium) for Warn, 7 (high) for Error and 10 (very-high) for Panic.
+func (l Level) CEFSeverity() int {
+    switch l {
+    case Debug:
+        return 1
+    case Info:
+        return 3
+    case Warn:
+        return 5
+    case Error:
+        return 7
+    default:
+        return 10
+    }
+}
+
 // ParseLevel parses a string into a Level. Returns an error if the string is not a valid Level.
 func ParseLevel(levelStr string) (Level, error) {
     switch strings.ToLower(levelStr) {
@@ -0,0 +1,41 @@
+package log
+
+import "sync"
+
+// lineCache memoizes the result of each formatter for a single line, so that destinations sharing a formatter, or
+// wrapping the same base formatter (e.g. a colorized console and a plain file), only pay for field processing once.
+// The logger attaches one to the LogLineArgs of every line it dispatches to more than one destination.
+type lineCache struct {
+	mu      sync.Mutex
+	results map[LogLineFormatter]*cachedLine
+}
+
+type cachedLine struct {
+	once   sync.Once
+	result FormatResult
+}
+
+func newLineCache() *lineCache {
+	return &lineCache{results: map[LogLineFormatter]*cachedLine{}}
+}
+
+// format returns the result of format for the formatter f, calling it only once per line. Concurrent callers for the
+// same formatter wait for the first.
+func (c *lineCache) format(f LogLineFormatter, format func() FormatResult) FormatResult {
+	c.mu.Lock()
+	line, ok := c.results[f]
+	if !ok {
+		line = &cachedLine{}
+		c.results[f] = line
+	}
+	c.mu.Unlock()
+
+	line.once.Do(func() {
+		line.result = format()
+	})
+
+	// Clip the shared bytes, so that appending to them, e.g. the newline added by the writer, never writes into
+	// another destination's line.
+	bytes := line.result.bytes
+	return FormatResult{bytes[:len(bytes):len(bytes)], line.result.err}
+}
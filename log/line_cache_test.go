@@ -0,0 +1,41 @@
+package log
+
+import (
+	"bytes"
+	"sync/atomic"
+	"testing"
+)
+
+func TestLogger_sharedFormatterFormatsOnce(t *testing.T) {
+	var calls atomic.Int32
+	countingField, _ := NewObjectField[int]("n", func(args LogLineArgs, data int) (any, error) {
+		calls.Add(1)
+		return data, nil
+	})
+	base, _ := NewFormatter(OutputFormatText, []Field{countingField})
+	colorized := NewColorizedFormatter(base, nil)
+	colorized.Policy = ColorPolicyAlways
+
+	plain, console, file := &bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{}
+	logger, err := NewLoggerWithOptions(
+		WithDestination(plain, base),
+		WithDestination(file, base),
+		WithDestination(console, colorized),
+		WithAsync(false),
+	)
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	logger.Info(42)
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("field formatted %d times, want 1", got)
+	}
+	if plain.String() != "n=42\n" || file.String() != "n=42\n" {
+		t.Errorf("plain = %q, file = %q, want %q", plain.String(), file.String(), "n=42\n")
+	}
+	if !bytes.Contains(console.Bytes(), []byte("n=42")) || bytes.Equal(console.Bytes(), plain.Bytes()) {
+		t.Errorf("console = %q, want colorized n=42", console.String())
+	}
+}
@@ -6,15 +6,59 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"runtime/trace"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // Logger defines the interface for a structured ultraLogger in Go.
 //
 // This interface is useful for either creating your own logger or for using an existing logger, and preventing changes
-// to the loggers formatting Settings.
+// to the loggers formatting Settings. Code that only logs, or only configures a logger, can depend on the smaller
+// LevelLogger, Configurable and Flusher interfaces instead; Loggers implement all of them.
 type Logger interface {
+	LevelLogger
+	Configurable
+	Flusher
+
+	// Close flushes the logger and releases the resources it owns, like files opened by NewFileLogger and WAL
+	// writers created by WithWALDestination. The logger must not be used after Close.
+	Close() error
+
+	// ForTenant returns a child logger that stamps the tenant ID on every line it logs. See TenantID.
+	ForTenant(id string) Logger
+
+	// Freeze returns a view of the logger that can log, but not change the configuration of the logger, so it can be
+	// handed to plugins and libraries safely. SetMinLevel, BoostLevel, SetTag and Silence do nothing on the view, and
+	// Close returns ErrorLoggerFrozen.
+	Freeze() Logger
+
+	// Pressure returns the load on the async queue of the logger, so applications can shed their own load before the
+	// logger starts dropping lines. See WithAsyncQueue.
+	Pressure() Pressure
+
+	// Tail returns a channel of the last n lines of the logger's ring buffer destination, followed by the lines logged
+	// after them, until ctx is done. See WithRingBufferDestination.
+	Tail(ctx context.Context, n int) (<-chan string, error)
+
+	// Query returns the lines of the logger's ring buffer destination that match the query, oldest first. See
+	// WithRingBufferDestination.
+	Query(query Query) ([]QueryResult, error)
+
+	// LogStartupInfo logs a single line describing the effective configuration of the logger (level, destinations and
+	// their formats) and the process (version, host), typically once at startup. See StartupInfo.
+	LogStartupInfo()
+
+	// InternalErrors returns a channel of the logger's internal errors (formatting failures, write failures, etc.), so
+	// applications can monitor the logging subsystem out-of-band.
+	InternalErrors() <-chan error
+}
+
+// LevelLogger logs lines. It's the part of a Logger that libraries which only log need.
+type LevelLogger interface {
 	// Log logs at the specified level without formatting.
 	Log(level Level, data ...any)
 
@@ -33,21 +77,54 @@ type Logger interface {
 	// Panic logs a panic-level message and then panics.
 	Panic(data ...any)
 
+	// LogEntry logs an Entry built programmatically, e.g. by a bridge from another logging library. See Entry.
+	LogEntry(entry Entry)
+
+	// LogMsg logs a single message string at the specified level. Unlike Log, the message isn't boxed into an any, so
+	// synchronous loggers using the level+message text layout log without allocating.
+	LogMsg(level Level, msg string)
+
+	// DebugMsg logs a debug-level message string. See LogMsg.
+	DebugMsg(msg string)
+
+	// InfoMsg logs an info-level message string. See LogMsg.
+	InfoMsg(msg string)
+
+	// WarnMsg logs a warning-level message string. See LogMsg.
+	WarnMsg(msg string)
+
+	// ErrorMsg logs an error-level message string. See LogMsg.
+	ErrorMsg(msg string)
+}
+
+// Configurable changes which lines a logger writes, and how. It's the part of a Logger that frozen loggers don't
+// honor; see Logger.Freeze.
+type Configurable interface {
 	// SetMinLevel sets the minimum logging level that will be output.
 	SetMinLevel(level Level)
 
+	// BoostLevel temporarily lowers the minimum level to level, for the duration or until the returned cancel func is
+	// called. A duration <= 0 boosts until cancel is called.
+	BoostLevel(level Level, duration time.Duration) (cancel func())
+
 	// SetTag sets the tag for the logger.
 	SetTag(tag string)
 
 	// Silence enables or disables logging for the logger.
 	Silence(enable bool)
+}
 
+// Flusher flushes the lines a logger has yet to write.
+type Flusher interface {
 	// Flush flushes the logger's output.
 	Flush()
 }
 
 const loglineTimeout = time.Millisecond * 250
 
+// internalErrorsBufferSize is the buffer size of the channel returned by InternalErrors.
+const internalErrorsBufferSize = 64
+
 var defaultFields = []Field{
 	NewDefaultCurrentTimeField(),
 	NewDefaultLevelField(),
@@ -57,17 +134,55 @@ var defaultFields = []Field{
 func NewLoggerWithOptions(opts ...LoggerOption) (Logger, error) {
 	l := newUltraLogger()
 
+	// The resources the options handed to the logger are closed if it isn't created.
+	fail := func(err error) (Logger, error) {
+		_ = l.closeOwned()
+		return nil, err
+	}
+
 	for _, opt := range opts {
 		if err := opt(l); err != nil {
-			return nil, err
+			return fail(err)
 		}
 	}
 
-	if len(l.destinations) == 0 {
+	if err := l.validateOptions(); err != nil {
+		return fail(err)
+	}
+	if err := l.destinationSet.validateRoutes(); err != nil {
+		return fail(err)
+	}
+
+	if len(l.destinations) == 0 && len(l.groups) == 0 && len(l.namedGroups) == 0 {
 		defaultFormatter, _ := NewFormatter(OutputFormatText, defaultFields)
 		l.destinations = map[io.Writer]LogLineFormatter{os.Stdout: defaultFormatter}
 	}
 
+	if l.coalescing != nil {
+		coalesced := make(map[io.Writer]LogLineFormatter, len(l.destinations))
+		l.origins = make(map[io.Writer]io.Writer, len(l.destinations))
+		for w, f := range l.destinations {
+			if _, ok := w.(LineWriter); ok || l.destinationSet.deliveryMode(w) != DeliveryBestEffort {
+				// A CoalescingWriter would drop the arguments of the lines.
+				coalesced[w] = f
+				continue
+			}
+			coalescing := NewCoalescingWriter(w, l.coalescing)
+			coalesced[coalescing] = f
+			l.origins[coalescing] = w
+		}
+		l.destinations = coalesced
+	}
+
+	l.publishDestinations()
+
+	if l.heartbeatInterval > 0 {
+		l.heartbeat = startHeartbeat(l, l.heartbeatInterval)
+	}
+	if l.guardSettings != nil {
+		l.guard = startResourceGuard(l, l.guardSettings)
+	}
+
 	return l, nil
 }
 
@@ -80,30 +195,85 @@ func NewLogger() Logger {
 	return logger
 }
 
-// NewFileLogger returns a new Logger that writes to a file.
+// NewFileLogger returns a new Logger that writes to a file, opened in append mode unless WithFileTruncate is enabled.
+// The options configure the file, its formatter and the logger:
+//
+//	logger, err := log.NewFileLogger("logs/app.log", log.OutputFormatJSON,
+//	    log.WithFileCreateDirs(true),
+//	    log.WithFileLoggerOptions(log.WithMinLevel(log.Debug)),
+//	)
 //
 // If the filename is empty, ErrorFileNotSpecified is returned.
-// If the file does not exist, ErrorFileNotFound is returned.
-func NewFileLogger(filename string, outputFormat OutputFormat) (Logger, error) {
+// If the directory of the file does not exist, ErrorFileNotFound is returned, unless WithFileCreateDirs is enabled.
+// If WithFileTruncate and WithFileRotation are both enabled, an ErrorOptionConflict is returned.
+func NewFileLogger(filename string, outputFormat OutputFormat, opts ...FileLoggerOption) (Logger, error) {
 	if filename == "" {
 		return nil, ErrorFileNotSpecified
 	}
 
-	var err error
-	filePtr, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return nil, &ErrorFileNotFound{filename: filename}
-		}
-		return nil, err
+	settings := defaultFileLoggerSettings
+	for _, opt := range opts {
+		opt(&settings)
 	}
 
-	formatter, err := NewFormatter(outputFormat, defaultFields)
+	formatter, err := NewFormatter(outputFormat, settings.fields, settings.formatterOptions...)
 	if err != nil {
 		return nil, err
 	}
 
-	fileLogger, err := NewLoggerWithOptions(WithDestination(filePtr, formatter))
+	var options []LoggerOption
+	if settings.rotation != nil {
+		if settings.truncate {
+			return nil, &ErrorOptionConflict{
+				options: []string{"WithFileTruncate", "WithFileRotation"},
+				reason:  "rotated files are always appended to",
+			}
+		}
+		rotation := *settings.rotation
+		rotation.Path = datedFilePath(filename)
+		if rotation.FileMode == 0 {
+			rotation.FileMode = settings.mode
+		}
+		if rotation.Separator == "" {
+			rotation.Separator = settings.separator
+		}
+		options = append(options, WithDatedFileDestination(formatter, &rotation))
+	} else {
+		if settings.createDirs {
+			if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+				return nil, err
+			}
+		}
+		flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		if settings.truncate {
+			flags = os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+		}
+		filePtr, err := os.OpenFile(filename, flags, settings.mode)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, &ErrorFileNotFound{filename: filename}
+			}
+			return nil, err
+		}
+		if settings.separator != "" {
+			if _, err := filePtr.Write(separatorLine(settings.separator, time.Now())); err != nil {
+				_ = filePtr.Close()
+				return nil, err
+			}
+		}
+		// Owned first, so that the file is closed if any of the options fail.
+		options = append(options, withOwnedCloser(filePtr), WithDestination(filePtr, formatter))
+	}
+
+	if settings.guard != nil {
+		guard := *settings.guard
+		if guard.Path == "" {
+			guard.Path = guardPath(filename)
+		}
+		options = append(options, WithResourceGuard(&guard))
+	}
+
+	fileLogger, err := NewLoggerWithOptions(append(options, settings.loggerOptions...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -114,53 +284,201 @@ func NewFileLogger(filename string, outputFormat OutputFormat) (Logger, error) {
 // ultraLogger is standard implementation of the /ultra/log Logger interface.
 type ultraLogger struct {
 	minLevel          Level
-	destinations      map[io.Writer]LogLineFormatter
 	tag               string
 	silent            bool
 	fallback          bool
 	panicOnPanicLevel bool
 	async             bool
 	flushWg           sync.WaitGroup
+	queue             asyncQueue
+	coalescing        *CoalescingSettings
+	runtimeTrace      bool
+	partialDelivery   bool             // Whether lines that reach some destinations but not all are reported.
+	pprofLabels       *pprofLabelCache // Nil unless WithPprofLabels is enabled.
+	syncLevels        bool             // Whether lines at or above syncLevel are written synchronously.
+	syncLevel         Level
+	stats             loggerStats
+	boosts            *levelBoosts
+	recorder          *flightRecorder
+	heartbeatInterval time.Duration // Zero unless WithHeartbeat is enabled.
+	heartbeat         *heartbeat
+	guardSettings     *ResourceGuardSettings // Nil unless WithResourceGuard is enabled.
+	guard             *resourceGuard
+	sequences         *sequenceCounters // Nil unless WithSequenceNumbers is enabled.
+	ring              *RingBufferWriter // Nil unless WithRingBufferDestination is enabled.
+	entryIDs          bool
+	entryHooks        []EntryHook
+	analyzer          *SeverityAnalyzer // Nil unless WithSeverityAnalyzer is enabled.
+	options           optionRecord      // What the options did, to detect conflicts.
+	started           time.Time         // When the logger was created, for uptime fields.
+
+	// destinationSet is built by the options, and read through loadDestinations once the logger is created.
+	destinationSet
+	published      atomic.Pointer[destinationSet]
+	destinationsMu sync.Mutex // Serializes the writers of published.
+
+	closers              []io.Closer // Resources owned by the logger, closed by Close.
+	internalErrorHandler func(error)
+	internalErrors       atomic.Pointer[chan error]
+	internalErrorsOnce   sync.Once
 }
 
 func newUltraLogger() *ultraLogger {
 	return &ultraLogger{
 		minLevel:          Info,
-		destinations:      map[io.Writer]LogLineFormatter{},
+		destinationSet:    destinationSet{destinations: map[io.Writer]LogLineFormatter{}},
 		silent:            false,
 		fallback:          true,
 		panicOnPanicLevel: false,
 		async:             true,
 		flushWg:           sync.WaitGroup{},
+		boosts:            newLevelBoosts(),
+		started:           time.Now(),
 	}
 }
 
 // Log logs a message with the given level and message.
 func (l *ultraLogger) Log(level Level, data ...any) {
-	if l.silent || level < l.minLevel {
+	l.log(level, data, true)
+}
+
+// log logs the line. Lines that report internal errors aren't tracked, so that a line that only reaches some of its
+// destinations can't trigger an endless chain of partial delivery reports.
+func (l *ultraLogger) log(level Level, data []any, tracked bool) {
+	l.logArgs(LogLineArgs{Level: level, Tag: l.tag}, data, tracked)
+}
+
+// logArgs logs the line with the args, unless the logger is silenced or the level is disabled.
+func (l *ultraLogger) logArgs(args LogLineArgs, data []any, tracked bool) {
+	if l.silent {
 		return
 	}
 
-	args := LogLineArgs{
-		Level: level,
-		Tag:   l.tag,
+	level := args.Level
+	if l.guard.drops(level) {
+		l.stats.dropped.Add(1)
+		return
+	}
+	if !l.enabled(level) {
+		if l.recorder != nil {
+			l.recorder.capture(args, data)
+		}
+		return
 	}
 
-	for w, f := range l.destinations {
-		if f == nil {
-			continue
+	if l.recorder != nil && level >= l.recorder.trigger {
+		for _, line := range l.recorder.drain() {
+			l.dispatch(line.args, line.data, tracked)
+		}
+	}
+
+	l.dispatch(args, data, tracked)
+}
+
+// dispatch formats and writes the line to every destination.
+func (l *ultraLogger) dispatch(args LogLineArgs, data []any, tracked bool) {
+	ctx := context.Background()
+	if l.runtimeTrace && trace.IsEnabled() {
+		var task *trace.Task
+		ctx, task = trace.NewTask(ctx, "ultra/log.Log")
+		defer task.End()
+	}
+
+	set := l.loadDestinations()
+	args.loggerStarted = l.started
+	if l.sequences != nil {
+		args.Sequence = l.sequences.next()
+	}
+	if l.entryIDs {
+		args.EntryID = newEntryID(args.Time)
+	}
+	for _, hook := range l.entryHooks {
+		hook(args, data)
+	}
+	if l.analyzer != nil {
+		if anomaly := l.analyzer.observe(args); anomaly != nil {
+			// Reported once the line that raised it is dispatched.
+			defer l.reportAnomaly(anomaly)
 		}
+	}
 
-		if l.async {
-			l.flushWg.Add(1)
-			go func() {
-				defer l.flushWg.Done()
-				l.writeLogLineAsync(w, f, args, loglineTimeout, data)
-			}()
+	// The destinations written one by one, and tracked for partial delivery if enabled; all-or-nothing groups report
+	// their own.
+	targets := make([]Destination, 0, len(set.destinations))
+	for w, f := range set.destinations {
+		if f != nil {
+			targets = append(targets, Destination{Writer: w, Formatter: f})
+		}
+	}
+	allOrNothing := set.groups
+	for _, group := range set.routedGroups(args, data) {
+		if group.allOrNothing {
+			allOrNothing = append(slices.Clip(allOrNothing), group)
 			continue
 		}
+		for _, destination := range group.destinations {
+			if destination.Formatter != nil {
+				targets = append(targets, destination)
+			}
+		}
+	}
+
+	if len(targets)+len(allOrNothing) > 1 && l.sequences == nil {
+		// Destinations sharing a formatter format the line once.
+		args.line = newLineCache()
+	}
+	if tracked && l.partialDelivery && len(targets) > 1 {
+		args.delivery = newDeliveryTracker(l, len(targets))
+	}
+
+	for _, target := range targets {
+		destinationArgs := l.stampDestination(args, target.Writer)
+		l.dispatchTo(ctx, target.Writer, target.Formatter, set.deliveryMode(target.Writer), destinationArgs, data)
+	}
+
+	for _, group := range allOrNothing {
+		group.deliverAll(l, args, data)
+	}
+}
+
+// dispatchTo formats and writes the line to a single destination, in the background if the logger is async.
+func (l *ultraLogger) dispatchTo(
+	ctx context.Context, w io.Writer, f LogLineFormatter, mode DeliveryMode, args LogLineArgs, data []any,
+) {
+	synchronous := l.syncLevels && args.Level >= l.syncLevel
+	if l.async && !synchronous && mode == DeliveryBestEffort {
+		lane := l.queue.acquire(args.Level)
+		if lane == nil {
+			args.delivery.done(w, &ErrorDestinationWrite{writer: w, err: ErrorAsyncQueueFull})
+			l.stats.dropped.Add(1)
+			return
+		}
+		l.flushWg.Add(1)
+		go func() {
+			defer l.flushWg.Done()
+			defer l.queue.release(lane)
+			if l.pprofLabels == nil {
+				l.writeLogLineAsync(ctx, w, f, args, loglineTimeout, data)
+				return
+			}
+			l.pprofLabels.doLabelled(ctx, w, f, func(ctx context.Context) {
+				l.writeLogLineAsync(ctx, w, f, args, loglineTimeout, data)
+			})
+		}()
+		return
+	}
+
+	if l.pprofLabels == nil {
+		l.writeLogLine(ctx, w, f, args, data)
+	} else {
+		l.pprofLabels.doLabelled(ctx, w, f, func(ctx context.Context) {
+			l.writeLogLine(ctx, w, f, args, data)
+		})
+	}
 
-		l.writeLogLine(w, f, args, data)
+	// WAL destinations already hold the line durably; flushing them would wait for its delivery.
+	if synchronous && mode == DeliveryBestEffort {
+		l.flushDestination(w)
 	}
 }
 
@@ -193,6 +511,67 @@ func (l *ultraLogger) Panic(data ...any) {
 	}
 }
 
+// LogMsg logs a single message string with the given level. Synchronous loggers write lines for formatters that
+// support it (the level+message text layout) straight into a pooled buffer, without allocating. Everything else falls
+// back to Log.
+func (l *ultraLogger) LogMsg(level Level, msg string) {
+	if l.silent || (!l.enabled(level) && l.recorder == nil) {
+		return
+	}
+	if l.guard.drops(level) {
+		l.stats.dropped.Add(1)
+		return
+	}
+
+	set := l.loadDestinations()
+	if l.async || l.runtimeTrace || l.pprofLabels != nil || l.recorder != nil || l.sequences != nil ||
+		l.entryIDs || len(l.entryHooks) > 0 || l.ring != nil || l.analyzer != nil || len(set.groups) > 0 ||
+		len(set.namedGroups) > 0 {
+		l.Log(level, msg)
+		return
+	}
+
+	args := LogLineArgs{
+		Level: level,
+		Tag:   l.tag,
+	}
+	if l.partialDelivery {
+		if active := set.activeDestinations(); active > 1 {
+			args.delivery = newDeliveryTracker(l, active)
+		}
+	}
+
+	for w, f := range set.destinations {
+		if f == nil {
+			continue
+		}
+
+		if !l.writeMessageLine(w, f, args, msg) {
+			l.writeLogLine(context.Background(), w, f, args, []any{msg})
+		}
+	}
+}
+
+// DebugMsg logs a message string with the Debug level.
+func (l *ultraLogger) DebugMsg(msg string) {
+	l.LogMsg(Debug, msg)
+}
+
+// InfoMsg logs a message string with the Info level.
+func (l *ultraLogger) InfoMsg(msg string) {
+	l.LogMsg(Info, msg)
+}
+
+// WarnMsg logs a message string with the Warn level.
+func (l *ultraLogger) WarnMsg(msg string) {
+	l.LogMsg(Warn, msg)
+}
+
+// ErrorMsg logs a message string with the Error level.
+func (l *ultraLogger) ErrorMsg(msg string) {
+	l.LogMsg(Error, msg)
+}
+
 func (l *ultraLogger) SetMinLevel(level Level) {
 	l.minLevel = level
 }
@@ -205,14 +584,73 @@ func (l *ultraLogger) Silence(enable bool) {
 	l.silent = enable
 }
 
+// destinationFlusher is implemented by destinations that buffer lines, like the CoalescingWriter.
+type destinationFlusher interface {
+	Flush() error
+}
+
 func (l *ultraLogger) Flush() {
 	l.flushWg.Wait()
+
+	set := l.loadDestinations()
+	for w := range set.destinations {
+		l.flushDestination(w)
+	}
+	for _, group := range set.namedGroups {
+		for _, destination := range group.destinations {
+			l.flushDestination(destination.Writer)
+		}
+	}
+}
+
+// flushDestination flushes the lines buffered by the destination, if it buffers any. A WALWriter is only synced to
+// disk; waiting for the delivery of its lines could block on the network for as long as its FlushTimeout.
+func (l *ultraLogger) flushDestination(w io.Writer) {
+	var err error
+	switch w := w.(type) {
+	case *WALWriter:
+		err = w.syncLocal()
+	case destinationFlusher:
+		err = w.Flush()
+	}
+	if err != nil {
+		l.reportInternalError(fmt.Errorf("failed to flush log writer. writer=%v, err=%w", w, err))
+	}
+}
+
+// Close flushes the logger and closes the resources it owns.
+func (l *ultraLogger) Close() error {
+	// Stopped first, so the last heartbeat and alert are flushed.
+	l.heartbeat.stopAndWait()
+	l.guard.stopAndWait()
+	l.Flush()
+
+	return l.closeOwned()
+}
+
+// closeOwned closes the resources owned by the logger.
+func (l *ultraLogger) closeOwned() error {
+	var errs []error
+	for _, c := range l.closers {
+		errs = append(errs, c.Close())
+	}
+	l.closers = nil
+
+	return errors.Join(errs...)
 }
 
 // handleLogWriterError handles errors that occur while writing to the output. On failure, the log will fall back to
 // writing to os.Stdout.
 func (l *ultraLogger) handleLogWriterError(writer io.Writer, msgLevel Level, err error, data ...any) {
-	if !l.fallback || writer == os.Stdout {
+	set := l.loadDestinations()
+	if _, ok := set.destinations[writer]; !ok {
+		// Destinations of a group are never disabled, and the line isn't logged again, or it would fail again.
+		l.reportInternalError(&ErrorDestinationWrite{writer: writer, err: err})
+		return
+	}
+
+	if !l.fallback || set.origin(writer) == os.Stdout {
+		l.stats.recordError(err)
 		panic(err)
 	}
 
@@ -223,76 +661,187 @@ func (l *ultraLogger) handleLogWriterError(writer io.Writer, msgLevel Level, err
 	//  an HTTP endpoint, they can do that. As such they should be responsible for their own error handling. We just
 	//  need to make the logger's behavior on writer errors clear. More thought needed here.
 
-	l.destinations[writer] = nil
-	l.Error(
-		fmt.Sprintf("error writing to original log writer, disabling formatter for writer: %v", err),
-	)
+	// Lines written concurrently may fail on the same writer; it's only reported once.
+	if l.disableDestination(writer) {
+		l.reportInternalError(&ErrorDestinationWrite{writer: writer, err: err})
+	}
 	l.Log(msgLevel, data...)
 }
 
+// handleFormatError handles errors returned by a formatter. The line is not written.
+func (l *ultraLogger) handleFormatError(f LogLineFormatter, data []any, err error) {
+	l.reportInternalError(&ErrorLineFormat{formatter: f, data: data, err: err})
+}
+
+// reportInternalError records an internal error of the logger and reports it. If the application monitors internal
+// errors, via InternalErrors or WithInternalErrorHandler, the error is reported there. Otherwise, it is logged at the
+// Error level.
+func (l *ultraLogger) reportInternalError(err error) {
+	l.stats.recordError(err)
+
+	monitored := false
+	if l.internalErrorHandler != nil {
+		l.internalErrorHandler(err)
+		monitored = true
+	}
+	if errChan := l.internalErrors.Load(); errChan != nil {
+		// Never block the logger on a slow consumer.
+		select {
+		case *errChan <- err:
+		default:
+		}
+		monitored = true
+	}
+
+	if !monitored {
+		l.log(Error, []any{err.Error()}, false)
+	}
+}
+
+// InternalErrors returns a channel that receives the logger's internal errors, e.g. formatting and write failures.
+// Once InternalErrors has been called, internal errors are no longer logged by the logger itself. The channel is
+// buffered; errors are dropped if the buffer is full.
+func (l *ultraLogger) InternalErrors() <-chan error {
+	l.internalErrorsOnce.Do(func() {
+		errChan := make(chan error, internalErrorsBufferSize)
+		l.internalErrors.Store(&errChan)
+	})
+	return *l.internalErrors.Load()
+}
+
 func (l *ultraLogger) writeLogLine(
+	ctx context.Context,
 	w io.Writer,
 	f LogLineFormatter,
 	args LogLineArgs,
 	data []any,
 ) {
-	formatResult := f.FormatLogLine(args, data)
+	ring, toRing := w.(*RingBufferWriter)
+	var formatResult FormatResult
+	var fields map[string]any
+	if toRing {
+		formatResult, fields = formatRingLine(ctx, l.runtimeTrace, f, args, data)
+	} else {
+		formatResult = formatLogLine(ctx, l.runtimeTrace, f, args, data)
+	}
 	if formatResult.err != nil {
-		l.Error(fmt.Sprintf("failed to format log line. formatter=%v, data=%v, err=%v", f, data, formatResult.err))
+		args.delivery.done(w, &ErrorLineFormat{formatter: f, data: data, err: formatResult.err})
+		l.handleFormatError(f, data, formatResult.err)
+		return
+	}
+	if len(formatResult.bytes) == 0 {
+		// Dropped by the formatter, e.g. a repeat compressed by CompressRepeatsMiddleware.
+		args.delivery.done(w, nil)
 		return
 	}
 
-	writeResult := write(w, formatResult.bytes)
-	if writeResult != nil {
-		l.handleLogWriterError(w, args.Level, writeResult, data...)
+	if toRing {
+		// Kept along with its fields, so that Queries can match them.
+		ring.writeEntry(args, fields, formatResult.bytes)
+		l.completeWrite(w, args, nil, data)
+		return
+	}
+
+	if l.writeCoalesced(w, append(formatResult.bytes, '\n'), args, data) {
+		return
+	}
+
+	writeResult := traceWrite(ctx, l.runtimeTrace, w, args, formatResult.bytes)
+	l.completeWrite(w, args, writeResult, data)
+}
+
+// writeCoalesced buffers the line in a CoalescingWriter destination. The line only counts as delivered once the writer
+// flushes it. It returns false if w isn't a CoalescingWriter.
+func (l *ultraLogger) writeCoalesced(w io.Writer, line []byte, args LogLineArgs, data []any) bool {
+	coalescing, ok := w.(*CoalescingWriter)
+	if !ok {
+		return false
+	}
+
+	coalescing.writeDeferred(line, func(err error) {
+		l.completeWrite(w, args, err, data)
+	})
+	return true
+}
+
+// completeWrite records the outcome of writing the line to w.
+func (l *ultraLogger) completeWrite(w io.Writer, args LogLineArgs, err error, data []any) {
+	if err != nil {
+		args.delivery.done(w, &ErrorDestinationWrite{writer: w, err: err})
+		l.handleLogWriterError(w, args.Level, err, data...)
+		return
 	}
+	args.delivery.done(w, nil)
+	l.stats.lines.Add(1)
 }
 
 func (l *ultraLogger) writeLogLineAsync(
+	parent context.Context,
 	w io.Writer,
 	f LogLineFormatter,
 	args LogLineArgs,
 	timeout time.Duration,
 	data []any,
 ) {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	if _, ok := w.(*RingBufferWriter); ok {
+		// Kept in memory along with its fields, which only writeLogLine captures.
+		l.writeLogLine(parent, w, f, args, data)
+		return
+	}
+
+	if l.runtimeTrace && trace.IsEnabled() {
+		var task *trace.Task
+		parent, task = trace.NewTask(parent, "ultra/log.writeAsync")
+		defer task.End()
+	}
+
+	ctx, cancel := context.WithTimeout(parent, timeout)
 	defer cancel()
 
 	fmtChan := make(chan FormatResult, 1)
-	go formatLogLineAsync(ctx, fmtChan, args, f, data)
+	go formatLogLineAsync(ctx, l.runtimeTrace, fmtChan, args, f, data)
 
 	var logBytes []byte
 	select {
 	case result := <-fmtChan:
 		if result.err != nil {
-			l.Error(fmt.Sprintf("failed to format log line. formatter=%v, data=%v, err=%v", f, data, result.err))
+			args.delivery.done(w, &ErrorLineFormat{formatter: f, data: data, err: result.err})
+			l.handleFormatError(f, data, result.err)
 			return
 		}
 
 		if len(result.bytes) == 0 {
+			args.delivery.done(w, nil)
 			return
 		}
 
 		logBytes = result.bytes
 	case <-ctx.Done():
+		args.delivery.done(w, &ErrorDestinationWrite{writer: w, err: ctx.Err()})
+		l.stats.dropped.Add(1)
+		return
+	}
+
+	if l.writeCoalesced(w, append(logBytes, '\n'), args, data) {
 		return
 	}
 
 	writeChan := make(chan error, 1)
-	go writeLogLineAsync(ctx, writeChan, w, logBytes)
+	go writeLogLineAsync(ctx, l.runtimeTrace, writeChan, w, args, logBytes)
 
 	select {
 	case err := <-writeChan:
-		if err != nil {
-			l.handleLogWriterError(w, args.Level, err, data)
-		}
+		l.completeWrite(w, args, err, data)
 	case <-ctx.Done():
+		args.delivery.done(w, &ErrorDestinationWrite{writer: w, err: ctx.Err()})
+		l.stats.dropped.Add(1)
 		return
 	}
 }
 
 func formatLogLineAsync(
 	ctx context.Context,
+	traced bool,
 	resultChan chan FormatResult,
 	args LogLineArgs,
 	formatter LogLineFormatter,
@@ -303,14 +852,16 @@ func formatLogLineAsync(
 	select {
 	case <-ctx.Done():
 		return
-	case resultChan <- formatter.FormatLogLine(args, data):
+	case resultChan <- formatLogLine(ctx, traced, formatter, args, data):
 	}
 }
 
 func writeLogLineAsync(
 	ctx context.Context,
+	traced bool,
 	resultChan chan error,
 	w io.Writer,
+	args LogLineArgs,
 	b []byte,
 ) {
 	defer close(resultChan)
@@ -318,11 +869,99 @@ func writeLogLineAsync(
 	select {
 	case <-ctx.Done():
 		return
-	case resultChan <- write(w, b):
+	case resultChan <- traceWrite(ctx, traced, w, args, b):
+	}
+}
+
+// formatLogLine formats the line, wrapped in a runtime/trace region if tracing is enabled for the logger.
+func formatLogLine(
+	ctx context.Context,
+	traced bool,
+	formatter LogLineFormatter,
+	args LogLineArgs,
+	data []any,
+) FormatResult {
+	if !traced {
+		return formatter.FormatLogLine(args, data)
+	}
+
+	defer trace.StartRegion(ctx, "ultra/log.format").End()
+	return formatter.FormatLogLine(args, data)
+}
+
+// traceWrite writes the line, wrapped in a runtime/trace region if tracing is enabled for the logger.
+func traceWrite(ctx context.Context, traced bool, w io.Writer, args LogLineArgs, b []byte) error {
+	if !traced {
+		return write(w, args, b)
+	}
+
+	defer trace.StartRegion(ctx, "ultra/log.write").End()
+	return write(w, args, b)
+}
+
+// messageLineFormatter is implemented by formatters that can format a single message without boxing it into an any.
+type messageLineFormatter interface {
+	// appendMessageLine appends the formatted line to dst. It returns false if the formatter can't take the fast path
+	// for this line, in which case the caller must use FormatLogLine instead.
+	appendMessageLine(dst []byte, args LogLineArgs, msg string) ([]byte, bool)
+}
+
+var lineBufferPool = sync.Pool{
+	New: func() any {
+		b := make([]byte, 0, 256)
+		return &b
+	},
+}
+
+// writeMessageLine formats and writes the message using a pooled buffer. It returns false if the formatter doesn't
+// support the fast path; nothing has been written in that case.
+func (l *ultraLogger) writeMessageLine(w io.Writer, f LogLineFormatter, args LogLineArgs, msg string) bool {
+	mf, ok := f.(messageLineFormatter)
+	if !ok {
+		return false
+	}
+
+	buf := lineBufferPool.Get().(*[]byte)
+	defer lineBufferPool.Put(buf)
+
+	line, ok := mf.appendMessageLine((*buf)[:0], args, msg)
+	if !ok {
+		return false
+	}
+	line = append(line, '\n')
+	*buf = line
+
+	// The message is only boxed when it's needed, to keep the fast path free of allocations.
+	if _, ok := w.(*CoalescingWriter); ok {
+		l.writeCoalesced(w, line, args, []any{msg})
+		return true
 	}
+
+	if _, err := writeTo(w, args, line); err != nil {
+		l.completeWrite(w, args, err, []any{msg})
+		return true
+	}
+	l.completeWrite(w, args, nil, nil)
+	return true
 }
 
-func write(w io.Writer, b []byte) error {
-	_, err := w.Write(append(b, '\n'))
+func write(w io.Writer, args LogLineArgs, b []byte) error {
+	_, err := writeTo(w, args, append(b, '\n'))
 	return err
 }
+
+// LineWriter is implemented by destinations that need the arguments of the lines written to them, e.g. to pick a
+// file by the tag of the line, like the TagFileWriter. The logger writes lines to them with WriteLine instead of Write.
+type LineWriter interface {
+	io.Writer
+	// WriteLine writes the formatted line, including its trailing newline.
+	WriteLine(args LogLineArgs, line []byte) (int, error)
+}
+
+// writeTo writes the line to w, along with its arguments if w is a LineWriter.
+func writeTo(w io.Writer, args LogLineArgs, line []byte) (int, error) {
+	if lw, ok := w.(LineWriter); ok {
+		return lw.WriteLine(args, line)
+	}
+	return w.Write(line)
+}
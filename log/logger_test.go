@@ -1,6 +1,7 @@
 package log
 
 import (
+    "bytes"
     "errors"
     "fmt"
     "io"
@@ -150,3 +151,181 @@ func BenchmarkLogger_Log_TenFields(b *testing.B) {
         }
     })
 }
+
+func BenchmarkLogger_InfoMsg(b *testing.B) {
+    formatter, _ := NewFormatter(OutputFormatText, []Field{NewDefaultLevelField(), NewMessageField()})
+    logger, _ := NewLoggerWithOptions(WithDestination(io.Discard, formatter), WithMinLevel(Info), WithAsync(false))
+
+    b.ReportAllocs()
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        logger.InfoMsg("test")
+    }
+}
+
+func TestLogger_LogMsg_allocs(t *testing.T) {
+    plain, _ := NewFormatter(OutputFormatText, []Field{NewDefaultLevelField(), NewMessageField()})
+    colorized, _ := NewFormatter(
+        OutputFormatText,
+        []Field{NewDefaultLevelField(), NewMessageField()},
+        WithDefaultColorization(),
+        WithColorPolicy(ColorPolicyAlways),
+    )
+
+    tests := []struct {
+        name      string
+        formatter LogLineFormatter
+    }{
+        {"Text", plain},
+        {"Colorized text", colorized},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            logger, _ := NewLoggerWithOptions(WithDestination(io.Discard, tt.formatter), WithAsync(false))
+
+            msg := getMessage(0)
+            allocs := testing.AllocsPerRun(100, func() {
+                logger.InfoMsg(msg)
+            })
+
+            if allocs != 0 {
+                t.Errorf("InfoMsg() allocs = %v, want 0", allocs)
+            }
+        })
+    }
+
+    t.Run("Multiple destinations", func(t *testing.T) {
+        logger, _ := NewLoggerWithOptions(
+            WithDestination(io.Discard, plain),
+            WithDestination(io.MultiWriter(io.Discard), plain),
+            WithAsync(false),
+        )
+
+        msg := getMessage(0)
+        allocs := testing.AllocsPerRun(100, func() {
+            logger.InfoMsg(msg)
+        })
+
+        if allocs != 0 {
+            t.Errorf("InfoMsg() allocs = %v, want 0", allocs)
+        }
+    })
+}
+
+func TestLogger_LogMsg(t *testing.T) {
+    plain, _ := NewFormatter(OutputFormatText, []Field{NewDefaultLevelField(), NewMessageField()})
+    colorized, _ := NewFormatter(
+        OutputFormatText,
+        []Field{NewDefaultLevelField(), NewMessageField()},
+        WithDefaultColorization(),
+        WithColorPolicy(ColorPolicyAlways),
+    )
+    json, _ := NewFormatter(OutputFormatJSON, []Field{NewDefaultLevelField(), NewMessageField()})
+
+    tests := []struct {
+        name      string
+        formatter LogLineFormatter
+        async     bool
+    }{
+        {"Text", plain, false},
+        {"Colorized text", colorized, false},
+        {"JSON falls back to Log", json, false},
+        {"Async falls back to Log", plain, true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            msgBuf := &bytes.Buffer{}
+            logBuf := &bytes.Buffer{}
+
+            msgLogger, _ := NewLoggerWithOptions(WithDestination(msgBuf, tt.formatter), WithAsync(tt.async))
+            logLogger, _ := NewLoggerWithOptions(WithDestination(logBuf, tt.formatter), WithAsync(tt.async))
+
+            msgLogger.WarnMsg("message")
+            msgLogger.DebugMsg("hidden")
+            msgLogger.Flush()
+            logLogger.Warn("message")
+            logLogger.Debug("hidden")
+            logLogger.Flush()
+
+            if msgBuf.String() != logBuf.String() {
+                t.Errorf("WarnMsg() wrote %q, Warn() wrote %q", msgBuf.String(), logBuf.String())
+            }
+        })
+    }
+}
+
+func TestLogger_InternalErrors(t *testing.T) {
+    formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+    logger, _ := NewLoggerWithOptions(
+        WithDestination(failingWriter{}, formatter),
+        WithAsync(false),
+    )
+
+    errs := logger.InternalErrors()
+    logger.Info("hello")
+
+    select {
+    case err := <-errs:
+        var writeErr *ErrorDestinationWrite
+        if !errors.As(err, &writeErr) {
+            t.Fatalf("InternalErrors() received %T, want *ErrorDestinationWrite", err)
+        }
+        if _, ok := writeErr.Writer().(failingWriter); !ok {
+            t.Errorf("ErrorDestinationWrite.Writer() = %T, want failingWriter", writeErr.Writer())
+        }
+    default:
+        t.Fatal("InternalErrors() received nothing")
+    }
+}
+
+func TestWithInternalErrorHandler(t *testing.T) {
+    var reported []error
+    logger, err := NewLoggerWithOptions(
+        WithDestination(io.Discard, &erroringFormatter{}),
+        WithInternalErrorHandler(func(err error) {
+            reported = append(reported, err)
+        }),
+        WithAsync(false),
+    )
+    if err != nil {
+        t.Fatalf("NewLoggerWithOptions() error = %v", err)
+    }
+
+    logger.Info("hello")
+
+    if len(reported) != 1 {
+        t.Fatalf("handler received %d errors, want 1", len(reported))
+    }
+
+    var formatErr *ErrorLineFormat
+    if !errors.As(reported[0], &formatErr) {
+        t.Errorf("handler received %T, want *ErrorLineFormat", reported[0])
+    }
+}
+
+type erroringFormatter struct{}
+
+func (f *erroringFormatter) FormatLogLine(LogLineArgs, []any) FormatResult {
+    return FormatResult{nil, errors.New("formatting failed")}
+}
+
+type closeCounter struct {
+    closed int
+}
+
+func (c *closeCounter) Close() error {
+    c.closed++
+    return nil
+}
+
+func TestNewLoggerWithOptions_closesOwnedOnError(t *testing.T) {
+    owned := &closeCounter{}
+    failing := func(l *ultraLogger) error { return errors.New("bad option") }
+
+    if _, err := NewLoggerWithOptions(withOwnedCloser(owned), failing); err == nil {
+        t.Fatal("NewLoggerWithOptions() error = nil, want an error")
+    }
+    if owned.closed != 1 {
+        t.Errorf("closed %d times, want 1", owned.closed)
+    }
+}
@@ -0,0 +1,96 @@
+// Package logtest helps testing code that logs with ultra/log.
+package logtest
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fmdunlap/ultra/log"
+)
+
+// Capture holds the lines logged by an intercepted logger. It is safe for concurrent use.
+type Capture struct {
+	logger log.Logger
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Write implements io.Writer.
+func (c *Capture) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.buf.Write(p)
+}
+
+// String returns everything captured so far. Pending lines of async loggers are flushed first.
+func (c *Capture) String() string {
+	c.logger.Flush()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.buf.String()
+}
+
+// Lines returns the captured lines, without their trailing newlines. Pending lines of async loggers are flushed
+// first.
+func (c *Capture) Lines() []string {
+	s := strings.TrimSuffix(c.String(), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// Contains reports whether any captured line contains substr.
+func (c *Capture) Contains(substr string) bool {
+	return strings.Contains(c.String(), substr)
+}
+
+// Reset discards everything captured so far.
+func (c *Capture) Reset() {
+	c.logger.Flush()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.buf.Reset()
+}
+
+// Intercept reroutes the destinations of a live logger, including its destination groups, to a Capture for the
+// duration of the test, and restores them in t.Cleanup. This allows asserting against loggers constructed by
+// production code. The capture is written synchronously, so lines are captured in the order they were logged.
+//
+// Lines are formatted with the level+message text layout ("<INFO> message"); use InterceptWithFormatter to capture
+// other fields. The test fails immediately if the logger's destinations can't be replaced.
+func Intercept(t testing.TB, logger log.Logger) *Capture {
+	t.Helper()
+
+	formatter, err := log.NewFormatter(log.OutputFormatText, []log.Field{log.NewDefaultLevelField(), log.NewMessageField()})
+	if err != nil {
+		t.Fatalf("logtest: failed to create formatter: %v", err)
+	}
+
+	return InterceptWithFormatter(t, logger, formatter)
+}
+
+// InterceptWithFormatter is like Intercept, but formats the captured lines with formatter.
+func InterceptWithFormatter(t testing.TB, logger log.Logger, formatter log.LogLineFormatter) *Capture {
+	t.Helper()
+
+	swapper, ok := logger.(log.DestinationSwapper)
+	if !ok {
+		t.Fatalf("logtest: logger %T doesn't support replacing its destinations", logger)
+	}
+
+	capture := &Capture{logger: logger}
+	restore := swapper.SwapDestinations(map[io.Writer]log.LogLineFormatter{capture: formatter})
+	t.Cleanup(restore)
+
+	return capture
+}
@@ -0,0 +1,60 @@
+package logtest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fmdunlap/ultra/log"
+)
+
+func TestIntercept(t *testing.T) {
+	production := &bytes.Buffer{}
+	formatter, _ := log.NewFormatter(log.OutputFormatJSON, []log.Field{log.NewMessageField()})
+	logger, err := log.NewLoggerWithOptions(log.WithDestination(production, formatter))
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	t.Run("intercepted", func(t *testing.T) {
+		capture := Intercept(t, logger)
+
+		logger.Info("hello")
+		logger.Warn("careful")
+
+		lines := capture.Lines()
+		if len(lines) != 2 || lines[0] != "<INFO> hello" || lines[1] != "<WARN> careful" {
+			t.Errorf("Lines() = %q", lines)
+		}
+		if !capture.Contains("careful") {
+			t.Errorf("Contains() = false, want true")
+		}
+
+		capture.Reset()
+		if lines := capture.Lines(); lines != nil {
+			t.Errorf("Lines() after Reset() = %q, want none", lines)
+		}
+	})
+
+	if production.Len() != 0 {
+		t.Errorf("production destination received %q while intercepted", production.String())
+	}
+
+	// The destinations are restored once the subtest finishes.
+	logger.Info("restored")
+	logger.Flush()
+	if got := production.String(); got != "{\"message\":\"restored\"}\n" {
+		t.Errorf("production destination received %q after the test, want the restored line", got)
+	}
+}
+
+func TestInterceptWithFormatter(t *testing.T) {
+	logger := log.NewLogger()
+	formatter, _ := log.NewFormatter(log.OutputFormatJSON, []log.Field{log.NewMessageField()})
+
+	capture := InterceptWithFormatter(t, logger, formatter)
+	logger.Error("failed")
+
+	if got := capture.String(); got != "{\"message\":\"failed\"}\n" {
+		t.Errorf("String() = %q", got)
+	}
+}
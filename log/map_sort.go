@@ -0,0 +1,152 @@
+package log
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// WithSortedMapKeys determines whether the map fields of the formatter are rendered with their keys in sorted order,
+// so that the same map always produces the same line. Keys of the same numeric or string type are sorted by value
+// (e.g. 2 before 10); any other keys are sorted by their text form.
+//
+// Sorting is enabled by default for text formatters, and disabled by default for JSON formatters, where encoding/json
+// already writes keys in lexical order. Enabling it for JSON orders keys by value instead, and keys that format to the
+// same string resolve to the first value in sorted order rather than an arbitrary one.
+func WithSortedMapKeys(enabled bool) FormatterOption {
+	return func(f LogLineFormatter) LogLineFormatter {
+		if tf, ok := unwrapFormatter[*textFormatter](f); ok {
+			tf.SortMapKeys = enabled
+		}
+		if jf, ok := unwrapFormatter[*jsonFormatter](f); ok {
+			jf.SortMapKeys = enabled
+		}
+		return f
+	}
+}
+
+// orderedMap is a map field value with its entries in a fixed order. It is rendered like a map by both formatters,
+// according to the MapFieldSettings of the field.
+type orderedMap struct {
+	keys     []any
+	values   []any
+	more     int // Number of entries omitted because of the MaxElements setting.
+	settings *MapFieldSettings
+}
+
+// newOrderedMap orders the formatted entries of a map, in sorted order if sorted is set. If stringKeys is set, as for
+// JSON, the keys are converted to strings once sorted, and only the first of any keys that format to the same string
+// is kept. Entries beyond the MaxElements setting are omitted.
+func newOrderedMap(entries map[any]any, sorted, stringKeys bool, settings *MapFieldSettings) *orderedMap {
+	keys := make([]any, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	if sorted {
+		slices.SortFunc(keys, compareMapKeys)
+	}
+
+	m := &orderedMap{keys: make([]any, 0, len(keys)), values: make([]any, 0, len(keys)), settings: settings}
+	seen := map[string]bool{}
+	for _, key := range keys {
+		value := entries[key]
+		if stringKeys {
+			s := fmt.Sprintf("%v", key)
+			if seen[s] {
+				continue
+			}
+			seen[s] = true
+			key = s
+		}
+		m.keys = append(m.keys, key)
+		m.values = append(m.values, value)
+	}
+
+	if shown := (NestingLimits{MaxElements: settings.MaxElements}).shown(len(m.keys)); shown < len(m.keys) {
+		m.more = len(m.keys) - shown
+		m.keys, m.values = m.keys[:shown], m.values[:shown]
+	}
+	return m
+}
+
+// compareMapKeys orders keys of the same numeric or string kind by value, and any other keys by their text form, then
+// their type.
+func compareMapKeys(a, b any) int {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.IsValid() && vb.IsValid() && va.Kind() == vb.Kind() {
+		switch va.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return cmp.Compare(va.Int(), vb.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return cmp.Compare(va.Uint(), vb.Uint())
+		case reflect.Float32, reflect.Float64:
+			return cmp.Compare(va.Float(), vb.Float())
+		case reflect.String:
+			return strings.Compare(va.String(), vb.String())
+		}
+	}
+	if c := strings.Compare(fmt.Sprintf("%v", a), fmt.Sprintf("%v", b)); c != 0 {
+		return c
+	}
+	return strings.Compare(fmt.Sprintf("%T", a), fmt.Sprintf("%T", b))
+}
+
+// text renders the map with the separators and bracket of its settings, by default like %v renders a map, e.g.
+// "map[a:1 b:2]". It is cut short according to the limits.
+func (m *orderedMap) text(limits NestingLimits) string {
+	if limits.tooDeep(0) {
+		return depthMarker
+	}
+
+	var b []byte
+	shown := limits.shown(len(m.keys))
+	for i := range shown {
+		if i > 0 {
+			b = append(b, m.settings.EntrySeparator...)
+		}
+		b = appendTruncatedText(b, reflect.ValueOf(m.keys[i]), limits, refPath{}, 1, 1)
+		b = append(b, m.settings.KeyValueSeparator...)
+		b = appendTruncatedText(b, reflect.ValueOf(m.values[i]), limits, refPath{}, 1, 1)
+	}
+	if more := len(m.keys) - shown + m.more; more > 0 {
+		if shown > 0 {
+			b = append(b, m.settings.EntrySeparator...)
+		}
+		b = append(b, moreMarker(more)...)
+	}
+	return m.settings.Bracket.Wrap(string(b))
+}
+
+// MarshalJSON writes the map as a JSON object, with the keys in order.
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, err := json.Marshal(fmt.Sprintf("%v", key))
+		if err != nil {
+			return nil, err
+		}
+		v, err := marshalJSON(m.values[i])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	if m.more > 0 {
+		if len(m.keys) > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%q:%q", moreKey, fmt.Sprintf("(+%d more)", m.more))
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
@@ -0,0 +1,82 @@
+package log
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWithSortedMapKeys(t *testing.T) {
+	identity := func(args LogLineArgs, data any) (any, error) { return data, nil }
+	mapField, _ := NewMapField[any, any]("m", identity, identity)
+
+	data := map[any]any{10: "ten", 2: "two", "b": 1, "a": math.NaN(), 1: 1.5}
+
+	tests := []struct {
+		name   string
+		format OutputFormat
+		opts   []FormatterOption
+		want   string
+	}{
+		{
+			name:   "text sorts by default",
+			format: OutputFormatText,
+			want:   "m=map[1:1.5 2:two 10:ten a:NaN b:1]",
+		},
+		{
+			name:   "text with limits",
+			format: OutputFormatText,
+			opts:   []FormatterOption{WithNestingLimits(NestingLimits{MaxElements: 2})},
+			want:   "m=map[1:1.5 2:two ...(+3 more)]",
+		},
+		{
+			name:   "json opt-in",
+			format: OutputFormatJSON,
+			opts:   []FormatterOption{WithSortedMapKeys(true)},
+			want:   `{"m":{"1":1.5,"2":"two","10":"ten","a":"NaN","b":1}}`,
+		},
+		{
+			name:   "json default",
+			format: OutputFormatJSON,
+			want:   `{"m":{"1":1.5,"10":"ten","2":"two","a":"NaN","b":1}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter, err := NewFormatter(tt.format, []Field{mapField}, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+
+			for range 10 {
+				result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{data})
+				if result.err != nil {
+					t.Fatalf("FormatLogLine() error = %v", result.err)
+				}
+				if string(result.bytes) != tt.want {
+					t.Fatalf("FormatLogLine() = %s, want %s", result.bytes, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestWithSortedMapKeys_collidingJSONKeys(t *testing.T) {
+	// 1 and "1" are distinct keys, but both are written as "1".
+	mixedKey := func(args LogLineArgs, data int) (any, error) {
+		if data == 1 {
+			return 1, nil
+		}
+		return "1", nil
+	}
+	identity := func(args LogLineArgs, data int) (any, error) { return data, nil }
+	mapField, _ := NewMapField[int, int]("m", mixedKey, identity)
+
+	formatter, _ := NewFormatter(OutputFormatJSON, []Field{mapField}, WithSortedMapKeys(true))
+
+	for range 10 {
+		result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{map[int]int{1: 1, 2: 2}})
+		if want := `{"m":{"1":1}}`; string(result.bytes) != want {
+			t.Fatalf("FormatLogLine() = %s, want %s", result.bytes, want)
+		}
+	}
+}
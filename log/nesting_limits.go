@@ -0,0 +1,49 @@
+package log
+
+import "fmt"
+
+// NestingLimits bounds how much of nested data (arrays, slices, maps, and structs) is rendered, so that pathological
+// payloads can't produce unbounded output. See WithNestingLimits.
+type NestingLimits struct {
+	// MaxDepth is the maximum number of nested arrays, maps, and objects. Values nested deeper are replaced with
+	// "(max depth)". 0 means no limit.
+	MaxDepth int
+	// MaxElements is the maximum number of elements rendered per array, map, or object. The remaining elements are
+	// summarized, e.g. as "...(+900 more)". 0 means no limit.
+	MaxElements int
+}
+
+// WithNestingLimits applies the NestingLimits to the field values of a text or JSON formatter.
+//
+// Nested data is always checked for values that contain themselves, which are rendered as "(cycle)". For JSON
+// formatters, setting limits means every line is inspected before it is marshalled, rather than only lines that fail
+// to marshal.
+func WithNestingLimits(limits NestingLimits) FormatterOption {
+	return func(f LogLineFormatter) LogLineFormatter {
+		if tf, ok := unwrapFormatter[*textFormatter](f); ok {
+			tf.NestingLimits = limits
+		}
+		if jf, ok := unwrapFormatter[*jsonFormatter](f); ok {
+			jf.NestingLimits = limits
+		}
+		return f
+	}
+}
+
+// tooDeep reports whether a container at the given nesting level must be replaced with depthMarker.
+func (l NestingLimits) tooDeep(nesting int) bool {
+	return l.MaxDepth > 0 && nesting >= l.MaxDepth
+}
+
+// shown returns how many of n elements are rendered.
+func (l NestingLimits) shown(n int) int {
+	if l.MaxElements > 0 && n > l.MaxElements {
+		return l.MaxElements
+	}
+	return n
+}
+
+// moreMarker summarizes the n elements that weren't rendered.
+func moreMarker(n int) string {
+	return fmt.Sprintf("...(+%d more)", n)
+}
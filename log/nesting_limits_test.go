@@ -0,0 +1,106 @@
+package log
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type nestingLimitsTestStruct struct {
+	A, B, C int
+	Inner   []int `json:"inner"`
+}
+
+func nestingLimitsTestData() []any {
+	ints := make([]any, 1000)
+	for i := range ints {
+		ints[i] = i
+	}
+	return []any{
+		ints,
+		map[string]any{"d": 4, "a": 1, "c": 3, "b": 2},
+		[]any{[]any{[]any{"deep"}}},
+		nestingLimitsTestStruct{A: 1, B: 2, C: 3, Inner: []int{1}},
+	}
+}
+
+func TestFormatTextValue_nestingLimits(t *testing.T) {
+	data := nestingLimitsTestData()
+
+	tests := []struct {
+		name   string
+		limits NestingLimits
+		value  any
+		want   string
+	}{
+		{"Elements", NestingLimits{MaxElements: 3}, data[0], "[0 1 2 ...(+997 more)]"},
+		{"Map elements", NestingLimits{MaxElements: 2}, data[1], "map[a:1 b:2 ...(+2 more)]"},
+		{"Struct elements", NestingLimits{MaxElements: 2}, data[3], "{1 2 ...(+2 more)}"},
+		{"Depth", NestingLimits{MaxDepth: 2}, data[2], "[[(max depth)]]"},
+		{"Struct depth", NestingLimits{MaxDepth: 1}, data[3], "{1 2 3 (max depth)}"},
+		{"Within limits", NestingLimits{MaxDepth: 3, MaxElements: 4}, data[1], "map[a:1 b:2 c:3 d:4]"},
+		{"No limits", NestingLimits{}, data[2], "[[[deep]]]"},
+		{"Zero elements", NestingLimits{MaxElements: 1}, []any{}, "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatTextValue(tt.value, tt.limits); got != tt.want {
+				t.Errorf("formatTextValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithNestingLimits(t *testing.T) {
+	sliceField, _ := NewObjectField[[]any]("slice", func(args LogLineArgs, data []any) (any, error) {
+		return data, nil
+	})
+	mapField, _ := NewObjectField[map[string]any]("map", func(args LogLineArgs, data map[string]any) (any, error) {
+		return data, nil
+	})
+	structField, _ := NewObjectField[nestingLimitsTestStruct]("struct", func(args LogLineArgs, data nestingLimitsTestStruct) (any, error) {
+		return data, nil
+	})
+	fields := []Field{sliceField, mapField, structField}
+	data := nestingLimitsTestData()
+	data = []any{data[0], data[1], data[3]}
+	limits := NestingLimits{MaxDepth: 1, MaxElements: 3}
+
+	t.Run("text", func(t *testing.T) {
+		formatter, _ := NewFormatter(OutputFormatText, fields, WithNestingLimits(limits))
+
+		result := formatter.FormatLogLine(LogLineArgs{Level: Info}, data)
+		if result.err != nil {
+			t.Fatalf("FormatLogLine() error = %v", result.err)
+		}
+		want := "slice=[0 1 2 ...(+997 more)] map=map[a:1 b:2 c:3 ...(+1 more)] struct={1 2 3 ...(+1 more)}"
+		if string(result.bytes) != want {
+			t.Errorf("FormatLogLine() = %q, want %q", result.bytes, want)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		formatter, _ := NewFormatter(OutputFormatJSON, fields, WithNestingLimits(limits))
+
+		result := formatter.FormatLogLine(LogLineArgs{Level: Info}, data)
+		if result.err != nil {
+			t.Fatalf("FormatLogLine() error = %v", result.err)
+		}
+		want := `{"map":{"...":"(+1 more)","a":1,"b":2,"c":3},"slice":[0,1,2,"...(+997 more)"],` +
+			`"struct":{"...":"(+1 more)","A":1,"B":2,"C":3}}`
+		if string(result.bytes) != want {
+			t.Errorf("FormatLogLine() = %s, want %s", result.bytes, want)
+		}
+		if !json.Valid(result.bytes) {
+			t.Errorf("FormatLogLine() = %s, want valid JSON", result.bytes)
+		}
+	})
+
+	t.Run("json depth", func(t *testing.T) {
+		formatter, _ := NewFormatter(OutputFormatJSON, []Field{sliceField}, WithNestingLimits(NestingLimits{MaxDepth: 2}))
+
+		result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{nestingLimitsTestData()[2]})
+		if want := `{"slice":[["(max depth)"]]}`; string(result.bytes) != want {
+			t.Errorf("FormatLogLine() = %s, want %s", result.bytes, want)
+		}
+	})
+}
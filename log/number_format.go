@@ -0,0 +1,153 @@
+package log
+
+import (
+	"strconv"
+	"strings"
+)
+
+// NumberNotation is the notation of numbers formatted with a NumberFormat.
+type NumberNotation int
+
+const (
+	// NotationShortest writes as many digits as needed to represent the number exactly, e.g. "1234.5". This is the
+	// default.
+	NotationShortest NumberNotation = iota
+	// NotationFixed writes exactly Precision digits after the decimal separator, e.g. "1234.50".
+	NotationFixed
+	// NotationScientific writes the number as a mantissa with Precision digits after the decimal separator and an
+	// exponent, e.g. "1.23e+03".
+	NotationScientific
+)
+
+// NumberFormat configures how numbers are written in text output, so that human-facing logs of large counters remain
+// readable. JSON output always contains the raw number.
+type NumberFormat struct {
+	// Notation is the notation of the number. Defaults to NotationShortest.
+	Notation NumberNotation
+	// Precision is the number of digits after the decimal separator, for NotationFixed and NotationScientific.
+	Precision int
+	// ThousandsSeparator separates groups of three digits in the integer part, e.g. "," for "1,234,567". Empty means
+	// no grouping. Ignored for NotationScientific.
+	ThousandsSeparator string
+	// DecimalSeparator separates the integer part from the fraction. Defaults to ".".
+	DecimalSeparator string
+}
+
+var defaultNumberFormat = NumberFormat{
+	Notation:         NotationShortest,
+	DecimalSeparator: ".",
+}
+
+func (f *NumberFormat) mergeDefault() {
+	if f.DecimalSeparator == "" {
+		f.DecimalSeparator = defaultNumberFormat.DecimalSeparator
+	}
+	if f.Precision < 0 {
+		f.Precision = 0
+	}
+}
+
+// FormatInt formats an integer.
+func (f NumberFormat) FormatInt(v int64) string {
+	switch f.Notation {
+	case NotationScientific:
+		return f.FormatFloat(float64(v))
+	case NotationFixed:
+		if f.Precision > 0 {
+			return f.localize(strconv.FormatInt(v, 10) + "." + strings.Repeat("0", f.Precision))
+		}
+	}
+	return f.localize(strconv.FormatInt(v, 10))
+}
+
+// FormatFloat formats a finite float.
+func (f NumberFormat) FormatFloat(v float64) string {
+	switch f.Notation {
+	case NotationFixed:
+		return f.localize(strconv.FormatFloat(v, 'f', f.Precision, 64))
+	case NotationScientific:
+		return strings.Replace(strconv.FormatFloat(v, 'e', f.Precision, 64), ".", f.DecimalSeparator, 1)
+	default:
+		return f.localize(strconv.FormatFloat(v, 'f', -1, 64))
+	}
+}
+
+// localize replaces the separators of a number formatted by strconv in decimal notation.
+func (f NumberFormat) localize(number string) string {
+	sign := ""
+	if strings.HasPrefix(number, "-") {
+		sign, number = "-", number[1:]
+	}
+	integer, fraction, hasFraction := strings.Cut(number, ".")
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, digit := range integer {
+		if i > 0 && f.ThousandsSeparator != "" && (len(integer)-i)%3 == 0 {
+			b.WriteString(f.ThousandsSeparator)
+		}
+		b.WriteRune(digit)
+	}
+	if hasFraction {
+		b.WriteString(f.DecimalSeparator)
+		b.WriteString(fraction)
+	}
+
+	return b.String()
+}
+
+// NewFormattedIntField returns a new Field that formats an int with the NumberFormat in text output.
+//
+// If the name is empty, an error is returned.
+//
+// OutputFormats:
+//   - OutputFormatText => int is formatted as a string with the NumberFormat, e.g. "1,234,567".
+//   - OutputFormatJSON => int is formatted as a int.
+func NewFormattedIntField(name string, format *NumberFormat) (Field, error) {
+	if format == nil {
+		format = &NumberFormat{}
+	}
+	format.mergeDefault()
+	numberFormat := *format
+
+	return NewObjectField[int](
+		name,
+		func(args LogLineArgs, data int) (any, error) {
+			if args.OutputFormat == OutputFormatText {
+				return numberFormat.FormatInt(int64(data)), nil
+			}
+			return data, nil
+		},
+	)
+}
+
+// NewFormattedFloatField returns a new Field that formats a float64 with the NumberFormat in text output.
+//
+// If the name is empty, an error is returned.
+//
+// OutputFormats:
+//   - OutputFormatText => float64 is formatted as a string with the NumberFormat, e.g. "1,234.50".
+//   - OutputFormatJSON => float64 is formatted as a float64.
+//
+// NaN and ±Inf are handled according to the formatter's NonFiniteFloatPolicy.
+func NewFormattedFloatField(name string, format *NumberFormat) (Field, error) {
+	if format == nil {
+		format = &NumberFormat{}
+	}
+	format.mergeDefault()
+	numberFormat := *format
+
+	return NewObjectField[float64](
+		name,
+		func(args LogLineArgs, data float64) (any, error) {
+			if isNonFinite(data) {
+				return nonFiniteFloatResult(args, name, data)
+			}
+
+			if args.OutputFormat == OutputFormatText {
+				return numberFormat.FormatFloat(data), nil
+			}
+			return data, nil
+		},
+	)
+}
@@ -0,0 +1,66 @@
+package log
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNumberFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		format NumberFormat
+		int    int64
+		float  float64
+		want   [2]string // FormatInt, FormatFloat
+	}{
+		{"default", NumberFormat{}, 1234567, 1234.5, [2]string{"1234567", "1234.5"}},
+		{"thousands", NumberFormat{ThousandsSeparator: ","}, 1234567, 1234567.25, [2]string{"1,234,567", "1,234,567.25"}},
+		{"negative", NumberFormat{ThousandsSeparator: ","}, -123456, -999.5, [2]string{"-123,456", "-999.5"}},
+		{"short", NumberFormat{ThousandsSeparator: ","}, 123, 0.5, [2]string{"123", "0.5"}},
+		{"fixed", NumberFormat{Notation: NotationFixed, Precision: 2}, 42, 3.14159, [2]string{"42.00", "3.14"}},
+		{"locale", NumberFormat{Notation: NotationFixed, Precision: 1, ThousandsSeparator: ".", DecimalSeparator: ","},
+			1234567, 1234.56, [2]string{"1.234.567,0", "1.234,6"}},
+		{"scientific", NumberFormat{Notation: NotationScientific, Precision: 2, ThousandsSeparator: ","},
+			1234567, 0.000123, [2]string{"1.23e+06", "1.23e-04"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.format.mergeDefault()
+			if got := tt.format.FormatInt(tt.int); got != tt.want[0] {
+				t.Errorf("FormatInt(%d) = %q, want %q", tt.int, got, tt.want[0])
+			}
+			if got := tt.format.FormatFloat(tt.float); got != tt.want[1] {
+				t.Errorf("FormatFloat(%v) = %q, want %q", tt.float, got, tt.want[1])
+			}
+		})
+	}
+}
+
+func TestNewFormattedFields(t *testing.T) {
+	countField, _ := NewFormattedIntField("count", &NumberFormat{ThousandsSeparator: ","})
+	ratioField, _ := NewFormattedFloatField("ratio", &NumberFormat{Notation: NotationFixed, Precision: 3})
+	fields := []Field{countField, ratioField}
+
+	tests := []struct {
+		format OutputFormat
+		data   []any
+		want   string
+	}{
+		{OutputFormatText, []any{1500000, 0.25}, "count=1,500,000 ratio=0.250"},
+		{OutputFormatJSON, []any{1500000, 0.25}, `{"count":1500000,"ratio":0.25}`},
+		{OutputFormatText, []any{math.Inf(1)}, "ratio=+Inf"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			formatter, _ := NewFormatter(tt.format, fields)
+
+			result := formatter.FormatLogLine(LogLineArgs{Level: Info}, tt.data)
+			if result.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", result.err)
+			}
+			if string(result.bytes) != tt.want {
+				t.Errorf("FormatLogLine() = %s, want %s", result.bytes, tt.want)
+			}
+		})
+	}
+}
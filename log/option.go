@@ -63,9 +63,11 @@ func WithDestination(destination io.Writer, formatter LogLineFormatter) LoggerOp
 }
 
 // WithDestinations sets the destinations for the logger. If the formatter is nil, the destination will be ignored.
-// If the logger already has destinations, this will overwrite them.
+// If options before it already set destinations, NewLoggerWithOptions returns an ErrorOptionConflict, since they would
+// be silently replaced.
 func WithDestinations(destinations map[io.Writer]LogLineFormatter) LoggerOption {
     return func(l *ultraLogger) error {
+        l.options.replacedDestinations += len(l.destinations)
         l.destinations = destinations
         return nil
     }
@@ -109,6 +111,7 @@ func WithDefaultColorizationEnabled(writer io.Writer) LoggerOption {
         }
 
         l.destinations[writer] = NewColorizedFormatter(l.destinations[writer], nil)
+        l.options.colorized = true
         return nil
     }
 }
@@ -127,6 +130,7 @@ func WithCustomColorization(writer io.Writer, colors map[Level]Color) LoggerOpti
         }
 
         l.destinations[writer] = NewColorizedFormatter(l.destinations[writer], colors)
+        l.options.colorized = true
         return nil
     }
 }
@@ -149,3 +153,83 @@ func WithAsync(async bool) LoggerOption {
         return nil
     }
 }
+
+// WithSynchronousLevel writes lines at or above level synchronously, even if the logger is async, so that the most
+// important lines (e.g. Error and Panic) are never lost to line timeouts or the process exiting. When Log returns, the
+// line has been written, and flushed from any CoalescingWriter. Default=disabled.
+//
+// Synchronous lines may be written before lower-level lines logged earlier that are still in flight.
+func WithSynchronousLevel(level Level) LoggerOption {
+    return func(l *ultraLogger) error {
+        l.syncLevels = true
+        l.syncLevel = level
+        return nil
+    }
+}
+
+// WithWriteCoalescing wraps every destination of the logger in a [CoalescingWriter], so that lines logged within a
+// short window are written to the destination with a single Write call. It applies to all DeliveryBestEffort
+// destinations except LineWriters, regardless of the order of the options. Flush flushes the coalesced lines.
+//
+// A coalesced line only counts as written, e.g. in the stats of Inspect, once it has been flushed. If a flush fails,
+// the destination is handled like a failed write of each of its lines.
+func WithWriteCoalescing(settings *CoalescingSettings) LoggerOption {
+    return func(l *ultraLogger) error {
+        if settings == nil {
+            settings = &CoalescingSettings{}
+        }
+        l.coalescing = settings
+        return nil
+    }
+}
+
+// WithRuntimeTrace annotates logging with runtime/trace tasks and regions. Each Log call is a task ("ultra/log.Log"),
+// and the format and write phases of each destination are regions ("ultra/log.format" and "ultra/log.write"). This
+// lets you attribute logging cost in `go tool trace` when diagnosing latency. Default=false.
+//
+// The annotations are only recorded while a trace is being collected, e.g. with trace.Start or net/http/pprof.
+func WithRuntimeTrace(enabled bool) LoggerOption {
+    return func(l *ultraLogger) error {
+        l.runtimeTrace = enabled
+        return nil
+    }
+}
+
+// WithPartialDeliveryReports reports lines that reach some of the logger's destinations but not all as an
+// ErrorPartialDelivery internal error. Tracking the outcome of every line costs an allocation per line logged to more
+// than one destination. All-or-nothing groups always report partial delivery. Default=false.
+func WithPartialDeliveryReports(enabled bool) LoggerOption {
+    return func(l *ultraLogger) error {
+        l.partialDelivery = enabled
+        return nil
+    }
+}
+
+// WithInternalErrorHandler sets a callback for the logger's internal errors, e.g. formatting and write failures. When
+// a handler is set, internal errors are no longer logged by the logger itself. The handler is called synchronously
+// from the logging goroutine, so it should return quickly and must not log to the same logger.
+func WithInternalErrorHandler(handler func(err error)) LoggerOption {
+    return func(l *ultraLogger) error {
+        l.internalErrorHandler = handler
+        return nil
+    }
+}
+
+// WithWALDestination adds a destination that is written through a write-ahead log. Lines are appended to the WAL
+// before they are delivered to the destination in the background, and are only removed from the WAL once delivered,
+// so lines buffered for a remote destination survive a process crash. See [WALWriter]. The logger's Flush only syncs
+// the WAL to disk; it doesn't wait for the lines to be delivered.
+//
+// This is shorthand for WithDeliveryDestination with DeliveryAtLeastOnce. The WAL writer is owned by the logger; it is
+// closed by the logger's Close method.
+func WithWALDestination(destination io.Writer, formatter LogLineFormatter, settings *WALSettings) LoggerOption {
+    return WithDeliveryDestination(destination, formatter, &DeliverySettings{Mode: DeliveryAtLeastOnce, WAL: settings})
+}
+
+// withOwnedCloser hands ownership of c to the logger; it is closed by the logger's Close method.
+func withOwnedCloser(c io.Closer) LoggerOption {
+    return func(l *ultraLogger) error {
+        l.closers = append(l.closers, c)
+        return nil
+    }
+}
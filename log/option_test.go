@@ -5,6 +5,8 @@ import (
     "fmt"
     "io"
     "os"
+    "runtime/trace"
+    "testing"
 )
 
 func ExampleWithMinLevel() {
@@ -246,3 +248,31 @@ func ExampleWithTag() {
     // Output:
     // [TAG] <INFO> This is an info message.
 }
+
+func TestWithRuntimeTrace(t *testing.T) {
+    formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+
+    for _, async := range []bool{false, true} {
+        t.Run(fmt.Sprintf("async=%v", async), func(t *testing.T) {
+            logger, _ := NewLoggerWithOptions(
+                WithDestination(io.Discard, formatter),
+                WithRuntimeTrace(true),
+                WithAsync(async),
+            )
+
+            traceBuf := &bytes.Buffer{}
+            if err := trace.Start(traceBuf); err != nil {
+                t.Skipf("tracing unavailable: %v", err)
+            }
+            logger.Info("traced")
+            logger.Flush()
+            trace.Stop()
+
+            for _, annotation := range []string{"ultra/log.Log", "ultra/log.format", "ultra/log.write"} {
+                if !bytes.Contains(traceBuf.Bytes(), []byte(annotation)) {
+                    t.Errorf("trace does not contain %q", annotation)
+                }
+            }
+        })
+    }
+}
@@ -0,0 +1,46 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+)
+
+// optionRecord notes what the options of a logger did, for validateOptions to detect the options that conflict.
+type optionRecord struct {
+	replacedDestinations int // Destinations set by options, then replaced by a later WithDestinations.
+	colorized            bool
+	asyncQueue           bool
+}
+
+// validateOptions returns an ErrorLoggerInitialization with an ErrorOptionConflict for every conflict between the
+// options of the logger, or nil if there's none.
+func (l *ultraLogger) validateOptions() error {
+	var conflicts []error
+	if n := l.options.replacedDestinations; n > 0 {
+		conflicts = append(conflicts, &ErrorOptionConflict{
+			options: []string{"WithDestination", "WithDestinations"},
+			reason: fmt.Sprintf(
+				"WithDestinations replaces the %d destinations set by the options before it; "+
+					"pass them all to WithDestinations, or add them with WithDestination",
+				n,
+			),
+		})
+	}
+	if l.silent && l.options.colorized {
+		conflicts = append(conflicts, &ErrorOptionConflict{
+			options: []string{"WithSilent", "colorization"},
+			reason:  "the logger is silenced, so its colorized destinations never write",
+		})
+	}
+	if !l.async && l.options.asyncQueue {
+		conflicts = append(conflicts, &ErrorOptionConflict{
+			options: []string{"WithAsync(false)", "WithAsyncQueue"},
+			reason:  "the logger is synchronous, so it has no async queue",
+		})
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+	return &ErrorLoggerInitialization{err: errors.Join(conflicts...)}
+}
@@ -0,0 +1,76 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestNewLoggerWithOptions_conflicts(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+	buf := &bytes.Buffer{}
+
+	tests := []struct {
+		name      string
+		opts      []LoggerOption
+		conflicts int
+	}{
+		{"none", []LoggerOption{WithDestination(buf, formatter), WithSilent(true)}, 0},
+		{
+			"destinations after destination",
+			[]LoggerOption{
+				WithDestination(buf, formatter),
+				WithDestinations(map[io.Writer]LogLineFormatter{&bytes.Buffer{}: formatter}),
+			},
+			1,
+		},
+		{
+			"destination after destinations",
+			[]LoggerOption{
+				WithDestinations(map[io.Writer]LogLineFormatter{&bytes.Buffer{}: formatter}),
+				WithDestination(buf, formatter),
+			},
+			0,
+		},
+		{"silenced colorization", []LoggerOption{WithDefaultColorizationEnabled(buf), WithSilent(true)}, 1},
+		{"sync with async queue", []LoggerOption{WithAsyncQueue(nil), WithAsync(false)}, 1},
+		{
+			"several",
+			[]LoggerOption{
+				WithCustomColorization(buf, nil),
+				WithDestinations(map[io.Writer]LogLineFormatter{&bytes.Buffer{}: formatter}),
+				WithSilent(true),
+			},
+			2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLoggerWithOptions(tt.opts...)
+			if tt.conflicts == 0 {
+				if err != nil {
+					t.Fatalf("NewLoggerWithOptions() error = %v", err)
+				}
+				return
+			}
+
+			var initErr *ErrorLoggerInitialization
+			if !errors.As(err, &initErr) {
+				t.Fatalf("NewLoggerWithOptions() error = %v, want an ErrorLoggerInitialization", err)
+			}
+			joined, ok := initErr.Unwrap().(interface{ Unwrap() []error })
+			if !ok {
+				t.Fatalf("NewLoggerWithOptions() error = %v, want joined conflicts", err)
+			}
+			for _, conflict := range joined.Unwrap() {
+				if _, ok := conflict.(*ErrorOptionConflict); !ok {
+					t.Errorf("NewLoggerWithOptions() error = %v, want only ErrorOptionConflicts", conflict)
+				}
+			}
+			if got := len(joined.Unwrap()); got != tt.conflicts {
+				t.Errorf("NewLoggerWithOptions() reported %d conflicts, want %d: %v", got, tt.conflicts, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+// This file holds a minimal Parquet encoder: one row group per file, one uncompressed data page (v1) per column, PLAIN
+// encoded values, and optional (nullable) flat columns. That's all the ParquetWriter needs, and it keeps the package
+// free of dependencies.
+
+// Parquet physical types.
+const (
+	parquetBoolean   = 0
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+)
+
+// Parquet converted types.
+const (
+	parquetConvertedUTF8            = 0
+	parquetConvertedTimestampMillis = 9
+)
+
+// Parquet encodings, and other enum values of the format.
+const (
+	parquetEncodingPlain     = 0
+	parquetEncodingRLE       = 3
+	parquetRepetitionOpt     = 1
+	parquetPageTypeData      = 0
+	parquetCodecUncompressed = 0
+)
+
+var parquetMagic = []byte("PAR1")
+
+// parquetColumnData is a column of a Parquet file: its schema, and its values, nil for nulls. The values must match
+// the physical type: bool, int64, float64 or []byte.
+type parquetColumnData struct {
+	name      string
+	physical  int32
+	converted int32 // -1 if none.
+	values    []any
+}
+
+// encodeParquet returns a Parquet file of the columns, which must all have numRows values.
+func encodeParquet(columns []parquetColumnData, numRows int) []byte {
+	file := bytes.NewBuffer(bytes.Clone(parquetMagic))
+
+	chunks := make([]parquetChunk, len(columns))
+	for i, column := range columns {
+		chunks[i] = writeParquetChunk(file, column)
+	}
+
+	footer := &thriftWriter{}
+	footer.i32(1, 1) // version
+	footer.listBegin(2, thriftStruct, len(columns)+1)
+	footer.structBegin()
+	footer.binary(4, []byte("schema"))
+	footer.i32(5, int32(len(columns)))
+	footer.structEnd()
+	for _, column := range columns {
+		footer.structBegin()
+		footer.i32(1, column.physical)
+		footer.i32(3, parquetRepetitionOpt)
+		footer.binary(4, []byte(column.name))
+		if column.converted >= 0 {
+			footer.i32(6, column.converted)
+		}
+		footer.structEnd()
+	}
+	footer.i64(3, int64(numRows))
+	footer.listBegin(4, thriftStruct, 1)
+	footer.structBegin()
+	footer.listBegin(1, thriftStruct, len(columns))
+	var totalSize int64
+	for i, column := range columns {
+		chunk := chunks[i]
+		totalSize += chunk.size
+
+		footer.structBegin()
+		footer.i64(2, chunk.offset)
+		footer.fieldBegin(3, thriftStruct)
+		footer.structBegin()
+		footer.i32(1, column.physical)
+		footer.listBegin(2, thriftI32, 2)
+		footer.listI32(parquetEncodingPlain)
+		footer.listI32(parquetEncodingRLE)
+		footer.listBegin(3, thriftBinary, 1)
+		footer.listBinary([]byte(column.name))
+		footer.i32(4, parquetCodecUncompressed)
+		footer.i64(5, int64(numRows))
+		footer.i64(6, chunk.size)
+		footer.i64(7, chunk.size)
+		footer.i64(9, chunk.offset)
+		footer.structEnd()
+		footer.structEnd()
+	}
+	footer.i64(2, totalSize)
+	footer.i64(3, int64(numRows))
+	footer.structEnd()
+	footer.binary(6, []byte("github.com/fmdunlap/ultra/log"))
+	footer.stop()
+
+	file.Write(footer.buf)
+	file.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(footer.buf))))
+	file.Write(parquetMagic)
+	return file.Bytes()
+}
+
+// parquetChunk locates a column chunk in a Parquet file.
+type parquetChunk struct {
+	offset int64
+	size   int64
+}
+
+// writeParquetChunk writes the column as a single data page.
+func writeParquetChunk(file *bytes.Buffer, column parquetColumnData) parquetChunk {
+	var page []byte
+
+	// Definition levels: 1 for values, 0 for nulls, RLE encoded with a bit width of 1, and prefixed with their length.
+	levels := appendParquetLevels(nil, column.values)
+	page = binary.LittleEndian.AppendUint32(page, uint32(len(levels)))
+	page = append(page, levels...)
+
+	var bits, nbits int
+	for _, value := range column.values {
+		switch value := value.(type) {
+		case nil:
+			continue
+		case bool:
+			// Bit-packed, least significant bit first.
+			if value {
+				bits |= 1 << nbits
+			}
+			if nbits++; nbits == 8 {
+				page = append(page, byte(bits))
+				bits, nbits = 0, 0
+			}
+		case int64:
+			page = binary.LittleEndian.AppendUint64(page, uint64(value))
+		case float64:
+			page = binary.LittleEndian.AppendUint64(page, math.Float64bits(value))
+		case []byte:
+			page = binary.LittleEndian.AppendUint32(page, uint32(len(value)))
+			page = append(page, value...)
+		}
+	}
+	if nbits > 0 {
+		page = append(page, byte(bits))
+	}
+
+	header := &thriftWriter{}
+	header.i32(1, parquetPageTypeData)
+	header.i32(2, int32(len(page)))
+	header.i32(3, int32(len(page)))
+	header.fieldBegin(5, thriftStruct)
+	header.structBegin()
+	header.i32(1, int32(len(column.values)))
+	header.i32(2, parquetEncodingPlain)
+	header.i32(3, parquetEncodingRLE)
+	header.i32(4, parquetEncodingRLE)
+	header.structEnd()
+	header.stop()
+
+	chunk := parquetChunk{offset: int64(file.Len())}
+	file.Write(header.buf)
+	file.Write(page)
+	chunk.size = int64(file.Len()) - chunk.offset
+	return chunk
+}
+
+// appendParquetLevels appends the definition levels of the values, as RLE runs.
+func appendParquetLevels(dst []byte, values []any) []byte {
+	for start := 0; start < len(values); {
+		defined := values[start] != nil
+		end := start + 1
+		for end < len(values) && (values[end] != nil) == defined {
+			end++
+		}
+
+		dst = binary.AppendUvarint(dst, uint64(end-start)<<1)
+		if defined {
+			dst = append(dst, 1)
+		} else {
+			dst = append(dst, 0)
+		}
+		start = end
+	}
+	return dst
+}
+
+// Thrift compact protocol types.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter writes the Thrift compact protocol, as used by the Parquet metadata. The top-level struct is implicit:
+// fields can be written right away, and the struct is ended with stop.
+type thriftWriter struct {
+	buf  []byte
+	last []int16 // The ID of the last field written, of each struct being written.
+	id   int16
+}
+
+func (w *thriftWriter) fieldBegin(id int16, typ byte) {
+	if delta := id - w.id; delta > 0 && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|typ)
+	} else {
+		w.buf = append(w.buf, typ)
+		w.buf = binary.AppendVarint(w.buf, int64(id))
+	}
+	w.id = id
+}
+
+func (w *thriftWriter) i32(id int16, v int32) {
+	w.fieldBegin(id, thriftI32)
+	w.buf = binary.AppendVarint(w.buf, int64(v))
+}
+
+func (w *thriftWriter) i64(id int16, v int64) {
+	w.fieldBegin(id, thriftI64)
+	w.buf = binary.AppendVarint(w.buf, v)
+}
+
+func (w *thriftWriter) binary(id int16, v []byte) {
+	w.fieldBegin(id, thriftBinary)
+	w.listBinary(v)
+}
+
+// listBegin writes a list field of n elements of the type. The elements are written with the list* methods, or as
+// structs.
+func (w *thriftWriter) listBegin(id int16, elemType byte, n int) {
+	w.fieldBegin(id, thriftList)
+	if n < 15 {
+		w.buf = append(w.buf, byte(n)<<4|elemType)
+	} else {
+		w.buf = append(w.buf, 0xf0|elemType)
+		w.buf = binary.AppendUvarint(w.buf, uint64(n))
+	}
+}
+
+func (w *thriftWriter) listI32(v int32) {
+	w.buf = binary.AppendVarint(w.buf, int64(v))
+}
+
+func (w *thriftWriter) listBinary(v []byte) {
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+// structBegin starts a struct, after its field header or as a list element.
+func (w *thriftWriter) structBegin() {
+	w.last = append(w.last, w.id)
+	w.id = 0
+}
+
+func (w *thriftWriter) structEnd() {
+	w.stop()
+	w.id = w.last[len(w.last)-1]
+	w.last = w.last[:len(w.last)-1]
+}
+
+func (w *thriftWriter) stop() {
+	w.buf = append(w.buf, 0)
+}
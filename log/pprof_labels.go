@@ -0,0 +1,60 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime/pprof"
+	"sync"
+)
+
+// Keys of the pprof labels set by WithPprofLabels.
+const (
+	PprofLabelDestination = "ultra_log.destination"
+	PprofLabelFormatter   = "ultra_log.formatter"
+)
+
+// WithPprofLabels runs the formatting and writing of lines with pprof labels identifying the destination
+// (PprofLabelDestination) and formatter (PprofLabelFormatter), so that CPU spent logging can be attributed in profiles,
+// e.g. with `go tool pprof -tagfocus`. Default=false.
+//
+// The goroutines of async destinations carry the labels for their whole lifetime. Synchronous writes are labelled for
+// the duration of the write; the labels of the calling goroutine are restored afterward. The background delivery of
+// DeliveryAtLeastOnce destinations isn't labelled.
+func WithPprofLabels(enabled bool) LoggerOption {
+	return func(l *ultraLogger) error {
+		if enabled {
+			l.pprofLabels = &pprofLabelCache{}
+		} else {
+			l.pprofLabels = nil
+		}
+		return nil
+	}
+}
+
+// pprofLabelCache caches the label sets of the destinations, so describing a destination doesn't cost anything per
+// line.
+type pprofLabelCache struct {
+	sets sync.Map // pprofLabelKey -> pprof.LabelSet
+}
+
+type pprofLabelKey struct {
+	w io.Writer
+	f LogLineFormatter
+}
+
+func (c *pprofLabelCache) labels(w io.Writer, f LogLineFormatter) pprof.LabelSet {
+	key := pprofLabelKey{w: w, f: f}
+	if labels, ok := c.sets.Load(key); ok {
+		return labels.(pprof.LabelSet)
+	}
+
+	labels := pprof.Labels(PprofLabelDestination, describeWriter(w), PprofLabelFormatter, fmt.Sprintf("%T", f))
+	c.sets.Store(key, labels)
+	return labels
+}
+
+// doLabelled runs fn with the pprof labels of the destination.
+func (c *pprofLabelCache) doLabelled(ctx context.Context, w io.Writer, f LogLineFormatter, fn func(ctx context.Context)) {
+	pprof.Do(ctx, c.labels(w, f), fn)
+}
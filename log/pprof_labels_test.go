@@ -0,0 +1,58 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"runtime/pprof"
+	"strings"
+	"testing"
+	"time"
+)
+
+// blockingWriter blocks every Write until release is closed, and signals each write on entered.
+type blockingWriter struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	w.entered <- struct{}{}
+	<-w.release
+	return len(p), nil
+}
+
+func TestWithPprofLabels(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+
+	for _, async := range []bool{false, true} {
+		t.Run(fmt.Sprintf("async=%v", async), func(t *testing.T) {
+			w := &blockingWriter{entered: make(chan struct{}, 1), release: make(chan struct{})}
+			logger, _ := NewLoggerWithOptions(
+				WithDestination(w, formatter),
+				WithPprofLabels(true),
+				WithAsync(async),
+			)
+
+			go logger.Info("labelled")
+			select {
+			case <-w.entered:
+			case <-time.After(time.Second):
+				t.Fatal("timed out waiting for the write")
+			}
+
+			profile := &bytes.Buffer{}
+			_ = pprof.Lookup("goroutine").WriteTo(profile, 1)
+			close(w.release)
+			logger.Flush()
+
+			for _, label := range []string{
+				fmt.Sprintf("%q:%q", PprofLabelDestination, "*log.blockingWriter"),
+				fmt.Sprintf("%q:%q", PprofLabelFormatter, "*log.textFormatter"),
+			} {
+				if !strings.Contains(profile.String(), label) {
+					t.Errorf("goroutine profile does not contain label %s", label)
+				}
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ func processFieldsWithData(
 	args LogLineArgs,
 	fields []Field,
 	fieldFormatters map[string]FieldFormatter,
+	cache *fieldResultCache,
 	data []any,
 ) {
 	defer close(resultChan)
@@ -22,8 +23,10 @@ func processFieldsWithData(
 		args:        args,
 		fields:      fields,
 		formatters:  fieldFormatters,
+		cache:       cache,
 		data:        data,
 		matchedData: make([]bool, len(data)),
+		results:     map[string]any{},
 		resultChan:  resultChan,
 	}
 
@@ -34,8 +37,10 @@ type fieldProcessor struct {
 	args        LogLineArgs
 	fields      []Field
 	formatters  map[string]FieldFormatter
+	cache       *fieldResultCache
 	data        []any
 	matchedData []bool
+	results     map[string]any // Results sent so far, by field name, for derived fields.
 	resultChan  chan fieldProcessingResult
 }
 
@@ -58,6 +63,9 @@ func (p *fieldProcessor) processField(field Field) error {
 		return err
 	}
 
+	if field.Settings().derived {
+		return p.processDerivedField(field, formatter)
+	}
 	if field.Settings().AlwaysMatch {
 		return p.processAlwaysMatchField(field, formatter)
 	}
@@ -74,14 +82,46 @@ func (p *fieldProcessor) getFormatter(field Field) (FieldFormatter, error) {
 }
 
 func (p *fieldProcessor) processAlwaysMatchField(field Field, formatter FieldFormatter) error {
-	// TODO: the formatter could panic... we should handle that nicely by logging an error about it, and exposing a
-	//  setting to allow the user to squelch formatter panics. Hmmmm... Generally, I think the error handling of the
-	//  logger should be configurable by the user. Do you want to allow a panic to propagate? Do you want to squelch and
-	//  log? Do you want to disable a destination on panic? Two options: either add predefined set of 'on-panic'
-	//  behaviors, create a panic handler interface that allows the user to define their own behavior. Leaning towards
-	//  the former b/c we don't need every possible behavior to be configurable. The latter is more flexible, but
-	//  requires more work, and adds complexity.
-	result, err := formatter(p.args, struct{}{})
+	if !field.Settings().passes(p.args, p.data) {
+		return nil
+	}
+
+	cacheable := p.cache != nil && field.Settings().Cacheable
+	var key fieldCacheKey
+	if cacheable {
+		key = newFieldCacheKey(field.Name(), p.args)
+		if result, ok := p.cache.load(key); ok {
+			p.sendResult(field, result)
+			return nil
+		}
+	}
+
+	result, err := callFieldFormatter(field, formatter, p.args, struct{}{})
+	if err != nil {
+		if p.handleProcessorError(field, err) {
+			return nil
+		}
+		return err
+	}
+
+	if result == nil {
+		p.sendDefault(field)
+		return nil
+	}
+
+	if cacheable {
+		p.cache.store(key, result)
+	}
+	p.sendResult(field, result)
+	return nil
+}
+
+func (p *fieldProcessor) processDerivedField(field Field, formatter FieldFormatter) error {
+	if !field.Settings().passes(p.args, p.data) {
+		return nil
+	}
+
+	result, err := callFieldFormatter(field, formatter, p.args, FieldResults{results: p.results})
 	if err != nil {
 		if p.handleProcessorError(field, err) {
 			return nil
@@ -89,45 +129,132 @@ func (p *fieldProcessor) processAlwaysMatchField(field Field, formatter FieldFor
 		return err
 	}
 
-	if result != nil {
-		p.sendResult(field, result)
+	if result == nil {
+		p.sendDefault(field)
+		return nil
 	}
+	p.sendResult(field, result)
 	return nil
 }
 
+// joiningField is implemented by fields that format all the data they match together, like the message field joining
+// the strings of a line, instead of a result per datum.
+type joiningField interface {
+	Field
+	joinResults(args LogLineArgs, results []any) ([]joinedResult, error)
+}
+
+// joinedResult is a result of a joiningField, written under its own name.
+type joinedResult struct {
+	name    string
+	value   any
+	showKey bool // Write the key even if the field hides it.
+}
+
 func (p *fieldProcessor) processDataMatchingField(field Field, formatter FieldFormatter) error {
-	for i, datum := range p.data {
-		if p.matchedData[i] {
-			continue
+	joiner, joining := field.(joiningField)
+	var joined []any
+
+	// matchDatum formats the datum, and reports whether the field matched it.
+	matchDatum := func(datum any) (bool, error) {
+		check := checkCondition(field, p.args, datum)
+		switch check {
+		case conditionSkip:
+			return false, nil
+		case conditionSuppress:
+			return true, nil
 		}
 
-		// TODO: See above comment about processor panic handling.
-		result, err := formatter(p.args, datum)
+		result, err := callFieldFormatter(field, formatter, p.args, datum)
 		if err != nil {
 			if p.handleProcessorError(field, err) {
-				continue
+				return false, nil
 			}
-			return err
+			return false, err
 		}
 
 		// TODO: Add a mechanism for a field to disclaim a match even if the data type is a match. E.g. a field that
 		//  matches on a string with a specific prefix. Currently it'll match to the first string field. Not always the
 		//  desired behavior.
 
-		if result != nil {
+		if result == nil {
+			return false, nil
+		}
+		if check == conditionFormat || field.Settings().passes(p.args, datum) {
+			if joining {
+				joined = append(joined, result)
+			} else {
+				p.sendResult(field, result)
+			}
+		}
+		return true, nil
+	}
+
+	matched := false
+	if datum, ok := p.args.entry.datum(field); ok {
+		// The data of an Entry for the field is only offered to the field.
+		var err error
+		if matched, err = matchDatum(datum); err != nil {
+			return err
+		}
+	}
+	for i, datum := range p.data {
+		if p.matchedData[i] {
+			continue
+		}
+
+		ok, err := matchDatum(datum)
+		if err != nil {
+			return err
+		}
+		if ok {
+			matched = true
 			p.matchedData[i] = true
-			p.sendResult(field, result)
 		}
 	}
+
+	if len(joined) > 0 {
+		results, err := callFieldFormatter(field, func(args LogLineArgs, _ any) (any, error) {
+			return joiner.joinResults(args, joined)
+		}, p.args, nil)
+		if err != nil {
+			if p.handleProcessorError(field, err) {
+				return nil
+			}
+			return err
+		}
+		for _, result := range results.([]joinedResult) {
+			settings := field.Settings()
+			settings.HideKey = settings.HideKey && !result.showKey
+			p.sendNamedResult(result.name, settings, result.value)
+		}
+	}
+
+	if !matched {
+		p.sendDefault(field)
+	}
 	return nil
 }
 
+// callFieldFormatter calls the formatter, turning a panic into an ErrorFieldFormatterPanic. Fields run on the
+// processor goroutine, where a panic would take down the whole process.
+func callFieldFormatter(field Field, formatter FieldFormatter, args LogLineArgs, data any) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, &ErrorFieldFormatterPanic{fieldName: field.Name(), value: r}
+		}
+	}()
+
+	return formatter(args, data)
+}
+
 func (p *fieldProcessor) handleProcessorError(field Field, err error) bool {
 	nonFatalError := &ErrorNonFatalFormatterError{}
 	InvalidFieldDataTypeError := &ErrorInvalidFieldDataType{}
+	panicError := &ErrorFieldFormatterPanic{}
 
 	switch {
-	case errors.As(err, &nonFatalError):
+	case errors.As(err, &nonFatalError), errors.As(err, &panicError):
 		p.sendResult(field, err.Error())
 		return true
 	case errors.As(err, &InvalidFieldDataTypeError):
@@ -138,13 +265,27 @@ func (p *fieldProcessor) handleProcessorError(field Field, err error) bool {
 }
 
 func (p *fieldProcessor) sendResult(field Field, data any) {
+	p.sendNamedResult(field.Name(), field.Settings(), data)
+}
+
+// sendNamedResult sends a result of a field under the name, which may differ from the name of the field for the
+// results of a joiningField.
+func (p *fieldProcessor) sendNamedResult(name string, settings FieldSettings, data any) {
+	p.results[name] = data
 	p.resultChan <- fieldProcessingResult{
-		fieldName:     field.Name(),
-		fieldSettings: field.Settings(),
+		fieldName:     name,
+		fieldSettings: settings,
 		fieldData:     data,
 	}
 }
 
+// sendDefault sends the default value of a field that has nothing to emit on the line, if it has one.
+func (p *fieldProcessor) sendDefault(field Field) {
+	if defaultValue := field.Settings().Default; defaultValue != nil {
+		p.sendResult(field, defaultValue)
+	}
+}
+
 func (p *fieldProcessor) sendError(fieldName string, err error) {
 	p.resultChan <- fieldProcessingResult{
 		fieldName: fieldName,
@@ -0,0 +1,100 @@
+package log
+
+import (
+	"io"
+	"sync"
+)
+
+// ansiClearLine moves the cursor to the start of the line and erases it.
+var ansiClearLine = []byte("\r\033[2K")
+
+// ProgressHooks clear and redraw an interactive terminal UI, like a progress bar or spinner, around the lines written
+// by a ProgressWriter. Both hooks are called with the writer's lock held, and must not write through the
+// ProgressWriter.
+type ProgressHooks struct {
+	// Clear is called before a line is written. It should erase the progress UI, and leave the cursor at the start of
+	// an empty line.
+	Clear func()
+	// Redraw is called after a line is written. It should draw the progress UI again, below the line.
+	Redraw func()
+}
+
+// ProgressWriter is a destination for interactive CLIs that show progress in the terminal. It wraps every line in
+// calls to its ProgressHooks, so that log lines are written above the progress UI instead of garbling it.
+//
+// Updates to the progress UI must be made through Do, so they don't interleave with lines.
+type ProgressWriter struct {
+	mu    sync.Mutex
+	w     io.Writer
+	hooks ProgressHooks
+}
+
+// NewProgressWriter returns a ProgressWriter that writes lines to w, typically os.Stderr.
+func NewProgressWriter(w io.Writer, hooks ProgressHooks) *ProgressWriter {
+	return &ProgressWriter{w: w, hooks: hooks}
+}
+
+// Write clears the progress UI, writes p, and redraws the progress UI.
+func (p *ProgressWriter) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.hooks.Clear != nil {
+		p.hooks.Clear()
+	}
+	n, err := p.w.Write(b)
+	if p.hooks.Redraw != nil {
+		p.hooks.Redraw()
+	}
+
+	return n, err
+}
+
+// Do runs fn with the writer's lock held, e.g. to update the progress UI without a line being written halfway through.
+func (p *ProgressWriter) Do(fn func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fn()
+}
+
+// StatusLine is a ProgressWriter with a built-in progress UI: a single line of text below the log lines, e.g. "fetching
+// 12/40". Use it as the destination of the logger, and update the text with Set.
+type StatusLine struct {
+	*ProgressWriter
+	text string
+}
+
+// NewStatusLine returns a StatusLine that writes to w, typically os.Stderr. w should be a terminal; the status line is
+// drawn with ANSI escape sequences.
+func NewStatusLine(w io.Writer) *StatusLine {
+	s := &StatusLine{}
+	s.ProgressWriter = NewProgressWriter(w, ProgressHooks{Clear: s.clear, Redraw: s.draw})
+	return s
+}
+
+// Set replaces the text of the status line. An empty text removes the status line.
+func (s *StatusLine) Set(text string) {
+	s.Do(func() {
+		s.clear()
+		s.text = text
+		s.draw()
+	})
+}
+
+// Clear removes the status line, e.g. once the work it tracks is done.
+func (s *StatusLine) Clear() {
+	s.Set("")
+}
+
+func (s *StatusLine) clear() {
+	if s.text != "" {
+		_, _ = s.w.Write(ansiClearLine)
+	}
+}
+
+func (s *StatusLine) draw() {
+	if s.text != "" {
+		_, _ = io.WriteString(s.w, s.text)
+	}
+}
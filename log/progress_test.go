@@ -0,0 +1,52 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestProgressWriter(t *testing.T) {
+	var events []string
+	buf := &bytes.Buffer{}
+	w := NewProgressWriter(buf, ProgressHooks{
+		Clear:  func() { events = append(events, "clear") },
+		Redraw: func() { events = append(events, "redraw") },
+	})
+
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+	logger, _ := NewLoggerWithOptions(WithDestination(w, formatter), WithAsync(false))
+	logger.Info("hello")
+
+	if got, want := strings.Join(events, ","), "clear,redraw"; got != want {
+		t.Errorf("hooks ran %q, want %q", got, want)
+	}
+	if got := buf.String(); got != "hello\n" {
+		t.Errorf("destination received %q", got)
+	}
+}
+
+func TestStatusLine(t *testing.T) {
+	buf := &bytes.Buffer{}
+	status := NewStatusLine(buf)
+
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+	logger, _ := NewLoggerWithOptions(WithDestination(status, formatter), WithAsync(false))
+
+	logger.Info("before")
+	status.Set("fetching 1/2")
+	logger.Info("during")
+	status.Set("fetching 2/2")
+	status.Clear()
+	logger.Info("after")
+
+	want := "before\n" +
+		"fetching 1/2" +
+		"\r\033[2K" + "during\n" + "fetching 1/2" +
+		"\r\033[2K" + "fetching 2/2" +
+		"\r\033[2K" +
+		"after\n"
+	if got := buf.String(); got != want {
+		t.Errorf("terminal received %q, want %q", got, want)
+	}
+}
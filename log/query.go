@@ -0,0 +1,102 @@
+package log
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Query selects lines kept by a RingBufferWriter, e.g. to show the recent errors of a tenant. The zero Query matches
+// every line.
+type Query struct {
+	// MinLevel is the minimum level of the lines.
+	MinLevel Level
+	// Since, if set, is the earliest time of the lines, inclusive, and Until, if set, the latest, exclusive.
+	Since time.Time
+	Until time.Time
+	// Tag, if set, is the tag of the lines.
+	Tag string
+	// Fields, if set, are the values of the fields of the lines, by key. Values are compared by their fmt.Sprint
+	// representation, so 42 matches a field formatted as "42". Only lines formatted by a RecordFormatter, like the
+	// formatters returned by NewFormatter, or by a formatter wrapping one, have fields.
+	Fields map[string]any
+	// Limit, if positive, is the maximum number of lines returned: the most recent ones.
+	Limit int
+}
+
+// QueryResult is a line returned by a Query.
+type QueryResult struct {
+	Level Level
+	Tag   string
+	Time  time.Time
+	// EntryID is zero unless the logger has WithEntryIDs.
+	EntryID EntryID
+	// Line is the formatted line, without its trailing newline.
+	Line string
+	// Fields are the values of the fields of the line when it was logged, by key. Values that could change since, like
+	// maps and structs, are the strings they were written as. It's nil unless the line was formatted by a
+	// RecordFormatter, or a formatter wrapping one.
+	Fields map[string]any
+}
+
+// Query returns the lines matching the query, oldest first. The fields of the lines are matched as they were when the
+// lines were logged.
+func (w *RingBufferWriter) Query(query Query) []QueryResult {
+	w.mu.Lock()
+	candidates := w.recentEntriesLocked(w.count)
+	w.mu.Unlock()
+
+	var entries []QueryResult
+	for _, candidate := range slices.Backward(candidates) {
+		if query.Limit > 0 && len(entries) == query.Limit {
+			break
+		}
+		if entry, ok := query.match(candidate); ok {
+			entries = append(entries, entry)
+		}
+	}
+	slices.Reverse(entries)
+	return entries
+}
+
+// match returns the QueryResult of the line, and whether it matches the query.
+func (q Query) match(line ringEntry) (QueryResult, bool) {
+	args := line.args
+	if args.Level < q.MinLevel ||
+		(q.Tag != "" && args.Tag != q.Tag) ||
+		(!q.Since.IsZero() && args.Time.Before(q.Since)) ||
+		(!q.Until.IsZero() && !args.Time.Before(q.Until)) {
+		return QueryResult{}, false
+	}
+
+	entry := QueryResult{
+		Level:   args.Level,
+		Tag:     args.Tag,
+		Time:    args.Time,
+		EntryID: args.EntryID,
+		Line:    line.line,
+		Fields:  line.fields,
+	}
+	for key, want := range q.Fields {
+		value, ok := entry.Fields[key]
+		if !ok || fmt.Sprint(value) != fmt.Sprint(want) {
+			return QueryResult{}, false
+		}
+	}
+	return entry, true
+}
+
+// Query returns the lines of the logger's ring buffer destination that match the query, oldest first. It returns
+// ErrorNoRingBuffer if the logger has no WithRingBufferDestination, and ErrorQueryFieldsUnavailable if the query has
+// Fields but the formatter of the ring buffer has none.
+func (l *ultraLogger) Query(query Query) ([]QueryResult, error) {
+	if l.ring == nil {
+		return nil, ErrorNoRingBuffer
+	}
+	if len(query.Fields) > 0 {
+		if _, ok := unwrapFormatter[RecordFormatter](l.loadDestinations().destinations[l.ring]); !ok {
+			return nil, ErrorQueryFieldsUnavailable
+		}
+	}
+	return l.ring.Query(query), nil
+}
@@ -0,0 +1,142 @@
+package log
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLogger_Query(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField(), NewTenantField()})
+	logger, _ := NewLoggerWithOptions(WithAsync(false), WithRingBufferDestination(formatter, 10))
+
+	start := time.Now()
+	logger.ForTenant("acme").Error("acme failed")
+	logger.ForTenant("globex").Error("globex failed")
+	logger.ForTenant("acme").Info("acme signed up")
+	logger.ForTenant("acme").Error("acme failed again")
+	logger.InfoMsg("no tenant")
+
+	lines := func(entries []QueryResult) []string {
+		var lines []string
+		for _, entry := range entries {
+			lines = append(lines, entry.Line)
+		}
+		return lines
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{
+			name:  "all",
+			query: Query{},
+			want: []string{
+				"acme failed tenant_id=acme",
+				"globex failed tenant_id=globex",
+				"acme signed up tenant_id=acme",
+				"acme failed again tenant_id=acme",
+				"no tenant",
+			},
+		},
+		{
+			name:  "errors of a tenant",
+			query: Query{MinLevel: Error, Fields: map[string]any{"tenant_id": "acme"}},
+			want:  []string{"acme failed tenant_id=acme", "acme failed again tenant_id=acme"},
+		},
+		{
+			name:  "limit",
+			query: Query{MinLevel: Error, Limit: 1},
+			want:  []string{"acme failed again tenant_id=acme"},
+		},
+		{
+			name:  "until",
+			query: Query{Until: start},
+			want:  nil,
+		},
+		{
+			name:  "tag",
+			query: Query{Tag: "other"},
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := logger.Query(tt.query)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if got := lines(entries); !slices.Equal(got, tt.want) {
+				t.Errorf("Query() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	entries, _ := logger.Query(Query{Limit: 1})
+	if got := entries[0]; got.Level != Info || got.Time.Before(start) || got.Fields["message"] != "no tenant" {
+		t.Errorf("Query() = %+v, want the Info line with its fields", got)
+	}
+}
+
+type queryCart struct {
+	Items []string
+}
+
+func TestLogger_Query_fieldsAsLogged(t *testing.T) {
+	calls := 0
+	cartField, _ := NewObjectField[*queryCart]("cart", func(args LogLineArgs, cart *queryCart) (any, error) {
+		calls++
+		return cart.Items, nil
+	})
+	base, _ := NewFormatter(OutputFormatJSON, []Field{NewMessageField(), cartField})
+
+	tests := []struct {
+		name      string
+		formatter LogLineFormatter
+		async     bool
+	}{
+		{"record formatter", base, false},
+		{"wrapped record formatter", NewColorizedFormatter(base, nil), false},
+		{"async", base, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, _ := NewLoggerWithOptions(WithAsync(tt.async), WithRingBufferDestination(tt.formatter, 10))
+			cart := &queryCart{Items: []string{"apple"}}
+			logger.Info("checked out", cart)
+			logger.Flush()
+			cart.Items[0] = "pear"
+			calls = 0
+
+			for range 2 {
+				entries, err := logger.Query(Query{Fields: map[string]any{"cart": `["apple"]`}})
+				if err != nil {
+					t.Fatalf("Query() error = %v", err)
+				}
+				if len(entries) != 1 || entries[0].Fields["message"] != "checked out" {
+					t.Errorf("Query() = %+v, want the line as it was logged", entries)
+				}
+			}
+			if calls != 0 {
+				t.Errorf("the fields ran %d times on Query, want 0", calls)
+			}
+		})
+	}
+}
+
+func TestLogger_Query_fieldsUnavailable(t *testing.T) {
+	logger, _ := NewLoggerWithOptions(
+		WithAsync(false),
+		WithRingBufferDestination(NewAccessLogFormatter(AccessLogCommon), 10),
+	)
+
+	if _, err := logger.Query(Query{Fields: map[string]any{"status": 200}}); !errors.Is(err, ErrorQueryFieldsUnavailable) {
+		t.Errorf("Query() error = %v, want %v", err, ErrorQueryFieldsUnavailable)
+	}
+	if _, err := logger.Query(Query{MinLevel: Error}); err != nil {
+		t.Errorf("Query() error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,129 @@
+package log
+
+// Record is a log line after field processing, and before encoding: the results of the fields of a formatter, in order,
+// along with the arguments of the line. Building Records separately from encoding them lets several consumers share
+// one field-processing pass per line.
+//
+// The field values are formatted for Args.OutputFormat, so a Record can only be encoded by a formatter of that format.
+type Record struct {
+	// Args are the arguments of the line, as set by the formatter that built the Record.
+	Args LogLineArgs
+	// Fields are the results of the fields, in the order they are written. A field written under several keys, e.g.
+	// legacy keys of a schema, appears once per key.
+	Fields []RecordField
+}
+
+// RecordField is the result of a field in a Record.
+type RecordField struct {
+	// Name is the name of the field.
+	Name string
+	// Key is the key the field is written under. It differs from the name if the formatter maps keys, e.g. with
+	// WithFieldAlias.
+	Key string
+	// Value is the formatted value of the field.
+	Value any
+	// Settings are the settings of the field.
+	Settings FieldSettings
+}
+
+// Get returns the value of the first field written under the key, and false if there is none.
+func (r *Record) Get(key string) (any, bool) {
+	for _, field := range r.Fields {
+		if field.Key == key {
+			return field.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Encoder renders Records.
+type Encoder interface {
+	// Encode renders the Record as a log line. The Record must have been built for the OutputFormat of the Encoder.
+	Encode(record *Record) ([]byte, error)
+}
+
+// RecordFormatter is a LogLineFormatter that splits formatting a line into building a Record and encoding it. The text
+// and JSON formatters returned by NewFormatter are RecordFormatters; FormatLogLine is equivalent to BuildRecord followed
+// by Encode.
+type RecordFormatter interface {
+	LogLineFormatter
+	Encoder
+	// BuildRecord runs the fields of the formatter over the data, and returns their results as a Record.
+	BuildRecord(args LogLineArgs, data []any) (*Record, error)
+}
+
+// recordBuilder is the field-processing stage shared by the text and JSON formatters.
+type recordBuilder struct {
+	fields     []Field
+	formatters map[string]FieldFormatter
+	cache      *fieldResultCache
+	keys       *fieldKeys
+	validation *entryValidation
+	// maxSensitivity drops the results of the fields classified above it. The fields still run, so that they use up
+	// the data they match. Nil when there's no maximum.
+	maxSensitivity *Sensitivity
+}
+
+// build processes the fields of a line into a Record. args must already be set up by the formatter.
+func (b recordBuilder) build(args LogLineArgs, data []any) (*Record, error) {
+	record := &Record{Args: args, Fields: make([]RecordField, 0, len(b.fields))}
+	resultChan := make(chan fieldProcessingResult)
+
+	var results map[string]any
+	if b.validation != nil {
+		results = map[string]any{}
+	}
+
+	// Guaranteed to close on error result and once all fields have been processed.
+	// TODO: Could potentially optimize this by moving the goroutine *into* the processor, spinning up goroutines for
+	//  each field we need to process, and using a shared structure for the checked fields/written data... That will
+	//  make field-to-data-type mappings a bit more complex, but we'd just need to make sure that all data of the same
+	//  type is processed in-order. :thinking:
+	go processFieldsWithData(resultChan, args, b.fields, b.formatters, b.cache, data)
+
+	for {
+		result, ok := <-resultChan
+		if !ok {
+			break
+		}
+
+		if result.err != nil {
+			return nil, result.err
+		}
+		if results != nil {
+			results[result.fieldName] = result.fieldData
+		}
+		if b.maxSensitivity != nil && result.fieldSettings.Sensitivity > *b.maxSensitivity {
+			continue
+		}
+
+		keys, ok := b.keys.lookup(result.fieldName)
+		if !ok {
+			keys = []string{result.fieldName}
+		}
+		for _, key := range keys {
+			record.Fields = append(record.Fields, RecordField{
+				Name:     result.fieldName,
+				Key:      key,
+				Value:    result.fieldData,
+				Settings: result.fieldSettings,
+			})
+		}
+	}
+
+	if b.validation != nil {
+		annotation, err := b.validation.validate(args, results)
+		if err != nil {
+			return nil, err
+		}
+		if annotation != "" {
+			record.Fields = append(record.Fields, RecordField{
+				Name:  ValidationErrorKey,
+				Key:   ValidationErrorKey,
+				Value: annotation,
+			})
+		}
+	}
+
+	return record, nil
+}
@@ -0,0 +1,45 @@
+package log
+
+import "testing"
+
+func TestRecordFormatter(t *testing.T) {
+	countField, _ := NewObjectField[int]("count", func(args LogLineArgs, data int) (any, error) {
+		return data, nil
+	})
+	fields := []Field{NewMessageField(), countField}
+
+	for _, format := range []OutputFormat{OutputFormatText, OutputFormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			formatter, _ := NewFormatter(format, fields, WithFieldAlias("count", "n"))
+			recordFormatter, ok := formatter.(RecordFormatter)
+			if !ok {
+				t.Fatalf("NewFormatter() = %T, want a RecordFormatter", formatter)
+			}
+
+			args := LogLineArgs{Level: Info}
+			data := []any{"hello", 3}
+
+			record, err := recordFormatter.BuildRecord(args, data)
+			if err != nil {
+				t.Fatalf("BuildRecord() error = %v", err)
+			}
+			if record.Args.OutputFormat != format {
+				t.Errorf("record.Args.OutputFormat = %v, want %v", record.Args.OutputFormat, format)
+			}
+			if len(record.Fields) != 2 || record.Fields[1].Name != "count" || record.Fields[1].Key != "n" {
+				t.Errorf("record.Fields = %+v, want message and count under n", record.Fields)
+			}
+			if value, ok := record.Get("n"); !ok || value != 3 {
+				t.Errorf("record.Get(n) = %v, %v, want 3, true", value, ok)
+			}
+
+			encoded, err := recordFormatter.Encode(record)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if want := formatter.FormatLogLine(args, data); string(encoded) != string(want.bytes) {
+				t.Errorf("Encode() = %s, want FormatLogLine() = %s", encoded, want.bytes)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RequestLoggerSettings configures a RequestLogger.
+type RequestLoggerSettings struct {
+	// Level is the minimum level of the summary line. If a higher-level line was logged through the RequestLogger, the
+	// summary is logged at that level instead, so failed requests stand out. Defaults to Info if nil; see LevelPtr.
+	Level *Level
+	// Incremental determines whether lines logged through the RequestLogger are also written as they happen. If false,
+	// the summary line is the only line written for the request.
+	Incremental bool
+	// DurationKey is the summary key of the request duration. Defaults to "duration".
+	DurationKey string
+}
+
+var defaultRequestLoggerSettings = RequestLoggerSettings{
+	Level:       LevelPtr(Info),
+	Incremental: false,
+	DurationKey: "duration",
+}
+
+func (s *RequestLoggerSettings) mergeDefault() {
+	if s.Level == nil {
+		s.Level = LevelPtr(*defaultRequestLoggerSettings.Level)
+	}
+	if s.DurationKey == "" {
+		s.DurationKey = defaultRequestLoggerSettings.DurationKey
+	}
+}
+
+// RequestLogger accumulates fields across the lifetime of a request, and logs them as a single summary line when the
+// request finishes (a "canonical log line"). Pass it along in a context with ContextWithRequestLogger, so that any
+// code handling the request can add to the summary with AddRequestField.
+//
+// The summary is logged as a [*RequestSummary]; the destination formatters need a NewRequestSummaryField to write it.
+//
+// A RequestLogger is safe for concurrent use. All methods are no-ops on a nil *RequestLogger.
+type RequestLogger struct {
+	logger   Logger
+	settings RequestLoggerSettings
+	start    time.Time
+
+	mu       sync.Mutex
+	summary  RequestSummary
+	level    Level
+	finished bool
+}
+
+// NewRequestLogger returns a RequestLogger for a request that starts now, logging its summary to logger.
+func NewRequestLogger(logger Logger, settings *RequestLoggerSettings) *RequestLogger {
+	if settings == nil {
+		settings = &RequestLoggerSettings{}
+	}
+	settings.mergeDefault()
+
+	return &RequestLogger{
+		logger:   logger,
+		settings: *settings,
+		start:    time.Now(),
+		level:    *settings.Level,
+	}
+}
+
+// Add sets the summary field key to value. Setting a key again overwrites its value, but keeps its position. Fields
+// added after Finish are ignored.
+func (r *RequestLogger) Add(key string, value any) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.finished {
+		return
+	}
+	r.summary.set(key, value)
+}
+
+// Log logs an incremental line for the request. The line is only written if the RequestLogger is Incremental, but its
+// level always counts towards the level of the summary.
+func (r *RequestLogger) Log(level Level, data ...any) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	r.level = max(r.level, level)
+	r.mu.Unlock()
+
+	if r.settings.Incremental {
+		r.logger.Log(level, data...)
+	}
+}
+
+// Finish logs the summary line, with the request duration and any additional data. Only the first call logs; the
+// request's fields can't be changed afterwards.
+func (r *RequestLogger) Finish(data ...any) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	if r.finished {
+		r.mu.Unlock()
+		return
+	}
+	r.finished = true
+	r.summary.set(r.settings.DurationKey, time.Since(r.start))
+	summary, level := &r.summary, r.level
+	r.mu.Unlock()
+
+	r.logger.Log(level, append(data, summary)...)
+}
+
+// RequestSummary is the ordered set of fields accumulated by a RequestLogger.
+type RequestSummary struct {
+	keys   []string
+	values map[string]any
+}
+
+func (s *RequestSummary) set(key string, value any) {
+	if s.values == nil {
+		s.values = map[string]any{}
+	}
+	if _, ok := s.values[key]; !ok {
+		s.keys = append(s.keys, key)
+	}
+	s.values[key] = value
+}
+
+// Keys returns the keys of the summary, in the order they were first added.
+func (s *RequestSummary) Keys() []string {
+	return s.keys
+}
+
+// Get returns the value of the summary field key.
+func (s *RequestSummary) Get(key string) (any, bool) {
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// String returns the summary as space separated key=value pairs.
+func (s *RequestSummary) String() string {
+	var b strings.Builder
+	for i, key := range s.keys {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%s=%v", key, s.values[key])
+	}
+	return b.String()
+}
+
+// EventFields returns the summary fields.
+func (s *RequestSummary) EventFields() map[string]any {
+	return s.values
+}
+
+// NewRequestSummaryField returns a new Field that formats the [*RequestSummary] logged by a RequestLogger. See
+// NewEventField.
+//
+// OutputFormats:
+//   - OutputFormatText => space separated key=value pairs, in the order the keys were added.
+//   - OutputFormatJSON => an object with the summary fields.
+func NewRequestSummaryField(name string) (Field, error) {
+	return NewEventField[*RequestSummary](name)
+}
+
+type requestLoggerContextKey struct{}
+
+// ContextWithRequestLogger returns a copy of ctx that carries the RequestLogger.
+func ContextWithRequestLogger(ctx context.Context, r *RequestLogger) context.Context {
+	return context.WithValue(ctx, requestLoggerContextKey{}, r)
+}
+
+// RequestLoggerFromContext returns the RequestLogger carried by ctx, or nil if there is none. The methods of a nil
+// RequestLogger are no-ops, so the result can be used without checking.
+func RequestLoggerFromContext(ctx context.Context) *RequestLogger {
+	r, _ := ctx.Value(requestLoggerContextKey{}).(*RequestLogger)
+	return r
+}
+
+// AddRequestField adds a summary field to the RequestLogger carried by ctx, if any.
+func AddRequestField(ctx context.Context, key string, value any) {
+	RequestLoggerFromContext(ctx).Add(key, value)
+}
@@ -0,0 +1,119 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func newRequestLoggerTestLogger(t *testing.T, format OutputFormat) (Logger, *bytes.Buffer) {
+	t.Helper()
+
+	summaryField, err := NewRequestSummaryField("request")
+	if err != nil {
+		t.Fatalf("NewRequestSummaryField() error = %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	formatter, _ := NewFormatter(format, []Field{NewDefaultLevelField(), NewMessageField(), summaryField})
+	logger, err := NewLoggerWithOptions(WithAsync(false), WithMinLevel(Debug), WithDestination(buf, formatter))
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	return logger, buf
+}
+
+func TestRequestLogger(t *testing.T) {
+	logger, buf := newRequestLoggerTestLogger(t, OutputFormatText)
+
+	r := NewRequestLogger(logger, nil)
+	ctx := ContextWithRequestLogger(context.Background(), r)
+
+	AddRequestField(ctx, "method", "GET")
+	AddRequestField(ctx, "user", 42)
+	AddRequestField(ctx, "method", "POST")
+	RequestLoggerFromContext(ctx).Log(Debug, "cache miss")
+	r.Finish("request done")
+	r.Finish("request done again")
+	AddRequestField(ctx, "late", true)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want a single summary line: %q", len(lines), buf.String())
+	}
+	if want := "<INFO> request done method=POST user=42 duration="; !strings.HasPrefix(lines[0], want) {
+		t.Errorf("summary = %q, want prefix %q", lines[0], want)
+	}
+}
+
+func TestRequestLogger_incremental(t *testing.T) {
+	logger, buf := newRequestLoggerTestLogger(t, OutputFormatText)
+
+	r := NewRequestLogger(logger, &RequestLoggerSettings{Incremental: true})
+	r.Log(Warn, "slow query")
+	r.Finish("request done")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want the incremental line and the summary: %q", len(lines), buf.String())
+	}
+	if lines[0] != "<WARN> slow query" {
+		t.Errorf("incremental line = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "<WARN> request done duration=") {
+		t.Errorf("summary = %q, want it to be logged at the highest level seen", lines[1])
+	}
+}
+
+func TestRequestLogger_json(t *testing.T) {
+	logger, buf := newRequestLoggerTestLogger(t, OutputFormatJSON)
+
+	r := NewRequestLogger(logger, &RequestLoggerSettings{DurationKey: "took"})
+	r.Add("status", 200)
+	r.Finish()
+
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v, line = %q", err, buf.String())
+	}
+
+	summary, ok := line["request"].(map[string]any)
+	if !ok {
+		t.Fatalf("request = %v, want an object", line["request"])
+	}
+	if summary["status"] != float64(200) {
+		t.Errorf("status = %v, want 200", summary["status"])
+	}
+	if _, ok := summary["took"]; !ok {
+		t.Errorf("summary %v is missing the duration", summary)
+	}
+}
+
+func TestRequestLoggerFromContext_missing(t *testing.T) {
+	r := RequestLoggerFromContext(context.Background())
+	if r != nil {
+		t.Fatalf("RequestLoggerFromContext() = %v, want nil", r)
+	}
+
+	// A nil RequestLogger is safe to use.
+	AddRequestField(context.Background(), "key", "value")
+	r.Log(Error, "ignored")
+	r.Finish()
+}
+
+func TestRequestLoggerSettings_mergeDefault(t *testing.T) {
+	settings := &RequestLoggerSettings{}
+	settings.mergeDefault()
+	if *settings.Level != Info {
+		t.Errorf("Level = %v, want %v", *settings.Level, Info)
+	}
+
+	settings = &RequestLoggerSettings{Level: LevelPtr(Debug)}
+	settings.mergeDefault()
+	if *settings.Level != Debug {
+		t.Errorf("Level = %v, want %v", *settings.Level, Debug)
+	}
+}
@@ -0,0 +1,195 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ResourceGuardSettings are the settings of WithResourceGuard.
+type ResourceGuardSettings struct {
+	// Path is a path on the file system of the log files, whose free space is checked. Defaults to the working
+	// directory.
+	Path string
+	// MinFreeBytes is the free disk space below which the logger is degraded. Defaults to 100MiB.
+	MinFreeBytes uint64
+	// MinFreeFiles is the number of file descriptors left before the open file limit of the process (RLIMIT_NOFILE)
+	// below which the logger is degraded. Defaults to 64.
+	MinFreeFiles uint64
+	// Interval is the time between checks. Defaults to 30s.
+	Interval time.Duration
+}
+
+var defaultResourceGuardSettings = ResourceGuardSettings{
+	Path:         ".",
+	MinFreeBytes: 100 << 20,
+	MinFreeFiles: 64,
+	Interval:     30 * time.Second,
+}
+
+func (s *ResourceGuardSettings) mergeDefault() {
+	if s.Path == "" {
+		s.Path = defaultResourceGuardSettings.Path
+	}
+	if s.MinFreeBytes == 0 {
+		s.MinFreeBytes = defaultResourceGuardSettings.MinFreeBytes
+	}
+	if s.MinFreeFiles == 0 {
+		s.MinFreeFiles = defaultResourceGuardSettings.MinFreeFiles
+	}
+	if s.Interval <= 0 {
+		s.Interval = defaultResourceGuardSettings.Interval
+	}
+}
+
+// ResourceAlert is logged by loggers created with WithResourceGuard when they are degraded, and when they recover. The
+// destination formatters need a NewResourceAlertField to write it.
+type ResourceAlert struct {
+	// Degraded is whether the logger is degraded, dropping its Debug lines.
+	Degraded bool
+	// FreeBytes is the free disk space of the path of the guard.
+	FreeBytes uint64
+	// OpenFiles and FileLimit are the number of open file descriptors of the process, and its limit. Both are zero on
+	// platforms where they aren't available.
+	OpenFiles uint64
+	FileLimit uint64
+}
+
+// String returns the alert as key=value pairs.
+func (a *ResourceAlert) String() string {
+	return fmt.Sprintf(
+		"degraded=%t free=%s open_files=%d/%d",
+		a.Degraded,
+		formatBinarySize(a.FreeBytes),
+		a.OpenFiles,
+		a.FileLimit,
+	)
+}
+
+// EventFields returns the alert fields.
+func (a *ResourceAlert) EventFields() map[string]any {
+	return map[string]any{
+		"degraded":   a.Degraded,
+		"free_bytes": a.FreeBytes,
+		"open_files": a.OpenFiles,
+		"file_limit": a.FileLimit,
+	}
+}
+
+// NewResourceAlertField returns a new Field that formats the [*ResourceAlert] logged by loggers created with
+// WithResourceGuard. See NewEventField.
+func NewResourceAlertField() Field {
+	field, _ := NewEventField[*ResourceAlert]("resources")
+	return field
+}
+
+// WithResourceGuard checks the free disk space of the file system of the log files and the open file descriptors of
+// the process every interval, and degrades the logger when either runs low, instead of letting the writes of its file
+// destinations fail with "no space left on device" or "too many open files" errors. A degraded logger drops its Debug
+// lines, and logs a Warn line with a [*ResourceAlert]; it logs an Info line with another when it recovers. The alerts
+// are logged regardless of the minimum level, unless the logger is silenced. The checks stop when the logger is closed.
+//
+// The checks are skipped on platforms where the resources can't be read. Default=disabled.
+func WithResourceGuard(settings *ResourceGuardSettings) LoggerOption {
+	return func(l *ultraLogger) error {
+		s := ResourceGuardSettings{}
+		if settings != nil {
+			s = *settings
+		}
+		s.mergeDefault()
+		l.guardSettings = &s
+		return nil
+	}
+}
+
+// resourceUsage is the usage of the resources checked by a resource guard. The fields are zero if unknown.
+type resourceUsage struct {
+	freeBytes uint64
+	diskKnown bool
+	openFiles uint64
+	fileLimit uint64
+}
+
+// readResourceUsage reads the resource usage of the process, for the file system of the path. Replaced in tests.
+var readResourceUsage = func(path string) resourceUsage {
+	var usage resourceUsage
+	usage.freeBytes, usage.diskKnown = diskFree(path)
+	usage.openFiles, usage.fileLimit, _ = openFiles()
+	return usage
+}
+
+// low reports whether the usage is below the thresholds of the settings.
+func (u resourceUsage) low(s *ResourceGuardSettings) bool {
+	if u.diskKnown && u.freeBytes < s.MinFreeBytes {
+		return true
+	}
+	return u.fileLimit > 0 && u.fileLimit-min(u.openFiles, u.fileLimit) < s.MinFreeFiles
+}
+
+// resourceGuard checks the resources of a logger until it's closed.
+type resourceGuard struct {
+	degraded atomic.Bool
+	stop     chan struct{}
+	stopped  chan struct{}
+	once     sync.Once
+}
+
+// startResourceGuard checks the resources of the logger once, then every interval of the settings.
+func startResourceGuard(l *ultraLogger, s *ResourceGuardSettings) *resourceGuard {
+	g := &resourceGuard{stop: make(chan struct{}), stopped: make(chan struct{})}
+	g.check(l, s)
+
+	go func() {
+		defer close(g.stopped)
+
+		ticker := time.NewTicker(s.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-g.stop:
+				return
+			case <-ticker.C:
+				g.check(l, s)
+			}
+		}
+	}()
+
+	return g
+}
+
+// check reads the resource usage, and logs an alert if the logger is degraded or recovers.
+func (g *resourceGuard) check(l *ultraLogger, s *ResourceGuardSettings) {
+	usage := readResourceUsage(s.Path)
+	degraded := usage.low(s)
+	if g.degraded.Swap(degraded) == degraded || l.silent {
+		return
+	}
+
+	alert := &ResourceAlert{
+		Degraded:  degraded,
+		FreeBytes: usage.freeBytes,
+		OpenFiles: usage.openFiles,
+		FileLimit: usage.fileLimit,
+	}
+	if degraded {
+		l.dispatch(LogLineArgs{Level: Warn, Tag: l.tag}, []any{"log resources low, dropping debug lines", alert}, true)
+	} else {
+		l.dispatch(LogLineArgs{Level: Info, Tag: l.tag}, []any{"log resources recovered", alert}, true)
+	}
+}
+
+// drops reports whether lines of the level are dropped. It's false on a nil *resourceGuard.
+func (g *resourceGuard) drops(level Level) bool {
+	return g != nil && level == Debug && g.degraded.Load()
+}
+
+// stopAndWait stops the checks, and waits for the last one to finish. It's a no-op on a nil *resourceGuard.
+func (g *resourceGuard) stopAndWait() {
+	if g == nil {
+		return
+	}
+	g.once.Do(func() { close(g.stop) })
+	<-g.stopped
+}
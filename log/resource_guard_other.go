@@ -0,0 +1,15 @@
+//go:build !(linux || darwin || freebsd)
+
+package log
+
+// diskFree returns the space of the file system of the path available to unprivileged users. It isn't available on
+// this platform.
+func diskFree(string) (uint64, bool) {
+	return 0, false
+}
+
+// openFiles returns the number of open file descriptors of the process, and its soft limit. They aren't available on
+// this platform.
+func openFiles() (open, limit uint64, ok bool) {
+	return 0, 0, false
+}
@@ -0,0 +1,89 @@
+package log
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithResourceGuard(t *testing.T) {
+	usage := resourceUsage{freeBytes: 10 << 20, diskKnown: true, openFiles: 10, fileLimit: 1024}
+	defer func(read func(string) resourceUsage) { readResourceUsage = read }(readResourceUsage)
+	readResourceUsage = func(string) resourceUsage { return usage }
+
+	dest := &toggleWriter{}
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField(), NewResourceAlertField()})
+	logger, err := NewLoggerWithOptions(
+		WithAsync(false),
+		WithMinLevel(Debug),
+		WithResourceGuard(&ResourceGuardSettings{Interval: time.Hour}),
+		WithDestination(dest, formatter),
+	)
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+	defer logger.Close()
+	l := logger.(*ultraLogger)
+
+	logger.Debug("dropped")
+	logger.DebugMsg("dropped too")
+	logger.Info("kept")
+
+	usage.freeBytes = 1 << 30
+	l.guard.check(l, l.guardSettings)
+	logger.Debug("debug again")
+	logger.DebugMsg("debug message again")
+
+	want := []string{
+		"log resources low, dropping debug lines degraded=true free=10MiB open_files=10/1024\n",
+		"kept\n",
+		"log resources recovered degraded=false free=1GiB open_files=10/1024\n",
+		"debug again\n",
+		"debug message again\n",
+	}
+	got := dest.received()
+	if len(got) != len(want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if dropped := l.stats.snapshot().Dropped; dropped != 2 {
+		t.Errorf("Dropped = %d, want 2", dropped)
+	}
+}
+
+func TestResourceUsage_low(t *testing.T) {
+	s := &ResourceGuardSettings{}
+	s.mergeDefault()
+
+	tests := []struct {
+		name  string
+		usage resourceUsage
+		want  bool
+	}{
+		{"unknown", resourceUsage{}, false},
+		{"enough", resourceUsage{freeBytes: 1 << 30, diskKnown: true, openFiles: 10, fileLimit: 1024}, false},
+		{"low disk", resourceUsage{freeBytes: 1 << 20, diskKnown: true}, true},
+		{"low files", resourceUsage{openFiles: 1000, fileLimit: 1024}, true},
+		{"over the limit", resourceUsage{openFiles: 2000, fileLimit: 1024}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.usage.low(s); got != tt.want {
+				t.Errorf("low() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadResourceUsage(t *testing.T) {
+	usage := readResourceUsage(".")
+	if usage.diskKnown && usage.freeBytes == 0 {
+		t.Errorf("readResourceUsage() = %+v, want free space", usage)
+	}
+	if usage.openFiles > usage.fileLimit {
+		t.Errorf("readResourceUsage() = %+v, want fewer open files than the limit", usage)
+	}
+}
@@ -0,0 +1,37 @@
+//go:build linux || darwin || freebsd
+
+package log
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+)
+
+// diskFree returns the space of the file system of the path available to unprivileged users.
+func diskFree(path string) (uint64, bool) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, false
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), true
+}
+
+// openFiles returns the number of open file descriptors of the process, and its soft limit.
+func openFiles() (open, limit uint64, ok bool) {
+	var rlimit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rlimit); err != nil {
+		return 0, 0, false
+	}
+
+	dir := "/dev/fd"
+	if runtime.GOOS == "linux" {
+		dir = "/proc/self/fd"
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, 0, false
+	}
+	// Less the descriptor of the directory itself.
+	return uint64(max(len(entries)-1, 0)), uint64(rlimit.Cur), true
+}
@@ -0,0 +1,119 @@
+package log
+
+import (
+	"maps"
+	"slices"
+	"sync"
+)
+
+// SchemaVersion is a version of a LogSchema: the keys that fields are written under in that version.
+type SchemaVersion struct {
+	// Version identifies the version. It is written by the NewSchemaVersionField.
+	Version string
+	// Keys maps field names, after any WithFieldAlias, to the key they are written under. Fields that aren't in the
+	// map are written under their name.
+	Keys map[string]string
+	// LegacyKeys maps field names to keys of previous versions that the field is written under as well. Use them during
+	// a migration window, so downstream parsers can move to the new key before the old one disappears.
+	LegacyKeys map[string][]string
+}
+
+// LogSchema is a registry of the versions of a log schema. It lets teams with downstream parsers evolve field names
+// deliberately: register a version per change, and select the version a formatter writes with WithSchema.
+//
+// A LogSchema is safe for concurrent use.
+type LogSchema struct {
+	mu       sync.RWMutex
+	versions map[string]SchemaVersion
+	order    []string
+}
+
+// NewLogSchema returns a LogSchema with the versions registered. It returns an error if a version can't be registered.
+func NewLogSchema(versions ...SchemaVersion) (*LogSchema, error) {
+	s := &LogSchema{versions: map[string]SchemaVersion{}}
+	for _, version := range versions {
+		if err := s.Register(version); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Register adds a version to the schema. It returns an error if the version is empty or already registered.
+func (s *LogSchema) Register(version SchemaVersion) error {
+	if version.Version == "" {
+		return ErrorSchemaVersionNotSpecified
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.versions[version.Version]; ok {
+		return &ErrorDuplicateSchemaVersion{version: version.Version}
+	}
+
+	// Copy the mappings, so changes to the caller's maps don't reach formatters already using the version.
+	version.Keys = maps.Clone(version.Keys)
+	version.LegacyKeys = maps.Clone(version.LegacyKeys)
+	for name, keys := range version.LegacyKeys {
+		version.LegacyKeys[name] = slices.Clone(keys)
+	}
+
+	s.versions[version.Version] = version
+	s.order = append(s.order, version.Version)
+	return nil
+}
+
+// Version returns the registered version, and false if there is no such version.
+func (s *LogSchema) Version(version string) (SchemaVersion, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	v, ok := s.versions[version]
+	return v, ok
+}
+
+// Versions returns the registered versions, in the order they were registered.
+func (s *LogSchema) Versions() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.order)
+}
+
+// WithSchema writes the fields of the formatter under the keys of the schema version. NewFormatter returns an error if
+// the version isn't registered in the schema.
+func WithSchema(schema *LogSchema, version string) FormatterOption {
+	return func(f LogLineFormatter) LogLineFormatter {
+		keys := formatterKeys(f)
+		if keys == nil {
+			return f
+		}
+
+		v, ok := schema.Version(version)
+		if !ok {
+			keys.setErr(&ErrorUnknownSchemaVersion{version: version})
+			return f
+		}
+		keys.schema = &v
+		return f
+	}
+}
+
+// NewSchemaVersionField returns a new Field that writes the schema version of the formatter, as selected with
+// WithSchema. The field is omitted if the formatter doesn't use a schema.
+//
+// OutputFormats:
+//   - All OutputFormats => the version string.
+func NewSchemaVersionField(name string) (Field, error) {
+	return NewLineArgsField(
+		name,
+		func(args LogLineArgs) (any, error) {
+			if args.SchemaVersion == "" {
+				return nil, nil
+			}
+			return args.SchemaVersion, nil
+		},
+		WithHideKey(false),
+	)
+}
@@ -0,0 +1,105 @@
+package log
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func testSchema(t *testing.T) *LogSchema {
+	t.Helper()
+
+	schema, err := NewLogSchema(
+		SchemaVersion{Version: "1"},
+		SchemaVersion{
+			Version:    "2",
+			Keys:       map[string]string{"message": "msg"},
+			LegacyKeys: map[string][]string{"message": {"message"}},
+		},
+		SchemaVersion{
+			Version: "3",
+			Keys:    map[string]string{"message": "msg", "user": "user_id"},
+		},
+	)
+	if err != nil {
+		t.Fatalf("NewLogSchema() error = %v", err)
+	}
+	return schema
+}
+
+func TestLogSchema_Register(t *testing.T) {
+	schema := testSchema(t)
+
+	if got, want := schema.Versions(), []string{"1", "2", "3"}; !slices.Equal(got, want) {
+		t.Errorf("Versions() = %v, want %v", got, want)
+	}
+
+	var duplicate *ErrorDuplicateSchemaVersion
+	if err := schema.Register(SchemaVersion{Version: "2"}); !errors.As(err, &duplicate) {
+		t.Errorf("Register() error = %v, want ErrorDuplicateSchemaVersion", err)
+	}
+	if err := schema.Register(SchemaVersion{}); !errors.Is(err, ErrorSchemaVersionNotSpecified) {
+		t.Errorf("Register() error = %v, want ErrorSchemaVersionNotSpecified", err)
+	}
+
+	keys := map[string]string{"level": "lvl"}
+	if err := schema.Register(SchemaVersion{Version: "4", Keys: keys}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	keys["level"] = "severity"
+	if v, _ := schema.Version("4"); v.Keys["level"] != "lvl" {
+		t.Errorf("Version() keys = %v, want the keys at registration", v.Keys)
+	}
+}
+
+func TestWithSchema(t *testing.T) {
+	schema := testSchema(t)
+
+	versionField, _ := NewSchemaVersionField("schema")
+	userField, _ := NewIntField("user")
+	fields := []Field{versionField, NewMessageField(), userField}
+
+	tests := []struct {
+		name    string
+		format  OutputFormat
+		version string
+		want    string
+	}{
+		{"json v1", OutputFormatJSON, "1", `{"message":"hello","schema":"1","user":42}`},
+		{"json v2", OutputFormatJSON, "2", `{"message":"hello","msg":"hello","schema":"2","user":42}`},
+		{"json v3", OutputFormatJSON, "3", `{"msg":"hello","schema":"3","user_id":42}`},
+		{"text v3", OutputFormatText, "3", `schema=3 hello user_id=42`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter, err := NewFormatter(tt.format, fields, WithSchema(schema, tt.version))
+			if err != nil {
+				t.Fatalf("NewFormatter() error = %v", err)
+			}
+
+			result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{"hello", 42})
+			if result.err != nil {
+				t.Fatalf("FormatLogLine() error = %v", result.err)
+			}
+			if string(result.bytes) != tt.want {
+				t.Errorf("FormatLogLine() = %s, want %s", result.bytes, tt.want)
+			}
+		})
+	}
+
+	t.Run("unknown version", func(t *testing.T) {
+		var unknown *ErrorUnknownSchemaVersion
+		if _, err := NewFormatter(OutputFormatJSON, fields, WithSchema(schema, "9")); !errors.As(err, &unknown) {
+			t.Errorf("NewFormatter() error = %v, want ErrorUnknownSchemaVersion", err)
+		}
+	})
+
+	t.Run("no schema", func(t *testing.T) {
+		formatter, _ := NewFormatter(OutputFormatJSON, fields)
+
+		result := formatter.FormatLogLine(LogLineArgs{Level: Info}, []any{"hello"})
+		if want := `{"message":"hello"}`; string(result.bytes) != want {
+			t.Errorf("FormatLogLine() = %s, want %s", result.bytes, want)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package log
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// WithSequenceNumbers stamps every line with monotonically increasing sequence numbers, starting at 1: one counting
+// the lines of the logger (LogLineArgs.Sequence), and one counting the lines of each destination
+// (LogLineArgs.DestinationSequence). Unlike timestamps, they don't depend on the resolution or adjustments of the
+// clock, so consumers can detect reordered and lost lines from gaps in the numbers. The destination formatters need a
+// NewSequenceField or NewDestinationSequenceField to write them. Default=false.
+//
+// The numbers are assigned as lines are dispatched, so the lines of an async logger may still reach a destination out
+// of order; that's what the numbers reveal. Since each destination numbers its own lines, destinations sharing a
+// formatter format their lines separately.
+func WithSequenceNumbers(enabled bool) LoggerOption {
+	return func(l *ultraLogger) error {
+		l.sequences = nil
+		if enabled {
+			l.sequences = &sequenceCounters{}
+		}
+		return nil
+	}
+}
+
+// sequenceCounters are the sequence counters of a logger. They are safe for concurrent use.
+type sequenceCounters struct {
+	lines        atomic.Uint64
+	destinations sync.Map // io.Writer -> *atomic.Uint64
+}
+
+// next returns the sequence number of the next line of the logger.
+func (c *sequenceCounters) next() uint64 {
+	return c.lines.Add(1)
+}
+
+// nextFor returns the sequence number of the next line of the destination w.
+func (c *sequenceCounters) nextFor(w io.Writer) uint64 {
+	counter, ok := c.destinations.Load(w)
+	if !ok {
+		counter, _ = c.destinations.LoadOrStore(w, &atomic.Uint64{})
+	}
+	return counter.(*atomic.Uint64).Add(1)
+}
+
+// stampDestination sets the DestinationSequence of the line for the destination w, if sequence numbers are enabled.
+func (l *ultraLogger) stampDestination(args LogLineArgs, w io.Writer) LogLineArgs {
+	if l.sequences != nil {
+		args.DestinationSequence = l.sequences.nextFor(w)
+	}
+	return args
+}
+
+// NewSequenceField returns a new Field that formats the sequence number of the line in the logger, with the key "seq".
+// Lines without one, i.e. of loggers without WithSequenceNumbers, don't have the field.
+func NewSequenceField() Field {
+	field, _ := NewLineArgsField(
+		"seq",
+		func(args LogLineArgs) (any, error) {
+			if args.Sequence == 0 {
+				return nil, nil
+			}
+			return args.Sequence, nil
+		},
+		WithHideKey(false),
+	)
+	return field
+}
+
+// NewDestinationSequenceField returns a new Field that formats the sequence number of the line in its destination,
+// with the key "dest_seq". Lines without one, i.e. of loggers without WithSequenceNumbers, don't have the field.
+func NewDestinationSequenceField() Field {
+	field, _ := NewLineArgsField(
+		"dest_seq",
+		func(args LogLineArgs) (any, error) {
+			if args.DestinationSequence == 0 {
+				return nil, nil
+			}
+			return args.DestinationSequence, nil
+		},
+		WithHideKey(false),
+	)
+	return field
+}
@@ -0,0 +1,49 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWithSequenceNumbers(t *testing.T) {
+	fields := []Field{NewMessageField(), NewSequenceField(), NewDestinationSequenceField()}
+	formatter, _ := NewFormatter(OutputFormatText, fields)
+
+	all, errs := &bytes.Buffer{}, &bytes.Buffer{}
+	logger, _ := NewLoggerWithOptions(
+		WithAsync(false),
+		WithSequenceNumbers(true),
+		WithDestination(all, formatter),
+		WithDestinationGroup("errors", &DestinationGroupSettings{
+			Destinations: []Destination{{Writer: errs, Formatter: formatter}},
+		}),
+		WithRoutes(RouteRule{
+			Match:  func(args LogLineArgs, data []any) bool { return args.Level >= Error },
+			Groups: []string{"errors"},
+		}),
+	)
+
+	logger.Info("one")
+	logger.Error("two")
+	logger.Info("three")
+	logger.Error("four")
+
+	if got, want := all.String(), "one seq=1 dest_seq=1\ntwo seq=2 dest_seq=2\nthree seq=3 dest_seq=3\nfour seq=4 dest_seq=4\n"; got != want {
+		t.Errorf("all = %q, want %q", got, want)
+	}
+	if got, want := errs.String(), "two seq=2 dest_seq=1\nfour seq=4 dest_seq=2\n"; got != want {
+		t.Errorf("errors = %q, want %q", got, want)
+	}
+}
+
+func TestWithSequenceNumbers_disabled(t *testing.T) {
+	buf := &bytes.Buffer{}
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField(), NewSequenceField(), NewDestinationSequenceField()})
+	logger, _ := NewLoggerWithOptions(WithAsync(false), WithDestination(buf, formatter))
+
+	logger.Info("one")
+
+	if got, want := buf.String(), "one\n"; got != want {
+		t.Errorf("line = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,270 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SeverityAnalyzerSettings are the settings of a SeverityAnalyzer.
+type SeverityAnalyzerSettings struct {
+	// Window is the width of the windows lines are counted in. Defaults to one minute.
+	Window time.Duration
+	// Baseline is the number of trailing windows the rate of a window is compared to. Defaults to 15.
+	Baseline int
+	// Factor is how many times the average count of the trailing windows the count of a window must exceed to be an
+	// anomaly. Defaults to 3.
+	Factor float64
+	// MinCount is the count below which a window is never an anomaly, so that a couple of errors after a quiet period
+	// don't raise one. Defaults to 10.
+	MinCount int
+	// Level is the lowest level of the lines whose rate is watched. Defaults to Error if nil; see LevelPtr.
+	Level *Level
+	// OnAnomaly, if set, is called with the anomalies. Otherwise, they are logged as Warn lines with an [*Anomaly],
+	// which the destination formatters need a NewAnomalyField to write.
+	OnAnomaly func(anomaly *Anomaly)
+}
+
+var defaultSeverityAnalyzerSettings = SeverityAnalyzerSettings{
+	Window:   time.Minute,
+	Baseline: 15,
+	Factor:   3,
+	MinCount: 10,
+	Level:    LevelPtr(Error),
+}
+
+func (s *SeverityAnalyzerSettings) mergeDefault() {
+	if s.Window <= 0 {
+		s.Window = defaultSeverityAnalyzerSettings.Window
+	}
+	if s.Baseline <= 0 {
+		s.Baseline = defaultSeverityAnalyzerSettings.Baseline
+	}
+	if s.Factor <= 0 {
+		s.Factor = defaultSeverityAnalyzerSettings.Factor
+	}
+	if s.MinCount <= 0 {
+		s.MinCount = defaultSeverityAnalyzerSettings.MinCount
+	}
+	if s.Level == nil {
+		s.Level = LevelPtr(*defaultSeverityAnalyzerSettings.Level)
+	}
+}
+
+// Anomaly is reported by a SeverityAnalyzer when the rate of the watched lines of a tag deviates from its baseline.
+type Anomaly struct {
+	// Tag is the tag of the lines.
+	Tag string
+	// Start is the start of the window.
+	Start time.Time
+	// Count is the number of watched lines of the tag in the window, when the anomaly was reported.
+	Count int
+	// Baseline is the average number of watched lines of the tag in the trailing windows.
+	Baseline float64
+	// Exemplar is the line that raised the anomaly. It's nil unless the logger has WithEntryIDs.
+	Exemplar *Exemplar
+}
+
+// String returns the anomaly as key=value pairs.
+func (a *Anomaly) String() string {
+	s := fmt.Sprintf("tag=%s count=%d baseline=%.1f", a.Tag, a.Count, a.Baseline)
+	if a.Exemplar != nil {
+		s += " exemplar=" + a.Exemplar.EntryID.String()
+	}
+	return s
+}
+
+// EventFields returns the anomaly fields.
+func (a *Anomaly) EventFields() map[string]any {
+	fields := map[string]any{
+		"tag":      a.Tag,
+		"start":    a.Start.Format(time.RFC3339),
+		"count":    a.Count,
+		"baseline": a.Baseline,
+	}
+	if a.Exemplar != nil {
+		fields["exemplar"] = a.Exemplar.EntryID.String()
+	}
+	return fields
+}
+
+// Exemplar links a count to one of the lines it counts, by EntryID, so dashboards can jump from an error rate spike to
+// a concrete line.
+type Exemplar struct {
+	EntryID EntryID
+	// Time is when the line was logged.
+	Time time.Time
+}
+
+// NewAnomalyField returns a new Field that formats the [*Anomaly] logged by a SeverityAnalyzer. See NewEventField.
+func NewAnomalyField() Field {
+	field, _ := NewEventField[*Anomaly]("anomaly")
+	return field
+}
+
+// SeverityBucket is the number of lines of a tag logged in a window, by level.
+type SeverityBucket struct {
+	Start  time.Time
+	Tag    string
+	Counts map[Level]int
+	// Exemplar is the last watched line of the tag logged in the window. It's nil if there is none, or the logger
+	// doesn't have WithEntryIDs.
+	Exemplar *Exemplar
+}
+
+// SeverityAnalyzer counts the lines of a logger by window, level and tag, and reports an Anomaly when the rate of
+// errors of a tag exceeds its trailing baseline by the configured factor. Add it to a logger with
+// WithSeverityAnalyzer. With WithEntryIDs, the counts and anomalies carry an Exemplar of the errors they count.
+type SeverityAnalyzer struct {
+	settings SeverityAnalyzerSettings
+	now      func() time.Time
+
+	mu       sync.Mutex
+	current  severityWindow
+	history  []severityWindow // The trailing windows, oldest first.
+	reported map[string]bool  // The tags reported in the current window.
+}
+
+// severityWindow is the number of lines logged in a window, by tag and level, and the exemplars of the watched lines
+// by tag.
+type severityWindow struct {
+	start     time.Time
+	counts    map[string]map[Level]int
+	exemplars map[string]Exemplar
+}
+
+func newSeverityWindow(start time.Time) severityWindow {
+	return severityWindow{start: start, counts: map[string]map[Level]int{}, exemplars: map[string]Exemplar{}}
+}
+
+// NewSeverityAnalyzer returns a SeverityAnalyzer with the settings.
+func NewSeverityAnalyzer(settings *SeverityAnalyzerSettings) *SeverityAnalyzer {
+	var s SeverityAnalyzerSettings
+	if settings != nil {
+		s = *settings
+	}
+	s.mergeDefault()
+
+	return &SeverityAnalyzer{settings: s, now: time.Now, reported: map[string]bool{}}
+}
+
+// WithSeverityAnalyzer counts the lines the logger dispatches with the analyzer. Anomalies are reported once the line
+// that raised them is dispatched; without an OnAnomaly callback, they are logged at the Warn level regardless of the
+// minimum level, unless the logger is silenced.
+func WithSeverityAnalyzer(analyzer *SeverityAnalyzer) LoggerOption {
+	return func(l *ultraLogger) error {
+		if analyzer == nil {
+			return &ErrorLoggerInitialization{err: fmt.Errorf("nil severity analyzer")}
+		}
+		l.analyzer = analyzer
+		return nil
+	}
+}
+
+// Histogram returns the counts of the trailing windows and the current one, oldest first, with a bucket per tag
+// logged in each window.
+func (a *SeverityAnalyzer) Histogram() []SeverityBucket {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.advanceLocked(a.now())
+
+	var buckets []SeverityBucket
+	for _, window := range append(a.history, a.current) {
+		for tag, counts := range window.counts {
+			bucket := SeverityBucket{Start: window.start, Tag: tag, Counts: make(map[Level]int, len(counts))}
+			for level, count := range counts {
+				bucket.Counts[level] = count
+			}
+			if exemplar, ok := window.exemplars[tag]; ok {
+				bucket.Exemplar = &exemplar
+			}
+			buckets = append(buckets, bucket)
+		}
+	}
+	return buckets
+}
+
+// observe counts a line, and returns the Anomaly it raises, if any.
+func (a *SeverityAnalyzer) observe(args LogLineArgs) *Anomaly {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	a.advanceLocked(now)
+
+	tag, level := args.Tag, args.Level
+	counts, ok := a.current.counts[tag]
+	if !ok {
+		counts = map[Level]int{}
+		a.current.counts[tag] = counts
+	}
+	counts[level]++
+
+	if level < *a.settings.Level {
+		return nil
+	}
+	var exemplar *Exemplar
+	if !args.EntryID.IsZero() {
+		exemplar = &Exemplar{EntryID: args.EntryID, Time: now}
+		a.current.exemplars[tag] = *exemplar
+	}
+	if a.reported[tag] || len(a.history) == 0 {
+		return nil
+	}
+
+	count := a.watchedLocked(a.current, tag)
+	var total int
+	for _, window := range a.history {
+		total += a.watchedLocked(window, tag)
+	}
+	baseline := float64(total) / float64(len(a.history))
+	if count < a.settings.MinCount || float64(count) <= a.settings.Factor*baseline {
+		return nil
+	}
+
+	a.reported[tag] = true
+	return &Anomaly{Tag: tag, Start: a.current.start, Count: count, Baseline: baseline, Exemplar: exemplar}
+}
+
+// watchedLocked returns the number of lines of the tag, at or above the watched level, in the window.
+func (a *SeverityAnalyzer) watchedLocked(window severityWindow, tag string) int {
+	var count int
+	for level, n := range window.counts[tag] {
+		if level >= *a.settings.Level {
+			count += n
+		}
+	}
+	return count
+}
+
+// advanceLocked moves the current window to the one holding now, keeping the trailing windows, including empty ones.
+func (a *SeverityAnalyzer) advanceLocked(now time.Time) {
+	start := now.Truncate(a.settings.Window)
+	if a.current.counts == nil {
+		a.current = newSeverityWindow(start)
+		return
+	}
+
+	for i := 0; a.current.start.Before(start) && i <= a.settings.Baseline; i++ {
+		a.history = append(a.history, a.current)
+		a.current = newSeverityWindow(a.current.start.Add(a.settings.Window))
+		clear(a.reported)
+	}
+	if a.current.start.Before(start) {
+		// Idle for longer than the baseline: every trailing window is empty.
+		a.current.start = start
+	}
+	if len(a.history) > a.settings.Baseline {
+		a.history = a.history[len(a.history)-a.settings.Baseline:]
+	}
+}
+
+// reportAnomaly reports the anomaly to the OnAnomaly callback of the analyzer, or logs it.
+func (l *ultraLogger) reportAnomaly(anomaly *Anomaly) {
+	if l.analyzer.settings.OnAnomaly != nil {
+		l.analyzer.settings.OnAnomaly(anomaly)
+		return
+	}
+	l.dispatch(LogLineArgs{Level: Warn, Tag: anomaly.Tag}, []any{"error rate anomaly", anomaly}, true)
+}
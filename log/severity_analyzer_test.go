@@ -0,0 +1,114 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSeverityAnalyzer(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	analyzer := NewSeverityAnalyzer(&SeverityAnalyzerSettings{Baseline: 2, Factor: 2, MinCount: 3})
+	analyzer.now = func() time.Time { return now }
+
+	buf := &bytes.Buffer{}
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewLevelField(nil), NewMessageField(), NewAnomalyField()})
+	logger, _ := NewLoggerWithOptions(
+		WithAsync(false),
+		WithMinLevel(Error),
+		WithTag("api"),
+		WithDestination(buf, formatter),
+		WithSeverityAnalyzer(analyzer),
+	)
+
+	// A baseline of two errors a minute.
+	for range 2 {
+		logger.Error("failed")
+		logger.Error("failed")
+		now = now.Add(time.Minute)
+	}
+	// Four errors don't exceed twice the baseline; the fifth does, and is reported once.
+	for range 6 {
+		logger.Error("failed")
+	}
+
+	var anomalies []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if strings.Contains(line, "anomaly") {
+			anomalies = append(anomalies, line)
+		}
+	}
+	want := "<WARN> error rate anomaly tag=api count=5 baseline=2.0"
+	if len(anomalies) != 1 || anomalies[0] != want {
+		t.Errorf("anomalies = %q, want [%q]", anomalies, want)
+	}
+
+	buckets := analyzer.Histogram()
+	if len(buckets) != 3 {
+		t.Fatalf("Histogram() = %+v, want 3 buckets", buckets)
+	}
+	if got := buckets[2].Counts; got[Error] != 6 || got[Warn] != 1 {
+		t.Errorf("Histogram() current counts = %v, want 6 Error and 1 Warn", got)
+	}
+}
+
+func TestSeverityAnalyzer_onAnomaly(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var reported []*Anomaly
+	analyzer := NewSeverityAnalyzer(&SeverityAnalyzerSettings{
+		MinCount:  2,
+		OnAnomaly: func(anomaly *Anomaly) { reported = append(reported, anomaly) },
+	})
+	analyzer.now = func() time.Time { return now }
+
+	logger, _ := NewLoggerWithOptions(WithAsync(false), WithSeverityAnalyzer(analyzer))
+
+	logger.Error("failed")
+	// No baseline yet.
+	logger.Error("failed")
+	// A quiet window, then more than three times the baseline.
+	now = now.Add(2 * time.Minute)
+	for range 5 {
+		logger.Error("failed")
+	}
+
+	if len(reported) != 1 || reported[0].Count != 4 || reported[0].Baseline != 1 {
+		t.Errorf("reported = %+v, want one anomaly of 4 lines over a baseline of 1", reported)
+	}
+}
+
+func TestSeverityAnalyzer_exemplars(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var reported *Anomaly
+	analyzer := NewSeverityAnalyzer(&SeverityAnalyzerSettings{
+		MinCount:  1,
+		OnAnomaly: func(anomaly *Anomaly) { reported = anomaly },
+	})
+	analyzer.now = func() time.Time { return now }
+
+	var ids []EntryID
+	logger, _ := NewLoggerWithOptions(
+		WithAsync(false),
+		WithEntryIDs(true),
+		WithEntryHook(func(args LogLineArgs, data []any) { ids = append(ids, args.EntryID) }),
+		WithSeverityAnalyzer(analyzer),
+	)
+
+	logger.Info("quiet")
+	now = now.Add(time.Minute)
+	logger.Error("failed")
+	logger.Info("still failing")
+
+	if reported == nil || reported.Exemplar == nil || reported.Exemplar.EntryID != ids[1] {
+		t.Fatalf("reported = %+v, want an anomaly with the exemplar %v", reported, ids[1])
+	}
+
+	buckets := analyzer.Histogram()
+	if got := buckets[len(buckets)-1].Exemplar; got == nil || got.EntryID != ids[1] || !got.Time.Equal(now) {
+		t.Errorf("Histogram() exemplar = %+v, want %v at %v", got, ids[1], now)
+	}
+	if got := buckets[0].Exemplar; got != nil {
+		t.Errorf("Histogram() exemplar of a window without errors = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,58 @@
+package log
+
+// SeverityProfile maps levels to the severity names and numbers of a target system, so exported severities match its
+// conventions. Select one on a level field with LevelFieldSettings.Profile, or on a formatter with
+// WithSeverityProfile.
+type SeverityProfile struct {
+	// Names are the severity names of the levels, written by level fields with LevelJSONString.
+	Names map[Level]string
+	// Numbers are the severity numbers of the levels, written by level fields with LevelJSONNumber.
+	Numbers map[Level]int
+}
+
+// SeverityProfiles are the built-in SeverityProfiles.
+var SeverityProfiles = struct {
+	// Syslog is the RFC 5424 syslog severities: debug (7), info (6), warning (4), err (3) and crit (2).
+	Syslog *SeverityProfile
+	// OTel is the OpenTelemetry SeverityNumbers and SeverityTexts: DEBUG (5), INFO (9), WARN (13), ERROR (17) and
+	// FATAL (21).
+	OTel *SeverityProfile
+	// Stackdriver is the Google Cloud Logging LogSeverities: DEBUG (100), INFO (200), WARNING (400), ERROR (500) and
+	// CRITICAL (600).
+	Stackdriver *SeverityProfile
+	// Python is the levels of Python's logging module: DEBUG (10), INFO (20), WARNING (30), ERROR (40) and
+	// CRITICAL (50).
+	Python *SeverityProfile
+}{
+	Syslog: &SeverityProfile{
+		Names:   map[Level]string{Debug: "debug", Info: "info", Warn: "warning", Error: "err", Panic: "crit"},
+		Numbers: map[Level]int{Debug: 7, Info: 6, Warn: 4, Error: 3, Panic: 2},
+	},
+	OTel: &SeverityProfile{
+		Names:   map[Level]string{Debug: "DEBUG", Info: "INFO", Warn: "WARN", Error: "ERROR", Panic: "FATAL"},
+		Numbers: map[Level]int{Debug: 5, Info: 9, Warn: 13, Error: 17, Panic: 21},
+	},
+	Stackdriver: &SeverityProfile{
+		Names:   map[Level]string{Debug: "DEBUG", Info: "INFO", Warn: "WARNING", Error: "ERROR", Panic: "CRITICAL"},
+		Numbers: map[Level]int{Debug: 100, Info: 200, Warn: 400, Error: 500, Panic: 600},
+	},
+	Python: &SeverityProfile{
+		Names:   map[Level]string{Debug: "DEBUG", Info: "INFO", Warn: "WARNING", Error: "ERROR", Panic: "CRITICAL"},
+		Numbers: map[Level]int{Debug: 10, Info: 20, Warn: 30, Error: 40, Panic: 50},
+	},
+}
+
+// WithSeverityProfile sets the SeverityProfile of the level fields of the formatter that don't set their own, e.g. so
+// that a destination feeding Cloud Logging writes its severities. Like LevelFieldSettings.Profile, it applies to
+// JSON and logfmt output; text output keeps the level strings of the fields.
+func WithSeverityProfile(profile *SeverityProfile) FormatterOption {
+	return func(f LogLineFormatter) LogLineFormatter {
+		if tf, ok := unwrapFormatter[*textFormatter](f); ok {
+			tf.SeverityProfile = profile
+		}
+		if jf, ok := unwrapFormatter[*jsonFormatter](f); ok {
+			jf.SeverityProfile = profile
+		}
+		return f
+	}
+}
@@ -0,0 +1,116 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ShutdownComponent is a component stopped by ShutdownLogger, e.g. an HTTP server or a queue consumer.
+type ShutdownComponent struct {
+	// Name is the name of the component in the logged lines.
+	Name string
+	// Shutdown stops the component gracefully, within the deadline of the context.
+	Shutdown func(ctx context.Context) error
+}
+
+// ShutdownPhase is the phase of a ShutdownEvent.
+type ShutdownPhase string
+
+const (
+	ShutdownPhaseStart  ShutdownPhase = "start"
+	ShutdownPhaseFinish ShutdownPhase = "finish"
+)
+
+// ShutdownEvent describes the shutdown of a component by ShutdownLogger.
+type ShutdownEvent struct {
+	Component string
+	Phase     ShutdownPhase
+	// Duration is the time the component took to shut down. Zero for the start phase.
+	Duration time.Duration
+	// Err is the error returned by the component, or the recovered panic value wrapped in an error.
+	Err error
+}
+
+// String returns the event as space separated key=value pairs.
+func (e *ShutdownEvent) String() string {
+	parts := []string{
+		"component=" + e.Component,
+		"phase=" + string(e.Phase),
+	}
+	if e.Phase == ShutdownPhaseFinish {
+		parts = append(parts, "duration="+e.Duration.String())
+	}
+	if e.Err != nil {
+		parts = append(parts, fmt.Sprintf("err=%q", e.Err.Error()))
+	}
+	return strings.Join(parts, " ")
+}
+
+// EventFields returns the component, phase, duration (in nanoseconds), and error of the event. The duration is
+// omitted for the start phase, and the error if there is none.
+func (e *ShutdownEvent) EventFields() map[string]any {
+	fields := map[string]any{
+		"component": e.Component,
+		"phase":     e.Phase,
+	}
+	if e.Phase == ShutdownPhaseFinish {
+		fields["duration"] = e.Duration
+	}
+	if e.Err != nil {
+		fields["error"] = e.Err.Error()
+	}
+	return fields
+}
+
+// NewShutdownEventField returns a new Field that formats the [*ShutdownEvent] logged by ShutdownLogger. See
+// NewEventField.
+func NewShutdownEventField(name string) (Field, error) {
+	return NewEventField[*ShutdownEvent](name)
+}
+
+// ShutdownLogger shuts the components down in order, logging the start of each one at the Info level, and its finish
+// with its duration at the Info level, or at the Error level if it failed. The logger is flushed once every component
+// has shut down, so the lines are written before the process exits. It returns the errors of the components, joined.
+//
+// Lines are logged with the message "shutting down" or "shut down", and a [*ShutdownEvent]; the logger's formatters
+// need a NewShutdownEventField to write it.
+func ShutdownLogger(ctx context.Context, logger Logger, components ...ShutdownComponent) error {
+	var errs []error
+	for _, component := range components {
+		logger.Info("shutting down", &ShutdownEvent{Component: component.Name, Phase: ShutdownPhaseStart})
+
+		start := time.Now()
+		err := shutdownComponent(ctx, component)
+		event := &ShutdownEvent{
+			Component: component.Name,
+			Phase:     ShutdownPhaseFinish,
+			Duration:  time.Since(start),
+			Err:       err,
+		}
+
+		if err != nil {
+			logger.Error("shut down", event)
+			errs = append(errs, fmt.Errorf("%s: %w", component.Name, err))
+			continue
+		}
+		logger.Info("shut down", event)
+	}
+
+	logger.Flush()
+
+	return errors.Join(errs...)
+}
+
+// shutdownComponent shuts the component down, recovering from panics.
+func shutdownComponent(ctx context.Context, component ShutdownComponent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return component.Shutdown(ctx)
+}
@@ -0,0 +1,65 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShutdownLogger(t *testing.T) {
+	dest := &toggleWriter{}
+	eventField, _ := NewShutdownEventField("shutdown")
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewDefaultLevelField(), NewMessageField(), eventField})
+	logger, _ := NewLoggerWithOptions(
+		WithAsync(false),
+		WithWriteCoalescing(&CoalescingSettings{Window: time.Hour}),
+		WithDestination(dest, formatter),
+	)
+
+	dbErr := errors.New("connection busy")
+	var order []string
+	err := ShutdownLogger(
+		context.Background(),
+		logger,
+		ShutdownComponent{Name: "http", Shutdown: func(context.Context) error {
+			order = append(order, "http")
+			return nil
+		}},
+		ShutdownComponent{Name: "db", Shutdown: func(context.Context) error {
+			order = append(order, "db")
+			return dbErr
+		}},
+		ShutdownComponent{Name: "queue", Shutdown: func(context.Context) error {
+			panic("boom")
+		}},
+	)
+
+	if !errors.Is(err, dbErr) || !strings.Contains(err.Error(), "queue: panic: boom") {
+		t.Errorf("ShutdownLogger() error = %v, want the db and queue errors", err)
+	}
+	if strings.Join(order, ",") != "http,db" {
+		t.Errorf("components shut down in order %v", order)
+	}
+
+	// The lines are coalesced; ShutdownLogger must have flushed them.
+	lines := strings.SplitAfter(strings.Join(dest.received(), ""), "\n")
+	lines = lines[:len(lines)-1]
+	if len(lines) != 6 {
+		t.Fatalf("got %d lines, want 6: %q", len(lines), lines)
+	}
+	for i, want := range []string{
+		"<INFO> shutting down component=http phase=start\n",
+		"<INFO> shut down component=http phase=finish duration=",
+		"<INFO> shutting down component=db phase=start\n",
+		`<ERROR> shut down component=db phase=finish duration=`,
+	} {
+		if !strings.HasPrefix(lines[i], want) {
+			t.Errorf("line %d = %q, want prefix %q", i, lines[i], want)
+		}
+	}
+	if !strings.HasSuffix(lines[3], ` err="connection busy"`+"\n") {
+		t.Errorf("line 3 = %q, want the error", lines[3])
+	}
+}
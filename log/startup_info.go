@@ -0,0 +1,111 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strings"
+)
+
+// StartupInfo describes the effective configuration of a logger, and the process it runs in. It's logged by
+// LogStartupInfo; the destination formatters need a NewStartupInfoField to write it.
+type StartupInfo struct {
+	// MinLevel is the minimum level of the logger.
+	MinLevel string
+	// Destinations describes the destinations of the logger, including the destinations of its groups, and the
+	// format each of them is written in.
+	Destinations []string
+	// Version is the version of the main module of the binary, "(devel)" for builds outside of a module.
+	Version string
+	// GoVersion is the version of Go the binary was built with.
+	GoVersion string
+	// Host is the host name of the machine.
+	Host string
+	// PID is the process ID.
+	PID int
+}
+
+// String returns the startup info as key=value pairs.
+func (s *StartupInfo) String() string {
+	return fmt.Sprintf(
+		"level=%s destinations=[%s] version=%s go=%s host=%s pid=%d",
+		s.MinLevel,
+		strings.Join(s.Destinations, ", "),
+		s.Version,
+		s.GoVersion,
+		s.Host,
+		s.PID,
+	)
+}
+
+// EventFields returns the startup info fields.
+func (s *StartupInfo) EventFields() map[string]any {
+	return map[string]any{
+		"level":        s.MinLevel,
+		"destinations": s.Destinations,
+		"version":      s.Version,
+		"go":           s.GoVersion,
+		"host":         s.Host,
+		"pid":          s.PID,
+	}
+}
+
+// NewStartupInfoField returns a new Field that formats the [*StartupInfo] logged by LogStartupInfo. See
+// NewEventField.
+func NewStartupInfoField() Field {
+	field, _ := NewEventField[*StartupInfo]("startup")
+	return field
+}
+
+// LogStartupInfo logs a single Info line with the message "startup" and a [*StartupInfo] describing the effective
+// configuration of the logger and the process.
+func (l *ultraLogger) LogStartupInfo() {
+	l.Log(Info, "startup", l.startupInfo())
+}
+
+func (l *ultraLogger) startupInfo() *StartupInfo {
+	info := &StartupInfo{
+		MinLevel:  l.minLevel.String(),
+		GoVersion: runtime.Version(),
+		PID:       os.Getpid(),
+	}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		info.Version = build.Main.Version
+	}
+	info.Host, _ = os.Hostname()
+
+	set := l.loadDestinations()
+	for w, f := range set.destinations {
+		if f != nil {
+			info.Destinations = append(info.Destinations, describeDestination(set.origin(w), f))
+		}
+	}
+	for _, group := range append(slices.Clip(set.groups), set.namedGroups...) {
+		for _, destination := range group.destinations {
+			info.Destinations = append(info.Destinations, describeDestination(destination.Writer, destination.Formatter))
+		}
+	}
+	slices.Sort(info.Destinations)
+
+	return info
+}
+
+// describeDestination returns a short, human-readable description of a destination and its format.
+func describeDestination(w io.Writer, f LogLineFormatter) string {
+	format := fmt.Sprintf("%T", f)
+	if jf, ok := unwrapFormatter[*jsonFormatter](f); ok {
+		format = string(OutputFormatJSON)
+		if jf.Output != "" {
+			format = string(jf.Output)
+		}
+	} else if tf, ok := unwrapFormatter[*textFormatter](f); ok {
+		format = string(OutputFormatText)
+		if tf.Logfmt {
+			format = string(OutputFormatLogfmt)
+		}
+	}
+	return describeWriter(w) + " " + format
+}
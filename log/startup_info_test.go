@@ -0,0 +1,50 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLogger_LogStartupInfo(t *testing.T) {
+	buf := &bytes.Buffer{}
+	formatter, _ := NewFormatter(OutputFormatJSON, []Field{NewMessageField(), NewStartupInfoField()})
+	logger, err := NewLoggerWithOptions(WithAsync(false), WithMinLevel(Debug), WithDestination(buf, formatter))
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	logger.LogStartupInfo()
+
+	var line struct {
+		Message string `json:"message"`
+		Startup struct {
+			Level        string   `json:"level"`
+			Destinations []string `json:"destinations"`
+			PID          int      `json:"pid"`
+		} `json:"startup"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("Unmarshal(%q) error = %v", buf.String(), err)
+	}
+	if line.Message != "startup" || line.Startup.Level != "DEBUG" || line.Startup.PID != os.Getpid() {
+		t.Errorf("got %q", buf.String())
+	}
+	if got := line.Startup.Destinations; len(got) != 1 || got[0] != "*bytes.Buffer json" {
+		t.Errorf("destinations = %q, want the JSON buffer", got)
+	}
+}
+
+func TestLogger_LogStartupInfo_text(t *testing.T) {
+	buf := &bytes.Buffer{}
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField(), NewStartupInfoField()})
+	logger, _ := NewLoggerWithOptions(WithAsync(false), WithDestination(buf, formatter))
+
+	logger.LogStartupInfo()
+
+	if got := buf.String(); !strings.HasPrefix(got, "startup level=INFO destinations=[*bytes.Buffer text] version=") {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,147 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// recentErrorsCapacity is the number of internal errors a logger remembers for inspection.
+const recentErrorsCapacity = 32
+
+// LoggerStats are the internal counters of a logger.
+type LoggerStats struct {
+	// Lines is the number of lines successfully written, summed over all destinations.
+	Lines uint64 `json:"lines"`
+	// Dropped is the number of lines that were dropped, e.g. because an async write timed out.
+	Dropped uint64 `json:"dropped"`
+	// Errors is the number of internal errors, e.g. formatting or write failures.
+	Errors uint64 `json:"errors"`
+}
+
+// InternalErrorRecord is an internal error of a logger, along with the time it occurred.
+type InternalErrorRecord struct {
+	Time  time.Time `json:"time"`
+	Error string    `json:"error"`
+}
+
+// DestinationSnapshot describes a destination of a logger.
+type DestinationSnapshot struct {
+	// Writer describes the destination writer.
+	Writer string `json:"writer"`
+	// Formatter describes the formatter of the destination.
+	Formatter string `json:"formatter"`
+	// Healthy is false if the destination has been disabled after a write error.
+	Healthy bool `json:"healthy"`
+	// Delivery is the delivery mode of the destination.
+	Delivery string `json:"delivery"`
+}
+
+// LoggerSnapshot is a point-in-time view of the configuration and health of a logger.
+type LoggerSnapshot struct {
+	MinLevel     string                `json:"minLevel"`
+	BoostedLevel string                `json:"boostedLevel,omitempty"` // Lowest level of the active BoostLevel boosts.
+	Tag          string                `json:"tag"`
+	Silent       bool                  `json:"silent"`
+	Async        bool                  `json:"async"`
+	Fallback     bool                  `json:"fallback"`
+	Destinations []DestinationSnapshot `json:"destinations"`
+	Stats        LoggerStats           `json:"stats"`
+	Queue        AsyncQueueStats       `json:"queue"`
+	RecentErrors []InternalErrorRecord `json:"recentErrors"`
+}
+
+// Inspector is implemented by loggers that expose their internals for debugging and monitoring. Loggers returned by
+// this package implement it.
+type Inspector interface {
+	// Inspect returns a snapshot of the logger's configuration, destination health, and internal counters.
+	Inspect() LoggerSnapshot
+}
+
+// loggerStats holds the internal counters of a logger. It is safe for concurrent use.
+type loggerStats struct {
+	lines   atomic.Uint64
+	dropped atomic.Uint64
+	errors  atomic.Uint64
+
+	mu     sync.Mutex
+	recent []InternalErrorRecord
+	next   int
+}
+
+func (s *loggerStats) recordError(err error) {
+	s.errors.Add(1)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record := InternalErrorRecord{Time: time.Now(), Error: err.Error()}
+	if len(s.recent) < recentErrorsCapacity {
+		s.recent = append(s.recent, record)
+		return
+	}
+	s.recent[s.next] = record
+	s.next = (s.next + 1) % recentErrorsCapacity
+}
+
+func (s *loggerStats) snapshot() LoggerStats {
+	return LoggerStats{
+		Lines:   s.lines.Load(),
+		Dropped: s.dropped.Load(),
+		Errors:  s.errors.Load(),
+	}
+}
+
+// recentErrors returns the remembered internal errors, oldest first.
+func (s *loggerStats) recentErrors() []InternalErrorRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records := make([]InternalErrorRecord, 0, len(s.recent))
+	records = append(records, s.recent[s.next:]...)
+	records = append(records, s.recent[:s.next]...)
+
+	return records
+}
+
+// Inspect returns a snapshot of the logger's configuration, destination health, and internal counters.
+func (l *ultraLogger) Inspect() LoggerSnapshot {
+	set := l.loadDestinations()
+	destinations := make([]DestinationSnapshot, 0, len(set.destinations))
+	for w, f := range set.destinations {
+		destinations = append(destinations, DestinationSnapshot{
+			Writer:    describeWriter(set.origin(w)),
+			Formatter: fmt.Sprintf("%T", f),
+			Healthy:   f != nil,
+			Delivery:  set.deliveryMode(w).String(),
+		})
+	}
+
+	boostedLevel := ""
+	if level, ok := l.boosts.boosted(); ok {
+		boostedLevel = level.String()
+	}
+
+	return LoggerSnapshot{
+		MinLevel:     l.minLevel.String(),
+		BoostedLevel: boostedLevel,
+		Tag:          l.tag,
+		Silent:       l.silent,
+		Async:        l.async,
+		Fallback:     l.fallback,
+		Destinations: destinations,
+		Stats:        l.stats.snapshot(),
+		Queue:        l.queue.stats(),
+		RecentErrors: l.stats.recentErrors(),
+	}
+}
+
+// describeWriter returns a short, human-readable description of a destination writer.
+func describeWriter(w io.Writer) string {
+	if named, ok := w.(interface{ Name() string }); ok {
+		return fmt.Sprintf("%T(%s)", w, named.Name())
+	}
+	return fmt.Sprintf("%T", w)
+}
@@ -0,0 +1,163 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// tailFilePollInterval is how often TailFile checks the file for new lines.
+const tailFilePollInterval = 250 * time.Millisecond
+
+// tailFileChunkSize is the size of the chunks TailFile reads the end of the file in.
+const tailFileChunkSize = 64 * 1024
+
+// TailFile returns a channel of the last n lines of the log file at path, followed by the lines appended to it, like
+// tail -F, until ctx is done; the channel is then closed. The file is polled for new lines. If the file is truncated,
+// it's followed from its start; if it's replaced, e.g. rotated, or the path is a symlink switched to another file, the
+// new file is followed from its start.
+func TailFile(ctx context.Context, path string, n int) (<-chan string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	recent, offset, err := lastLines(file, n)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	lines := make(chan string, len(recent)+tailBufferSize)
+	for _, line := range recent {
+		lines <- line
+	}
+
+	follower := &fileFollower{path: path, file: file, offset: offset, lines: lines}
+	go follower.follow(ctx)
+
+	return lines, nil
+}
+
+// lastLines returns the last n complete lines of the file, and the offset of the end of the last complete line.
+func lastLines(file *os.File, n int) ([]string, int64, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Read chunks from the end until they hold n complete lines, i.e. n+1 newlines, or the start of the file.
+	end := info.Size()
+	var tail []byte
+	for start := end; start > 0 && bytes.Count(tail, []byte{'\n'}) <= n; {
+		start = max(start-tailFileChunkSize, 0)
+		chunk := make([]byte, end-start-int64(len(tail)))
+		if _, err := file.ReadAt(chunk, start); err != nil && err != io.EOF {
+			return nil, 0, err
+		}
+		tail = append(chunk, tail...)
+	}
+
+	// A partial last line is followed, not returned.
+	complete := bytes.LastIndexByte(tail, '\n') + 1
+	offset := end - int64(len(tail)-complete)
+
+	var lines []string
+	for _, line := range bytes.Split(tail[:complete], []byte{'\n'}) {
+		lines = append(lines, string(line))
+	}
+	lines = lines[:len(lines)-1] // The empty string after the last newline.
+	if int64(len(tail)) < end {
+		lines = lines[1:] // The first line may be partial.
+	}
+	if n <= 0 {
+		return nil, offset, nil
+	}
+	return lines[max(len(lines)-n, 0):], offset, nil
+}
+
+// fileFollower follows a file for TailFile.
+type fileFollower struct {
+	path    string
+	file    *os.File
+	offset  int64
+	partial []byte // The start of a line whose end hasn't been written yet.
+	lines   chan string
+}
+
+func (f *fileFollower) follow(ctx context.Context) {
+	defer close(f.lines)
+	defer func() { _ = f.file.Close() }()
+
+	ticker := time.NewTicker(tailFilePollInterval)
+	defer ticker.Stop()
+
+	for {
+		if !f.poll(ctx) {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll sends the lines appended to the file since the last poll, and switches to the new file at path if it was
+// replaced. It returns false once ctx is done.
+func (f *fileFollower) poll(ctx context.Context) bool {
+	if info, err := f.file.Stat(); err == nil && info.Size() < f.offset {
+		// Truncated.
+		f.offset, f.partial = 0, nil
+	}
+	if !f.readNew(ctx) {
+		return false
+	}
+
+	current, err := f.file.Stat()
+	if err != nil {
+		return true
+	}
+	if info, err := os.Stat(f.path); err == nil && !os.SameFile(current, info) {
+		file, err := os.Open(f.path)
+		if err != nil {
+			return true
+		}
+		_ = f.file.Close()
+		f.file, f.offset, f.partial = file, 0, nil
+		return f.readNew(ctx)
+	}
+	return true
+}
+
+// readNew sends the complete lines written to the file after the offset.
+func (f *fileFollower) readNew(ctx context.Context) bool {
+	buf := make([]byte, tailFileChunkSize)
+	for {
+		n, err := f.file.ReadAt(buf, f.offset)
+		f.offset += int64(n)
+		data := append(f.partial, buf[:n]...)
+
+		for {
+			line, rest, found := bytes.Cut(data, []byte{'\n'})
+			if !found {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return false
+			case f.lines <- string(line):
+			}
+			data = rest
+		}
+		f.partial = bytes.Clone(data)
+
+		if n < len(buf) || err != nil {
+			return true
+		}
+	}
+}
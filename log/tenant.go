@@ -0,0 +1,163 @@
+package log
+
+import "slices"
+
+// TenantID identifies the tenant a line is logged for. Loggers returned by ForTenant add it to the data of every
+// line they log; NewTenantField writes it, TenantRoute routes on it, and TenantMiddleware applies per-tenant policies.
+type TenantID string
+
+// NewTenantField returns a new Field that formats the TenantID of lines logged by the loggers returned by ForTenant,
+// with the key "tenant_id". Lines without a tenant don't have the field.
+func NewTenantField() Field {
+	field, _ := NewObjectField[TenantID](
+		"tenant_id",
+		func(args LogLineArgs, id TenantID) (any, error) {
+			return string(id), nil
+		},
+	)
+	return field
+}
+
+// tenantOf returns the tenant of the line, if it has one.
+func tenantOf(data []any) (TenantID, bool) {
+	for _, datum := range slices.Backward(data) {
+		if id, ok := datum.(TenantID); ok {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// TenantRoute returns a RouteRule that routes the lines of the tenant to the destination groups, e.g. to give every
+// tenant its own destinations. See WithRoutes.
+func TenantRoute(id string, groups ...string) RouteRule {
+	return RouteRule{
+		Match: func(args LogLineArgs, data []any) bool {
+			tenant, ok := tenantOf(data)
+			return ok && tenant == TenantID(id)
+		},
+		Groups: groups,
+	}
+}
+
+// TenantMiddleware returns a FormatterMiddleware that applies the middleware of the tenant of each line, e.g. a
+// RedactMiddleware for tenants with stricter redaction policies. Lines of other tenants, and lines without a tenant, are
+// formatted by the wrapped formatter as is.
+func TenantMiddleware(policies map[string]FormatterMiddleware) FormatterMiddleware {
+	return func(next LogLineFormatter) LogLineFormatter {
+		tenants := make(map[TenantID]LogLineFormatter, len(policies))
+		for id, middleware := range policies {
+			tenants[TenantID(id)] = middleware(next)
+		}
+		return &tenantFormatter{BaseFormatter: next, tenants: tenants}
+	}
+}
+
+// tenantFormatter formats lines with the formatter of their tenant.
+type tenantFormatter struct {
+	BaseFormatter LogLineFormatter
+	tenants       map[TenantID]LogLineFormatter
+}
+
+// FormatLogLine formats the log line using the provided data and returns a FormatResult which contains the formatted
+// log line and any errors that may have occurred.
+func (f *tenantFormatter) FormatLogLine(args LogLineArgs, data []any) FormatResult {
+	if id, ok := tenantOf(data); ok {
+		if tenant, ok := f.tenants[id]; ok {
+			return tenant.FormatLogLine(args, data)
+		}
+	}
+	return f.BaseFormatter.FormatLogLine(args, data)
+}
+
+// Unwrap returns the base formatter.
+func (f *tenantFormatter) Unwrap() LogLineFormatter {
+	return f.BaseFormatter
+}
+
+// ForTenant returns a logger that adds the TenantID to the data of every line it logs. It shares the configuration,
+// destinations, and level of the logger.
+func (l *ultraLogger) ForTenant(id string) Logger {
+	return &tenantLogger{ultraLogger: l, tenant: TenantID(id)}
+}
+
+// tenantLogger is the Logger returned by ForTenant.
+type tenantLogger struct {
+	*ultraLogger
+	tenant TenantID
+}
+
+// Log logs a message with the given level and message, and the tenant.
+func (t *tenantLogger) Log(level Level, data ...any) {
+	t.ultraLogger.Log(level, append(slices.Clip(data), t.tenant)...)
+}
+
+// Freeze returns a view of the logger that can't change its configuration. See Logger.Freeze.
+func (t *tenantLogger) Freeze() Logger {
+	return &frozenLogger{Logger: t}
+}
+
+// LogEntry logs the entry, and the tenant.
+func (t *tenantLogger) LogEntry(entry Entry) {
+	t.ultraLogger.LogEntry(entry.WithData(t.tenant))
+}
+
+// Debug logs a message with the Debug level and message.
+func (t *tenantLogger) Debug(data ...any) {
+	t.Log(Debug, data...)
+}
+
+// Info logs a message with the Info level and message.
+func (t *tenantLogger) Info(data ...any) {
+	t.Log(Info, data...)
+}
+
+// Warn logs a message with the Warn level and message.
+func (t *tenantLogger) Warn(data ...any) {
+	t.Log(Warn, data...)
+}
+
+// Error logs a message with the Error level and message.
+func (t *tenantLogger) Error(data ...any) {
+	t.Log(Error, data...)
+}
+
+// Panic logs a message with the Panic level and message. If panicOnPanicLevel is true, it panics.
+func (t *tenantLogger) Panic(data ...any) {
+	t.Log(Panic, data...)
+
+	if t.panicOnPanicLevel {
+		panic(data)
+	}
+}
+
+// LogMsg logs a message string with the given level, and the tenant. Unlike the LogMsg of the parent logger, it
+// allocates.
+func (t *tenantLogger) LogMsg(level Level, msg string) {
+	t.Log(level, msg)
+}
+
+// DebugMsg logs a message string with the Debug level.
+func (t *tenantLogger) DebugMsg(msg string) {
+	t.LogMsg(Debug, msg)
+}
+
+// InfoMsg logs a message string with the Info level.
+func (t *tenantLogger) InfoMsg(msg string) {
+	t.LogMsg(Info, msg)
+}
+
+// WarnMsg logs a message string with the Warn level.
+func (t *tenantLogger) WarnMsg(msg string) {
+	t.LogMsg(Warn, msg)
+}
+
+// ErrorMsg logs a message string with the Error level.
+func (t *tenantLogger) ErrorMsg(msg string) {
+	t.LogMsg(Error, msg)
+}
+
+// LogStartupInfo logs the startup info of the logger, and the tenant.
+func (t *tenantLogger) LogStartupInfo() {
+	t.Log(Info, "startup", t.startupInfo())
+}
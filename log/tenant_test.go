@@ -0,0 +1,47 @@
+package log
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestLogger_ForTenant(t *testing.T) {
+	console := &toggleWriter{}
+	acme := &toggleWriter{}
+	formatter, _ := NewFormatter(
+		OutputFormatText,
+		[]Field{NewMessageField(), NewTenantField()},
+		WithMiddleware(TenantMiddleware(map[string]FormatterMiddleware{
+			"acme": RedactMiddleware(regexp.MustCompile(`[a-z]+@[a-z.]+`)),
+		})),
+	)
+
+	logger, err := NewLoggerWithOptions(
+		WithAsync(false),
+		WithDestination(console, formatter),
+		WithDestinationGroup("acme", &DestinationGroupSettings{
+			Destinations: []Destination{{Writer: acme, Formatter: formatter}},
+		}),
+		WithRoutes(TenantRoute("acme", "acme")),
+	)
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	logger.Info("signed up: bob@example.com")
+	logger.ForTenant("acme").Info("signed up: ann@acme.com")
+	logger.ForTenant("globex").InfoMsg("signed up: joe@globex.com")
+
+	want := []string{
+		"signed up: bob@example.com\n",
+		"signed up: [REDACTED] tenant_id=acme\n",
+		"signed up: joe@globex.com tenant_id=globex\n",
+	}
+	if got := console.received(); !slices.Equal(got, want) {
+		t.Errorf("console received %q, want %q", got, want)
+	}
+	if got := acme.received(); !slices.Equal(got, want[1:2]) {
+		t.Errorf("acme destination received %q, want only the lines of the tenant", got)
+	}
+}
@@ -0,0 +1,148 @@
+package log
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TokenPrefix prefixes the tokens of a Tokenizer, so tokenized values are recognizable in the logs.
+const TokenPrefix = "tok_"
+
+// Tokenizer replaces the values of identifier fields, e.g. email or user_id, with stable keyed hashes (HMAC-SHA256).
+// The same value always has the same token under the same key, so lines stay correlatable, but the value can't be
+// recovered from the token. Rotating the key "erases" the identifiers of the lines logged under the previous key: once
+// it's discarded, their tokens can no longer be matched to anyone.
+type Tokenizer struct {
+	key    []byte
+	fields []string
+}
+
+// NewTokenizer returns a Tokenizer that tokenizes the fields written under the given keys with the key. Keys of values
+// nested in objects are dotted paths, e.g. "user.email" for the email of a composite, event or map field named user.
+func NewTokenizer(key []byte, fields ...string) (*Tokenizer, error) {
+	if len(key) == 0 {
+		return nil, ErrorTokenizerKeyNotSpecified
+	}
+	return &Tokenizer{key: slices.Clone(key), fields: slices.Clone(fields)}, nil
+}
+
+// Token returns the token of the value: TokenPrefix followed by the hex-encoded first 16 bytes of its HMAC.
+func (t *Tokenizer) Token(value string) string {
+	mac := hmac.New(sha256.New, t.key)
+	mac.Write([]byte(value))
+	return TokenPrefix + hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// Middleware returns a FormatterMiddleware that tokenizes the fields of the Tokenizer in the lines of the formatter it
+// wraps. The values are tokenized as they are formatted, e.g. an int user ID as its digits. Nil values are left as is.
+//
+// Nested values are found in objects, like the values of composite fields, maps, structs and the EventFields of events
+// in JSON output, and in the elements of slices, e.g. "users.email" tokenizes the email of every user. If a key
+// matches an object, every value nested in it is tokenized. Values written as strings, like events in text output,
+// can't be walked: tokenize the whole field instead.
+//
+// The wrapped formatter must be a RecordFormatter, like the formatters returned by NewFormatter; other formatters fail
+// every line with ErrorTokenizerUnsupportedFormatter, rather than writing identifiers in the clear.
+func (t *Tokenizer) Middleware() FormatterMiddleware {
+	return func(next LogLineFormatter) LogLineFormatter {
+		return &tokenizingFormatter{BaseFormatter: next, tokenizer: t}
+	}
+}
+
+// tokenizingFormatter tokenizes the identifier fields of the Records of the base formatter.
+type tokenizingFormatter struct {
+	BaseFormatter LogLineFormatter
+	tokenizer     *Tokenizer
+}
+
+// FormatLogLine formats the log line using the provided data and returns a FormatResult which contains the formatted
+// log line and any errors that may have occurred.
+func (f *tokenizingFormatter) FormatLogLine(args LogLineArgs, data []any) FormatResult {
+	recordFormatter, ok := f.BaseFormatter.(RecordFormatter)
+	if !ok {
+		return FormatResult{nil, ErrorTokenizerUnsupportedFormatter}
+	}
+
+	record, err := recordFormatter.BuildRecord(args, data)
+	if err != nil {
+		return FormatResult{nil, err}
+	}
+
+	for i, field := range record.Fields {
+		record.Fields[i].Value = f.tokenizer.tokenize(field.Key, field.Value, false)
+	}
+
+	line, err := recordFormatter.Encode(record)
+	return FormatResult{line, err}
+}
+
+// Unwrap returns the base formatter.
+func (f *tokenizingFormatter) Unwrap() LogLineFormatter {
+	return f.BaseFormatter
+}
+
+// tokenize returns the value written under the dotted path, with the values of the fields of the Tokenizer nested in
+// it tokenized. Every value nested in a matched value is tokenized.
+func (t *Tokenizer) tokenize(path string, value any, matched bool) any {
+	if value == nil {
+		return nil
+	}
+	matched = matched || slices.Contains(t.fields, path)
+	if !matched && !t.hasFieldsUnder(path) {
+		return value
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		object := make(map[string]any, len(value))
+		for key, v := range value {
+			object[key] = t.tokenize(path+"."+key, v, matched)
+		}
+		return object
+	case *compositeValue:
+		composite := &compositeValue{names: value.names, settings: value.settings, values: make([]any, len(value.values))}
+		for i, v := range value.values {
+			composite.values[i] = t.tokenize(path+"."+value.names[i], v, matched)
+		}
+		return composite
+	case []any:
+		elements := make([]any, len(value))
+		for i, v := range value {
+			elements[i] = t.tokenize(path, v, matched)
+		}
+		return elements
+	case string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64,
+		time.Time, time.Duration, fmt.Stringer, error:
+		if matched {
+			return t.Token(fmt.Sprint(value))
+		}
+		return value
+	}
+
+	// Structs, typed maps and slices are walked as the JSON they are written as.
+	b, err := json.Marshal(value)
+	if err != nil {
+		if matched {
+			return t.Token(fmt.Sprint(value))
+		}
+		return value
+	}
+	var decoded any
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return value
+	}
+	return t.tokenize(path, decoded, matched)
+}
+
+// hasFieldsUnder reports whether any field of the Tokenizer is nested under the path.
+func (t *Tokenizer) hasFieldsUnder(path string) bool {
+	return slices.ContainsFunc(t.fields, func(field string) bool {
+		return strings.HasPrefix(field, path+".")
+	})
+}
@@ -0,0 +1,156 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenizer_Middleware(t *testing.T) {
+	emailField, _ := NewObjectField[testEmail](
+		"email",
+		func(args LogLineArgs, email testEmail) (any, error) { return string(email), nil },
+	)
+	fields := []Field{NewDefaultLevelField(), NewMessageField(), emailField}
+
+	tokenizer, err := NewTokenizer([]byte("key"), "email")
+	if err != nil {
+		t.Fatalf("NewTokenizer() error = %v", err)
+	}
+	token := tokenizer.Token("ann@example.com")
+
+	for _, tt := range []struct {
+		format OutputFormat
+		want   string
+	}{
+		{OutputFormatJSON, `{"email":"` + token + `","level":"INFO","message":"signed up"}` + "\n"},
+		{OutputFormatText, "<INFO> signed up email=" + token + "\n"},
+	} {
+		t.Run(string(tt.format), func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			formatter, _ := NewFormatter(tt.format, fields, WithMiddleware(tokenizer.Middleware()))
+			logger, _ := NewLoggerWithOptions(WithAsync(false), WithDestination(buf, formatter))
+
+			logger.Info("signed up", testEmail("ann@example.com"))
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("line = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+type testUser struct {
+	Name  string
+	Email string
+}
+
+type testAccount struct {
+	ID    int    `json:"id"`
+	Owner string `json:"owner"`
+}
+
+func TestTokenizer_Middleware_nested(t *testing.T) {
+	nameField, _ := NewObjectField[testUser](
+		"name",
+		func(args LogLineArgs, user testUser) (any, error) { return user.Name, nil },
+	)
+	emailField, _ := NewObjectField[testUser](
+		"email",
+		func(args LogLineArgs, user testUser) (any, error) { return user.Email, nil },
+	)
+	userField, _ := NewCompositeField("user", nameField, emailField)
+	accountField, _ := NewObjectField[testAccount](
+		"account",
+		func(args LogLineArgs, account testAccount) (any, error) { return account, nil },
+	)
+	fields := []Field{NewMessageField(), userField, accountField}
+
+	tokenizer, _ := NewTokenizer([]byte("key"), "user.email", "account")
+	email := tokenizer.Token("ann@example.com")
+	id, owner := tokenizer.Token("42"), tokenizer.Token("ann")
+
+	for _, tt := range []struct {
+		format OutputFormat
+		want   string
+	}{
+		{
+			OutputFormatJSON,
+			`{"account":{"id":"` + id + `","owner":"` + owner + `"},"message":"signed up",` +
+				`"user":{"email":"` + email + `","name":"ann"}}` + "\n",
+		},
+		{
+			OutputFormatText,
+			"signed up user.name=ann user.email=" + email + " account=map[id:" + id + " owner:" + owner + "]\n",
+		},
+	} {
+		t.Run(string(tt.format), func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			formatter, _ := NewFormatter(tt.format, fields, WithMiddleware(tokenizer.Middleware()))
+			logger, _ := NewLoggerWithOptions(WithAsync(false), WithDestination(buf, formatter))
+
+			logger.Info("signed up", testUser{"ann", "ann@example.com"}, testAccount{42, "ann"})
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("line = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenizer_tokenize(t *testing.T) {
+	tokenizer, _ := NewTokenizer([]byte("key"), "request.users.email")
+
+	value := map[string]any{
+		"path":  "/signup",
+		"users": []any{map[string]any{"email": "ann@example.com"}, map[string]any{"email": "bob@example.com"}},
+	}
+	got := tokenizer.tokenize("request", value, false)
+
+	want := map[string]any{
+		"path": "/signup",
+		"users": []any{
+			map[string]any{"email": tokenizer.Token("ann@example.com")},
+			map[string]any{"email": tokenizer.Token("bob@example.com")},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tokenize() = %v, want %v", got, want)
+	}
+	if email := value["users"].([]any)[0].(map[string]any)["email"]; email != "ann@example.com" {
+		t.Errorf("tokenize() changed the original value to %v", email)
+	}
+}
+
+func TestTokenizer_Token(t *testing.T) {
+	tokenizer, _ := NewTokenizer([]byte("key"))
+	rotated, _ := NewTokenizer([]byte("rotated"))
+
+	token := tokenizer.Token("ann@example.com")
+	if !strings.HasPrefix(token, TokenPrefix) || len(token) != len(TokenPrefix)+32 {
+		t.Errorf("Token() = %q, want %q followed by 32 hex digits", token, TokenPrefix)
+	}
+	if got := tokenizer.Token("ann@example.com"); got != token {
+		t.Errorf("Token() = %q, want the stable token %q", got, token)
+	}
+	if got := tokenizer.Token("bob@example.com"); got == token {
+		t.Errorf("Token() of another value = %q, want a different token", got)
+	}
+	if got := rotated.Token("ann@example.com"); got == token {
+		t.Errorf("Token() under a rotated key = %q, want a different token", got)
+	}
+}
+
+func TestNewTokenizer_errors(t *testing.T) {
+	if _, err := NewTokenizer(nil, "email"); !errors.Is(err, ErrorTokenizerKeyNotSpecified) {
+		t.Errorf("NewTokenizer() error = %v, want %v", err, ErrorTokenizerKeyNotSpecified)
+	}
+
+	tokenizer, _ := NewTokenizer([]byte("key"), "email")
+	formatter := tokenizer.Middleware()(&transformFormatter{})
+	if res := formatter.FormatLogLine(LogLineArgs{}, nil); !errors.Is(res.err, ErrorTokenizerUnsupportedFormatter) {
+		t.Errorf("FormatLogLine() error = %v, want %v", res.err, ErrorTokenizerUnsupportedFormatter)
+	}
+}
@@ -0,0 +1,92 @@
+package ultragrpc
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fmdunlap/ultra/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// ClientCall describes a call made through the client interceptors.
+type ClientCall struct {
+	// Target is the target of the client connection.
+	Target string
+	// Method is the full method name, e.g. "/package.Service/Method".
+	Method string
+	// Stream is true for streaming calls.
+	Stream   bool
+	Code     codes.Code
+	Duration time.Duration
+	// RequestSize and ResponseSize are the total sizes, in bytes, of the protobuf messages sent and received.
+	RequestSize  int
+	ResponseSize int
+	// Metadata is the outgoing metadata, with sensitive values redacted. Nil unless ClientInterceptorSettings.LogMetadata
+	// is set.
+	Metadata metadata.MD
+	// Err is the error the call failed with, if any.
+	Err error
+
+	start time.Time
+}
+
+// String returns the call as "TARGET METHOD CODE DURATION", followed by the payload sizes, error, and metadata.
+func (c *ClientCall) String() string {
+	parts := []string{
+		c.Target,
+		c.Method,
+		c.Code.String(),
+		c.Duration.String(),
+		"req_bytes=" + strconv.Itoa(c.RequestSize),
+		"resp_bytes=" + strconv.Itoa(c.ResponseSize),
+	}
+	if c.Err != nil {
+		parts = append(parts, fmt.Sprintf("err=%q", c.Err.Error()))
+	}
+
+	keys := make([]string, 0, len(c.Metadata))
+	for key := range c.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%q", key, strings.Join(c.Metadata[key], ",")))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// EventFields returns the target, method, stream, code, duration (in nanoseconds), payload sizes, metadata, and error
+// of the call. The metadata and error are omitted if there are none.
+func (c *ClientCall) EventFields() map[string]any {
+	fields := map[string]any{
+		"target":       c.Target,
+		"method":       c.Method,
+		"stream":       c.Stream,
+		"code":         c.Code.String(),
+		"duration":     c.Duration,
+		"requestSize":  c.RequestSize,
+		"responseSize": c.ResponseSize,
+	}
+	if c.Metadata != nil {
+		fields["metadata"] = c.Metadata
+	}
+	if c.Err != nil {
+		fields["error"] = c.Err.Error()
+	}
+	return fields
+}
+
+// NewClientCallField returns a new Field that formats the [*ClientCall] logged by the client interceptors. See
+// log.NewEventField.
+//
+// OutputFormats:
+//   - OutputFormatText => [ClientCall.String].
+//   - OutputFormatJSON => [ClientCall.EventFields].
+func NewClientCallField(name string) (log.Field, error) {
+	return log.NewEventField[*ClientCall](name)
+}
@@ -0,0 +1,23 @@
+module github.com/fmdunlap/ultra/log/ultragrpc
+
+go 1.23.1
+
+require (
+	github.com/fmdunlap/ultra v0.0.0-00010101000000-000000000000
+	google.golang.org/grpc v1.75.1
+	google.golang.org/protobuf v1.36.11
+)
+
+require (
+	golang.org/x/net v0.43.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
+)
+
+// Builds in this repository use the ultra/log next to this module. Consumers ignore the replacement, and use the
+// required version of github.com/fmdunlap/ultra instead, so it must be raised whenever this module needs newer APIs.
+//
+// No release of github.com/fmdunlap/ultra has the APIs this module needs yet, so it requires the zero placeholder
+// version. Require the first tagged release that has them before tagging this module.
+replace github.com/fmdunlap/ultra => ../..
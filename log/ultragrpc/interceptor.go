@@ -0,0 +1,266 @@
+// Package ultragrpc provides gRPC integrations for ultra/log. It is a separate module, so that ultra/log itself only
+// depends on the standard library.
+package ultragrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fmdunlap/ultra/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// ClientInterceptorSettings configures the client interceptors.
+type ClientInterceptorSettings struct {
+	// Level is the level of calls that completed with codes.OK. Defaults to Info if nil; see log.LevelPtr.
+	Level *log.Level
+	// ErrorLevel is the level of calls that completed with any other code. Defaults to Error if nil.
+	ErrorLevel *log.Level
+	// Message is the message of the logged lines. Defaults to "grpc call".
+	Message string
+	// LogMetadata determines whether the outgoing metadata of calls is logged.
+	LogMetadata bool
+	// RedactedMetadata are the metadata keys whose values are replaced with "[REDACTED]" when metadata is logged. Keys
+	// are case-insensitive. Defaults to DefaultRedactedMetadata.
+	RedactedMetadata []string
+}
+
+// DefaultRedactedMetadata are the metadata keys redacted by default; they commonly carry credentials.
+var DefaultRedactedMetadata = []string{"authorization", "cookie", "x-api-key"}
+
+var defaultClientInterceptorSettings = ClientInterceptorSettings{
+	Level:            log.LevelPtr(log.Info),
+	ErrorLevel:       log.LevelPtr(log.Error),
+	Message:          "grpc call",
+	LogMetadata:      false,
+	RedactedMetadata: DefaultRedactedMetadata,
+}
+
+func (s *ClientInterceptorSettings) mergeDefault() {
+	if s.Level == nil {
+		s.Level = log.LevelPtr(*defaultClientInterceptorSettings.Level)
+	}
+	if s.ErrorLevel == nil {
+		s.ErrorLevel = log.LevelPtr(*defaultClientInterceptorSettings.ErrorLevel)
+	}
+	if s.Message == "" {
+		s.Message = defaultClientInterceptorSettings.Message
+	}
+	if s.RedactedMetadata == nil {
+		s.RedactedMetadata = defaultClientInterceptorSettings.RedactedMetadata
+	}
+}
+
+// UnaryClientInterceptor returns a grpc.UnaryClientInterceptor that logs every unary call with its target, method,
+// status code, duration, and payload sizes. Lines are logged with the message and a [*ClientCall]; the logger's
+// formatters need a NewClientCallField to write it.
+func UnaryClientInterceptor(logger log.Logger, settings *ClientInterceptorSettings) grpc.UnaryClientInterceptor {
+	settings = withDefaults(settings)
+
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		call := newClientCall(ctx, cc.Target(), method, false, settings)
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		call.RequestSize = payloadSize(req)
+		if err == nil {
+			call.ResponseSize = payloadSize(reply)
+		}
+		logCall(logger, settings, call, err)
+
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a grpc.StreamClientInterceptor that logs every streaming call once it completes,
+// with its target, method, status code, duration, and the total size of the messages sent and received. See
+// UnaryClientInterceptor.
+//
+// A stream completes when receiving fails (io.EOF being a successful completion), after the single response of a
+// client-streaming call, when CloseSend fails, or when the stream's context is done. Streams the client abandons by
+// canceling their context are logged with codes.Canceled.
+func StreamClientInterceptor(logger log.Logger, settings *ClientInterceptorSettings) grpc.StreamClientInterceptor {
+	settings = withDefaults(settings)
+
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		call := newClientCall(ctx, cc.Target(), method, true, settings)
+
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			logCall(logger, settings, call, err)
+			return nil, err
+		}
+
+		logged := &loggingClientStream{
+			ClientStream: stream,
+			logger:       logger,
+			settings:     settings,
+			call:         call,
+			serverStream: desc.ServerStreams,
+		}
+		logged.mu.Lock()
+		logged.stop = context.AfterFunc(stream.Context(), func() {
+			logged.finish(status.FromContextError(stream.Context().Err()).Err())
+		})
+		logged.mu.Unlock()
+
+		return logged, nil
+	}
+}
+
+func withDefaults(settings *ClientInterceptorSettings) *ClientInterceptorSettings {
+	if settings == nil {
+		settings = &ClientInterceptorSettings{}
+	}
+	merged := *settings
+	merged.mergeDefault()
+	return &merged
+}
+
+func newClientCall(
+	ctx context.Context,
+	target, method string,
+	stream bool,
+	settings *ClientInterceptorSettings,
+) *ClientCall {
+	call := &ClientCall{
+		Target: target,
+		Method: method,
+		Stream: stream,
+		start:  time.Now(),
+	}
+
+	if settings.LogMetadata {
+		if md, ok := metadata.FromOutgoingContext(ctx); ok {
+			call.Metadata = redactMetadata(md, settings.RedactedMetadata)
+		}
+	}
+
+	return call
+}
+
+func logCall(logger log.Logger, settings *ClientInterceptorSettings, call *ClientCall, err error) {
+	call.Duration = time.Since(call.start)
+	call.Code = status.Code(err)
+	if err != nil {
+		call.Err = err
+	}
+
+	level := *settings.Level
+	if call.Code != codes.OK {
+		level = *settings.ErrorLevel
+	}
+	logger.Log(level, settings.Message, call)
+}
+
+// loggingClientStream logs the call once the stream completes. See StreamClientInterceptor.
+type loggingClientStream struct {
+	grpc.ClientStream
+	logger       log.Logger
+	settings     *ClientInterceptorSettings
+	serverStream bool
+	stop         func() bool // Stops logging the call when the stream's context is done.
+
+	mu   sync.Mutex
+	call *ClientCall
+	done bool
+}
+
+func (s *loggingClientStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+
+	s.mu.Lock()
+	if err == nil {
+		s.call.RequestSize += payloadSize(m)
+	}
+	s.mu.Unlock()
+
+	return err
+}
+
+func (s *loggingClientStream) CloseSend() error {
+	err := s.ClientStream.CloseSend()
+	if err != nil {
+		s.finish(err)
+	}
+	return err
+}
+
+func (s *loggingClientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil {
+		s.call.ResponseSize += payloadSize(m)
+		if !s.serverStream {
+			s.finishLocked(nil)
+		}
+		return nil
+	}
+
+	if errors.Is(err, io.EOF) {
+		s.finishLocked(nil)
+	} else {
+		s.finishLocked(err)
+	}
+	return err
+}
+
+func (s *loggingClientStream) finish(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.finishLocked(err)
+}
+
+func (s *loggingClientStream) finishLocked(err error) {
+	if s.done {
+		return
+	}
+	s.done = true
+	s.stop()
+	logCall(s.logger, s.settings, s.call, err)
+}
+
+func payloadSize(m any) int {
+	if msg, ok := m.(proto.Message); ok {
+		return proto.Size(msg)
+	}
+	return 0
+}
+
+const redacted = "[REDACTED]"
+
+func redactMetadata(md metadata.MD, redactedKeys []string) metadata.MD {
+	md = md.Copy()
+	for _, key := range redactedKeys {
+		key = strings.ToLower(key)
+		if _, ok := md[key]; ok {
+			md[key] = []string{redacted}
+		}
+	}
+	return md
+}
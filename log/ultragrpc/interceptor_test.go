@@ -0,0 +1,220 @@
+package ultragrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fmdunlap/ultra/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// healthServer answers Check for the "ok" service, and sends a single update from Watch.
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+}
+
+func (healthServer) Check(_ context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if req.Service != "ok" {
+		return nil, status.Error(codes.NotFound, "unknown service")
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func (healthServer) Watch(_ *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	return stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING})
+}
+
+// lockedBuffer is a bytes.Buffer that is safe for concurrent use, for streams logged when their context is done.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func (b *lockedBuffer) Bytes() []byte {
+	return []byte(b.String())
+}
+
+func newTestClient(t *testing.T, format log.OutputFormat, settings *ClientInterceptorSettings) (healthpb.HealthClient, *lockedBuffer) {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer{})
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	callField, err := NewClientCallField("call")
+	if err != nil {
+		t.Fatalf("NewClientCallField() error = %v", err)
+	}
+
+	buf := &lockedBuffer{}
+	formatter, _ := log.NewFormatter(format, []log.Field{log.NewDefaultLevelField(), log.NewMessageField(), callField})
+	logger, err := log.NewLoggerWithOptions(log.WithAsync(false), log.WithDestination(buf, formatter))
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(logger, settings)),
+		grpc.WithStreamInterceptor(StreamClientInterceptor(logger, settings)),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return healthpb.NewHealthClient(conn), buf
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name       string
+		service    string
+		wantPrefix string
+	}{
+		{"OK", "ok", "<INFO> grpc call passthrough:///bufnet /grpc.health.v1.Health/Check OK "},
+		{"Error", "missing", "<ERROR> grpc call passthrough:///bufnet /grpc.health.v1.Health/Check NotFound "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, buf := newTestClient(t, log.OutputFormatText, nil)
+
+			_, _ = client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: tt.service})
+
+			if got := buf.String(); !strings.HasPrefix(got, tt.wantPrefix) {
+				t.Errorf("line = %q, want prefix %q", got, tt.wantPrefix)
+			}
+		})
+	}
+}
+
+func TestUnaryClientInterceptor_json(t *testing.T) {
+	client, buf := newTestClient(t, log.OutputFormatJSON, &ClientInterceptorSettings{LogMetadata: true})
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer token", "x-request-id", "abc")
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "ok"}); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	var line struct {
+		Call struct {
+			Code         string
+			RequestSize  int
+			ResponseSize int
+			Metadata     map[string][]string
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v, line = %q", err, buf.String())
+	}
+
+	if line.Call.Code != "OK" {
+		t.Errorf("code = %q, want OK", line.Call.Code)
+	}
+	if line.Call.RequestSize == 0 || line.Call.ResponseSize == 0 {
+		t.Errorf("payload sizes = %d/%d, want non-zero", line.Call.RequestSize, line.Call.ResponseSize)
+	}
+	if got := line.Call.Metadata["authorization"]; len(got) != 1 || got[0] != "[REDACTED]" {
+		t.Errorf("authorization = %v, want it redacted", got)
+	}
+	if got := line.Call.Metadata["x-request-id"]; len(got) != 1 || got[0] != "abc" {
+		t.Errorf("x-request-id = %v", got)
+	}
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	client, buf := newTestClient(t, log.OutputFormatText, nil)
+
+	stream, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{Service: "ok"})
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	for {
+		if _, err := stream.Recv(); err != nil {
+			if !errors.Is(err, io.EOF) {
+				t.Fatalf("Recv() error = %v", err)
+			}
+			break
+		}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want one line once the stream completes: %q", len(lines), buf.String())
+	}
+	if want := "<INFO> grpc call passthrough:///bufnet /grpc.health.v1.Health/Watch OK "; !strings.HasPrefix(lines[0], want) {
+		t.Errorf("line = %q, want prefix %q", lines[0], want)
+	}
+	if strings.Contains(lines[0], "resp_bytes=0") {
+		t.Errorf("line = %q, want the received payload size", lines[0])
+	}
+}
+
+func TestStreamClientInterceptor_abandoned(t *testing.T) {
+	client, buf := newTestClient(t, log.OutputFormatText, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "ok"})
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("Recv() error = %v", err)
+	}
+	cancel()
+
+	// The stream is never drained; the line is logged once its context is done.
+	deadline := time.Now().Add(5 * time.Second)
+	for buf.String() == "" && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	want := "<ERROR> grpc call passthrough:///bufnet /grpc.health.v1.Health/Watch Canceled "
+	if got := buf.String(); !strings.HasPrefix(got, want) {
+		t.Errorf("line = %q, want prefix %q", got, want)
+	}
+}
+
+func TestClientInterceptorSettings_mergeDefault(t *testing.T) {
+	settings := &ClientInterceptorSettings{Level: log.LevelPtr(log.Debug)}
+	settings.mergeDefault()
+
+	if *settings.Level != log.Debug {
+		t.Errorf("Level = %v, want %v", *settings.Level, log.Debug)
+	}
+	if *settings.ErrorLevel != log.Error {
+		t.Errorf("ErrorLevel = %v, want %v", *settings.ErrorLevel, log.Error)
+	}
+}
@@ -0,0 +1,87 @@
+package ultrahttp
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fmdunlap/ultra/log"
+)
+
+// OutboundRequest describes a request sent by a LoggingTransport.
+type OutboundRequest struct {
+	Method string
+	// URL is the request URL, with any password redacted.
+	URL string
+	// StatusCode is the status code of the response, or 0 if the request failed.
+	StatusCode int
+	Duration   time.Duration
+	// Headers are the request headers, with sensitive values redacted. Nil unless TransportSettings.LogHeaders is set.
+	Headers http.Header
+	// Err is the error returned by the underlying transport, if any.
+	Err error
+	// Attempt is the attempt number from the request's RetryInfo, or 0 if the request has none.
+	Attempt int
+}
+
+// String returns the request as "METHOD URL STATUS DURATION", followed by the attempt, error, and headers, if any.
+func (r *OutboundRequest) String() string {
+	parts := []string{r.Method, r.URL}
+	if r.StatusCode != 0 {
+		parts = append(parts, strconv.Itoa(r.StatusCode))
+	}
+	parts = append(parts, r.Duration.String())
+	if r.Attempt != 0 {
+		parts = append(parts, "attempt="+strconv.Itoa(r.Attempt))
+	}
+	if r.Err != nil {
+		parts = append(parts, fmt.Sprintf("err=%q", r.Err.Error()))
+	}
+
+	names := make([]string, 0, len(r.Headers))
+	for name := range r.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%q", name, strings.Join(r.Headers[name], ",")))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// EventFields returns the method, url, status, duration (in nanoseconds), attempt, headers, and error of the request.
+// Unset members are omitted.
+func (r *OutboundRequest) EventFields() map[string]any {
+	fields := map[string]any{
+		"method":   r.Method,
+		"url":      r.URL,
+		"duration": r.Duration,
+	}
+	if r.StatusCode != 0 {
+		fields["status"] = r.StatusCode
+	}
+	if r.Headers != nil {
+		fields["headers"] = r.Headers
+	}
+	if r.Attempt != 0 {
+		fields["attempt"] = r.Attempt
+	}
+	if r.Err != nil {
+		fields["error"] = r.Err.Error()
+	}
+	return fields
+}
+
+// NewOutboundRequestField returns a new Field that formats the [*OutboundRequest] logged by a LoggingTransport. See
+// log.NewEventField.
+//
+// OutputFormats:
+//   - OutputFormatText => [OutboundRequest.String].
+//   - OutputFormatJSON => [OutboundRequest.EventFields].
+func NewOutboundRequestField(name string) (log.Field, error) {
+	return log.NewEventField[*OutboundRequest](name)
+}
@@ -0,0 +1,25 @@
+package ultrahttp
+
+import "context"
+
+// RetryInfo describes the attempt a request is part of, for clients that retry failed requests. Attach it to the
+// request's context with ContextWithRetryInfo, so the LoggingTransport can tell intermediate failures from final ones.
+type RetryInfo struct {
+	// Attempt is the 1-based number of the attempt.
+	Attempt int
+	// Final is true if no further attempts will be made if this one fails.
+	Final bool
+}
+
+type retryInfoContextKey struct{}
+
+// ContextWithRetryInfo returns a copy of ctx that carries the RetryInfo of a request attempt.
+func ContextWithRetryInfo(ctx context.Context, info RetryInfo) context.Context {
+	return context.WithValue(ctx, retryInfoContextKey{}, info)
+}
+
+// RetryInfoFromContext returns the RetryInfo carried by ctx, and false if there is none.
+func RetryInfoFromContext(ctx context.Context) (RetryInfo, bool) {
+	info, ok := ctx.Value(retryInfoContextKey{}).(RetryInfo)
+	return info, ok
+}
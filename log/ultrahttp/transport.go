@@ -0,0 +1,135 @@
+// Package ultrahttp provides net/http integrations for ultra/log.
+package ultrahttp
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/fmdunlap/ultra/log"
+)
+
+// TransportSettings configures a LoggingTransport.
+type TransportSettings struct {
+	// Level is the level of requests that got a response below 500. Defaults to Info if nil; see log.LevelPtr.
+	Level *log.Level
+	// ErrorLevel is the level of requests that failed, or got a 5xx response. Defaults to Error if nil.
+	ErrorLevel *log.Level
+	// RetryLevel is the level of failed attempts that will be retried, according to the request's RetryInfo. Only the
+	// final failure is logged at the ErrorLevel, so retried requests don't inflate error counts. Defaults to Debug if
+	// nil.
+	RetryLevel *log.Level
+	// Message is the message of the logged lines. Defaults to "outbound request".
+	Message string
+	// LogHeaders determines whether the request headers are logged.
+	LogHeaders bool
+	// RedactedHeaders are the headers whose values are replaced with "[REDACTED]" when headers are logged. Defaults to
+	// DefaultRedactedHeaders.
+	RedactedHeaders []string
+}
+
+// DefaultRedactedHeaders are the headers redacted by default; they commonly carry credentials.
+var DefaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+var defaultTransportSettings = TransportSettings{
+	Level:           log.LevelPtr(log.Info),
+	ErrorLevel:      log.LevelPtr(log.Error),
+	RetryLevel:      log.LevelPtr(log.Debug),
+	Message:         "outbound request",
+	LogHeaders:      false,
+	RedactedHeaders: DefaultRedactedHeaders,
+}
+
+func (s *TransportSettings) mergeDefault() {
+	if s.Level == nil {
+		s.Level = log.LevelPtr(*defaultTransportSettings.Level)
+	}
+	if s.ErrorLevel == nil {
+		s.ErrorLevel = log.LevelPtr(*defaultTransportSettings.ErrorLevel)
+	}
+	if s.RetryLevel == nil {
+		s.RetryLevel = log.LevelPtr(*defaultTransportSettings.RetryLevel)
+	}
+	if s.Message == "" {
+		s.Message = defaultTransportSettings.Message
+	}
+	if s.RedactedHeaders == nil {
+		s.RedactedHeaders = defaultTransportSettings.RedactedHeaders
+	}
+}
+
+// LoggingTransport is an http.RoundTripper that logs every outbound request it sends, with its method, URL, status,
+// and duration. Lines are logged with the message and an [*OutboundRequest]; the logger's formatters need a
+// NewOutboundRequestField to write it.
+//
+// If the request's context carries a RetryInfo (see ContextWithRetryInfo), the attempt number is logged, and failed
+// attempts that will be retried are logged at the RetryLevel.
+type LoggingTransport struct {
+	base     http.RoundTripper
+	logger   log.Logger
+	settings TransportSettings
+}
+
+// NewLoggingTransport returns a LoggingTransport that sends requests with base, and logs them to logger. If base is
+// nil, http.DefaultTransport is used.
+func NewLoggingTransport(base http.RoundTripper, logger log.Logger, settings *TransportSettings) *LoggingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if settings == nil {
+		settings = &TransportSettings{}
+	}
+	settings.mergeDefault()
+
+	return &LoggingTransport{
+		base:     base,
+		logger:   logger,
+		settings: *settings,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+
+	entry := &OutboundRequest{
+		Method:   req.Method,
+		URL:      req.URL.Redacted(),
+		Duration: time.Since(start),
+		Err:      err,
+	}
+	if resp != nil {
+		entry.StatusCode = resp.StatusCode
+	}
+	if t.settings.LogHeaders {
+		entry.Headers = redactHeaders(req.Header, t.settings.RedactedHeaders)
+	}
+
+	retry, retried := RetryInfoFromContext(req.Context())
+	if retried {
+		entry.Attempt = retry.Attempt
+	}
+
+	level := *t.settings.Level
+	if err != nil || entry.StatusCode >= http.StatusInternalServerError {
+		level = *t.settings.ErrorLevel
+		if retried && !retry.Final {
+			level = *t.settings.RetryLevel
+		}
+	}
+	t.logger.Log(level, t.settings.Message, entry)
+
+	return resp, err
+}
+
+const redacted = "[REDACTED]"
+
+func redactHeaders(header http.Header, redactedHeaders []string) http.Header {
+	header = header.Clone()
+	for _, name := range redactedHeaders {
+		if values := header.Values(name); len(values) > 0 {
+			header.Set(name, redacted)
+		}
+	}
+	return header
+}
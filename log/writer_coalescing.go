@@ -0,0 +1,180 @@
+package log
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// CoalescingSettings are the settings for a CoalescingWriter.
+type CoalescingSettings struct {
+	// Window is the maximum amount of time a line is held before it is written. Defaults to 1ms.
+	Window time.Duration
+	// MaxLines is the number of buffered lines that triggers an immediate write. Defaults to 64.
+	MaxLines int
+	// MaxBytes is the number of buffered bytes that triggers an immediate write. Defaults to 64KiB.
+	MaxBytes int
+}
+
+var defaultCoalescingSettings = CoalescingSettings{
+	Window:   time.Millisecond,
+	MaxLines: 64,
+	MaxBytes: 64 * 1024,
+}
+
+func (s *CoalescingSettings) mergeDefault() {
+	if s.Window <= 0 {
+		s.Window = defaultCoalescingSettings.Window
+	}
+	if s.MaxLines <= 0 {
+		s.MaxLines = defaultCoalescingSettings.MaxLines
+	}
+	if s.MaxBytes <= 0 {
+		s.MaxBytes = defaultCoalescingSettings.MaxBytes
+	}
+}
+
+// CoalescingWriter gathers the lines written to it within a small window into a single Write call on the underlying
+// writer. When many goroutines log at the same time this dramatically reduces the number of syscalls made against
+// files and sockets.
+//
+// Writes never block on the underlying writer unless the buffer is full. Errors from a background write are returned
+// by the next call to Flush; later writes are still buffered.
+type CoalescingWriter struct {
+	w        io.Writer
+	settings CoalescingSettings
+
+	mu        sync.Mutex
+	buf       []byte
+	lines     int
+	untracked int           // Buffered lines written with Write, whose errors are kept in err.
+	done      []func(error) // Callbacks of the buffered lines written with writeDeferred.
+	timer     *time.Timer
+	err       error
+}
+
+// NewCoalescingWriter returns a CoalescingWriter that writes to w. If settings is nil, the defaults are used.
+func NewCoalescingWriter(w io.Writer, settings *CoalescingSettings) *CoalescingWriter {
+	if settings == nil {
+		settings = &CoalescingSettings{}
+	}
+	settings.mergeDefault()
+
+	return &CoalescingWriter{
+		w:        w,
+		settings: *settings,
+		buf:      make([]byte, 0, settings.MaxBytes),
+	}
+}
+
+// Write buffers p. The buffer is written to the underlying writer once the window elapses or the buffer reaches
+// MaxLines or MaxBytes, whichever comes first.
+func (c *CoalescingWriter) Write(p []byte) (int, error) {
+	if err := c.write(p, nil); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// writeDeferred buffers p like Write, and calls done with the outcome once p has been written to the underlying
+// writer. The logger uses it to count a line as delivered only once it has been flushed.
+func (c *CoalescingWriter) writeDeferred(p []byte, done func(error)) {
+	_ = c.write(p, done)
+}
+
+// write buffers p. It returns the error of writing the buffer, if p filled it and done is nil.
+func (c *CoalescingWriter) write(p []byte, done func(error)) error {
+	c.mu.Lock()
+
+	c.buf = append(c.buf, p...)
+	c.lines++
+	if done != nil {
+		c.done = append(c.done, done)
+	} else {
+		c.untracked++
+	}
+
+	if c.lines >= c.settings.MaxLines || len(c.buf) >= c.settings.MaxBytes {
+		callbacks, err := c.flushLocked()
+		if done == nil && err != nil {
+			// Returned to the caller instead.
+			c.err = nil
+		}
+		c.mu.Unlock()
+
+		completeLines(callbacks, err)
+		if done != nil {
+			return nil
+		}
+		return err
+	}
+
+	if c.timer == nil {
+		c.timer = time.AfterFunc(c.settings.Window, c.flushFromTimer)
+	}
+	c.mu.Unlock()
+
+	return nil
+}
+
+// Flush writes any buffered lines to the underlying writer immediately. If the underlying writer buffers lines
+// itself, it is flushed as well. It returns the error of the last failed write of lines written with Write.
+func (c *CoalescingWriter) Flush() error {
+	c.mu.Lock()
+	callbacks, flushErr := c.flushLocked()
+	err := c.err
+	c.err = nil
+	c.mu.Unlock()
+
+	completeLines(callbacks, flushErr)
+	if err != nil {
+		return err
+	}
+
+	if flusher, ok := c.w.(destinationFlusher); ok {
+		return flusher.Flush()
+	}
+	return nil
+}
+
+func (c *CoalescingWriter) flushFromTimer() {
+	c.mu.Lock()
+	callbacks, err := c.flushLocked()
+	c.mu.Unlock()
+
+	completeLines(callbacks, err)
+}
+
+// flushLocked writes the buffered lines to the underlying writer. It returns the callbacks of the lines written with
+// writeDeferred, to be called with the error once c.mu is released. If the lines written with Write failed, the error
+// is kept in c.err.
+func (c *CoalescingWriter) flushLocked() ([]func(error), error) {
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+
+	if len(c.buf) == 0 {
+		return nil, nil
+	}
+
+	_, err := c.w.Write(c.buf)
+	if err != nil && c.untracked > 0 {
+		c.err = err
+	}
+
+	callbacks := c.done
+	c.buf = c.buf[:0]
+	c.lines = 0
+	c.untracked = 0
+	c.done = nil
+
+	return callbacks, err
+}
+
+// completeLines calls the callbacks of flushed lines with the outcome of the flush.
+func completeLines(callbacks []func(error), err error) {
+	for _, done := range callbacks {
+		done(err)
+	}
+}
@@ -0,0 +1,195 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type countingWriter struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	writes int
+	err    error
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	w.writes++
+	return w.buf.Write(p)
+}
+
+func (w *countingWriter) snapshot() (string, int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String(), w.writes
+}
+
+func TestCoalescingWriter_MaxLines(t *testing.T) {
+	dest := &countingWriter{}
+	c := NewCoalescingWriter(dest, &CoalescingSettings{Window: time.Hour, MaxLines: 3})
+
+	for _, line := range []string{"a\n", "b\n", "c\n", "d\n"} {
+		if _, err := c.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	if got, writes := dest.snapshot(); got != "a\nb\nc\n" || writes != 1 {
+		t.Errorf("before Flush() got %q in %d writes, want %q in 1 write", got, writes, "a\nb\nc\n")
+	}
+
+	if err := c.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if got, writes := dest.snapshot(); got != "a\nb\nc\nd\n" || writes != 2 {
+		t.Errorf("after Flush() got %q in %d writes, want %q in 2 writes", got, writes, "a\nb\nc\nd\n")
+	}
+}
+
+func TestCoalescingWriter_Window(t *testing.T) {
+	dest := &countingWriter{}
+	c := NewCoalescingWriter(dest, &CoalescingSettings{Window: 5 * time.Millisecond})
+
+	_, _ = c.Write([]byte("a\n"))
+	_, _ = c.Write([]byte("b\n"))
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if got, writes := dest.snapshot(); got == "a\nb\n" {
+			if writes != 1 {
+				t.Errorf("got %d writes, want 1", writes)
+			}
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	got, _ := dest.snapshot()
+	t.Errorf("window elapsed but got %q, want %q", got, "a\nb\n")
+}
+
+func TestCoalescingWriter_backgroundError(t *testing.T) {
+	writeErr := errors.New("write failed")
+	dest := &countingWriter{err: writeErr}
+	c := NewCoalescingWriter(dest, &CoalescingSettings{Window: time.Hour})
+
+	_, _ = c.Write([]byte("a\n"))
+	c.flushFromTimer()
+
+	if _, err := c.Write([]byte("b\n")); err != nil {
+		t.Errorf("Write() error = %v, want the line to be buffered", err)
+	}
+
+	dest.mu.Lock()
+	dest.err = nil
+	dest.mu.Unlock()
+
+	if err := c.Flush(); !errors.Is(err, writeErr) {
+		t.Errorf("Flush() error = %v, want %v", err, writeErr)
+	}
+	if got, _ := dest.snapshot(); got != "b\n" {
+		t.Errorf("got %q, want the line written after the error", got)
+	}
+}
+
+func TestWithWriteCoalescing(t *testing.T) {
+	dest := &countingWriter{}
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+
+	logger, err := NewLoggerWithOptions(
+		WithWriteCoalescing(&CoalescingSettings{Window: time.Hour}),
+		WithDestination(dest, formatter),
+		WithAsync(false),
+	)
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	logger.Info("one")
+	logger.Info("two")
+
+	if got, _ := dest.snapshot(); got != "" {
+		t.Errorf("before Flush() got %q, want nothing written", got)
+	}
+
+	logger.Flush()
+
+	if got, writes := dest.snapshot(); got != "one\ntwo\n" || writes != 1 {
+		t.Errorf("after Flush() got %q in %d writes, want %q in 1 write", got, writes, "one\ntwo\n")
+	}
+}
+
+func TestWithWriteCoalescing_deliveredOnFlush(t *testing.T) {
+	dest := &countingWriter{}
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+
+	logger, err := NewLoggerWithOptions(
+		WithWriteCoalescing(&CoalescingSettings{Window: time.Hour}),
+		WithDestination(dest, formatter),
+		WithAsync(false),
+	)
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	logger.Info("one")
+	logger.InfoMsg("two")
+
+	snapshot := logger.(Inspector).Inspect()
+	if snapshot.Stats.Lines != 0 {
+		t.Errorf("before Flush() Lines = %d, want 0", snapshot.Stats.Lines)
+	}
+	if got := snapshot.Destinations[0].Writer; got != "*log.countingWriter" {
+		t.Errorf("Writer = %q, want the wrapped writer", got)
+	}
+
+	logger.Flush()
+
+	if lines := logger.(Inspector).Inspect().Stats.Lines; lines != 2 {
+		t.Errorf("after Flush() Lines = %d, want 2", lines)
+	}
+}
+
+func TestWithWriteCoalescing_flushError(t *testing.T) {
+	failing := &toggleWriter{failed: true}
+	healthy := &toggleWriter{}
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+
+	var internalErrors []error
+	logger, err := NewLoggerWithOptions(
+		WithWriteCoalescing(&CoalescingSettings{Window: time.Hour}),
+		WithDestination(failing, formatter),
+		WithDestination(healthy, formatter),
+		WithAsync(false),
+		WithInternalErrorHandler(func(err error) { internalErrors = append(internalErrors, err) }),
+	)
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	logger.Info("one")
+	logger.Flush()
+	logger.Info("two")
+	logger.Flush()
+
+	// The failing destination is disabled, and the line it failed is logged again to the healthy one.
+	if got := strings.Join(healthy.received(), ""); got != "one\none\ntwo\n" {
+		t.Errorf("healthy destination received %q", got)
+	}
+
+	var writeErr *ErrorDestinationWrite
+	if len(internalErrors) == 0 || !errors.As(internalErrors[0], &writeErr) {
+		t.Errorf("internal errors = %v, want an ErrorDestinationWrite", internalErrors)
+	}
+}
@@ -0,0 +1,206 @@
+package log
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// datePlaceholder is replaced with the date in the path of a DatedFileWriter.
+const datePlaceholder = "{date}"
+
+// DatedFileSettings are the settings for a DatedFileWriter.
+type DatedFileSettings struct {
+	// Path is the path of the files, with a "{date}" placeholder for the date, e.g. "logs/app-{date}.log". Required.
+	Path string
+	// DateLayout is the time layout of the date in the path. Defaults to "2006-01-02".
+	DateLayout string
+	// Location is the time zone of the dates, and of the midnight at which files are switched. Defaults to
+	// time.Local.
+	Location *time.Location
+	// Symlink is the path of a symlink to the current file, e.g. "logs/app.log", maintained so that tail -F and humans
+	// always find it. Empty disables the symlink, e.g. on platforms without symlinks. Failing to update the symlink
+	// doesn't fail writes.
+	Symlink string
+	// Archiver, if set, uploads every file once it's closed: in the background when the writer switches to the file of
+	// the next date, and before Close returns for the last one.
+	Archiver *Archiver
+	// FileMode is the permissions of the files, when they're created. Defaults to 0644.
+	FileMode os.FileMode
+	// Separator, if set, is written as the first line of every file the writer opens, e.g. to mark where a run starts
+	// in a file appended to by many runs. A "{time}" placeholder is replaced with the time the file is opened.
+	Separator string
+}
+
+var defaultDatedFileSettings = DatedFileSettings{
+	DateLayout: time.DateOnly,
+	Location:   time.Local,
+	FileMode:   0644,
+}
+
+func (s *DatedFileSettings) mergeDefault() {
+	if s.DateLayout == "" {
+		s.DateLayout = defaultDatedFileSettings.DateLayout
+	}
+	if s.Location == nil {
+		s.Location = defaultDatedFileSettings.Location
+	}
+	if s.FileMode == 0 {
+		s.FileMode = defaultDatedFileSettings.FileMode
+	}
+}
+
+// DatedFileWriter is a destination that writes to date-partitioned files, e.g. logs/app-2025-01-02.log. It switches
+// to the file of the next date at midnight, in the configured time zone, on the first write after it. Files are
+// opened in append mode, and their directories are created as needed.
+type DatedFileWriter struct {
+	settings DatedFileSettings
+	now      func() time.Time
+
+	mu     sync.Mutex
+	path   string
+	file   *os.File
+	closed bool
+}
+
+// NewDatedFileWriter returns a DatedFileWriter writing to the files of settings.Path. The file of the current date is
+// opened on the first write.
+func NewDatedFileWriter(settings *DatedFileSettings) (*DatedFileWriter, error) {
+	if settings == nil || !strings.Contains(settings.Path, datePlaceholder) {
+		return nil, ErrorDatedFilePathNotSpecified
+	}
+	s := *settings
+	s.mergeDefault()
+
+	return &DatedFileWriter{settings: s, now: time.Now}, nil
+}
+
+// WithDatedFileDestination adds a DatedFileWriter destination, writing lines to date-partitioned files. The writer is
+// owned by the logger; it is closed by the logger's Close method.
+func WithDatedFileDestination(formatter LogLineFormatter, settings *DatedFileSettings) LoggerOption {
+	return func(l *ultraLogger) error {
+		w, err := NewDatedFileWriter(settings)
+		if err != nil {
+			return &ErrorLoggerInitialization{err: err}
+		}
+		l.closers = append(l.closers, w)
+
+		if l.destinations == nil {
+			l.destinations = map[io.Writer]LogLineFormatter{}
+		}
+		l.destinations[w] = formatter
+		return nil
+	}
+}
+
+// Write writes p to the file of the current date.
+func (w *DatedFileWriter) Write(p []byte) (int, error) {
+	path := w.pathAt(w.now())
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+
+	if path != w.path {
+		if err := w.openLocked(path); err != nil {
+			return 0, err
+		}
+	}
+
+	return w.file.Write(p)
+}
+
+// Path returns the path of the file currently written, or "" if nothing has been written yet.
+func (w *DatedFileWriter) Path() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.path
+}
+
+// Close closes the current file. The writer must not be used after Close.
+func (w *DatedFileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	if w.file == nil {
+		return nil
+	}
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	if w.settings.Archiver == nil {
+		return nil
+	}
+
+	w.settings.Archiver.Wait()
+	return w.settings.Archiver.ArchiveFile(context.Background(), w.path)
+}
+
+// pathAt returns the path of the file of the date of t.
+func (w *DatedFileWriter) pathAt(t time.Time) string {
+	date := t.In(w.settings.Location).Format(w.settings.DateLayout)
+	return strings.ReplaceAll(w.settings.Path, datePlaceholder, date)
+}
+
+// openLocked closes the current file, and opens the file at path.
+func (w *DatedFileWriter) openLocked(path string) error {
+	if w.file != nil {
+		if err := w.file.Close(); err == nil && w.settings.Archiver != nil {
+			w.settings.Archiver.archiveInBackground(w.path)
+		}
+		w.file, w.path = nil, ""
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, w.settings.FileMode)
+	if err != nil {
+		return err
+	}
+
+	if w.settings.Separator != "" {
+		if _, err := file.Write(separatorLine(w.settings.Separator, w.now())); err != nil {
+			_ = file.Close()
+			return err
+		}
+	}
+
+	w.file, w.path = file, path
+	w.updateSymlink()
+	return nil
+}
+
+// updateSymlink points the symlink to the current file. The symlink is replaced atomically, so it's never missing.
+func (w *DatedFileWriter) updateSymlink() {
+	if w.settings.Symlink == "" {
+		return
+	}
+
+	target := w.path
+	if rel, err := filepath.Rel(filepath.Dir(w.settings.Symlink), w.path); err == nil {
+		target = rel
+	}
+
+	tmp := w.settings.Symlink + ".tmp"
+	_ = os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, w.settings.Symlink); err != nil {
+		_ = os.Remove(tmp)
+	}
+}
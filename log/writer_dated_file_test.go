@@ -0,0 +1,124 @@
+package log
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDatedFileWriter(t *testing.T) {
+	dir := t.TempDir()
+	tokyo := time.FixedZone("JST", 9*60*60)
+	w, err := NewDatedFileWriter(&DatedFileSettings{Path: filepath.Join(dir, "app-{date}.log"), Location: tokyo})
+	if err != nil {
+		t.Fatalf("NewDatedFileWriter() error = %v", err)
+	}
+	defer w.Close()
+
+	// 14:59 UTC is 23:59 in Tokyo; the file switches at midnight in Tokyo, not in UTC.
+	now := time.Date(2025, 1, 2, 14, 59, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+
+	_, _ = w.Write([]byte("before midnight\n"))
+	now = now.Add(2 * time.Minute)
+	_, _ = w.Write([]byte("after midnight\n"))
+
+	if got, want := w.Path(), filepath.Join(dir, "app-2025-01-03.log"); got != want {
+		t.Errorf("Path() = %q, want %q", got, want)
+	}
+	if got := readFile(t, filepath.Join(dir, "app-2025-01-02.log")); got != "before midnight\n" {
+		t.Errorf("app-2025-01-02.log = %q", got)
+	}
+	if got := readFile(t, filepath.Join(dir, "app-2025-01-03.log")); got != "after midnight\n" {
+		t.Errorf("app-2025-01-03.log = %q", got)
+	}
+}
+
+func TestWithDatedFileDestination(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+
+	logger, err := NewLoggerWithOptions(
+		WithAsync(false),
+		WithDatedFileDestination(formatter, &DatedFileSettings{Path: filepath.Join(dir, "app-{date}.log")}),
+	)
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	logger.Info("hello")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	path := filepath.Join(dir, "app-"+time.Now().Format(time.DateOnly)+".log")
+	if got := readFile(t, path); got != "hello\n" {
+		t.Errorf("%s = %q, want %q", path, got, "hello\n")
+	}
+}
+
+func TestNewDatedFileWriter_missingPlaceholder(t *testing.T) {
+	_, err := NewDatedFileWriter(&DatedFileSettings{Path: "app.log"})
+	if !errors.Is(err, ErrorDatedFilePathNotSpecified) {
+		t.Errorf("NewDatedFileWriter() error = %v, want %v", err, ErrorDatedFilePathNotSpecified)
+	}
+}
+
+func TestDatedFileWriter_symlink(t *testing.T) {
+	dir := t.TempDir()
+	symlink := filepath.Join(dir, "app.log")
+	w, err := NewDatedFileWriter(&DatedFileSettings{
+		Path:     filepath.Join(dir, "app-{date}.log"),
+		Location: time.UTC,
+		Symlink:  symlink,
+	})
+	if err != nil {
+		t.Fatalf("NewDatedFileWriter() error = %v", err)
+	}
+	defer w.Close()
+
+	now := time.Date(2025, 1, 2, 23, 59, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+
+	_, _ = w.Write([]byte("one\n"))
+	if target, err := os.Readlink(symlink); err != nil || target != "app-2025-01-02.log" {
+		t.Fatalf("Readlink() = %q, %v, want app-2025-01-02.log", target, err)
+	}
+
+	now = now.Add(time.Hour)
+	_, _ = w.Write([]byte("two\n"))
+	if got := readFile(t, symlink); got != "two\n" {
+		t.Errorf("symlink points to a file with %q, want the current file", got)
+	}
+}
+
+func TestDatedFileWriter_separator(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewDatedFileWriter(&DatedFileSettings{
+		Path:      filepath.Join(dir, "app-{date}.log"),
+		Location:  time.UTC,
+		Separator: "--- {time} ---",
+	})
+	if err != nil {
+		t.Fatalf("NewDatedFileWriter() error = %v", err)
+	}
+	defer w.Close()
+
+	now := time.Date(2025, 1, 2, 23, 59, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+
+	_, _ = w.Write([]byte("before midnight\n"))
+	now = now.Add(2 * time.Minute)
+	_, _ = w.Write([]byte("after midnight\n"))
+
+	want := "--- 2025-01-02T23:59:00Z ---\nbefore midnight\n"
+	if got := readFile(t, filepath.Join(dir, "app-2025-01-02.log")); got != want {
+		t.Errorf("app-2025-01-02.log = %q, want %q", got, want)
+	}
+	want = "--- 2025-01-03T00:01:00Z ---\nafter midnight\n"
+	if got := readFile(t, filepath.Join(dir, "app-2025-01-03.log")); got != want {
+		t.Errorf("app-2025-01-03.log = %q, want %q", got, want)
+	}
+}
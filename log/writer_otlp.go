@@ -0,0 +1,65 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OTLPWriter is a destination that sends the lines of an OTLP formatter to an OTLP/HTTP endpoint, e.g. the
+// OpenTelemetry Collector, so lines reach it without a bridge process. Every line is sent as a request on its own;
+// use an async logger to keep the requests off the logging goroutines.
+type OTLPWriter struct {
+	// URL is the URL of the logs endpoint, e.g. "http://localhost:4318/v1/logs". Required.
+	URL string
+	// Headers are added to every request, e.g. an authorization header.
+	Headers map[string]string
+	// Timeout is the timeout of every request. Defaults to 10 seconds.
+	Timeout time.Duration
+	// Client is the HTTP client of the requests. Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// Write sends the line, an OTLP/JSON ExportLogsServiceRequest, to the endpoint. Responses other than 2xx are returned
+// as an ErrorOTLPExport.
+func (w *OTLPWriter) Write(p []byte) (int, error) {
+	if w.URL == "" {
+		return 0, ErrorOTLPURLNotSpecified
+	}
+
+	timeout := w.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(p))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range w.Headers {
+		req.Header.Set(key, value)
+	}
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return 0, &ErrorOTLPExport{status: resp.StatusCode, msg: strings.TrimSpace(string(msg))}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return len(p), nil
+}
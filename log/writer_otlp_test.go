@@ -0,0 +1,37 @@
+package log
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOTLPWriter(t *testing.T) {
+	var gotBody, gotType, gotAuth string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody, gotType, gotAuth = string(body), r.Header.Get("Content-Type"), r.Header.Get("Authorization")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("bad request"))
+	}))
+	defer server.Close()
+
+	w := &OTLPWriter{URL: server.URL + "/v1/logs", Headers: map[string]string{"Authorization": "Bearer token"}}
+	if n, err := w.Write([]byte(`{"resourceLogs":[]}`)); err != nil || n != 19 {
+		t.Fatalf("Write() = %d, %v, want 19, nil", n, err)
+	}
+	if gotBody != `{"resourceLogs":[]}` || gotType != "application/json" || gotAuth != "Bearer token" {
+		t.Errorf("request = %q, %q, %q", gotBody, gotType, gotAuth)
+	}
+
+	status = http.StatusBadRequest
+	if _, err := w.Write([]byte(`{}`)); !errors.As(err, new(*ErrorOTLPExport)) {
+		t.Errorf("Write() error = %v, want ErrorOTLPExport", err)
+	}
+	if _, err := (&OTLPWriter{}).Write(nil); !errors.Is(err, ErrorOTLPURLNotSpecified) {
+		t.Errorf("Write() error = %v, want %v", err, ErrorOTLPURLNotSpecified)
+	}
+}
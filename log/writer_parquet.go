@@ -0,0 +1,345 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ParquetType is the type of a column of the files of a ParquetWriter.
+type ParquetType int
+
+const (
+	// ParquetString columns hold UTF-8 strings. Values that aren't strings are written as JSON.
+	ParquetString ParquetType = iota
+	// ParquetInt64 columns hold integers.
+	ParquetInt64
+	// ParquetDouble columns hold floating-point numbers.
+	ParquetDouble
+	// ParquetBoolean columns hold booleans.
+	ParquetBoolean
+	// ParquetTimestamp columns hold times with millisecond precision, from RFC 3339 strings like those of the JSON
+	// current time field.
+	ParquetTimestamp
+)
+
+// ParquetColumn is a column of the files of a ParquetWriter.
+type ParquetColumn struct {
+	// Name is the key of the entry field written to the column.
+	Name string
+	// Type is the type of the column. Values that can't be converted to it are written as null.
+	Type ParquetType
+}
+
+// FileSink stores the files written by batching destinations, like the ParquetWriter.
+type FileSink interface {
+	// WriteFile stores a complete file under the name.
+	WriteFile(name string, data []byte) error
+}
+
+// DirSink is a FileSink that writes files to a local directory, created if it doesn't exist. Files are written to a
+// temporary file first, and renamed into place, so readers never see partial files.
+type DirSink string
+
+// WriteFile writes the file to the directory.
+func (d DirSink) WriteFile(name string, data []byte) error {
+	if err := os.MkdirAll(string(d), 0755); err != nil {
+		return err
+	}
+
+	path := filepath.Join(string(d), name)
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// ParquetSettings are the settings for a ParquetWriter.
+type ParquetSettings struct {
+	// Sink stores the Parquet files, e.g. a DirSink. Required.
+	Sink FileSink
+	// Prefix is the prefix of the file names, which are followed by the UTC time the file was written, e.g.
+	// "logs-20240102T150405.000000000Z.parquet". Defaults to "logs-".
+	Prefix string
+	// Columns are the columns of the files. If empty, the files have a column per key of the entries they hold, in
+	// sorted order, with types inferred from the values.
+	Columns []ParquetColumn
+	// MaxRows is the number of buffered entries that triggers writing a file. Defaults to 10000.
+	MaxRows int
+	// FlushInterval is the maximum amount of time an entry is buffered before it is written. Defaults to one minute.
+	FlushInterval time.Duration
+}
+
+var defaultParquetSettings = ParquetSettings{
+	Prefix:        "logs-",
+	MaxRows:       10000,
+	FlushInterval: time.Minute,
+}
+
+func (s *ParquetSettings) mergeDefault() {
+	if s.Prefix == "" {
+		s.Prefix = defaultParquetSettings.Prefix
+	}
+	if s.MaxRows <= 0 {
+		s.MaxRows = defaultParquetSettings.MaxRows
+	}
+	if s.FlushInterval <= 0 {
+		s.FlushInterval = defaultParquetSettings.FlushInterval
+	}
+}
+
+// ParquetWriter is a destination that batches entries into Parquet files, with a column per field, for cheap
+// analytical queries over archived logs. It must be written JSON lines, i.e. used with a JSON formatter; lines that
+// aren't JSON objects are skipped.
+//
+// A file is written once MaxRows entries are buffered, every FlushInterval, on Flush, and on Close. Errors writing a
+// file in the background are returned by the next call to Flush; the entries of that file are lost.
+type ParquetWriter struct {
+	settings ParquetSettings
+
+	mu     sync.Mutex
+	rows   []map[string]any
+	err    error
+	closed bool
+
+	done chan struct{}
+}
+
+// NewParquetWriter returns a ParquetWriter storing its files in settings.Sink.
+func NewParquetWriter(settings *ParquetSettings) (*ParquetWriter, error) {
+	if settings == nil || settings.Sink == nil {
+		return nil, ErrorParquetSinkNotSpecified
+	}
+	s := *settings
+	s.mergeDefault()
+
+	w := &ParquetWriter{settings: s, done: make(chan struct{})}
+	go w.flushLoop()
+
+	return w, nil
+}
+
+// WithParquetDestination adds a ParquetWriter destination. The formatter must be a JSON formatter. The writer is owned
+// by the logger; it is closed, writing its last file, by the logger's Close method.
+func WithParquetDestination(formatter LogLineFormatter, settings *ParquetSettings) LoggerOption {
+	return func(l *ultraLogger) error {
+		w, err := NewParquetWriter(settings)
+		if err != nil {
+			return &ErrorLoggerInitialization{err: err}
+		}
+		l.closers = append(l.closers, w)
+
+		if l.destinations == nil {
+			l.destinations = map[io.Writer]LogLineFormatter{}
+		}
+		l.destinations[w] = formatter
+		return nil
+	}
+}
+
+// Write buffers the JSON entry in p.
+func (w *ParquetWriter) Write(p []byte) (int, error) {
+	decoder := json.NewDecoder(bytes.NewReader(p))
+	decoder.UseNumber()
+
+	var row map[string]any
+	if err := decoder.Decode(&row); err != nil {
+		return len(p), nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+
+	w.rows = append(w.rows, row)
+	if len(w.rows) >= w.settings.MaxRows {
+		if err := w.flushLocked(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush writes the buffered entries to a file, and returns any error of a previous background write.
+func (w *ParquetWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	err := w.flushLocked()
+	err, w.err = errors.Join(w.err, err), nil
+	return err
+}
+
+// Close writes the buffered entries to a file. The writer must not be used after Close.
+func (w *ParquetWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	close(w.done)
+
+	err := w.flushLocked()
+	err, w.err = errors.Join(w.err, err), nil
+	return err
+}
+
+func (w *ParquetWriter) flushLoop() {
+	ticker := time.NewTicker(w.settings.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+			if err := w.flushLocked(); err != nil {
+				w.err = errors.Join(w.err, err)
+			}
+			w.mu.Unlock()
+		}
+	}
+}
+
+func (w *ParquetWriter) flushLocked() error {
+	if len(w.rows) == 0 {
+		return nil
+	}
+	rows := w.rows
+	w.rows = nil
+
+	columns := w.settings.Columns
+	if len(columns) == 0 {
+		columns = inferParquetColumns(rows)
+	}
+
+	data := make([]parquetColumnData, len(columns))
+	for i, column := range columns {
+		data[i] = column.data(rows)
+	}
+
+	name := w.settings.Prefix + time.Now().UTC().Format("20060102T150405.000000000Z") + ".parquet"
+	return w.settings.Sink.WriteFile(name, encodeParquet(data, len(rows)))
+}
+
+// inferParquetColumns returns a column for each key of the rows, typed after the values of the key.
+func inferParquetColumns(rows []map[string]any) []ParquetColumn {
+	types := map[string]ParquetType{}
+	for _, row := range rows {
+		for key, value := range row {
+			typ, ok := inferParquetType(value)
+			if !ok {
+				continue
+			}
+			if seen, ok := types[key]; ok && seen != typ {
+				switch {
+				case seen == ParquetInt64 && typ == ParquetDouble, seen == ParquetDouble && typ == ParquetInt64:
+					typ = ParquetDouble
+				default:
+					typ = ParquetString
+				}
+			}
+			types[key] = typ
+		}
+	}
+
+	columns := make([]ParquetColumn, 0, len(types))
+	for key, typ := range types {
+		columns = append(columns, ParquetColumn{Name: key, Type: typ})
+	}
+	slices.SortFunc(columns, func(a, b ParquetColumn) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return columns
+}
+
+// inferParquetType returns the type of a decoded JSON value. It returns false for nulls, which fit any type.
+func inferParquetType(value any) (ParquetType, bool) {
+	switch value := value.(type) {
+	case nil:
+		return 0, false
+	case bool:
+		return ParquetBoolean, true
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return ParquetInt64, true
+		}
+		return ParquetDouble, true
+	default:
+		return ParquetString, true
+	}
+}
+
+// data returns the values of the column in the rows.
+func (c ParquetColumn) data(rows []map[string]any) parquetColumnData {
+	data := parquetColumnData{name: c.Name, converted: -1, values: make([]any, len(rows))}
+	switch c.Type {
+	case ParquetInt64:
+		data.physical = parquetInt64
+	case ParquetDouble:
+		data.physical = parquetDouble
+	case ParquetBoolean:
+		data.physical = parquetBoolean
+	case ParquetTimestamp:
+		data.physical, data.converted = parquetInt64, parquetConvertedTimestampMillis
+	default:
+		data.physical, data.converted = parquetByteArray, parquetConvertedUTF8
+	}
+
+	for i, row := range rows {
+		data.values[i] = c.convert(row[c.Name])
+	}
+	return data
+}
+
+// convert converts a decoded JSON value to the physical type of the column, or returns nil if it can't.
+func (c ParquetColumn) convert(value any) any {
+	if value == nil {
+		return nil
+	}
+
+	switch c.Type {
+	case ParquetInt64:
+		if n, ok := value.(json.Number); ok {
+			if i, err := n.Int64(); err == nil {
+				return i
+			}
+		}
+	case ParquetDouble:
+		if n, ok := value.(json.Number); ok {
+			if f, err := n.Float64(); err == nil {
+				return f
+			}
+		}
+	case ParquetBoolean:
+		if b, ok := value.(bool); ok {
+			return b
+		}
+	case ParquetTimestamp:
+		if s, ok := value.(string); ok {
+			if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+				return t.UnixMilli()
+			}
+		}
+	default:
+		if s, ok := value.(string); ok {
+			return []byte(s)
+		}
+		b, _ := json.Marshal(value)
+		return b
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memorySink is a FileSink that keeps the files in memory.
+type memorySink struct {
+	mu    sync.Mutex
+	names []string
+	files map[string][]byte
+}
+
+func (s *memorySink) WriteFile(name string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.files == nil {
+		s.files = map[string][]byte{}
+	}
+	s.names = append(s.names, name)
+	s.files[name] = data
+	return nil
+}
+
+// readThrift decodes a Thrift compact struct into a map of field IDs to values, for inspecting Parquet metadata.
+// Structs are decoded as maps, and lists as slices.
+func readThrift(t *testing.T, b []byte) (map[int16]any, []byte) {
+	t.Helper()
+
+	fields := map[int16]any{}
+	var id int16
+	for {
+		header := b[0]
+		b = b[1:]
+		if header == 0 {
+			return fields, b
+		}
+
+		typ := header & 0x0f
+		if delta := int16(header >> 4); delta != 0 {
+			id += delta
+		} else {
+			v, n := binary.Varint(b)
+			id, b = int16(v), b[n:]
+		}
+		fields[id], b = readThriftValue(t, typ, b)
+	}
+}
+
+func readThriftValue(t *testing.T, typ byte, b []byte) (any, []byte) {
+	t.Helper()
+
+	switch typ {
+	case thriftI32, thriftI64:
+		v, n := binary.Varint(b)
+		return v, b[n:]
+	case thriftBinary:
+		size, n := binary.Uvarint(b)
+		return string(b[n : n+int(size)]), b[n+int(size):]
+	case thriftStruct:
+		return readThrift(t, b)
+	case thriftList:
+		size, elemType := int(b[0]>>4), b[0]&0x0f
+		b = b[1:]
+		if size == 15 {
+			v, n := binary.Uvarint(b)
+			size, b = int(v), b[n:]
+		}
+		list := make([]any, size)
+		for i := range list {
+			list[i], b = readThriftValue(t, elemType, b)
+		}
+		return list, b
+	}
+	t.Fatalf("unsupported thrift type %d", typ)
+	return nil, nil
+}
+
+// readParquetFooter returns the FileMetaData of the Parquet file.
+func readParquetFooter(t *testing.T, file []byte) map[int16]any {
+	t.Helper()
+
+	if !bytes.HasPrefix(file, parquetMagic) || !bytes.HasSuffix(file, parquetMagic) {
+		t.Fatalf("file isn't framed by %q", parquetMagic)
+	}
+	size := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footer, rest := readThrift(t, file[len(file)-8-size:len(file)-8])
+	if len(rest) != 0 {
+		t.Fatalf("footer has %d trailing bytes", len(rest))
+	}
+	return footer
+}
+
+func TestWithParquetDestination(t *testing.T) {
+	sink := &memorySink{}
+	countField, _ := NewIntField("count")
+	formatter, _ := NewFormatter(OutputFormatJSON, []Field{NewDefaultLevelField(), NewMessageField(), countField})
+
+	logger, _ := NewLoggerWithOptions(
+		WithAsync(false),
+		WithParquetDestination(formatter, &ParquetSettings{Sink: sink, FlushInterval: time.Hour}),
+	)
+	logger.Info("one", 1)
+	logger.Warn("two")
+	logger.Error("three", 3)
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if len(sink.names) != 1 {
+		t.Fatalf("files = %v, want one file", sink.names)
+	}
+	file := sink.files[sink.names[0]]
+	footer := readParquetFooter(t, file)
+
+	if got := footer[3]; got != int64(3) {
+		t.Errorf("num_rows = %v, want 3", got)
+	}
+	var names []string
+	for _, element := range footer[2].([]any)[1:] {
+		names = append(names, element.(map[int16]any)[4].(string))
+	}
+	if want := []string{"count", "level", "message"}; !slices.Equal(names, want) {
+		t.Errorf("columns = %v, want %v", names, want)
+	}
+
+	// The count column: INT64, with a null in the middle.
+	chunk := footer[4].([]any)[0].(map[int16]any)[1].([]any)[0].(map[int16]any)
+	meta := chunk[3].(map[int16]any)
+	if got := meta[1]; got != int64(parquetInt64) {
+		t.Errorf("count type = %v, want INT64", got)
+	}
+	page := file[meta[9].(int64):]
+	header, page := readThrift(t, page)
+	page = page[:header[3].(int64)]
+
+	levelsSize := int(binary.LittleEndian.Uint32(page))
+	if got, want := page[4:4+levelsSize], []byte{1 << 1, 1, 1 << 1, 0, 1 << 1, 1}; !bytes.Equal(got, want) {
+		t.Errorf("definition levels = %v, want %v", got, want)
+	}
+	values := page[4+levelsSize:]
+	if got := []uint64{binary.LittleEndian.Uint64(values), binary.LittleEndian.Uint64(values[8:])}; got[0] != 1 ||
+		got[1] != 3 || len(values) != 16 {
+		t.Errorf("values = %v, want [1 3]", values)
+	}
+}
+
+func TestParquetWriter_maxRows(t *testing.T) {
+	sink := &memorySink{}
+	w, _ := NewParquetWriter(&ParquetSettings{
+		Sink:          sink,
+		MaxRows:       2,
+		FlushInterval: time.Hour,
+		Columns:       []ParquetColumn{{Name: "ratio", Type: ParquetDouble}, {Name: "ok", Type: ParquetBoolean}},
+	})
+	defer w.Close()
+
+	_, _ = w.Write([]byte(`{"ratio": 0.5, "ok": true}` + "\n"))
+	if len(sink.names) != 0 {
+		t.Fatalf("files = %v, want none before MaxRows", sink.names)
+	}
+	_, _ = w.Write([]byte(`{"ratio": "n/a", "ok": false}` + "\n"))
+	if len(sink.names) != 1 {
+		t.Fatalf("files = %v, want one at MaxRows", sink.names)
+	}
+
+	footer := readParquetFooter(t, sink.files[sink.names[0]])
+	chunks := footer[4].([]any)[0].(map[int16]any)[1].([]any)
+	ratio := chunks[0].(map[int16]any)[3].(map[int16]any)
+	if got := ratio[1]; got != int64(parquetDouble) {
+		t.Errorf("ratio type = %v, want DOUBLE", got)
+	}
+
+	file := sink.files[sink.names[0]]
+	header, page := readThrift(t, file[ratio[9].(int64):])
+	page = page[:header[3].(int64)]
+	levelsSize := int(binary.LittleEndian.Uint32(page))
+	values := page[4+levelsSize:]
+	if len(values) != 8 || math.Float64frombits(binary.LittleEndian.Uint64(values)) != 0.5 {
+		t.Errorf("ratio values = %v, want [0.5] and a null", values)
+	}
+}
+
+func TestNewParquetWriter_noSink(t *testing.T) {
+	if _, err := NewParquetWriter(&ParquetSettings{}); err != ErrorParquetSinkNotSpecified {
+		t.Errorf("NewParquetWriter() error = %v, want %v", err, ErrorParquetSinkNotSpecified)
+	}
+}
@@ -0,0 +1,211 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// tailBufferSize is the number of lines buffered for a Tail subscriber, on top of the recent lines. Lines are dropped
+// for subscribers that fall further behind, so they never block the logger.
+const tailBufferSize = 256
+
+// RingBufferWriter is a destination that keeps the most recent lines in memory, e.g. to show them in an admin page,
+// streams them to Tail subscribers, and answers Queries over them.
+type RingBufferWriter struct {
+	mu          sync.Mutex
+	entries     []ringEntry
+	next        int
+	count       int
+	subscribers map[chan string]struct{}
+}
+
+// NewRingBufferWriter returns a RingBufferWriter that keeps the last capacity lines. A capacity <= 0 keeps 1000.
+func NewRingBufferWriter(capacity int) *RingBufferWriter {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+	return &RingBufferWriter{entries: make([]ringEntry, capacity), subscribers: map[chan string]struct{}{}}
+}
+
+// ringEntry is a line kept by a RingBufferWriter. The fields are only set for lines written by a logger through a
+// RecordFormatter, so that Queries can match them.
+type ringEntry struct {
+	args   LogLineArgs
+	fields map[string]any
+	line   string
+}
+
+// WithRingBufferDestination adds a RingBufferWriter destination keeping the last capacity lines, which the logger's
+// Tail method streams, and its Query method searches.
+func WithRingBufferDestination(formatter LogLineFormatter, capacity int) LoggerOption {
+	return func(l *ultraLogger) error {
+		w := NewRingBufferWriter(capacity)
+		l.ring = w
+
+		if l.destinations == nil {
+			l.destinations = map[io.Writer]LogLineFormatter{}
+		}
+		l.destinations[w] = formatter
+		return nil
+	}
+}
+
+// Write adds the lines of p, without their trailing newlines.
+func (w *RingBufferWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	rest := bytes.TrimSuffix(p, []byte{'\n'})
+	for {
+		line, after, found := bytes.Cut(rest, []byte{'\n'})
+		w.add(ringEntry{line: string(line)})
+		if !found {
+			return len(p), nil
+		}
+		rest = after
+	}
+}
+
+// WriteLine implements LineWriter, so that the lines aren't coalesced; they are written to subscribers as soon as
+// they are logged.
+func (w *RingBufferWriter) WriteLine(args LogLineArgs, line []byte) (int, error) {
+	w.add(ringEntry{args: args, line: string(bytes.TrimSuffix(line, []byte{'\n'}))})
+	return len(line), nil
+}
+
+// writeEntry adds a line logged by a logger, along with the values of its fields.
+func (w *RingBufferWriter) writeEntry(args LogLineArgs, fields map[string]any, line []byte) {
+	w.add(ringEntry{args: args, fields: fields, line: string(line)})
+}
+
+// formatRingLine formats a line for a RingBufferWriter, and returns the values of its fields as they are when it's
+// logged, by key. Lines of a RecordFormatter are built once, then encoded; the fields of the lines of formatters
+// wrapping one, e.g. colorized formatters, are built again by the RecordFormatter they wrap. The fields are nil if the
+// formatter has none.
+func formatRingLine(
+	ctx context.Context, traced bool, f LogLineFormatter, args LogLineArgs, data []any,
+) (FormatResult, map[string]any) {
+	recordFormatter, ok := unwrapFormatter[RecordFormatter](f)
+	if !ok {
+		return formatLogLine(ctx, traced, f, args, data), nil
+	}
+
+	record, err := recordFormatter.BuildRecord(args, data)
+	if err != nil {
+		return FormatResult{nil, err}, nil
+	}
+	result := formatLogLine(ctx, traced, f, args, data)
+	if recordFormatter == f {
+		line, err := recordFormatter.Encode(record)
+		result = FormatResult{line, err}
+	}
+	return result, snapshotRecordFields(record)
+}
+
+// snapshotRecordFields returns the values of the fields of the record, by key, keeping the first of the fields
+// written under the same key. Values that can change after the line is logged, like maps, slices and pointers, are
+// kept as the strings they are written as, so that Queries see them as they were, and don't keep them alive.
+func snapshotRecordFields(record *Record) map[string]any {
+	fields := make(map[string]any, len(record.Fields))
+	for _, field := range record.Fields {
+		if _, ok := fields[field.Key]; ok {
+			continue
+		}
+		switch value := field.Value.(type) {
+		case nil, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64,
+			time.Time, time.Duration:
+			fields[field.Key] = value
+		default:
+			fields[field.Key] = flatValueString(field.Key, value, record.Args.NonFiniteFloats, NestingLimits{})
+		}
+	}
+	return fields
+}
+
+func (w *RingBufferWriter) add(entry ringEntry) {
+	// Queries run long after the line was logged; it mustn't hold on to the logger's state for the line.
+	entry.args.line, entry.args.delivery = nil, nil
+	if entry.args.Time.IsZero() {
+		entry.args.Time = time.Now()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.entries[w.next] = entry
+	w.next = (w.next + 1) % len(w.entries)
+	w.count = min(w.count+1, len(w.entries))
+
+	for subscriber := range w.subscribers {
+		select {
+		case subscriber <- entry.line:
+		default:
+		}
+	}
+}
+
+// Lines returns the lines kept by the writer, oldest first.
+func (w *RingBufferWriter) Lines() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.recentLocked(w.count)
+}
+
+// recentLocked returns the last n lines, oldest first.
+func (w *RingBufferWriter) recentLocked(n int) []string {
+	entries := w.recentEntriesLocked(n)
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		lines[i] = entry.line
+	}
+	return lines
+}
+
+// recentEntriesLocked returns the last n entries, oldest first.
+func (w *RingBufferWriter) recentEntriesLocked(n int) []ringEntry {
+	n = max(min(n, w.count), 0)
+	entries := make([]ringEntry, 0, n)
+	for i := range n {
+		entries = append(entries, w.entries[(w.next-n+i+len(w.entries))%len(w.entries)])
+	}
+	return entries
+}
+
+// Tail returns a channel of the last n lines, followed by the lines written after them, until ctx is done; the
+// channel is then closed. Lines are dropped if the receiver falls too far behind.
+func (w *RingBufferWriter) Tail(ctx context.Context, n int) <-chan string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	recent := w.recentLocked(n)
+	lines := make(chan string, len(recent)+tailBufferSize)
+	for _, line := range recent {
+		lines <- line
+	}
+	w.subscribers[lines] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.subscribers, lines)
+		close(lines)
+	}()
+
+	return lines
+}
+
+// Tail returns a channel of the last n lines of the logger's ring buffer destination, followed by the lines logged
+// after them, until ctx is done. It returns ErrorNoRingBuffer if the logger has no WithRingBufferDestination.
+func (l *ultraLogger) Tail(ctx context.Context, n int) (<-chan string, error) {
+	if l.ring == nil {
+		return nil, ErrorNoRingBuffer
+	}
+	return l.ring.Tail(ctx, n), nil
+}
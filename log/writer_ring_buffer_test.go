@@ -0,0 +1,118 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRingBufferWriter_Lines(t *testing.T) {
+	w := NewRingBufferWriter(2)
+	_, _ = w.Write([]byte("one\ntwo\n"))
+	_, _ = w.Write([]byte("three\n"))
+
+	if got, want := w.Lines(), []string{"two", "three"}; !slices.Equal(got, want) {
+		t.Errorf("Lines() = %q, want %q", got, want)
+	}
+}
+
+func TestLogger_Tail(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+	logger, _ := NewLoggerWithOptions(WithAsync(false), WithRingBufferDestination(formatter, 10))
+
+	logger.Info("one")
+	logger.Info("two")
+	logger.Info("three")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lines, err := logger.Tail(ctx, 2)
+	if err != nil {
+		t.Fatalf("Tail() error = %v", err)
+	}
+	logger.Info("four")
+	cancel()
+
+	var got []string
+	for line := range lines {
+		got = append(got, line)
+	}
+	if want := []string{"two", "three", "four"}; !slices.Equal(got, want) {
+		t.Errorf("Tail() = %q, want %q", got, want)
+	}
+}
+
+func TestLogger_Tail_noRingBuffer(t *testing.T) {
+	logger, _ := NewLoggerWithOptions(WithAsync(false))
+
+	if _, err := logger.Tail(context.Background(), 10); !errors.Is(err, ErrorNoRingBuffer) {
+		t.Errorf("Tail() error = %v, want %v", err, ErrorNoRingBuffer)
+	}
+}
+
+func TestTailFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("one\ntwo\nthree\npart"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lines, err := TailFile(ctx, path, 2)
+	if err != nil {
+		t.Fatalf("TailFile() error = %v", err)
+	}
+
+	receive := func() string {
+		t.Helper()
+		select {
+		case line := <-lines:
+			return line
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a line")
+			return ""
+		}
+	}
+
+	for _, want := range []string{"two", "three"} {
+		if got := receive(); got != want {
+			t.Errorf("line = %q, want %q", got, want)
+		}
+	}
+
+	// The partial line is sent once it's complete.
+	file, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	_, _ = file.WriteString("ial\nfour\n")
+	_ = file.Close()
+	for _, want := range []string{"partial", "four"} {
+		if got := receive(); got != want {
+			t.Errorf("line = %q, want %q", got, want)
+		}
+	}
+
+	// A rotated file is followed from its start.
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("five\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := receive(), "five"; got != want {
+		t.Errorf("line = %q, want %q", got, want)
+	}
+
+	// A truncated file too.
+	if err := os.WriteFile(path, []byte("six\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := receive(), "six"; got != want {
+		t.Errorf("line = %q, want %q", got, want)
+	}
+
+	cancel()
+	for range lines {
+	}
+}
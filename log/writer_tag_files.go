@@ -0,0 +1,237 @@
+package log
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TagFileSettings are the settings for a TagFileWriter.
+type TagFileSettings struct {
+	// Dir is the directory of the files, created if it doesn't exist. Required.
+	Dir string
+	// Extension is the extension of the files. Defaults to ".log".
+	Extension string
+	// UntaggedName is the name of the file of lines without a tag, and of lines written with Write. Defaults to
+	// "untagged".
+	UntaggedName string
+	// MaxOpenFiles is the maximum number of files kept open. The least recently written file is closed to open
+	// another. Defaults to 32.
+	MaxOpenFiles int
+	// IdleTimeout is how long a file is kept open after its last write. Defaults to five minutes.
+	IdleTimeout time.Duration
+}
+
+var defaultTagFileSettings = TagFileSettings{
+	Extension:    ".log",
+	UntaggedName: "untagged",
+	MaxOpenFiles: 32,
+	IdleTimeout:  5 * time.Minute,
+}
+
+func (s *TagFileSettings) mergeDefault() {
+	if s.Extension == "" {
+		s.Extension = defaultTagFileSettings.Extension
+	}
+	if s.UntaggedName == "" {
+		s.UntaggedName = defaultTagFileSettings.UntaggedName
+	}
+	if s.MaxOpenFiles <= 0 {
+		s.MaxOpenFiles = defaultTagFileSettings.MaxOpenFiles
+	}
+	if s.IdleTimeout <= 0 {
+		s.IdleTimeout = defaultTagFileSettings.IdleTimeout
+	}
+}
+
+// TagFileWriter is a destination that fans lines out to one file per tag, e.g. logs/http.log and logs/db.log, so
+// subsystems get their own log files. Files are created lazily, on the first line with their tag. At most
+// MaxOpenFiles files are kept open, and files that haven't been written for the IdleTimeout are closed; they're
+// reopened, in append mode, on the next line with their tag.
+//
+// Characters of tags that aren't letters, digits, '-', '_' or '.' are replaced with '_' in file names.
+type TagFileWriter struct {
+	settings TagFileSettings
+
+	mu     sync.Mutex
+	files  map[string]*tagFile
+	closed bool
+
+	done chan struct{}
+}
+
+// tagFile is an open file of a TagFileWriter.
+type tagFile struct {
+	file      *os.File
+	lastWrite time.Time
+}
+
+// NewTagFileWriter returns a TagFileWriter writing to files in settings.Dir.
+func NewTagFileWriter(settings *TagFileSettings) (*TagFileWriter, error) {
+	if settings == nil || settings.Dir == "" {
+		return nil, ErrorTagFileDirNotSpecified
+	}
+	s := *settings
+	s.mergeDefault()
+
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return nil, err
+	}
+
+	w := &TagFileWriter{
+		settings: s,
+		files:    map[string]*tagFile{},
+		done:     make(chan struct{}),
+	}
+	go w.closeIdleLoop()
+
+	return w, nil
+}
+
+// WithTagFileDestination adds a TagFileWriter destination, writing lines to one file per tag. The writer is owned by
+// the logger; it is closed by the logger's Close method.
+func WithTagFileDestination(formatter LogLineFormatter, settings *TagFileSettings) LoggerOption {
+	return func(l *ultraLogger) error {
+		w, err := NewTagFileWriter(settings)
+		if err != nil {
+			return &ErrorLoggerInitialization{err: err}
+		}
+		l.closers = append(l.closers, w)
+
+		if l.destinations == nil {
+			l.destinations = map[io.Writer]LogLineFormatter{}
+		}
+		l.destinations[w] = formatter
+		return nil
+	}
+}
+
+// Write writes p to the file of untagged lines.
+func (w *TagFileWriter) Write(p []byte) (int, error) {
+	return w.writeTagged("", p)
+}
+
+// WriteLine implements LineWriter. It writes the line to the file of its tag.
+func (w *TagFileWriter) WriteLine(args LogLineArgs, line []byte) (int, error) {
+	return w.writeTagged(args.Tag, line)
+}
+
+func (w *TagFileWriter) writeTagged(tag string, p []byte) (int, error) {
+	name := w.fileName(tag)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+
+	f, ok := w.files[name]
+	if !ok {
+		if len(w.files) >= w.settings.MaxOpenFiles {
+			w.closeLeastRecentLocked()
+		}
+
+		file, err := os.OpenFile(filepath.Join(w.settings.Dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return 0, err
+		}
+		f = &tagFile{file: file}
+		w.files[name] = f
+	}
+
+	f.lastWrite = time.Now()
+	return f.file.Write(p)
+}
+
+// fileName returns the name of the file of the tag.
+func (w *TagFileWriter) fileName(tag string) string {
+	if tag == "" {
+		return w.settings.UntaggedName + w.settings.Extension
+	}
+
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, tag)
+	if strings.Trim(name, ".") == "" {
+		// "." and ".." aren't file names.
+		name = strings.Repeat("_", len(name))
+	}
+	return name + w.settings.Extension
+}
+
+// OpenFiles returns the number of files currently open.
+func (w *TagFileWriter) OpenFiles() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return len(w.files)
+}
+
+// Close closes every open file. The writer must not be used after Close.
+func (w *TagFileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	close(w.done)
+
+	var errs []error
+	for name, f := range w.files {
+		errs = append(errs, f.file.Close())
+		delete(w.files, name)
+	}
+	return errors.Join(errs...)
+}
+
+func (w *TagFileWriter) closeLeastRecentLocked() {
+	var oldest string
+	for name, f := range w.files {
+		if oldest == "" || f.lastWrite.Before(w.files[oldest].lastWrite) {
+			oldest = name
+		}
+	}
+	if oldest != "" {
+		_ = w.files[oldest].file.Close()
+		delete(w.files, oldest)
+	}
+}
+
+func (w *TagFileWriter) closeIdleLoop() {
+	ticker := time.NewTicker(max(w.settings.IdleTimeout/2, time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.closeIdle()
+		}
+	}
+}
+
+// closeIdle closes the files that haven't been written for the IdleTimeout.
+func (w *TagFileWriter) closeIdle() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for name, f := range w.files {
+		if time.Since(f.lastWrite) >= w.settings.IdleTimeout {
+			_ = f.file.Close()
+			delete(w.files, name)
+		}
+	}
+}
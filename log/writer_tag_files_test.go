@@ -0,0 +1,100 @@
+package log
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(%q) error = %v", path, err)
+	}
+	return string(b)
+}
+
+func TestWithTagFileDestination(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+
+	logger, err := NewLoggerWithOptions(
+		WithAsync(false),
+		WithWriteCoalescing(nil),
+		WithTagFileDestination(formatter, &TagFileSettings{Dir: dir}),
+	)
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	logger.Info("started")
+	logger.SetTag("http")
+	logger.Info("GET /")
+	logger.SetTag("db")
+	logger.Info("SELECT 1")
+	logger.SetTag("../etc")
+	logger.Info("escaped")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	for name, want := range map[string]string{
+		"untagged.log": "started\n",
+		"http.log":     "GET /\n",
+		"db.log":       "SELECT 1\n",
+		".._etc.log":   "escaped\n",
+	} {
+		if got := readFile(t, filepath.Join(dir, name)); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestTagFileWriter_maxOpenFiles(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewTagFileWriter(&TagFileSettings{Dir: dir, MaxOpenFiles: 2})
+	if err != nil {
+		t.Fatalf("NewTagFileWriter() error = %v", err)
+	}
+	defer w.Close()
+
+	for _, tag := range []string{"a", "b", "c", "a"} {
+		if _, err := w.WriteLine(LogLineArgs{Tag: tag}, []byte(tag+"\n")); err != nil {
+			t.Fatalf("WriteLine() error = %v", err)
+		}
+	}
+
+	if n := w.OpenFiles(); n != 2 {
+		t.Errorf("OpenFiles() = %d, want 2", n)
+	}
+	if got := readFile(t, filepath.Join(dir, "a.log")); got != "a\na\n" {
+		t.Errorf("a.log = %q, want the file to be reopened in append mode", got)
+	}
+}
+
+func TestTagFileWriter_idleTimeout(t *testing.T) {
+	w, err := NewTagFileWriter(&TagFileSettings{Dir: t.TempDir(), IdleTimeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewTagFileWriter() error = %v", err)
+	}
+	defer w.Close()
+
+	_, _ = w.WriteLine(LogLineArgs{Tag: "http"}, []byte("hello\n"))
+
+	deadline := time.Now().Add(time.Second)
+	for w.OpenFiles() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("idle file wasn't closed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestNewTagFileWriter_missingDir(t *testing.T) {
+	if _, err := NewTagFileWriter(&TagFileSettings{}); !errors.Is(err, ErrorTagFileDirNotSpecified) {
+		t.Errorf("NewTagFileWriter() error = %v, want %v", err, ErrorTagFileDirNotSpecified)
+	}
+}
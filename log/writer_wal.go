@@ -0,0 +1,367 @@
+package log
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// walRecordHeaderSize is the size of the length prefix of every WAL record.
+const walRecordHeaderSize = 4
+
+// WALSettings are the settings for a WALWriter.
+type WALSettings struct {
+	// Path is the path of the write-ahead log file. The checkpoint is stored next to it, in Path + ".checkpoint".
+	// Required.
+	Path string
+	// Fsync syncs the WAL to disk after every append, and the checkpoint after every delivery. Without it, lines
+	// survive a process crash but not necessarily an OS crash or power loss. Default=false.
+	Fsync bool
+	// RetryInterval is how long the writer waits before retrying a failed delivery. Defaults to one second.
+	RetryInterval time.Duration
+	// FlushTimeout is the maximum amount of time Flush waits for pending lines to be delivered. Defaults to five
+	// seconds.
+	FlushTimeout time.Duration
+	// CompactThreshold is the WAL size, in bytes, above which the WAL is truncated once every line in it has been
+	// delivered. Defaults to 1MiB.
+	CompactThreshold int64
+}
+
+var defaultWALSettings = WALSettings{
+	RetryInterval:    time.Second,
+	FlushTimeout:     5 * time.Second,
+	CompactThreshold: 1024 * 1024,
+}
+
+func (s *WALSettings) mergeDefault() {
+	if s.RetryInterval <= 0 {
+		s.RetryInterval = defaultWALSettings.RetryInterval
+	}
+	if s.FlushTimeout <= 0 {
+		s.FlushTimeout = defaultWALSettings.FlushTimeout
+	}
+	if s.CompactThreshold <= 0 {
+		s.CompactThreshold = defaultWALSettings.CompactThreshold
+	}
+}
+
+// WALWriter is a destination that appends every line to a local write-ahead log before delivering it to the
+// underlying destination, typically a network connection. Lines are checkpointed once they've been delivered, and
+// undelivered lines are replayed when a WALWriter is created for the same WAL, so a process crash doesn't lose lines
+// that were buffered for the network.
+//
+// Delivery happens in the background and is retried until it succeeds. Write only fails if the line can't be
+// appended to the WAL.
+type WALWriter struct {
+	destination io.Writer
+	settings    WALSettings
+
+	wal        *os.File
+	checkpoint *os.File
+
+	mu        sync.Mutex
+	size      int64 // Size of the WAL, in bytes. Only complete records are counted.
+	delivered int64 // Offset of the first undelivered record.
+	compacted int64 // Bytes removed from the WAL by compaction, so Flush can wait on positions that survive it.
+	caughtUp  *sync.Cond
+	closed    bool
+
+	wake chan struct{}
+	done chan struct{}
+}
+
+// NewWALWriter opens (or creates) the WAL at settings.Path and returns a WALWriter delivering to destination. Lines
+// left undelivered by a previous process are delivered first.
+func NewWALWriter(destination io.Writer, settings *WALSettings) (*WALWriter, error) {
+	if settings == nil || settings.Path == "" {
+		return nil, ErrorWALPathNotSpecified
+	}
+	s := *settings
+	s.mergeDefault()
+
+	wal, err := os.OpenFile(s.Path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint, err := os.OpenFile(s.Path+".checkpoint", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		_ = wal.Close()
+		return nil, err
+	}
+
+	w := &WALWriter{
+		destination: destination,
+		settings:    s,
+		wal:         wal,
+		checkpoint:  checkpoint,
+		wake:        make(chan struct{}, 1),
+		done:        make(chan struct{}),
+	}
+	w.caughtUp = sync.NewCond(&w.mu)
+
+	if err := w.recover(); err != nil {
+		_ = wal.Close()
+		_ = checkpoint.Close()
+		return nil, err
+	}
+
+	go w.deliverLoop()
+	w.signal()
+
+	return w, nil
+}
+
+// recover reads the checkpoint and drops a torn record at the end of the WAL, left by a crash mid-append.
+func (w *WALWriter) recover() error {
+	buf := make([]byte, 8)
+	n, err := w.checkpoint.ReadAt(buf, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if n == len(buf) {
+		w.delivered = int64(binary.BigEndian.Uint64(buf))
+	}
+
+	info, err := w.wal.Stat()
+	if err != nil {
+		return err
+	}
+	fileSize := info.Size()
+	if w.delivered > fileSize {
+		w.delivered = 0
+	}
+
+	offset := w.delivered
+	header := make([]byte, walRecordHeaderSize)
+	for offset+walRecordHeaderSize <= fileSize {
+		if _, err := w.wal.ReadAt(header, offset); err != nil {
+			return err
+		}
+		next := offset + walRecordHeaderSize + int64(binary.BigEndian.Uint32(header))
+		if next > fileSize {
+			break
+		}
+		offset = next
+	}
+
+	if offset != fileSize {
+		if err := w.wal.Truncate(offset); err != nil {
+			return err
+		}
+	}
+	w.size = offset
+
+	_, err = w.wal.Seek(w.size, io.SeekStart)
+	return err
+}
+
+// Write appends p to the WAL and schedules it for delivery.
+func (w *WALWriter) Write(p []byte) (int, error) {
+	record := make([]byte, walRecordHeaderSize+len(p))
+	binary.BigEndian.PutUint32(record, uint32(len(p)))
+	copy(record[walRecordHeaderSize:], p)
+
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return 0, os.ErrClosed
+	}
+
+	if err := w.appendLocked(record); err != nil {
+		w.mu.Unlock()
+		return 0, err
+	}
+	w.mu.Unlock()
+
+	w.signal()
+
+	return len(p), nil
+}
+
+// appendLocked appends the record to the WAL. If the append fails, the WAL is rolled back to its previous size, so a
+// partial record can't be mistaken for the start of the next one, and a line that failed isn't delivered anyway.
+func (w *WALWriter) appendLocked(record []byte) error {
+	_, err := w.wal.Write(record)
+	if err == nil && w.settings.Fsync {
+		err = w.wal.Sync()
+	}
+	if err != nil {
+		if truncErr := w.wal.Truncate(w.size); truncErr != nil {
+			return errors.Join(err, truncErr)
+		}
+		_, seekErr := w.wal.Seek(w.size, io.SeekStart)
+		return errors.Join(err, seekErr)
+	}
+
+	w.size += int64(len(record))
+	return nil
+}
+
+// syncLocal syncs the WAL to disk, without waiting for the lines in it to be delivered.
+func (w *WALWriter) syncLocal() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	return w.wal.Sync()
+}
+
+// Pending returns the number of bytes in the WAL that haven't been delivered yet.
+func (w *WALWriter) Pending() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.size - w.delivered
+}
+
+// Flush waits until every line written so far has been delivered, or until the FlushTimeout elapses, in which case
+// ErrorWALFlushTimeout is returned. Undelivered lines stay in the WAL either way.
+func (w *WALWriter) Flush() error {
+	deadline := time.AfterFunc(w.settings.FlushTimeout, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		w.caughtUp.Broadcast()
+	})
+	defer deadline.Stop()
+
+	start := time.Now()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	target := w.compacted + w.size
+	for w.compacted+w.delivered < target && !w.closed {
+		if time.Since(start) >= w.settings.FlushTimeout {
+			return ErrorWALFlushTimeout
+		}
+		w.caughtUp.Wait()
+	}
+
+	return nil
+}
+
+// Close attempts to deliver pending lines (see Flush), stops the delivery goroutine, and closes the WAL. Undelivered
+// lines are delivered by the next WALWriter opened for the same WAL.
+func (w *WALWriter) Close() error {
+	flushErr := w.Flush()
+
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.caughtUp.Broadcast()
+	w.mu.Unlock()
+
+	close(w.done)
+
+	return errors.Join(flushErr, w.wal.Close(), w.checkpoint.Close())
+}
+
+func (w *WALWriter) signal() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (w *WALWriter) deliverLoop() {
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-w.wake:
+		}
+
+		for w.deliverNext() {
+		}
+	}
+}
+
+// deliverNext delivers the next undelivered record. It returns false if there is nothing left to deliver, or the
+// delivery failed; failed deliveries are retried after the RetryInterval.
+func (w *WALWriter) deliverNext() bool {
+	w.mu.Lock()
+	offset, size := w.delivered, w.size
+	if w.closed {
+		w.mu.Unlock()
+		return false
+	}
+	if offset == size {
+		w.compactLocked()
+		w.caughtUp.Broadcast()
+		w.mu.Unlock()
+		return false
+	}
+	w.mu.Unlock()
+
+	header := make([]byte, walRecordHeaderSize)
+	if _, err := w.wal.ReadAt(header, offset); err != nil {
+		w.retryLater()
+		return false
+	}
+	line := make([]byte, binary.BigEndian.Uint32(header))
+	if _, err := w.wal.ReadAt(line, offset+walRecordHeaderSize); err != nil {
+		w.retryLater()
+		return false
+	}
+
+	if _, err := w.destination.Write(line); err != nil {
+		w.retryLater()
+		return false
+	}
+
+	next := offset + walRecordHeaderSize + int64(len(line))
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return false
+	}
+	w.delivered = next
+	_ = w.storeCheckpointLocked()
+	w.caughtUp.Broadcast()
+
+	return true
+}
+
+func (w *WALWriter) retryLater() {
+	time.AfterFunc(w.settings.RetryInterval, w.signal)
+}
+
+func (w *WALWriter) storeCheckpointLocked() error {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(w.delivered))
+	if _, err := w.checkpoint.WriteAt(buf, 0); err != nil {
+		return err
+	}
+	if w.settings.Fsync {
+		return w.checkpoint.Sync()
+	}
+	return nil
+}
+
+// compactLocked truncates the WAL once everything in it has been delivered and it has grown past the threshold.
+func (w *WALWriter) compactLocked() {
+	if w.size < w.settings.CompactThreshold {
+		return
+	}
+
+	if err := w.wal.Truncate(0); err != nil {
+		return
+	}
+	if _, err := w.wal.Seek(0, io.SeekStart); err != nil {
+		return
+	}
+	w.compacted += w.size
+	w.size, w.delivered = 0, 0
+	_ = w.storeCheckpointLocked()
+}
@@ -0,0 +1,244 @@
+package log
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// toggleWriter is a destination that can be switched between failing and succeeding.
+type toggleWriter struct {
+	mu     sync.Mutex
+	lines  []string
+	failed bool
+}
+
+func (w *toggleWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.failed {
+		return 0, errors.New("connection refused")
+	}
+	w.lines = append(w.lines, string(p))
+	return len(p), nil
+}
+
+func (w *toggleWriter) setFailed(failed bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.failed = failed
+}
+
+func (w *toggleWriter) received() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.lines...)
+}
+
+func TestWALWriter_delivery(t *testing.T) {
+	dest := &toggleWriter{}
+	w, err := NewWALWriter(dest, &WALSettings{Path: filepath.Join(t.TempDir(), "test.wal")})
+	if err != nil {
+		t.Fatalf("NewWALWriter() error = %v", err)
+	}
+	defer w.Close()
+
+	for _, line := range []string{"one\n", "two\n", "three\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if got := dest.received(); len(got) != 3 || got[0] != "one\n" || got[2] != "three\n" {
+		t.Errorf("destination received %q", got)
+	}
+	if pending := w.Pending(); pending != 0 {
+		t.Errorf("Pending() = %d, want 0", pending)
+	}
+}
+
+func TestWALWriter_replayAfterRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.wal")
+	settings := &WALSettings{Path: path, RetryInterval: time.Millisecond, FlushTimeout: 20 * time.Millisecond}
+
+	down := &toggleWriter{failed: true}
+	w, err := NewWALWriter(down, settings)
+	if err != nil {
+		t.Fatalf("NewWALWriter() error = %v", err)
+	}
+
+	_, _ = w.Write([]byte("one\n"))
+	_, _ = w.Write([]byte("two\n"))
+
+	if err := w.Close(); !errors.Is(err, ErrorWALFlushTimeout) {
+		t.Fatalf("Close() error = %v, want %v", err, ErrorWALFlushTimeout)
+	}
+
+	up := &toggleWriter{}
+	w, err = NewWALWriter(up, settings)
+	if err != nil {
+		t.Fatalf("NewWALWriter() error = %v", err)
+	}
+	defer w.Close()
+
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if got := up.received(); len(got) != 2 || got[0] != "one\n" || got[1] != "two\n" {
+		t.Errorf("destination received %q after restart, want the undelivered lines", got)
+	}
+}
+
+func TestWALWriter_retry(t *testing.T) {
+	dest := &toggleWriter{failed: true}
+	w, err := NewWALWriter(dest, &WALSettings{
+		Path:          filepath.Join(t.TempDir(), "test.wal"),
+		RetryInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewWALWriter() error = %v", err)
+	}
+	defer w.Close()
+
+	_, _ = w.Write([]byte("one\n"))
+	time.Sleep(5 * time.Millisecond)
+	dest.setFailed(false)
+
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if got := dest.received(); len(got) != 1 {
+		t.Errorf("destination received %q, want the retried line", got)
+	}
+}
+
+func TestWALWriter_tornRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.wal")
+
+	// A complete record, followed by a record header promising more bytes than were written before the crash.
+	torn := []byte{0, 0, 0, 4, 'o', 'n', 'e', '\n', 0, 0, 0, 9, 't', 'w'}
+	if err := os.WriteFile(path, torn, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := &toggleWriter{}
+	w, err := NewWALWriter(dest, &WALSettings{Path: path})
+	if err != nil {
+		t.Fatalf("NewWALWriter() error = %v", err)
+	}
+	defer w.Close()
+
+	_, _ = w.Write([]byte("three\n"))
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if got := dest.received(); len(got) != 2 || got[0] != "one\n" || got[1] != "three\n" {
+		t.Errorf("destination received %q, want the complete records only", got)
+	}
+}
+
+func TestWALWriter_compaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.wal")
+	dest := &toggleWriter{}
+	w, err := NewWALWriter(dest, &WALSettings{Path: path, CompactThreshold: 16})
+	if err != nil {
+		t.Fatalf("NewWALWriter() error = %v", err)
+	}
+	defer w.Close()
+
+	for i := 0; i < 10; i++ {
+		_, _ = w.Write([]byte("some line\n"))
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	// The WAL is compacted once the deliverer notices it has caught up.
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if info, _ := os.Stat(path); info.Size() < 16 {
+			return
+		}
+		w.signal()
+		time.Sleep(time.Millisecond)
+	}
+	t.Errorf("WAL was not compacted")
+}
+
+func TestWithWALDestination(t *testing.T) {
+	dest := &toggleWriter{}
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+
+	logger, err := NewLoggerWithOptions(
+		WithWALDestination(dest, formatter, &WALSettings{Path: filepath.Join(t.TempDir(), "test.wal")}),
+	)
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	logger.Info("hello")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if got := dest.received(); len(got) != 1 || got[0] != "hello\n" {
+		t.Errorf("destination received %q", got)
+	}
+}
+
+func TestWithWALDestination_flush(t *testing.T) {
+	dest := &toggleWriter{failed: true}
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+
+	logger, err := NewLoggerWithOptions(
+		WithWALDestination(dest, formatter, &WALSettings{
+			Path:          filepath.Join(t.TempDir(), "test.wal"),
+			RetryInterval: time.Millisecond,
+			FlushTimeout:  time.Hour,
+		}),
+	)
+	if err != nil {
+		t.Fatalf("NewLoggerWithOptions() error = %v", err)
+	}
+
+	logger.Info("hello")
+
+	// The destination is down; Flush must not wait for the line to be delivered.
+	flushed := make(chan struct{})
+	go func() {
+		logger.Flush()
+		close(flushed)
+	}()
+	select {
+	case <-flushed:
+	case <-time.After(time.Second):
+		t.Fatal("Flush() waited for the WAL to be delivered")
+	}
+
+	dest.setFailed(false)
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got := dest.received(); len(got) != 1 || got[0] != "hello\n" {
+		t.Errorf("destination received %q", got)
+	}
+}
+
+func TestWithWALDestination_missingPath(t *testing.T) {
+	formatter, _ := NewFormatter(OutputFormatText, []Field{NewMessageField()})
+
+	_, err := NewLoggerWithOptions(WithWALDestination(&toggleWriter{}, formatter, nil))
+	if !errors.Is(err, ErrorWALPathNotSpecified) {
+		t.Errorf("NewLoggerWithOptions() error = %v, want %v", err, ErrorWALPathNotSpecified)
+	}
+}